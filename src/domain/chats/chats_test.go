@@ -1,8 +1,1529 @@
 package chats
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
+func intPtr(value int) *int {
+	return &value
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func formatOptionalText(value *string) string {
+	if value == nil {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("%q", *value)
+}
+
+func getChatIds(chats []Chat) []int {
+	var chatIds []int
+	for _, chat := range chats {
+		chatIds = append(chatIds, chat.GetId())
+	}
+
+	return chatIds
+}
+
+func getFolderIds(folders []ChatFolder) []int {
+	var folderIds []int
+	for _, folder := range folders {
+		folderIds = append(folderIds, folder.GetId())
+	}
+
+	return folderIds
+}
+
 func TestCreateUserChatHandler(t *testing.T) {
 }
+
+var errTestConnection = errors.New("test connection refused")
+
+// unavailableSavedMessagesChatsAdapter fails saved messages chat lookups the
+// way a lost database connection does.
+type unavailableSavedMessagesChatsAdapter struct {
+	*TestChatsAdapter
+}
+
+func (adapter unavailableSavedMessagesChatsAdapter) GetSavedMessagesChat(userId int) (*Chat, error) {
+	return nil, errTestConnection
+}
+
+// racingSavedMessagesChatsAdapter lets a concurrent delivery create the saved
+// messages chat first, so the unique index rejects the next save.
+type racingSavedMessagesChatsAdapter struct {
+	*TestChatsAdapter
+}
+
+func (adapter racingSavedMessagesChatsAdapter) Save(chat Chat) (*Chat, error) {
+	adapter.TestChatsAdapter.Save(chat)
+	return nil, errors.New("duplicate key value violates unique constraint")
+}
+
+func TestCreateSavedMessagesChatHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		existingChats  []Chat
+		chatsPort      func(adapter *TestChatsAdapter) ChatsPort
+		expectedErr    error
+		expectedChatId int
+	}{
+		{name: "creates the chat", expectedChatId: 4},
+		{
+			name:           "returns the existing chat",
+			existingChats:  []Chat{NewChat(50, nil, "Saved messages", SavedMessagesChatType, []int{7}, false, 7, []int{})},
+			expectedChatId: 50,
+		},
+		{
+			name:        "lookup failure doesn't create the chat",
+			chatsPort:   func(adapter *TestChatsAdapter) ChatsPort { return unavailableSavedMessagesChatsAdapter{adapter} },
+			expectedErr: ErrFindingChat,
+		},
+		{
+			name:           "concurrent delivery created the chat",
+			chatsPort:      func(adapter *TestChatsAdapter) ChatsPort { return racingSavedMessagesChatsAdapter{adapter} },
+			expectedChatId: 4,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chatsAdapter := NewTestChatsAdapter(test.existingChats...)
+			var chatsPort ChatsPort = chatsAdapter
+			if test.chatsPort != nil {
+				chatsPort = test.chatsPort(chatsAdapter)
+			}
+			handler := NewCreateSavedMessagesChatHandler(chatsPort)
+
+			data := NewCreateChatData(SavedMessagesChatType, nil, nil, []int{}, intPtr(7), nil)
+			chat, err := handler.Execute(data, 7)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+
+			savedChats := chatsAdapter.GetAllByMember(7)
+			if test.expectedErr != nil {
+				if len(savedChats) != 0 {
+					t.Errorf("saved chats = %d, expected none", len(savedChats))
+				}
+				return
+			}
+
+			if chat.GetId() != test.expectedChatId || chat.GetAvatar() == nil {
+				t.Errorf("chat id = %d, avatar = %v, expected chat %d with avatar", chat.GetId(), chat.GetAvatar(), test.expectedChatId)
+			}
+			if repeatedChat, err := handler.Execute(data, 7); err != nil || repeatedChat.GetId() != test.expectedChatId {
+				t.Errorf("repeated Execute() = %v, %v, expected chat %d", repeatedChat, err, test.expectedChatId)
+			}
+			if savedChats := chatsAdapter.GetAllByMember(7); len(savedChats) != 1 {
+				t.Errorf("saved chats = %d, expected 1", len(savedChats))
+			}
+		})
+	}
+}
+
+func TestGetChatsHandlerUnreadCounters(t *testing.T) {
+	chatsAdapter := NewTestChatsAdapter()
+	chatsAdapter.unreadCounters = map[int]map[int]ChatUnreadCounters{
+		1: {1: NewChatUnreadCounters(3, 1)},
+		2: {1: NewChatUnreadCounters(7, 0), 2: NewChatUnreadCounters(2, 2)},
+	}
+	handler := NewGetChatsHandler(chatsAdapter, &TestUsersAdapter{}, &TestUserActionsAdapter{})
+
+	page, err := handler.Execute(1, false, nil, 1, 20)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	counters := make(map[int][2]int)
+	for _, chat := range page.GetData() {
+		chatCounters := chat.GetUnreadCounters()
+		counters[chat.GetId()] = [2]int{chatCounters.GetUnreadCount(), chatCounters.GetUnreadMentionsCount()}
+	}
+	expected := map[int][2]int{1: {3, 1}, 2: {0, 0}}
+	if fmt.Sprint(counters) != fmt.Sprint(expected) {
+		t.Errorf("unread counters = %v, expected %v", counters, expected)
+	}
+	if chatsAdapter.unreadCountersCalls != 1 {
+		t.Errorf("unread counters loaded %d times, expected once for the whole page", chatsAdapter.unreadCountersCalls)
+	}
+}
+
+func TestGetTotalUnreadHandler(t *testing.T) {
+	chatsAdapter := NewTestChatsAdapter()
+	chatsAdapter.unreadCounters = map[int]map[int]ChatUnreadCounters{
+		2: {1: NewChatUnreadCounters(7, 0), 2: NewChatUnreadCounters(2, 2)},
+	}
+	handler := NewGetTotalUnreadHandler(chatsAdapter)
+
+	total := handler.Execute(2)
+	if total.GetUnreadCount() != 9 || total.GetUnreadMentionsCount() != 2 {
+		t.Errorf("total unread = %d, mentions = %d, expected 9 and 2", total.GetUnreadCount(), total.GetUnreadMentionsCount())
+	}
+}
+
+func TestAdminRightsContains(t *testing.T) {
+	noRights := NewAdminRights(false, false, false, false, false, false)
+	tests := []struct {
+		name     string
+		rights   AdminRights
+		other    AdminRights
+		expected bool
+	}{
+		{name: "full contains none", rights: FullAdminRights(), other: noRights, expected: true},
+		{name: "full contains full", rights: FullAdminRights(), other: FullAdminRights(), expected: true},
+		{name: "none contains none", rights: noRights, other: noRights, expected: true},
+		{name: "none doesn't contain full", rights: noRights, other: FullAdminRights(), expected: false},
+		{
+			name:     "superset contains subset",
+			rights:   NewAdminRights(true, true, false, true, false, false),
+			other:    NewAdminRights(true, false, false, true, false, false),
+			expected: true,
+		},
+		{
+			name:     "subset doesn't contain superset",
+			rights:   NewAdminRights(true, false, false, true, false, false),
+			other:    NewAdminRights(true, true, false, true, false, false),
+			expected: false,
+		},
+		{
+			name:     "disjoint rights",
+			rights:   NewAdminRights(false, false, true, false, false, false),
+			other:    NewAdminRights(false, false, false, false, true, false),
+			expected: false,
+		},
+		{
+			name:     "manage admins alone",
+			rights:   NewAdminRights(true, true, true, true, true, false),
+			other:    NewAdminRights(false, false, false, false, false, true),
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if contains := test.rights.Contains(test.other); contains != test.expected {
+				t.Errorf("Contains() = %v, expected %v", contains, test.expected)
+			}
+		})
+	}
+}
+
+func TestValidateUserChatRight(t *testing.T) {
+	chat := NewTestGroupChat()
+	chat.SetMembers([]int{1, 2, 3, 4})
+	chat.SetAdmins([]int{1, 3, 4})
+	chat.SetAdminRights(3, NewAdminRights(false, true, false, false, true, false))
+	chat.SetMemberPermissions(NewMemberPermissions(true, true, false, true))
+
+	tests := []struct {
+		name     string
+		userId   int
+		right    ChatRights
+		expected bool
+	}{
+		{name: "owner has every right", userId: 1, right: ManageAdminsRight, expected: true},
+		{name: "admin with the right", userId: 3, right: RemoveMembersRight, expected: true},
+		{name: "admin without the right", userId: 3, right: ManageAdminsRight, expected: false},
+		{name: "admin falls back to member permissions", userId: 3, right: PinMessagesRight, expected: true},
+		{name: "admin without stored rights", userId: 4, right: DeleteMessagesRight, expected: false},
+		{name: "member with the permission", userId: 2, right: PinMessagesRight, expected: true},
+		{name: "member without the permission", userId: 2, right: AddMembersRight, expected: false},
+		{name: "member can't have admin only rights", userId: 2, right: RemoveMembersRight, expected: false},
+		{name: "not a member", userId: 5, right: PinMessagesRight, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if valid := ValidateUserChatRight(chat, test.userId, test.right); valid != test.expected {
+				t.Errorf("ValidateUserChatRight() = %v, expected %v", valid, test.expected)
+			}
+		})
+	}
+}
+
+func TestAddChatAdminsHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		chat        Chat
+		userId      int
+		expectedErr error
+	}{
+		{name: "owner adds admin", chat: NewTestGroupChat(), userId: 1},
+		{name: "admin without manage admins right", chat: NewTestGroupChat(), userId: 3, expectedErr: ErrNotEnoughRights},
+		{name: "member can't add admins", chat: NewTestGroupChat(), userId: 2, expectedErr: ErrNotEnoughRights},
+		{
+			name:        "not a group chat",
+			chat:        NewChat(10, nil, "Saved messages", SavedMessagesChatType, []int{1}, false, 1, []int{}),
+			userId:      1,
+			expectedErr: ErrChatNotGroup,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chatsAdapter := NewTestChatsAdapter(test.chat)
+			eventsAdapter := &TestChatEventsAdapter{}
+			handler := NewAddChatsAdminsHandler(chatsAdapter, &TestUsersAdapter{}, eventsAdapter)
+
+			_, err := handler.Execute(10, test.userId, []int{2, 5})
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+			if test.expectedErr != nil {
+				return
+			}
+
+			chat, _ := chatsAdapter.GetById(10)
+			if !slices.Equal(chat.GetAdmins(), []int{1, 3, 2}) {
+				t.Errorf("admins = %v, expected [1 3 2]", chat.GetAdmins())
+			}
+			if rights := chat.GetAdminRights(2); rights != NoAdminRights() {
+				t.Errorf("new admin rights = %+v, expected no rights", rights)
+			}
+			if !slices.Equal(eventsAdapter.sentEvents, []string{"chat_changed"}) {
+				t.Errorf("sent events = %v, expected chat_changed", eventsAdapter.sentEvents)
+			}
+		})
+	}
+}
+
+func TestSetAdminRightsHandler(t *testing.T) {
+	pinRights := NewAdminRights(false, false, false, true, false, true)
+	tests := []struct {
+		name        string
+		userId      int
+		adminId     int
+		rights      AdminRights
+		expectedErr error
+	}{
+		{name: "owner sets admin rights", userId: 1, adminId: 3, rights: FullAdminRights()},
+		{name: "admin grants the rights they have", userId: 3, adminId: 4, rights: NewAdminRights(false, false, false, false, false, true)},
+		{name: "admin can't grant rights they don't have", userId: 3, adminId: 4, rights: FullAdminRights(), expectedErr: ErrNotEnoughRights},
+		{name: "member can't set rights", userId: 2, adminId: 3, rights: pinRights, expectedErr: ErrNotEnoughRights},
+		{name: "owner rights can't change", userId: 3, adminId: 1, rights: pinRights, expectedErr: ErrCantChangeOwnerRights},
+		{name: "rights of a member", userId: 1, adminId: 2, rights: pinRights, expectedErr: ErrChatNotAdmin},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chat := NewTestGroupChat()
+			chat.SetMembers([]int{1, 2, 3, 4})
+			chat.SetAdmins([]int{1, 3, 4})
+			chat.SetAdminRights(3, NewAdminRights(false, false, false, false, false, true))
+			chatsAdapter := NewTestChatsAdapter(chat)
+			eventsAdapter := &TestChatEventsAdapter{}
+			handler := NewSetAdminRightsHandler(chatsAdapter, eventsAdapter)
+
+			_, err := handler.Execute(chat.GetId(), test.userId, test.adminId, test.rights)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+			if test.expectedErr != nil {
+				return
+			}
+
+			savedChat, _ := chatsAdapter.GetById(chat.GetId())
+			if rights := savedChat.GetAdminRights(test.adminId); rights != test.rights {
+				t.Errorf("admin rights = %+v, expected %+v", rights, test.rights)
+			}
+			if !slices.Equal(eventsAdapter.sentEvents, []string{"chat_changed"}) {
+				t.Errorf("sent events = %v, expected chat_changed", eventsAdapter.sentEvents)
+			}
+		})
+	}
+}
+
+func TestSetMemberPermissionsHandler(t *testing.T) {
+	permissions := NewMemberPermissions(true, false, false, false)
+	tests := []struct {
+		name        string
+		chatId      int
+		userId      int
+		expectedErr error
+	}{
+		{name: "admin with edit info right", chatId: 10, userId: 3},
+		{name: "member can't change permissions", chatId: 10, userId: 2, expectedErr: ErrNotEnoughRights},
+		{name: "not a group chat", chatId: 1, userId: 1, expectedErr: ErrChatNotGroup},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chatsAdapter := NewTestChatsAdapter(NewTestGroupChat())
+			handler := NewSetMemberPermissionsHandler(chatsAdapter, &TestChatEventsAdapter{})
+
+			if _, err := handler.Execute(test.chatId, test.userId, permissions); !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+			if test.expectedErr != nil {
+				return
+			}
+
+			savedChat, _ := chatsAdapter.GetById(test.chatId)
+			if savedChat.GetMemberPermissions() != permissions {
+				t.Errorf("member permissions = %+v, expected %+v", savedChat.GetMemberPermissions(), permissions)
+			}
+		})
+	}
+}
+
+func TestJoinChatByInviteHandler(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name               string
+		link               ChatInviteLink
+		userId             int
+		sendErr            error
+		expectedErr        error
+		expectedUsageCount int
+	}{
+		{
+			name:               "joins by link",
+			link:               NewChatInviteLink(0, 10, "code", 1, nil, intPtr(2), 1, false, nil, past),
+			userId:             4,
+			expectedUsageCount: 2,
+		},
+		{
+			name:               "failed member add doesn't use the link",
+			link:               NewChatInviteLink(0, 10, "code", 1, nil, intPtr(2), 1, false, nil, past),
+			userId:             4,
+			sendErr:            fmt.Errorf("broker is down"),
+			expectedErr:        ErrSendingEvent,
+			expectedUsageCount: 1,
+		},
+		{
+			name:        "revoked link",
+			link:        NewChatInviteLink(0, 10, "code", 1, nil, nil, 0, false, &past, past),
+			userId:      4,
+			expectedErr: ErrInviteLinkExpired,
+		},
+		{
+			name:        "expired link",
+			link:        NewChatInviteLink(0, 10, "code", 1, &past, nil, 0, false, nil, past),
+			userId:      4,
+			expectedErr: ErrInviteLinkExpired,
+		},
+		{
+			name:               "exhausted link",
+			link:               NewChatInviteLink(0, 10, "code", 1, nil, intPtr(1), 1, false, nil, past),
+			userId:             4,
+			expectedErr:        ErrInviteLinkExpired,
+			expectedUsageCount: 1,
+		},
+		{
+			name:        "link requires approval",
+			link:        NewChatInviteLink(0, 10, "code", 1, nil, nil, 0, true, nil, past),
+			userId:      4,
+			expectedErr: ErrInviteLinkNeedsApproval,
+		},
+		{
+			name:        "already a member",
+			link:        NewChatInviteLink(0, 10, "code", 1, nil, nil, 0, false, nil, past),
+			userId:      2,
+			expectedErr: ErrAlreadyChatMember,
+		},
+		{
+			name:        "link of a user chat",
+			link:        NewChatInviteLink(0, 1, "code", 1, nil, nil, 0, false, nil, past),
+			userId:      4,
+			expectedErr: ErrChatNotGroup,
+		},
+		{
+			name:        "banned user",
+			link:        NewChatInviteLink(0, 10, "code", 1, nil, nil, 0, false, nil, past),
+			userId:      5,
+			expectedErr: ErrUserBanned,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chatsAdapter := NewTestChatsAdapter(NewTestGroupChat())
+			chatsAdapter.SaveInviteLink(test.link)
+			chatsAdapter.SaveBan(NewChatBan(0, 10, 5, 1, nil, past))
+			systemMessagesAdapter := &TestChatSystemMessagesAdapter{}
+			handler := NewJoinChatByInviteHandler(chatsAdapter, &TestUsersAdapter{}, &TestChatEventsAdapter{sendErr: test.sendErr}, systemMessagesAdapter)
+
+			_, err := handler.Execute("code", test.userId)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+
+			link, _ := chatsAdapter.GetInviteLinkByCode("code")
+			if link.GetUsageCount() != test.expectedUsageCount {
+				t.Errorf("usage count = %d, expected %d", link.GetUsageCount(), test.expectedUsageCount)
+			}
+			if test.expectedErr != nil {
+				return
+			}
+
+			chat, _ := chatsAdapter.GetById(10)
+			if !slices.Contains(chat.GetMembers(), test.userId) {
+				t.Errorf("user %d is not a member after joining", test.userId)
+			}
+			if !slices.Equal(systemMessagesAdapter.sentEvents, []ChatSystemEvents{JoinedViaLinkSystemEvent}) {
+				t.Errorf("system events = %v, expected %v", systemMessagesAdapter.sentEvents, JoinedViaLinkSystemEvent)
+			}
+		})
+	}
+}
+
+func TestCreateInviteLinkHandler(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name        string
+		chatId      int
+		userId      int
+		data        CreateInviteLinkData
+		expectedErr error
+	}{
+		{name: "admin creates a link", chatId: 10, userId: 3, data: NewCreateInviteLinkData(nil, intPtr(5), false)},
+		{name: "member without the add members permission", chatId: 10, userId: 2, data: NewCreateInviteLinkData(nil, nil, false), expectedErr: ErrNotEnoughRights},
+		{name: "expiry in the past", chatId: 10, userId: 1, data: NewCreateInviteLinkData(&past, nil, false), expectedErr: ErrIncorrectLinkExpiresAt},
+		{name: "usage limit below one", chatId: 10, userId: 1, data: NewCreateInviteLinkData(nil, intPtr(0), false), expectedErr: ErrIncorrectLinkUsageLimit},
+		{name: "user chat", chatId: 1, userId: 1, data: NewCreateInviteLinkData(nil, nil, false), expectedErr: ErrChatNotGroup},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chatsAdapter := NewTestChatsAdapter(NewTestGroupChat())
+			handler := NewCreateInviteLinkHandler(chatsAdapter)
+
+			link, err := handler.Execute(test.chatId, test.userId, test.data)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+			if test.expectedErr != nil {
+				return
+			}
+
+			if link.GetCode() == "" || link.GetCreatedBy() != test.userId {
+				t.Errorf("link code = %q, created by %d, expected a code created by %d", link.GetCode(), link.GetCreatedBy(), test.userId)
+			}
+			if _, err := chatsAdapter.GetInviteLinkByCode(link.GetCode()); err != nil {
+				t.Errorf("link wasn't saved: %v", err)
+			}
+		})
+	}
+}
+
+func TestResolveJoinRequestHandler(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name           string
+		requestId      int
+		userId         int
+		approve        bool
+		userBanned     bool
+		expectedErr    error
+		expectedStatus JoinRequestStatuses
+		expectedEvents []string
+	}{
+		{
+			name:           "owner approves",
+			requestId:      1,
+			userId:         1,
+			approve:        true,
+			expectedStatus: ApprovedJoinRequestStatus,
+			expectedEvents: []string{"chat_changed", "join_request_resolved"},
+		},
+		{
+			name:           "owner declines",
+			requestId:      1,
+			userId:         1,
+			expectedStatus: DeclinedJoinRequestStatus,
+			expectedEvents: []string{"join_request_resolved"},
+		},
+		{
+			name:           "member without add members right",
+			requestId:      1,
+			userId:         2,
+			approve:        true,
+			expectedErr:    ErrNotEnoughRights,
+			expectedStatus: PendingJoinRequestStatus,
+		},
+		{
+			name:           "approving a banned user leaves the request pending",
+			requestId:      1,
+			userId:         1,
+			approve:        true,
+			userBanned:     true,
+			expectedErr:    ErrUserBanned,
+			expectedStatus: PendingJoinRequestStatus,
+		},
+		{
+			name:        "resolved request",
+			requestId:   2,
+			userId:      1,
+			approve:     true,
+			expectedErr: ErrJoinRequestResolved,
+		},
+		{
+			name:           "deleted user stays pending",
+			requestId:      3,
+			userId:         1,
+			approve:        true,
+			expectedErr:    ErrFindingUser,
+			expectedStatus: PendingJoinRequestStatus,
+		},
+		{
+			name:        "unknown request",
+			requestId:   4,
+			userId:      1,
+			approve:     true,
+			expectedErr: ErrJoinRequestNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chatsAdapter := NewTestChatsAdapter(NewTestGroupChat())
+			chatsAdapter.SaveJoinRequest(NewChatJoinRequest(0, 10, 4, nil, PendingJoinRequestStatus, nil, now, nil))
+			chatsAdapter.SaveJoinRequest(NewChatJoinRequest(0, 10, 5, nil, DeclinedJoinRequestStatus, intPtr(1), now, &now))
+			chatsAdapter.SaveJoinRequest(NewChatJoinRequest(0, 10, 6, nil, PendingJoinRequestStatus, nil, now, nil))
+			if test.userBanned {
+				chatsAdapter.SaveBan(NewChatBan(0, 10, 4, 1, nil, now))
+			}
+			eventsAdapter := &TestChatEventsAdapter{}
+			systemMessagesAdapter := &TestChatSystemMessagesAdapter{}
+			handler := NewResolveJoinRequestHandler(chatsAdapter, &TestUsersAdapter{}, eventsAdapter, systemMessagesAdapter)
+
+			_, err := handler.Execute(test.requestId, test.userId, test.approve)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+			if !slices.Equal(eventsAdapter.sentEvents, test.expectedEvents) {
+				t.Errorf("sent events = %v, expected %v", eventsAdapter.sentEvents, test.expectedEvents)
+			}
+			if test.expectedStatus == "" {
+				return
+			}
+
+			request, _ := chatsAdapter.GetJoinRequestById(test.requestId)
+			if request.GetStatus() != test.expectedStatus {
+				t.Errorf("request status = %s, expected %s", request.GetStatus(), test.expectedStatus)
+			}
+
+			chat, _ := chatsAdapter.GetById(10)
+			isApproved := test.expectedStatus == ApprovedJoinRequestStatus
+			if isMember := slices.Contains(chat.GetMembers(), 4); isMember != isApproved {
+				t.Errorf("user is member = %v, expected %v", isMember, isApproved)
+			}
+			if isApproved && !slices.Equal(systemMessagesAdapter.sentEvents, []ChatSystemEvents{JoinRequestApprovedSystemEvent}) {
+				t.Errorf("system events = %v, expected %v", systemMessagesAdapter.sentEvents, JoinRequestApprovedSystemEvent)
+			}
+		})
+	}
+}
+
+func TestRequestChatJoinHandler(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name                string
+		userId              int
+		linkWithoutApproval bool
+		expectedErr         error
+		expectedRequestId   int
+	}{
+		{name: "creates a pending request", userId: 4, expectedRequestId: 2},
+		{name: "link without approval is used to join directly", userId: 4, linkWithoutApproval: true, expectedErr: ErrInviteLinkNoApproval},
+		{name: "returns the pending request", userId: 5, expectedRequestId: 1},
+		{name: "already a member", userId: 2, expectedErr: ErrAlreadyChatMember},
+		{name: "banned user", userId: 6, expectedErr: ErrUserBanned},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chatsAdapter := NewTestChatsAdapter(NewTestGroupChat())
+			chatsAdapter.SaveInviteLink(NewChatInviteLink(0, 10, "code", 1, nil, nil, 0, !test.linkWithoutApproval, nil, now))
+			chatsAdapter.SaveJoinRequest(NewChatJoinRequest(0, 10, 5, nil, PendingJoinRequestStatus, nil, now, nil))
+			chatsAdapter.SaveBan(NewChatBan(0, 10, 6, 1, nil, now))
+			handler := NewRequestChatJoinHandler(chatsAdapter)
+
+			request, err := handler.Execute("code", test.userId)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+			if test.expectedErr != nil {
+				if _, err := chatsAdapter.GetPendingJoinRequest(10, test.userId); err == nil {
+					t.Errorf("unexpected pending request of user %d", test.userId)
+				}
+				return
+			}
+
+			if request.GetId() != test.expectedRequestId || !request.IsPending() {
+				t.Errorf("request id = %d, status = %s, expected pending request %d", request.GetId(), request.GetStatus(), test.expectedRequestId)
+			}
+			if requests := chatsAdapter.GetChatPendingJoinRequests(10); len(requests) != test.expectedRequestId {
+				t.Errorf("pending requests = %d, expected %d", len(requests), test.expectedRequestId)
+			}
+		})
+	}
+}
+
+func TestTransferChatOwnershipHandler(t *testing.T) {
+	tests := []struct {
+		name            string
+		userId          int
+		newOwnerId      int
+		expectedErr     error
+		expectedMembers []int
+		expectedAdmins  []int
+	}{
+		{name: "transfer to member", userId: 1, newOwnerId: 2, expectedMembers: []int{1, 2, 3}, expectedAdmins: []int{1, 3, 2}},
+		{name: "transfer to admin", userId: 1, newOwnerId: 3, expectedMembers: []int{1, 2, 3}, expectedAdmins: []int{1, 3}},
+		{name: "transfer to non-member", userId: 1, newOwnerId: 4, expectedErr: ErrNewOwnerNotMember},
+		{name: "admin can't transfer", userId: 3, newOwnerId: 2, expectedErr: ErrNotChatOwner},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chatsAdapter := NewTestChatsAdapter(NewTestGroupChat())
+			eventsAdapter := &TestChatEventsAdapter{}
+			handler := NewTransferChatOwnershipHandler(chatsAdapter, eventsAdapter)
+
+			_, err := handler.Execute(10, test.userId, test.newOwnerId)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+
+			chat, _ := chatsAdapter.GetById(10)
+			if test.expectedErr != nil {
+				if chat.GetOwnerId() != 1 {
+					t.Errorf("owner = %d, expected 1", chat.GetOwnerId())
+				}
+				return
+			}
+
+			if chat.GetOwnerId() != test.newOwnerId {
+				t.Errorf("owner = %d, expected %d", chat.GetOwnerId(), test.newOwnerId)
+			}
+			if !slices.Equal(chat.GetMembers(), test.expectedMembers) {
+				t.Errorf("members = %v, expected %v", chat.GetMembers(), test.expectedMembers)
+			}
+			if !slices.Equal(chat.GetAdmins(), test.expectedAdmins) {
+				t.Errorf("admins = %v, expected %v", chat.GetAdmins(), test.expectedAdmins)
+			}
+			if !ValidateUserChatRight(*chat, test.userId, ManageAdminsRight) {
+				t.Errorf("previous owner lost the admin rights")
+			}
+			if !slices.Equal(eventsAdapter.sentEvents, []string{"chat_changed"}) {
+				t.Errorf("sent events = %v, expected chat_changed", eventsAdapter.sentEvents)
+			}
+		})
+	}
+}
+
+func TestQuitChatHandler(t *testing.T) {
+	tests := []struct {
+		name            string
+		chat            Chat
+		userId          int
+		sendErr         error
+		expectedErr     error
+		expectedOwnerId int
+		expectedMembers []int
+	}{
+		{name: "member quits", chat: NewTestGroupChat(), userId: 2, expectedOwnerId: 1, expectedMembers: []int{1, 3}},
+		{name: "owner quits and admin takes over", chat: NewTestGroupChat(), userId: 1, expectedOwnerId: 3, expectedMembers: []int{2, 3}},
+		{
+			name:            "owner without admins quits and member takes over",
+			chat:            NewChat(10, nil, "group chat", GroupChatType, []int{1, 2, 3}, false, 1, []int{1}),
+			userId:          1,
+			expectedOwnerId: 2,
+			expectedMembers: []int{2, 3},
+		},
+		{
+			name:        "event sending fails",
+			chat:        NewTestGroupChat(),
+			userId:      2,
+			sendErr:     fmt.Errorf("broker is down"),
+			expectedErr: ErrSendingEvent,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chatsAdapter := NewTestChatsAdapter(test.chat)
+			eventsAdapter := &TestChatEventsAdapter{sendErr: test.sendErr}
+			handler := NewQuitChatHandler(chatsAdapter, eventsAdapter)
+
+			_, err := handler.Execute(10, test.userId)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+			if test.expectedErr != nil {
+				return
+			}
+
+			chat, _ := chatsAdapter.GetById(10)
+			if chat.GetOwnerId() != test.expectedOwnerId {
+				t.Errorf("owner = %d, expected %d", chat.GetOwnerId(), test.expectedOwnerId)
+			}
+			if !slices.Equal(chat.GetMembers(), test.expectedMembers) {
+				t.Errorf("members = %v, expected %v", chat.GetMembers(), test.expectedMembers)
+			}
+			if slices.Contains(chat.GetAdmins(), test.userId) {
+				t.Errorf("admins = %v, user %d should not be an admin after quitting", chat.GetAdmins(), test.userId)
+			}
+			expectedEvents := []string{"chat_changed", fmt.Sprintf("chat_members_removed:[%d]", test.userId)}
+			if !slices.Equal(eventsAdapter.sentEvents, expectedEvents) {
+				t.Errorf("sent events = %v, expected %v", eventsAdapter.sentEvents, expectedEvents)
+			}
+		})
+	}
+}
+
+func TestSetChatArchivedHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		userId      int
+		wasArchived bool
+		archived    bool
+		expectedErr error
+	}{
+		{name: "archive", userId: 2, archived: true},
+		{name: "unarchive", userId: 2, wasArchived: true, archived: false},
+		{name: "not a chat member", userId: 4, archived: true, expectedErr: ErrChatNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chatsAdapter := NewTestChatsAdapter(NewTestGroupChat())
+			chatsAdapter.SaveUserSettings(NewChatUserSettings(10, test.userId, test.wasArchived, false, nil, false, nil))
+			eventsAdapter := &TestChatEventsAdapter{}
+			handler := NewSetChatArchivedHandler(chatsAdapter, eventsAdapter)
+
+			chat, err := handler.Execute(10, test.userId, test.archived)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+			if test.expectedErr != nil {
+				return
+			}
+
+			if chat.GetIsArchived() != test.archived {
+				t.Errorf("chat is archived = %v, expected %v", chat.GetIsArchived(), test.archived)
+			}
+			settings := chatsAdapter.GetUserSettings([]int{10}, test.userId)[10]
+			if settings.GetIsArchived() != test.archived {
+				t.Errorf("stored archive state = %v, expected %v", settings.GetIsArchived(), test.archived)
+			}
+			if _, ok := chatsAdapter.GetUserSettings([]int{10}, 1)[10]; ok {
+				t.Errorf("settings of another member were changed")
+			}
+			expectedEvents := []string{fmt.Sprintf("chat_archive_changed:%d", test.userId)}
+			if !slices.Equal(eventsAdapter.sentEvents, expectedEvents) {
+				t.Errorf("sent events = %v, expected %v", eventsAdapter.sentEvents, expectedEvents)
+			}
+		})
+	}
+}
+
+func TestGetChatsHandlerArchived(t *testing.T) {
+	chatsAdapter := NewTestChatsAdapter(NewTestGroupChat())
+	chatsAdapter.SaveUserSettings(NewChatUserSettings(10, 1, true, false, nil, false, nil))
+	chatsAdapter.SaveUserSettings(NewChatUserSettings(2, 2, true, false, nil, false, nil))
+	getChatsHandler := NewGetChatsHandler(chatsAdapter, &TestUsersAdapter{}, &TestUserActionsAdapter{})
+	page, _ := getChatsHandler.Execute(1, false, nil, 1, 20)
+	if chatIds := getChatIds(page.GetData()); !slices.Equal(chatIds, []int{1, 2}) {
+		t.Errorf("chats = %v, expected [1 2] without the archived one", chatIds)
+	}
+	page, _ = getChatsHandler.Execute(1, true, nil, 1, 20)
+	if chatIds := getChatIds(page.GetData()); !slices.Equal(chatIds, []int{1, 2, 10}) {
+		t.Errorf("chats including archived = %v, expected [1 2 10]", chatIds)
+	}
+
+	getArchivedHandler := NewGetArchivedChatsHandler(chatsAdapter, &TestUsersAdapter{}, &TestUserActionsAdapter{})
+	archivedPage := getArchivedHandler.Execute(1, 1, 20)
+	archivedChats := archivedPage.GetData()
+	if chatIds := getChatIds(archivedChats); !slices.Equal(chatIds, []int{10}) {
+		t.Fatalf("archived chats = %v, expected [10]", chatIds)
+	}
+	if !archivedChats[0].GetIsArchived() {
+		t.Errorf("archived chat is returned without its archive state")
+	}
+}
+
+func TestCreateChatFolderHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        ChatFolderData
+		expectedErr error
+	}{
+		{name: "chats only", data: NewChatFolderData("work", []int{1, 2}, nil, false, false)},
+		{name: "rules only", data: NewChatFolderData("groups", nil, []ChatTypes{GroupChatType}, true, false)},
+		{name: "empty title", data: NewChatFolderData("  ", []int{1}, nil, false, false), expectedErr: ErrIncorrectFolderTitle},
+		{name: "too long title", data: NewChatFolderData(strings.Repeat("a", 65), []int{1}, nil, false, false), expectedErr: ErrIncorrectFolderTitle},
+		{name: "unknown chat type", data: NewChatFolderData("folder", nil, []ChatTypes{"bot"}, false, false), expectedErr: ErrIncorrectFolderChatType},
+		{name: "no chats and no rules", data: NewChatFolderData("folder", nil, nil, false, false), expectedErr: ErrIncorrectFolderRules},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chatsAdapter := NewTestChatsAdapter()
+			chatsAdapter.SaveFolder(NewChatFolder(0, 1, "existing", 0, []int{1}, nil, false, false))
+			eventsAdapter := &TestChatEventsAdapter{}
+			handler := NewCreateChatFolderHandler(chatsAdapter, eventsAdapter)
+
+			folder, err := handler.Execute(1, test.data)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+			if test.expectedErr != nil {
+				if len(chatsAdapter.GetUserFolders(1)) != 1 || len(eventsAdapter.sentEvents) != 0 {
+					t.Errorf("invalid folder was saved or announced")
+				}
+				return
+			}
+
+			if folder.GetPosition() != 1 {
+				t.Errorf("position = %d, expected the folder to go after the existing one", folder.GetPosition())
+			}
+			if folderIds := getFolderIds(chatsAdapter.GetUserFolders(1)); !slices.Equal(folderIds, []int{1, folder.GetId()}) {
+				t.Errorf("folders = %v, expected [1 %d]", folderIds, folder.GetId())
+			}
+			if !slices.Equal(eventsAdapter.sentEvents, []string{"folders_changed:1"}) {
+				t.Errorf("sent events = %v, expected folders_changed:1", eventsAdapter.sentEvents)
+			}
+		})
+	}
+}
+
+func TestChatFolderHandlersOwnership(t *testing.T) {
+	chatsAdapter := NewTestChatsAdapter()
+	chatsAdapter.SaveFolder(NewChatFolder(0, 1, "work", 0, []int{1}, nil, false, false))
+	eventsAdapter := &TestChatEventsAdapter{}
+	data := NewChatFolderData("renamed", []int{2}, nil, false, false)
+
+	updateHandler := NewUpdateChatFolderHandler(chatsAdapter, eventsAdapter)
+	if _, err := updateHandler.Execute(1, 2, data); !errors.Is(err, ErrChatFolderNotFound) {
+		t.Errorf("updating another user's folder error = %v, expected %v", err, ErrChatFolderNotFound)
+	}
+	deleteHandler := NewDeleteChatFolderHandler(chatsAdapter, eventsAdapter)
+	if err := deleteHandler.Execute(1, 2); !errors.Is(err, ErrChatFolderNotFound) {
+		t.Errorf("deleting another user's folder error = %v, expected %v", err, ErrChatFolderNotFound)
+	}
+	getChatsHandler := NewGetChatsHandler(chatsAdapter, &TestUsersAdapter{}, &TestUserActionsAdapter{})
+	if _, err := getChatsHandler.Execute(2, false, intPtr(1), 1, 20); !errors.Is(err, ErrChatFolderNotFound) {
+		t.Errorf("listing another user's folder error = %v, expected %v", err, ErrChatFolderNotFound)
+	}
+	if len(eventsAdapter.sentEvents) != 0 {
+		t.Errorf("sent events = %v, expected none", eventsAdapter.sentEvents)
+	}
+
+	folder, err := updateHandler.Execute(1, 1, data)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if folder.GetTitle() != "renamed" || !slices.Equal(folder.GetChatIds(), []int{2}) {
+		t.Errorf("folder = %q %v, expected renamed [2]", folder.GetTitle(), folder.GetChatIds())
+	}
+	if err := deleteHandler.Execute(1, 1); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if folders := chatsAdapter.GetUserFolders(1); len(folders) != 0 {
+		t.Errorf("folders = %v, expected the folder to be deleted", getFolderIds(folders))
+	}
+}
+
+func TestReorderChatFoldersHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		folderIds   []int
+		expectedErr error
+		expectedIds []int
+	}{
+		{name: "reorder", folderIds: []int{3, 1, 2}, expectedIds: []int{3, 1, 2}},
+		{name: "missing folder", folderIds: []int{3, 1}, expectedErr: ErrIncorrectFoldersOrder},
+		{name: "repeated folder", folderIds: []int{3, 1, 1}, expectedErr: ErrIncorrectFoldersOrder},
+		{name: "another user's folder", folderIds: []int{3, 1, 4}, expectedErr: ErrIncorrectFoldersOrder},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chatsAdapter := NewTestChatsAdapter()
+			for position, userId := range []int{1, 1, 1, 2} {
+				chatsAdapter.SaveFolder(NewChatFolder(0, userId, "folder", position, []int{1}, nil, false, false))
+			}
+			handler := NewReorderChatFoldersHandler(chatsAdapter, &TestChatEventsAdapter{})
+
+			folders, err := handler.Execute(1, test.folderIds)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+			if test.expectedErr != nil {
+				folders = chatsAdapter.GetUserFolders(1)
+				test.expectedIds = []int{1, 2, 3}
+			}
+
+			if folderIds := getFolderIds(folders); !slices.Equal(folderIds, test.expectedIds) {
+				t.Errorf("folders = %v, expected %v", folderIds, test.expectedIds)
+			}
+		})
+	}
+}
+
+func TestGetChatsHandlerFolder(t *testing.T) {
+	tests := []struct {
+		name            string
+		folder          ChatFolder
+		expectedChatIds []int
+	}{
+		{name: "chats only", folder: NewChatFolder(0, 1, "folder", 0, []int{1, 10}, nil, false, false), expectedChatIds: []int{1, 10}},
+		{name: "chat types", folder: NewChatFolder(0, 1, "folder", 0, nil, []ChatTypes{GroupChatType}, false, false), expectedChatIds: []int{2, 10}},
+		{name: "chats or rules", folder: NewChatFolder(0, 1, "folder", 0, []int{1}, []ChatTypes{GroupChatType}, true, false), expectedChatIds: []int{1, 10}},
+		{name: "muted only", folder: NewChatFolder(0, 1, "folder", 0, nil, nil, false, true), expectedChatIds: []int{2}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chatsAdapter := NewTestChatsAdapter(NewTestGroupChat())
+			chatsAdapter.unreadCounters = map[int]map[int]ChatUnreadCounters{1: {10: NewChatUnreadCounters(1, 0)}}
+			mutedSettings := NewChatUserSettings(2, 1, false, false, nil, false, nil)
+			mutedSettings.Mute(nil)
+			chatsAdapter.SaveUserSettings(mutedSettings)
+			folder, _ := chatsAdapter.SaveFolder(test.folder)
+			handler := NewGetChatsHandler(chatsAdapter, &TestUsersAdapter{}, &TestUserActionsAdapter{})
+
+			page, err := handler.Execute(1, false, intPtr(folder.GetId()), 1, 20)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if chatIds := getChatIds(page.GetData()); !slices.Equal(chatIds, test.expectedChatIds) {
+				t.Errorf("chats = %v, expected %v", chatIds, test.expectedChatIds)
+			}
+		})
+	}
+}
+
+func newTestChannel(slug string) Chat {
+	channel := NewChat(10, nil, "channel", ChannelChatType, []int{1, 2}, false, 1, []int{1})
+	channel.SetSlug(&slug)
+	return channel
+}
+
+func TestCreateChannelHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		slug        *string
+		expectedErr error
+	}{
+		{name: "without slug"},
+		{name: "with slug", slug: stringPtr("news_channel")},
+		{name: "too short slug", slug: stringPtr("news"), expectedErr: ErrIncorrectChatSlug},
+		{name: "slug starting with a digit", slug: stringPtr("1news_channel"), expectedErr: ErrIncorrectChatSlug},
+		{name: "taken slug", slug: stringPtr("taken_slug"), expectedErr: ErrChatSlugTaken},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chatsAdapter := NewTestChatsAdapter(newTestChannel("taken_slug"))
+			eventsAdapter := &TestChatEventsAdapter{}
+			handler := NewCreateChatHandler(chatsAdapter, eventsAdapter, &TestUsersAdapter{}, nil)
+
+			data := NewCreateChatData(ChannelChatType, nil, stringPtr("news"), []int{2}, nil, test.slug)
+			chat, err := handler.Execute(data, 1)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+			if test.expectedErr != nil {
+				if len(eventsAdapter.sentEvents) != 0 {
+					t.Errorf("sent events = %v, expected none", eventsAdapter.sentEvents)
+				}
+				return
+			}
+
+			if chat.GetType() != ChannelChatType || chat.GetOwnerId() != 1 {
+				t.Errorf("chat type = %s, owner = %d, expected a channel owned by 1", chat.GetType(), chat.GetOwnerId())
+			}
+			if !slices.Equal(chat.GetMembers(), []int{2, 1}) {
+				t.Errorf("members = %v, expected [2 1]", chat.GetMembers())
+			}
+			if fmt.Sprint(chat.GetSlug()) != fmt.Sprint(test.slug) {
+				t.Errorf("slug = %v, expected %v", chat.GetSlug(), test.slug)
+			}
+		})
+	}
+}
+
+func TestSubscribeChannelHandler(t *testing.T) {
+	tests := []struct {
+		name            string
+		chat            Chat
+		slug            string
+		userId          int
+		expectedErr     error
+		expectedMembers []int
+	}{
+		{name: "subscribe", chat: newTestChannel("news_channel"), slug: "news_channel", userId: 3, expectedMembers: []int{1, 2, 3}},
+		{name: "already subscribed", chat: newTestChannel("news_channel"), slug: "news_channel", userId: 2, expectedErr: ErrAlreadyChatMember},
+		{name: "unknown slug", chat: newTestChannel("news_channel"), slug: "other_channel", userId: 3, expectedErr: ErrChannelNotFound},
+		{name: "group chat slug", chat: func() Chat {
+			chat := NewTestGroupChat()
+			chat.SetSlug(stringPtr("group_chat"))
+			return chat
+		}(), slug: "group_chat", userId: 4, expectedErr: ErrChannelNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chatsAdapter := NewTestChatsAdapter(test.chat)
+			eventsAdapter := &TestChatEventsAdapter{}
+			handler := NewSubscribeChannelHandler(chatsAdapter, &TestUsersAdapter{}, eventsAdapter)
+
+			chat, err := handler.Execute(test.slug, test.userId)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+			if test.expectedErr != nil {
+				return
+			}
+
+			if !slices.Equal(chat.GetMembers(), test.expectedMembers) {
+				t.Errorf("members = %v, expected %v", chat.GetMembers(), test.expectedMembers)
+			}
+			if chat.GetSubscribersCount() != len(test.expectedMembers) {
+				t.Errorf("subscribers count = %d, expected %d", chat.GetSubscribersCount(), len(test.expectedMembers))
+			}
+			if !slices.Equal(eventsAdapter.sentEvents, []string{"chat_changed"}) {
+				t.Errorf("sent events = %v, expected chat_changed", eventsAdapter.sentEvents)
+			}
+		})
+	}
+}
+
+func TestValidateUserCanSendMessagesInChannel(t *testing.T) {
+	channel := newTestChannel("news_channel")
+	channel.SetMemberPermissions(NewMemberPermissions(true, true, true, true))
+	if !ValidateUserCanSendMessages(channel, 1, true) {
+		t.Errorf("channel admin can't post")
+	}
+	if ValidateUserCanSendMessages(channel, 2, false) {
+		t.Errorf("subscriber can post regardless of member permissions")
+	}
+}
+
+// newTestBanChat returns a group chat owned by user 1 where users 3 and 4 are
+// admins that can remove members.
+func newTestBanChat() Chat {
+	chat := NewChat(10, nil, "group chat", GroupChatType, []int{1, 2, 3, 4}, false, 1, []int{1, 3, 4})
+	chat.SetAdminRights(3, NewAdminRights(false, true, false, false, false, false))
+	chat.SetAdminRights(4, NewAdminRights(false, true, false, false, false, true))
+	return chat
+}
+
+func TestBanChatMemberHandler(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+	tests := []struct {
+		name            string
+		userId          int
+		memberId        int
+		expiresAt       *time.Time
+		expectedErr     error
+		expectedMembers []int
+		expectedAdmins  []int
+	}{
+		{name: "ban member", userId: 1, memberId: 2, expectedMembers: []int{1, 3, 4}, expectedAdmins: []int{1, 3, 4}},
+		{name: "ban admin", userId: 4, memberId: 3, expiresAt: &future, expectedMembers: []int{1, 2, 4}, expectedAdmins: []int{1, 4}},
+		{name: "expiration in the past", userId: 1, memberId: 2, expiresAt: &past, expectedErr: ErrIncorrectBanExpiresAt},
+		{name: "admin bans owner", userId: 4, memberId: 1, expectedErr: ErrCantBanOwner},
+		{name: "admin without manage admins right bans admin", userId: 3, memberId: 4, expectedErr: ErrNotEnoughRights},
+		{name: "admin bans self", userId: 4, memberId: 4, expectedErr: ErrCantBanYourself},
+		{name: "member can't ban", userId: 2, memberId: 3, expectedErr: ErrNotEnoughRights},
+		{name: "not a chat member", userId: 5, memberId: 2, expectedErr: ErrChatNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chatsAdapter := NewTestChatsAdapter(newTestBanChat())
+			eventsAdapter := &TestChatEventsAdapter{}
+			handler := NewBanChatMemberHandler(chatsAdapter, eventsAdapter)
+
+			_, err := handler.Execute(10, test.userId, test.memberId, test.expiresAt)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+
+			isBanned := slices.ContainsFunc(chatsAdapter.GetChatBans(10), func(ban ChatBan) bool {
+				return ban.GetUserId() == test.memberId
+			})
+			if isBanned != (test.expectedErr == nil) {
+				t.Errorf("member is banned = %v, expected %v", isBanned, test.expectedErr == nil)
+			}
+			if test.expectedErr != nil {
+				return
+			}
+
+			chat, _ := chatsAdapter.GetById(10)
+			if !slices.Equal(chat.GetMembers(), test.expectedMembers) {
+				t.Errorf("members = %v, expected %v", chat.GetMembers(), test.expectedMembers)
+			}
+			if !slices.Equal(chat.GetAdmins(), test.expectedAdmins) {
+				t.Errorf("admins = %v, expected %v", chat.GetAdmins(), test.expectedAdmins)
+			}
+			expectedEvents := []string{"chat_changed", fmt.Sprintf("chat_members_removed:[%d]", test.memberId)}
+			if !slices.Equal(eventsAdapter.sentEvents, expectedEvents) {
+				t.Errorf("sent events = %v, expected %v", eventsAdapter.sentEvents, expectedEvents)
+			}
+		})
+	}
+}
+
+func TestRemoveChatMembersHandler(t *testing.T) {
+	tests := []struct {
+		name            string
+		members         []int
+		expectedErr     error
+		expectedMembers []int
+		expectedEvents  []string
+	}{
+		{name: "remove member", members: []int{2}, expectedMembers: []int{1, 3}, expectedEvents: []string{"chat_changed", "chat_members_removed:[2]"}},
+		{name: "remove member and non-member", members: []int{2, 5}, expectedMembers: []int{1, 3}, expectedEvents: []string{"chat_changed", "chat_members_removed:[2]"}},
+		{name: "remove only non-members", members: []int{5}, expectedMembers: []int{1, 2, 3}, expectedEvents: []string{"chat_changed"}},
+		{name: "owner can't be removed", members: []int{1}, expectedErr: ErrCantRemoveOwner, expectedMembers: []int{1, 2, 3}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chatsAdapter := NewTestChatsAdapter(NewTestGroupChat())
+			eventsAdapter := &TestChatEventsAdapter{}
+			handler := NewRemoveChatMembersHandler(chatsAdapter, eventsAdapter)
+
+			if _, err := handler.Execute(10, 1, test.members); !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+
+			chat, _ := chatsAdapter.GetById(10)
+			if !slices.Equal(chat.GetMembers(), test.expectedMembers) {
+				t.Errorf("members = %v, expected %v", chat.GetMembers(), test.expectedMembers)
+			}
+			if !slices.Equal(eventsAdapter.sentEvents, test.expectedEvents) {
+				t.Errorf("sent events = %v, expected %v", eventsAdapter.sentEvents, test.expectedEvents)
+			}
+		})
+	}
+}
+
+func TestUnbanChatMemberHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		chatId      int
+		userId      int
+		memberId    int
+		expectedErr error
+	}{
+		{name: "owner unbans", chatId: 10, userId: 1, memberId: 5},
+		{name: "admin unbans", chatId: 10, userId: 3, memberId: 5},
+		{name: "member can't unban", chatId: 10, userId: 2, memberId: 5, expectedErr: ErrNotEnoughRights},
+		{name: "user is not banned", chatId: 10, userId: 1, memberId: 2, expectedErr: ErrUserNotBanned},
+		{name: "not a group chat", chatId: 1, userId: 1, memberId: 2, expectedErr: ErrChatNotGroup},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chatsAdapter := NewTestChatsAdapter(newTestBanChat())
+			chatsAdapter.SaveBan(NewChatBan(0, 10, 5, 1, nil, time.Now()))
+			handler := NewUnbanChatMemberHandler(chatsAdapter)
+
+			err := handler.Execute(test.chatId, test.userId, test.memberId)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+
+			expectedBansCount := 1
+			if test.expectedErr == nil {
+				expectedBansCount = 0
+			}
+			if bansCount := len(chatsAdapter.GetChatBans(10)); bansCount != expectedBansCount {
+				t.Errorf("bans count = %d, expected %d", bansCount, expectedBansCount)
+			}
+		})
+	}
+}
+
+func TestAddChatMembersHandlerBannedUser(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	chatsAdapter := NewTestChatsAdapter(NewTestGroupChat())
+	chatsAdapter.SaveBan(NewChatBan(0, 10, 4, 1, nil, past))
+	handler := NewAddChatsMembersHandler(chatsAdapter, &TestUsersAdapter{}, &TestChatEventsAdapter{})
+
+	if _, err := handler.Execute(10, 1, []int{4}); !errors.Is(err, ErrUserBanned) {
+		t.Fatalf("adding a banned user error = %v, expected %v", err, ErrUserBanned)
+	}
+
+	chatsAdapter.SaveBan(NewChatBan(0, 10, 4, 1, &past, past.Add(-time.Hour)))
+	chat, err := handler.Execute(10, 1, []int{4})
+	if err != nil {
+		t.Fatalf("adding a user with an expired ban error = %v", err)
+	}
+	if !slices.Contains(chat.GetMembers(), 4) {
+		t.Errorf("members = %v, expected the user with an expired ban to be added", chat.GetMembers())
+	}
+}
+
+func TestSetSlowModeHandler(t *testing.T) {
+	tests := []struct {
+		name             string
+		chatId           int
+		userId           int
+		interval         time.Duration
+		expectedErr      error
+		expectedInterval time.Duration
+	}{
+		{name: "owner enables slow mode", chatId: 10, userId: 1, interval: 30 * time.Second, expectedInterval: 30 * time.Second},
+		{name: "owner disables slow mode", chatId: 10, userId: 1, interval: 0},
+		{name: "interval above an hour", chatId: 10, userId: 1, interval: time.Hour + time.Second, expectedErr: ErrIncorrectSlowMode, expectedInterval: time.Minute},
+		{name: "negative interval", chatId: 10, userId: 1, interval: -time.Second, expectedErr: ErrIncorrectSlowMode, expectedInterval: time.Minute},
+		{name: "member without edit info right", chatId: 10, userId: 2, interval: 0, expectedErr: ErrNotEnoughRights, expectedInterval: time.Minute},
+		{name: "user chat", chatId: 1, userId: 1, interval: time.Minute, expectedErr: ErrChatNotGroup},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			groupChat := NewTestGroupChat()
+			groupChat.SetSlowModeInterval(time.Minute)
+			chatsAdapter := NewTestChatsAdapter(groupChat)
+			eventsAdapter := &TestChatEventsAdapter{}
+			handler := NewSetSlowModeHandler(chatsAdapter, eventsAdapter)
+
+			_, err := handler.Execute(test.chatId, test.userId, test.interval)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+
+			chat, _ := chatsAdapter.GetById(test.chatId)
+			if chat.GetSlowModeInterval() != test.expectedInterval {
+				t.Errorf("slow mode interval = %s, expected %s", chat.GetSlowModeInterval(), test.expectedInterval)
+			}
+			if sent := len(eventsAdapter.sentEvents) > 0; sent != (test.expectedErr == nil) {
+				t.Errorf("sent events = %v, expected an event only on success", eventsAdapter.sentEvents)
+			}
+		})
+	}
+}
+
+func TestChangeGroupChatHandler(t *testing.T) {
+	longDescription := strings.Repeat("a", 256)
+	tests := []struct {
+		name                string
+		userId              int
+		data                ChangeGroupChatData
+		expectedErr         error
+		expectedTitle       string
+		expectedDescription *string
+		expectedRules       *string
+		expectedSlug        *string
+		expectedEvents      []ChatSystemEvents
+		expectedValues      []*string
+	}{
+		{
+			name:           "title only",
+			userId:         1,
+			data:           NewChangeGroupChatData(stringPtr(" new title "), nil, nil, nil),
+			expectedTitle:  "new title",
+			expectedRules:  stringPtr("be nice"),
+			expectedEvents: []ChatSystemEvents{TitleChangedSystemEvent},
+			expectedValues: []*string{stringPtr("new title")},
+		},
+		{
+			name:                "description and slug",
+			userId:              1,
+			data:                NewChangeGroupChatData(nil, stringPtr("about"), nil, stringPtr("group_slug")),
+			expectedTitle:       "group chat",
+			expectedDescription: stringPtr("about"),
+			expectedRules:       stringPtr("be nice"),
+			expectedSlug:        stringPtr("group_slug"),
+			expectedEvents:      []ChatSystemEvents{DescriptionChangedSystemEvent, SlugChangedSystemEvent},
+			expectedValues:      []*string{stringPtr("about"), stringPtr("group_slug")},
+		},
+		{
+			name:           "blank rules clear them",
+			userId:         1,
+			data:           NewChangeGroupChatData(nil, nil, stringPtr("  "), nil),
+			expectedTitle:  "group chat",
+			expectedEvents: []ChatSystemEvents{RulesChangedSystemEvent},
+			expectedValues: []*string{nil},
+		},
+		{
+			name:          "unchanged values",
+			userId:        1,
+			data:          NewChangeGroupChatData(stringPtr("group chat"), nil, stringPtr("be nice"), nil),
+			expectedTitle: "group chat",
+			expectedRules: stringPtr("be nice"),
+		},
+		{
+			name:          "blank title",
+			userId:        1,
+			data:          NewChangeGroupChatData(stringPtr(" "), nil, nil, nil),
+			expectedErr:   ErrIncorrectChatTitle,
+			expectedTitle: "group chat",
+			expectedRules: stringPtr("be nice"),
+		},
+		{
+			name:          "too long description",
+			userId:        1,
+			data:          NewChangeGroupChatData(nil, &longDescription, nil, nil),
+			expectedErr:   ErrIncorrectDescription,
+			expectedTitle: "group chat",
+			expectedRules: stringPtr("be nice"),
+		},
+		{
+			name:          "taken slug",
+			userId:        1,
+			data:          NewChangeGroupChatData(stringPtr("new title"), nil, nil, stringPtr("taken_slug")),
+			expectedErr:   ErrChatSlugTaken,
+			expectedTitle: "group chat",
+			expectedRules: stringPtr("be nice"),
+		},
+		{
+			name:          "member without edit info right",
+			userId:        2,
+			data:          NewChangeGroupChatData(stringPtr("new title"), nil, nil, nil),
+			expectedErr:   ErrNotEnoughRights,
+			expectedTitle: "group chat",
+			expectedRules: stringPtr("be nice"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			groupChat := NewTestGroupChat()
+			groupChat.SetRules(stringPtr("be nice"))
+			takenSlugChat := NewChat(11, nil, "channel", ChannelChatType, []int{2}, false, 2, []int{2})
+			takenSlugChat.SetSlug(stringPtr("taken_slug"))
+			chatsAdapter := NewTestChatsAdapter(groupChat, takenSlugChat)
+			eventsAdapter := &TestChatEventsAdapter{}
+			systemMessagesAdapter := &TestChatSystemMessagesAdapter{}
+			handler := NewChangeGroupChatHandler(chatsAdapter, eventsAdapter, systemMessagesAdapter)
+
+			_, err := handler.Execute(10, test.userId, test.data)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+
+			chat, _ := chatsAdapter.GetById(10)
+			if chat.GetTitle() != test.expectedTitle {
+				t.Errorf("title = %q, expected %q", chat.GetTitle(), test.expectedTitle)
+			}
+			for field, values := range map[string][2]*string{
+				"description": {chat.GetDescription(), test.expectedDescription},
+				"rules":       {chat.GetRules(), test.expectedRules},
+				"slug":        {chat.GetSlug(), test.expectedSlug},
+			} {
+				if isOptionalTextChanged(values[0], values[1]) {
+					t.Errorf("%s = %s, expected %s", field, formatOptionalText(values[0]), formatOptionalText(values[1]))
+				}
+			}
+			if !slices.Equal(systemMessagesAdapter.sentEvents, test.expectedEvents) {
+				t.Errorf("system events = %v, expected %v", systemMessagesAdapter.sentEvents, test.expectedEvents)
+			}
+			if !slices.EqualFunc(systemMessagesAdapter.sentValues, test.expectedValues, func(value *string, expected *string) bool {
+				return !isOptionalTextChanged(value, expected)
+			}) {
+				t.Errorf("system event values differ from %d expected ones", len(test.expectedValues))
+			}
+			if changed := len(eventsAdapter.sentEvents) > 0; changed != (len(test.expectedEvents) > 0) {
+				t.Errorf("sent events = %v, expected chat_changed only when the profile changed", eventsAdapter.sentEvents)
+			}
+		})
+	}
+}
+
+func TestUpdateUserDataHandler(t *testing.T) {
+	tests := []struct {
+		name                   string
+		userId                 int
+		expectedErr            error
+		expectedEvents         []string
+		expectedRefreshedChats []int
+	}{
+		{
+			name:                   "user chat is resent to the other member",
+			userId:                 2,
+			expectedEvents:         []string{"chat_changed:1"},
+			expectedRefreshedChats: []int{1, 2, 10},
+		},
+		{
+			name:                   "user without user chats",
+			userId:                 3,
+			expectedRefreshedChats: []int{10},
+		},
+		{
+			name:        "unknown user",
+			userId:      5,
+			expectedErr: ErrFindingUser,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chatsAdapter := NewTestChatsAdapter(NewTestGroupChat())
+			eventsAdapter := &TestChatEventsAdapter{}
+			userActionsAdapter := &TestUserActionsAdapter{}
+			handler := NewUpdateUserDataHandler(chatsAdapter, eventsAdapter, &TestUsersAdapter{}, userActionsAdapter)
+
+			err := handler.Execute(test.userId)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+			if !slices.Equal(eventsAdapter.sentEvents, test.expectedEvents) {
+				t.Errorf("sent events = %v, expected %v", eventsAdapter.sentEvents, test.expectedEvents)
+			}
+			if !slices.Equal(userActionsAdapter.refreshedChats, test.expectedRefreshedChats) {
+				t.Errorf("refreshed chats = %v, expected %v", userActionsAdapter.refreshedChats, test.expectedRefreshedChats)
+			}
+		})
+	}
+}
+
+func TestDeleteUserDataHandler(t *testing.T) {
+	savedMessagesChat := NewChat(11, nil, "Saved messages", SavedMessagesChatType, []int{1}, false, 1, []int{})
+	chatsAdapter := NewTestChatsAdapter(NewTestGroupChat(), savedMessagesChat)
+	eventsAdapter := &TestChatEventsAdapter{}
+	userActionsAdapter := &TestUserActionsAdapter{}
+	handler := NewDeleteUserDataHandler(chatsAdapter, eventsAdapter, userActionsAdapter)
+
+	if err := handler.Execute(1); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	userChat, _ := chatsAdapter.GetById(1)
+	if !userChat.GetIsUserDeleted() || !slices.Equal(userChat.GetMembers(), []int{1, 2}) {
+		t.Errorf("user chat members = %v, deleted user = %v, expected a kept chat marked as deleted", userChat.GetMembers(), userChat.GetIsUserDeleted())
+	}
+	for chatId, expectedOwnerId := range map[int]int{2: 2, 10: 3} {
+		chat, _ := chatsAdapter.GetById(chatId)
+		if slices.Contains(chat.GetMembers(), 1) || chat.GetOwnerId() != expectedOwnerId {
+			t.Errorf("chat %d members = %v, owner = %d, expected the user to leave and %d to own it", chatId, chat.GetMembers(), chat.GetOwnerId(), expectedOwnerId)
+		}
+	}
+	if _, err := chatsAdapter.GetById(11); err == nil {
+		t.Errorf("saved messages chat wasn't deleted")
+	}
+	if !slices.Equal(userActionsAdapter.cleanedChats, []int{1, 2, 10, 11}) {
+		t.Errorf("chats cleaned from actions = %v, expected [1 2 10 11]", userActionsAdapter.cleanedChats)
+	}
+	if !slices.Equal(eventsAdapter.sentEvents, []string{"chat_changed", "chat_changed", "chat_changed"}) {
+		t.Errorf("sent events = %v, expected chat_changed for every kept chat", eventsAdapter.sentEvents)
+	}
+
+	eventsAdapter.sentEvents = nil
+	if err := handler.Execute(1); err != nil {
+		t.Fatalf("repeated Execute() error = %v", err)
+	}
+	if len(eventsAdapter.sentEvents) != 0 {
+		t.Errorf("repeated sent events = %v, expected none", eventsAdapter.sentEvents)
+	}
+}
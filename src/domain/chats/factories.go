@@ -22,7 +22,7 @@ func CreateChatDataToChat(data CreateChatData, currentUserId int) Chat {
 		data.membersIds = []int{currentUserId, *data.userId}
 	}
 
-	return NewChat(
+	chat := NewChat(
 		0,
 		avatar,
 		title,
@@ -32,4 +32,6 @@ func CreateChatDataToChat(data CreateChatData, currentUserId int) Chat {
 		0,
 		[]int{},
 	)
+	chat.SetSlug(data.GetSlug())
+	return chat
 }
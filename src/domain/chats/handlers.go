@@ -1,10 +1,14 @@
 package chats
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"regexp"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/chack-check/chats-service/domain/files"
 	"github.com/chack-check/chats-service/domain/users"
@@ -13,18 +17,66 @@ import (
 
 const SAVED_MESSAGES_CHAT_AVATAR_URL string = "https://storage.yandexcloud.net/diffaction/saved-messages-logo.svg"
 
+const inviteLinkCodeBytes = 12
+
+const maxSlowModeInterval = time.Hour
+
+const (
+	maxChatDescriptionLength = 255
+	maxChatRulesLength       = 4096
+)
+
 var (
 	ErrFindingUser             = fmt.Errorf("error finding user")
 	ErrCreatingNotUserChat     = fmt.Errorf("trying to create user chat with not specified user id")
 	ErrSavingChat              = fmt.Errorf("error saving chat")
+	ErrSendingEvent            = fmt.Errorf("error sending event")
 	ErrRestoringChat           = fmt.Errorf("error restoring chat")
 	ErrChatAlreadyExists       = fmt.Errorf("you already have chat with this user")
 	ErrChatNotFound            = fmt.Errorf("there is no such chat")
+	ErrFindingChat             = fmt.Errorf("error finding chat")
 	ErrNotGroupAdmin           = fmt.Errorf("you are not a group chat admin")
 	ErrChatNotGroup            = fmt.Errorf("the editing chat is not group")
-	ErrInvalidCreatingChatType = fmt.Errorf("invalid creating chat type. Valid values: group, user, saved_messages")
+	ErrInvalidCreatingChatType = fmt.Errorf("invalid creating chat type. Valid values: group, user, channel, saved_messages")
+	ErrIncorrectChatSlug       = fmt.Errorf("slug must start with a letter and contain 5-32 letters, digits or underscores")
+	ErrChatSlugTaken           = fmt.Errorf("this slug is already taken")
+	ErrChannelNotFound         = fmt.Errorf("there is no such public channel")
 	ErrChatNotAdmin            = fmt.Errorf("user is not admin in chat")
 	ErrChatWithSelf            = fmt.Errorf("you can't create chat with self user")
+	ErrNotEnoughRights         = fmt.Errorf("you don't have enough rights in this chat")
+	ErrCantChangeOwnerRights   = fmt.Errorf("you can't change rights of the chat owner")
+	ErrCantRemoveOwner         = fmt.Errorf("you can't remove the chat owner")
+	ErrInviteLinkNotFound      = fmt.Errorf("there is no such invite link")
+	ErrInviteLinkExpired       = fmt.Errorf("invite link is expired or revoked")
+	ErrInviteLinkNeedsApproval = fmt.Errorf("invite link requires admin approval, send a join request instead")
+	ErrInviteLinkNoApproval    = fmt.Errorf("invite link doesn't require admin approval, join the chat with it instead")
+	ErrIncorrectLinkExpiresAt  = fmt.Errorf("invite link expiration time must be in the future")
+	ErrIncorrectLinkUsageLimit = fmt.Errorf("invite link usage limit must be positive")
+	ErrSavingInviteLink        = fmt.Errorf("error saving invite link")
+	ErrAlreadyChatMember       = fmt.Errorf("you are already a member of this chat")
+	ErrIncorrectMutedUntil     = fmt.Errorf("mute end time must be in the future")
+	ErrSavingChatSettings      = fmt.Errorf("error saving chat settings")
+	ErrChatFolderNotFound      = fmt.Errorf("there is no such chat folder")
+	ErrIncorrectFolderTitle    = fmt.Errorf("chat folder title must be between 1 and 64 characters")
+	ErrIncorrectFolderRules    = fmt.Errorf("chat folder must contain chats or rules")
+	ErrIncorrectFolderChatType = fmt.Errorf("invalid chat folder chat type. Valid values: group, user, channel, saved_messages")
+	ErrIncorrectFoldersOrder   = fmt.Errorf("folders order must contain all your folders exactly once")
+	ErrSavingChatFolder        = fmt.Errorf("error saving chat folder")
+	ErrNotChatOwner            = fmt.Errorf("you are not the chat owner")
+	ErrNewOwnerNotMember       = fmt.Errorf("new owner must be a chat member")
+	ErrJoinRequestNotFound     = fmt.Errorf("there is no such join request")
+	ErrJoinRequestResolved     = fmt.Errorf("join request is already resolved")
+	ErrSavingJoinRequest       = fmt.Errorf("error saving join request")
+	ErrUserBanned              = fmt.Errorf("user is banned in this chat")
+	ErrUserNotBanned           = fmt.Errorf("user is not banned in this chat")
+	ErrCantBanOwner            = fmt.Errorf("you can't ban the chat owner")
+	ErrCantBanYourself         = fmt.Errorf("you can't ban yourself")
+	ErrIncorrectBanExpiresAt   = fmt.Errorf("ban expiration time must be in the future")
+	ErrSavingChatBan           = fmt.Errorf("error saving chat ban")
+	ErrIncorrectSlowMode       = fmt.Errorf("slow mode interval must be between 0 seconds and 1 hour")
+	ErrIncorrectChatTitle      = fmt.Errorf("chat title must not be empty")
+	ErrIncorrectDescription    = fmt.Errorf("chat description must not be longer than 255 characters")
+	ErrIncorrectRules          = fmt.Errorf("chat rules must not be longer than 4096 characters")
 )
 
 func setupSavedMessagesChatAvatar(chat *Chat) {
@@ -40,6 +92,61 @@ func setupSavedMessagesChatAvatar(chat *Chat) {
 	))
 }
 
+func setupChatsUnreadCounters(chatsPort ChatsPort, chats []Chat, userId int) {
+	var chatIds []int
+	for _, chat := range chats {
+		chatIds = append(chatIds, chat.GetId())
+	}
+
+	if len(chatIds) == 0 {
+		return
+	}
+
+	unreadCounters := chatsPort.GetUnreadCounters(chatIds, userId)
+	for i := range chats {
+		if counters, ok := unreadCounters[chats[i].GetId()]; ok {
+			chats[i].SetUnreadCounters(counters)
+		}
+	}
+}
+
+// SendChatChangedForUser sends the chat with the member's own unread counters
+// to that member only, because the counters differ between members.
+func SendChatChangedForUser(chatsPort ChatsPort, chatEventsPort ChatEventsPort, chat Chat, userId int) error {
+	chat.SetUnreadCounters(chatsPort.GetUnreadCounters([]int{chat.GetId()}, userId)[chat.GetId()])
+	if err := chatEventsPort.SendChatChangedForUser(chat, userId); err != nil {
+		return errors.Join(ErrSendingEvent, err)
+	}
+
+	return nil
+}
+
+func getChatUserSettings(chatsPort ChatsPort, chatId int, userId int) ChatUserSettings {
+	if settings, ok := chatsPort.GetUserSettings([]int{chatId}, userId)[chatId]; ok {
+		return settings
+	}
+
+	return NewChatUserSettings(chatId, userId, false, false, nil, false, nil)
+}
+
+func setupChatsUserSettings(chatsPort ChatsPort, chats []Chat, userId int) {
+	var chatIds []int
+	for _, chat := range chats {
+		chatIds = append(chatIds, chat.GetId())
+	}
+
+	if len(chatIds) == 0 {
+		return
+	}
+
+	userSettings := chatsPort.GetUserSettings(chatIds, userId)
+	for i := range chats {
+		if settings, ok := userSettings[chats[i].GetId()]; ok {
+			chats[i].SetUserSettings(settings)
+		}
+	}
+}
+
 func ValidateUserChatMember(chat Chat, userId int) bool {
 	return slices.Contains(chat.GetMembers(), userId)
 }
@@ -48,6 +155,187 @@ func ValidateUserChatAdmin(chat Chat, userId int) bool {
 	return chat.GetOwnerId() == userId || slices.Contains(chat.GetAdmins(), userId)
 }
 
+// Group chats and channels both have an owner, admins and managed members.
+func isManagedChat(chat Chat) bool {
+	return chat.GetType() == GroupChatType || chat.GetType() == ChannelChatType
+}
+
+func ValidateUserChatRight(chat Chat, userId int, right ChatRights) bool {
+	if chat.GetOwnerId() == userId {
+		return true
+	}
+
+	if slices.Contains(chat.GetAdmins(), userId) {
+		rights := chat.GetAdminRights(userId)
+		if rights.Has(right) {
+			return true
+		}
+	}
+
+	permissions := chat.GetMemberPermissions()
+	return ValidateUserChatMember(chat, userId) && permissions.Has(right)
+}
+
+func ValidateUserCanPinMessages(chat Chat, userId int) bool {
+	if isManagedChat(chat) {
+		return ValidateUserChatRight(chat, userId, PinMessagesRight)
+	}
+
+	return ValidateUserChatMember(chat, userId)
+}
+
+func ValidateUserCanSendMessages(chat Chat, userId int, withMedia bool) bool {
+	if chat.GetType() == ChannelChatType {
+		return ValidateUserChatAdmin(chat, userId)
+	}
+
+	if chat.GetType() != GroupChatType || ValidateUserChatAdmin(chat, userId) {
+		return true
+	}
+
+	permissions := chat.GetMemberPermissions()
+	if withMedia {
+		return permissions.GetCanSendMessages() && permissions.GetCanSendMedia()
+	}
+
+	return permissions.GetCanSendMessages()
+}
+
+var chatSlugPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]{4,31}$`)
+
+func validateChatSlug(chatsPort ChatsPort, slug string, chatId int) error {
+	if !chatSlugPattern.MatchString(slug) {
+		return ErrIncorrectChatSlug
+	}
+
+	if existingChat, err := chatsPort.GetBySlug(slug); err == nil && existingChat.GetId() != chatId {
+		return ErrChatSlugTaken
+	}
+
+	return nil
+}
+
+func generateInviteLinkCode() (string, error) {
+	code := make([]byte, inviteLinkCodeBytes)
+	if _, err := rand.Read(code); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(code), nil
+}
+
+func ValidateUsersNotBanned(chatsPort ChatsPort, chatId int, userIds []int) error {
+	for _, ban := range chatsPort.GetChatBans(chatId) {
+		if slices.Contains(userIds, ban.GetUserId()) {
+			return ErrUserBanned
+		}
+	}
+
+	return nil
+}
+
+func addChatMembers(chatsPort ChatsPort, usersPort users.UsersPort, chatEventsPort ChatEventsPort, chat Chat, members []int) (*Chat, error) {
+	if err := ValidateUsersNotBanned(chatsPort, chat.GetId(), members); err != nil {
+		return nil, err
+	}
+
+	newMembers := chat.GetMembers()
+	users := usersPort.GetByIds(members)
+	for _, member := range users {
+		if !slices.Contains(newMembers, member.GetId()) {
+			newMembers = append(newMembers, member.GetId())
+		}
+	}
+
+	chat.SetMembers(newMembers)
+	savedChat, err := chatsPort.Save(chat)
+	if err != nil {
+		return nil, ErrSavingChat
+	}
+
+	if err := chatEventsPort.SendChatChanged(*savedChat); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	return savedChat, nil
+}
+
+// setChatOwner hands the ownership over to newOwnerId, whose admin entry is
+// replaced by the owner one. The previous owner keeps the membership and the
+// admin entry, callers decide whether they stay.
+func setChatOwner(chat *Chat, newOwnerId int) {
+	var newAdmins []int
+	for _, admin := range chat.GetAdmins() {
+		if admin != newOwnerId {
+			newAdmins = append(newAdmins, admin)
+		}
+	}
+
+	chat.SetOwnerId(newOwnerId)
+	chat.SetAdmins(newAdmins)
+}
+
+// removeChatParticipant drops the user from members and admins and hands the
+// ownership of a group or channel over to a successor if the user owned it.
+func removeChatParticipant(chat *Chat, userId int) {
+	if isManagedChat(*chat) && chat.GetOwnerId() == userId {
+		if successor, ok := getChatOwnerSuccessor(*chat, userId); ok {
+			setChatOwner(chat, successor)
+		}
+	}
+
+	var newMembers []int
+	for _, member := range chat.GetMembers() {
+		if member != userId {
+			newMembers = append(newMembers, member)
+		}
+	}
+
+	var newAdmins []int
+	for _, admin := range chat.GetAdmins() {
+		if admin != userId {
+			newAdmins = append(newAdmins, admin)
+		}
+	}
+
+	chat.SetMembers(newMembers)
+	chat.SetAdmins(newAdmins)
+}
+
+func getChatOwnerSuccessor(chat Chat, leavingUserId int) (int, bool) {
+	for _, admin := range chat.GetAdmins() {
+		if admin != leavingUserId && ValidateUserChatMember(chat, admin) {
+			return admin, true
+		}
+	}
+
+	for _, member := range chat.GetMembers() {
+		if member != leavingUserId {
+			return member, true
+		}
+	}
+
+	return 0, false
+}
+
+func validateChatFolderData(data ChatFolderData) error {
+	title := data.GetTitle()
+	if len(strings.TrimSpace(title)) == 0 || len([]rune(title)) > 64 {
+		return ErrIncorrectFolderTitle
+	}
+
+	for _, chatType := range data.GetChatTypes() {
+		if !slices.Contains([]ChatTypes{GroupChatType, UserChatType, ChannelChatType, SavedMessagesChatType}, chatType) {
+			return ErrIncorrectFolderChatType
+		}
+	}
+
+	if len(data.GetChatIds()) == 0 && len(data.GetChatTypes()) == 0 && !data.GetUnreadOnly() && !data.GetMutedOnly() {
+		return ErrIncorrectFolderRules
+	}
+
+	return nil
+}
+
 func GetAnotherUserIdForUserChat(chat Chat, currentUserId int) int {
 	if chat.GetType() != "user" {
 		return 0
@@ -139,6 +427,30 @@ func (handler *CreateChatHandler) createGroupChat(data CreateChatData, currentUs
 	return savedChat, nil
 }
 
+func (handler *CreateChatHandler) createChannel(data CreateChatData, currentUser *users.User) (*Chat, error) {
+	if slug := data.GetSlug(); slug != nil {
+		if err := validateChatSlug(handler.chatsPort, *slug, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	chat := CreateChatDataToChat(data, 0)
+	chat.SetOwnerId(currentUser.GetId())
+	if !ValidateUserChatMember(chat, currentUser.GetId()) {
+		newMembers := chat.GetMembers()
+		newMembers = append(newMembers, currentUser.GetId())
+		chat.SetMembers(newMembers)
+	}
+
+	chat.SetType(ChannelChatType)
+	savedChat, err := handler.chatsPort.Save(chat)
+	if err != nil {
+		return nil, errors.Join(ErrSavingChat, err)
+	}
+
+	return savedChat, nil
+}
+
 func (handler *CreateChatHandler) createUserChat(data CreateChatData, currentUser *users.User) (*Chat, error) {
 	if data.userId == nil {
 		return nil, ErrCreatingNotUserChat
@@ -192,6 +504,8 @@ func (handler *CreateChatHandler) Execute(data CreateChatData, currentUserId int
 		savedChat, savingError = handler.createGroupChat(data, currentUser)
 	case UserChatType:
 		savedChat, savingError = handler.createUserChat(data, currentUser)
+	case ChannelChatType:
+		savedChat, savingError = handler.createChannel(data, currentUser)
 	default:
 		savingError = ErrInvalidCreatingChatType
 	}
@@ -200,7 +514,9 @@ func (handler *CreateChatHandler) Execute(data CreateChatData, currentUserId int
 		return nil, savingError
 	}
 
-	handler.chatEventsPort.SendChatCreated(*savedChat)
+	if err := handler.chatEventsPort.SendChatCreated(*savedChat); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
 	return savedChat, nil
 }
 
@@ -208,13 +524,31 @@ type CreateSavedMessagesChat struct {
 	chatsPort ChatsPort
 }
 
+// Execute returns the existing saved messages chat if the user already has
+// one, so handling a redelivered user_created event doesn't create another.
+// Concurrent deliveries are stopped by the unique saved messages chat index,
+// the one that loses the race returns the chat created by the other.
 func (handler *CreateSavedMessagesChat) Execute(data CreateChatData, currentUserId int) (*Chat, error) {
+	existingChat, err := handler.chatsPort.GetSavedMessagesChat(currentUserId)
+	if err == nil {
+		setupSavedMessagesChatAvatar(existingChat)
+		return existingChat, nil
+	}
+	if !errors.Is(err, ErrChatNotFound) {
+		return nil, errors.Join(ErrFindingChat, err)
+	}
+
 	chat := CreateChatDataToChat(data, currentUserId)
 	chat.SetOwnerId(currentUserId)
 	chat.SetMembers([]int{currentUserId})
 	chat.SetTitle("Saved messages")
 	savedChat, err := handler.chatsPort.Save(chat)
 	if err != nil {
+		if existingChat, findErr := handler.chatsPort.GetSavedMessagesChat(currentUserId); findErr == nil {
+			setupSavedMessagesChatAvatar(existingChat)
+			return existingChat, nil
+		}
+
 		return nil, errors.Join(ErrSavingChat, err)
 	}
 
@@ -234,7 +568,9 @@ func (handler *DeleteChatHandler) Execute(chatId, userId int) error {
 	}
 
 	handler.chatsPort.Delete(*chat)
-	handler.chatEventsPort.SendChatDeleted(*chat)
+	if err := handler.chatEventsPort.SendChatDeleted(*chat); err != nil {
+		return errors.Join(ErrSendingEvent, err)
+	}
 	return nil
 }
 
@@ -244,8 +580,43 @@ type GetChatsHandler struct {
 	userActionsPort UserActionsPort
 }
 
-func (handler *GetChatsHandler) Execute(userId int, page int, perPage int) utils.PaginatedResponse[Chat] {
-	paginatedChats := handler.chatsPort.GetUserAll(userId, page, perPage)
+func (handler *GetChatsHandler) Execute(userId int, includeArchived bool, folderId *int, page int, perPage int) (*utils.PaginatedResponse[Chat], error) {
+	var folder *ChatFolder
+	if folderId != nil {
+		userFolder, err := handler.chatsPort.GetFolderForUser(*folderId, userId)
+		if err != nil {
+			return nil, ErrChatFolderNotFound
+		}
+
+		folder = userFolder
+	}
+
+	paginatedChats := handler.chatsPort.GetUserAll(userId, includeArchived, folder, page, perPage)
+	fetchingUsers := GetUserChatsUsersIds(paginatedChats.GetData(), userId)
+	fetchedUsers := handler.usersPort.GetByIds(fetchingUsers)
+	chatsWithUsersData := SetupUserChatsData(paginatedChats.GetData(), fetchedUsers, userId)
+	var completeChats []Chat
+	for _, chat := range chatsWithUsersData {
+		setupSavedMessagesChatAvatar(&chat)
+		chatActions := handler.userActionsPort.GetAllChatActionsUsers(chat)
+		chat.SetupActions(chatActions)
+		completeChats = append(completeChats, chat)
+	}
+
+	setupChatsUnreadCounters(handler.chatsPort, completeChats, userId)
+	setupChatsUserSettings(handler.chatsPort, completeChats, userId)
+	paginatedChats.SetData(completeChats)
+	return &paginatedChats, nil
+}
+
+type GetArchivedChatsHandler struct {
+	chatsPort       ChatsPort
+	usersPort       users.UsersPort
+	userActionsPort UserActionsPort
+}
+
+func (handler *GetArchivedChatsHandler) Execute(userId int, page int, perPage int) utils.PaginatedResponse[Chat] {
+	paginatedChats := handler.chatsPort.GetUserArchived(userId, page, perPage)
 	fetchingUsers := GetUserChatsUsersIds(paginatedChats.GetData(), userId)
 	fetchedUsers := handler.usersPort.GetByIds(fetchingUsers)
 	chatsWithUsersData := SetupUserChatsData(paginatedChats.GetData(), fetchedUsers, userId)
@@ -257,6 +628,8 @@ func (handler *GetChatsHandler) Execute(userId int, page int, perPage int) utils
 		completeChats = append(completeChats, chat)
 	}
 
+	setupChatsUnreadCounters(handler.chatsPort, completeChats, userId)
+	setupChatsUserSettings(handler.chatsPort, completeChats, userId)
 	paginatedChats.SetData(completeChats)
 	return paginatedChats
 }
@@ -280,6 +653,8 @@ func (handler *GetChatsByIdsHandler) Execute(chatIds []int, userId int) []Chat {
 		completeChats = append(completeChats, chat)
 	}
 
+	setupChatsUnreadCounters(handler.chatsPort, completeChats, userId)
+	setupChatsUserSettings(handler.chatsPort, completeChats, userId)
 	return completeChats
 }
 
@@ -295,6 +670,11 @@ func (handler *GetChatHandler) Execute(userId int, chatId int) (*Chat, error) {
 		return nil, ErrChatNotFound
 	}
 
+	if counters, ok := handler.chatsPort.GetUnreadCounters([]int{chat.GetId()}, userId)[chat.GetId()]; ok {
+		chat.SetUnreadCounters(counters)
+	}
+	chat.SetUserSettings(getChatUserSettings(handler.chatsPort, chat.GetId(), userId))
+
 	if chat.GetType() != "user" {
 		setupSavedMessagesChatAvatar(chat)
 		return chat, nil
@@ -336,7 +716,9 @@ func (handler *UserActionHandler) Execute(chatId int, userId int, actionType Act
 
 	newChatActions := handler.userActionsPort.AddChatActionUser(*chat, *user, actionType)
 	chat.SetupActions(newChatActions)
-	handler.chatEventsPort.SendChatUserAction(*chat)
+	if err := handler.chatEventsPort.SendChatUserAction(*chat); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
 	return chat, nil
 }
 
@@ -354,7 +736,9 @@ func (handler *StopUserActionHandler) Execute(chatId int, userId int, actionType
 
 	newChatActions := handler.userActionsPort.RemoveChatActionUser(*chat, userId, actionType)
 	chat.SetupActions(newChatActions)
-	handler.chatEventsPort.SendChatUserAction(*chat)
+	if err := handler.chatEventsPort.SendChatUserAction(*chat); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
 	return chat, nil
 }
 
@@ -370,29 +754,14 @@ func (handler *AddChatMembersHandler) Execute(chatId int, userId int, members []
 		return nil, ErrChatNotFound
 	}
 
-	if !ValidateUserChatAdmin(*chat, userId) {
-		return nil, ErrChatNotAdmin
+	if !ValidateUserChatRight(*chat, userId, AddMembersRight) {
+		return nil, ErrNotEnoughRights
 	}
-	if chat.GetType() != GroupChatType {
+	if !isManagedChat(*chat) {
 		return nil, ErrChatNotGroup
 	}
 
-	newMembers := chat.GetMembers()
-	users := handler.usersPort.GetByIds(members)
-	for _, member := range users {
-		if !slices.Contains(newMembers, member.GetId()) {
-			newMembers = append(newMembers, member.GetId())
-		}
-	}
-
-	chat.SetMembers(newMembers)
-	savedChat, err := handler.chatsPort.Save(*chat)
-	if err != nil {
-		return nil, ErrSavingChat
-	}
-
-	handler.chatEventsPort.SendChatChanged(*savedChat)
-	return savedChat, nil
+	return addChatMembers(handler.chatsPort, handler.usersPort, handler.chatEventsPort, *chat, members)
 }
 
 type AddChatAdminsHandler struct {
@@ -407,10 +776,10 @@ func (handler *AddChatAdminsHandler) Execute(chatId int, userId int, admins []in
 		return nil, ErrChatNotFound
 	}
 
-	if !ValidateUserChatAdmin(*chat, userId) {
-		return nil, ErrChatNotAdmin
+	if !ValidateUserChatRight(*chat, userId, ManageAdminsRight) {
+		return nil, ErrNotEnoughRights
 	}
-	if chat.GetType() != GroupChatType {
+	if !isManagedChat(*chat) {
 		return nil, ErrChatNotGroup
 	}
 
@@ -419,6 +788,7 @@ func (handler *AddChatAdminsHandler) Execute(chatId int, userId int, admins []in
 	for _, admin := range users {
 		if !slices.Contains(newAdmins, admin.GetId()) {
 			newAdmins = append(newAdmins, admin.GetId())
+			chat.SetAdminRights(admin.GetId(), NoAdminRights())
 		}
 	}
 
@@ -428,7 +798,9 @@ func (handler *AddChatAdminsHandler) Execute(chatId int, userId int, admins []in
 		return nil, ErrSavingChat
 	}
 
-	handler.chatEventsPort.SendChatChanged(*savedChat)
+	if err := handler.chatEventsPort.SendChatChanged(*savedChat); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
 	return savedChat, nil
 }
 
@@ -443,17 +815,23 @@ func (handler *RemoveChatMembersHandler) Execute(chatId int, userId int, members
 		return nil, ErrChatNotFound
 	}
 
-	if !ValidateUserChatAdmin(*chat, userId) {
-		return nil, ErrChatNotAdmin
+	if !ValidateUserChatRight(*chat, userId, RemoveMembersRight) {
+		return nil, ErrNotEnoughRights
 	}
-	if chat.GetType() != GroupChatType {
+	if !isManagedChat(*chat) {
 		return nil, ErrChatNotGroup
 	}
+	if slices.Contains(members, chat.GetOwnerId()) {
+		return nil, ErrCantRemoveOwner
+	}
 
 	var newMembers []int
+	var removedMembers []int
 	for _, member := range chat.GetMembers() {
 		if !slices.Contains(members, member) || member == userId {
 			newMembers = append(newMembers, member)
+		} else {
+			removedMembers = append(removedMembers, member)
 		}
 	}
 
@@ -463,7 +841,14 @@ func (handler *RemoveChatMembersHandler) Execute(chatId int, userId int, members
 		return nil, ErrSavingChat
 	}
 
-	handler.chatEventsPort.SendChatChanged(*savedChat)
+	if err := handler.chatEventsPort.SendChatChanged(*savedChat); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	if len(removedMembers) > 0 {
+		if err := handler.chatEventsPort.SendChatMembersRemoved(*savedChat, removedMembers); err != nil {
+			return nil, errors.Join(ErrSendingEvent, err)
+		}
+	}
 	return savedChat, nil
 }
 
@@ -478,10 +863,10 @@ func (handler *RemoveChatAdminsHandler) Execute(chatId int, userId int, admins [
 		return nil, ErrChatNotFound
 	}
 
-	if !ValidateUserChatAdmin(*chat, userId) {
-		return nil, ErrChatNotAdmin
+	if !ValidateUserChatRight(*chat, userId, ManageAdminsRight) {
+		return nil, ErrNotEnoughRights
 	}
-	if chat.GetType() != GroupChatType {
+	if !isManagedChat(*chat) {
 		return nil, ErrChatNotGroup
 	}
 
@@ -498,7 +883,9 @@ func (handler *RemoveChatAdminsHandler) Execute(chatId int, userId int, admins [
 		return nil, ErrSavingChat
 	}
 
-	handler.chatEventsPort.SendChatChanged(*savedChat)
+	if err := handler.chatEventsPort.SendChatChanged(*savedChat); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
 	return savedChat, nil
 }
 
@@ -513,34 +900,48 @@ func (handler *QuitChatHandler) Execute(chatId int, userId int) (*Chat, error) {
 		return nil, ErrChatNotFound
 	}
 
-	var newMembers []int
-	for _, member := range chat.GetMembers() {
-		if member != userId {
-			newMembers = append(newMembers, member)
-		}
-	}
-
-	var newAdmins []int
-	for _, admin := range chat.GetAdmins() {
-		if admin != userId {
-			newAdmins = append(newAdmins, admin)
-		}
-	}
-
-	chat.SetMembers(newMembers)
-	chat.SetAdmins(newAdmins)
+	removeChatParticipant(chat, userId)
 	savedChat, err := handler.chatsPort.Save(*chat)
 	if err != nil {
 		return nil, ErrSavingChat
 	}
 
-	handler.chatEventsPort.SendChatChanged(*savedChat)
+	if err := handler.chatEventsPort.SendChatChanged(*savedChat); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	if err := handler.chatEventsPort.SendChatMembersRemoved(*savedChat, []int{userId}); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
 	return savedChat, nil
 }
 
+type chatProfileChange struct {
+	event ChatSystemEvents
+	value *string
+}
+
+// Blank optional profile fields are stored as nil so they can be cleared.
+func getOptionalProfileText(value string) *string {
+	trimmedValue := strings.TrimSpace(value)
+	if trimmedValue == "" {
+		return nil
+	}
+
+	return &trimmedValue
+}
+
+func isOptionalTextChanged(oldValue *string, newValue *string) bool {
+	if oldValue == nil || newValue == nil {
+		return oldValue != newValue
+	}
+
+	return *oldValue != *newValue
+}
+
 type ChangeGroupChatHandler struct {
-	chatsPort      ChatsPort
-	chatEventsPort ChatEventsPort
+	chatsPort          ChatsPort
+	chatEventsPort     ChatEventsPort
+	systemMessagesPort ChatSystemMessagesPort
 }
 
 func (handler *ChangeGroupChatHandler) Execute(chatId int, userId int, chatData ChangeGroupChatData) (*Chat, error) {
@@ -549,26 +950,83 @@ func (handler *ChangeGroupChatHandler) Execute(chatId int, userId int, chatData
 		return nil, ErrChatNotFound
 	}
 
-	if userId != chat.GetOwnerId() && !ValidateUserChatAdmin(*chat, userId) {
-		return nil, ErrChatNotAdmin
+	if !ValidateUserChatRight(*chat, userId, EditInfoRight) {
+		return nil, ErrNotEnoughRights
 	}
 
-	if chat.GetType() != GroupChatType {
+	if !isManagedChat(*chat) {
 		return nil, ErrChatNotGroup
 	}
 
-	if chatData.GetTitle() != nil {
-		chat.SetTitle(*chatData.GetTitle())
-	} else {
-		chat.SetTitle("")
+	var changes []chatProfileChange
+	if title := chatData.GetTitle(); title != nil {
+		newTitle := strings.TrimSpace(*title)
+		if newTitle == "" {
+			return nil, ErrIncorrectChatTitle
+		}
+
+		if newTitle != chat.GetTitle() {
+			chat.SetTitle(newTitle)
+			changes = append(changes, chatProfileChange{event: TitleChangedSystemEvent, value: &newTitle})
+		}
 	}
 
-	savedChat, err := handler.chatsPort.Save(*chat)
-	if err != nil {
+	if description := chatData.GetDescription(); description != nil {
+		newDescription := getOptionalProfileText(*description)
+		if newDescription != nil && len([]rune(*newDescription)) > maxChatDescriptionLength {
+			return nil, ErrIncorrectDescription
+		}
+
+		if isOptionalTextChanged(chat.GetDescription(), newDescription) {
+			chat.SetDescription(newDescription)
+			changes = append(changes, chatProfileChange{event: DescriptionChangedSystemEvent, value: newDescription})
+		}
+	}
+
+	if rules := chatData.GetRules(); rules != nil {
+		newRules := getOptionalProfileText(*rules)
+		if newRules != nil && len([]rune(*newRules)) > maxChatRulesLength {
+			return nil, ErrIncorrectRules
+		}
+
+		if isOptionalTextChanged(chat.GetRules(), newRules) {
+			chat.SetRules(newRules)
+			changes = append(changes, chatProfileChange{event: RulesChangedSystemEvent, value: newRules})
+		}
+	}
+
+	if slug := chatData.GetSlug(); slug != nil {
+		newSlug := getOptionalProfileText(*slug)
+		if newSlug != nil {
+			if err := validateChatSlug(handler.chatsPort, *newSlug, chat.GetId()); err != nil {
+				return nil, err
+			}
+		}
+
+		if isOptionalTextChanged(chat.GetSlug(), newSlug) {
+			chat.SetSlug(newSlug)
+			changes = append(changes, chatProfileChange{event: SlugChangedSystemEvent, value: newSlug})
+		}
+	}
+
+	if len(changes) == 0 {
+		return chat, nil
+	}
+
+	savedChat, err := handler.chatsPort.Save(*chat)
+	if err != nil {
 		return nil, ErrSavingChat
 	}
 
-	handler.chatEventsPort.SendChatChanged(*savedChat)
+	if err := handler.chatEventsPort.SendChatChanged(*savedChat); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	for _, change := range changes {
+		if err := handler.systemMessagesPort.SendSystemEvent(*savedChat, userId, change.event, change.value); err != nil {
+			return nil, errors.Join(ErrSendingEvent, err)
+		}
+	}
+
 	return savedChat, nil
 }
 
@@ -584,11 +1042,11 @@ func (handler *UpdateGroupChatAvatar) Execute(chatId int, userId int, newAvatar
 		return nil, ErrChatNotFound
 	}
 
-	if userId != chat.GetOwnerId() && !ValidateUserChatAdmin(*chat, userId) {
-		return nil, ErrChatNotAdmin
+	if !ValidateUserChatRight(*chat, userId, EditInfoRight) {
+		return nil, ErrNotEnoughRights
 	}
 
-	if chat.GetType() != GroupChatType {
+	if !isManagedChat(*chat) {
 		return nil, ErrChatNotGroup
 	}
 
@@ -604,7 +1062,9 @@ func (handler *UpdateGroupChatAvatar) Execute(chatId int, userId int, newAvatar
 		return nil, ErrSavingChat
 	}
 
-	handler.chatEventsPort.SendChatChanged(*savedChat)
+	if err := handler.chatEventsPort.SendChatChanged(*savedChat); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
 	return savedChat, nil
 }
 
@@ -635,6 +1095,842 @@ func (handler *SearchChatsHandler) Execute(userId int, query string, page int, p
 		resultChats = append(resultChats, chat)
 	}
 
+	setupChatsUnreadCounters(handler.chatsPort, resultChats, userId)
+	setupChatsUserSettings(handler.chatsPort, resultChats, userId)
 	chats.SetData(resultChats)
 	return chats
 }
+
+type GetTotalUnreadHandler struct {
+	chatsPort ChatsPort
+}
+
+func (handler *GetTotalUnreadHandler) Execute(userId int) ChatUnreadCounters {
+	return handler.chatsPort.GetTotalUnread(userId)
+}
+
+type SetAdminRightsHandler struct {
+	chatsPort      ChatsPort
+	chatEventsPort ChatEventsPort
+}
+
+func (handler *SetAdminRightsHandler) Execute(chatId int, userId int, adminId int, rights AdminRights) (*Chat, error) {
+	chat, err := handler.chatsPort.GetByIdForUser(chatId, userId)
+	if err != nil {
+		return nil, ErrChatNotFound
+	}
+
+	if !isManagedChat(*chat) {
+		return nil, ErrChatNotGroup
+	}
+	if !ValidateUserChatRight(*chat, userId, ManageAdminsRight) {
+		return nil, ErrNotEnoughRights
+	}
+	if adminId == chat.GetOwnerId() {
+		return nil, ErrCantChangeOwnerRights
+	}
+	if !slices.Contains(chat.GetAdmins(), adminId) {
+		return nil, ErrChatNotAdmin
+	}
+
+	currentUserRights := chat.GetAdminRights(userId)
+	if !currentUserRights.Contains(rights) {
+		return nil, ErrNotEnoughRights
+	}
+
+	chat.SetAdminRights(adminId, rights)
+	savedChat, err := handler.chatsPort.Save(*chat)
+	if err != nil {
+		return nil, ErrSavingChat
+	}
+
+	if err := handler.chatEventsPort.SendChatChanged(*savedChat); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	return savedChat, nil
+}
+
+type SetMemberPermissionsHandler struct {
+	chatsPort      ChatsPort
+	chatEventsPort ChatEventsPort
+}
+
+func (handler *SetMemberPermissionsHandler) Execute(chatId int, userId int, permissions MemberPermissions) (*Chat, error) {
+	chat, err := handler.chatsPort.GetByIdForUser(chatId, userId)
+	if err != nil {
+		return nil, ErrChatNotFound
+	}
+
+	if !isManagedChat(*chat) {
+		return nil, ErrChatNotGroup
+	}
+	if !ValidateUserChatRight(*chat, userId, EditInfoRight) {
+		return nil, ErrNotEnoughRights
+	}
+
+	chat.SetMemberPermissions(permissions)
+	savedChat, err := handler.chatsPort.Save(*chat)
+	if err != nil {
+		return nil, ErrSavingChat
+	}
+
+	if err := handler.chatEventsPort.SendChatChanged(*savedChat); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	return savedChat, nil
+}
+
+type SetSlowModeHandler struct {
+	chatsPort      ChatsPort
+	chatEventsPort ChatEventsPort
+}
+
+func (handler *SetSlowModeHandler) Execute(chatId int, userId int, interval time.Duration) (*Chat, error) {
+	chat, err := handler.chatsPort.GetByIdForUser(chatId, userId)
+	if err != nil {
+		return nil, ErrChatNotFound
+	}
+
+	if !isManagedChat(*chat) {
+		return nil, ErrChatNotGroup
+	}
+	if !ValidateUserChatRight(*chat, userId, EditInfoRight) {
+		return nil, ErrNotEnoughRights
+	}
+	if interval < 0 || interval > maxSlowModeInterval {
+		return nil, ErrIncorrectSlowMode
+	}
+
+	chat.SetSlowModeInterval(interval)
+	savedChat, err := handler.chatsPort.Save(*chat)
+	if err != nil {
+		return nil, ErrSavingChat
+	}
+
+	if err := handler.chatEventsPort.SendChatChanged(*savedChat); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	return savedChat, nil
+}
+
+type CreateInviteLinkHandler struct {
+	chatsPort ChatsPort
+}
+
+func (handler *CreateInviteLinkHandler) Execute(chatId int, userId int, data CreateInviteLinkData) (*ChatInviteLink, error) {
+	chat, err := handler.chatsPort.GetByIdForUser(chatId, userId)
+	if err != nil {
+		return nil, ErrChatNotFound
+	}
+
+	if !isManagedChat(*chat) {
+		return nil, ErrChatNotGroup
+	}
+	if !ValidateUserChatRight(*chat, userId, AddMembersRight) {
+		return nil, ErrNotEnoughRights
+	}
+	if expiresAt := data.GetExpiresAt(); expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, ErrIncorrectLinkExpiresAt
+	}
+	if usageLimit := data.GetUsageLimit(); usageLimit != nil && *usageLimit <= 0 {
+		return nil, ErrIncorrectLinkUsageLimit
+	}
+
+	code, err := generateInviteLinkCode()
+	if err != nil {
+		return nil, errors.Join(ErrSavingInviteLink, err)
+	}
+
+	link := NewChatInviteLink(
+		0,
+		chat.GetId(),
+		code,
+		userId,
+		data.GetExpiresAt(),
+		data.GetUsageLimit(),
+		0,
+		data.GetRequiresApproval(),
+		nil,
+		time.Now(),
+	)
+	savedLink, err := handler.chatsPort.SaveInviteLink(link)
+	if err != nil {
+		return nil, errors.Join(ErrSavingInviteLink, err)
+	}
+
+	return savedLink, nil
+}
+
+type RevokeInviteLinkHandler struct {
+	chatsPort ChatsPort
+}
+
+func (handler *RevokeInviteLinkHandler) Execute(code string, userId int) (*ChatInviteLink, error) {
+	link, err := handler.chatsPort.GetInviteLinkByCode(code)
+	if err != nil {
+		return nil, ErrInviteLinkNotFound
+	}
+
+	chat, err := handler.chatsPort.GetByIdForUser(link.GetChatId(), userId)
+	if err != nil {
+		return nil, ErrInviteLinkNotFound
+	}
+
+	if link.GetCreatedBy() != userId && !ValidateUserChatRight(*chat, userId, AddMembersRight) {
+		return nil, ErrNotEnoughRights
+	}
+	if link.IsRevoked() {
+		return link, nil
+	}
+
+	link.Revoke(time.Now())
+	savedLink, err := handler.chatsPort.SaveInviteLink(*link)
+	if err != nil {
+		return nil, errors.Join(ErrSavingInviteLink, err)
+	}
+
+	return savedLink, nil
+}
+
+type JoinChatByInviteHandler struct {
+	chatsPort          ChatsPort
+	usersPort          users.UsersPort
+	chatEventsPort     ChatEventsPort
+	systemMessagesPort ChatSystemMessagesPort
+}
+
+func (handler *JoinChatByInviteHandler) Execute(code string, userId int) (*Chat, error) {
+	link, err := handler.chatsPort.GetInviteLinkByCode(code)
+	if err != nil {
+		return nil, ErrInviteLinkNotFound
+	}
+
+	if link.IsRevoked() || link.IsExpired(time.Now()) || link.IsExhausted() {
+		return nil, ErrInviteLinkExpired
+	}
+	if link.GetRequiresApproval() {
+		return nil, ErrInviteLinkNeedsApproval
+	}
+
+	chat, err := handler.chatsPort.GetById(link.GetChatId())
+	if err != nil {
+		return nil, ErrChatNotFound
+	}
+
+	if !isManagedChat(*chat) {
+		return nil, ErrChatNotGroup
+	}
+	if ValidateUserChatMember(*chat, userId) {
+		return nil, ErrAlreadyChatMember
+	}
+	if err := ValidateUsersNotBanned(handler.chatsPort, chat.GetId(), []int{userId}); err != nil {
+		return nil, err
+	}
+
+	savedChat, err := addChatMembers(handler.chatsPort, handler.usersPort, handler.chatEventsPort, *chat, []int{userId})
+	if err != nil {
+		return nil, err
+	}
+
+	// The use is counted only once the member is added. A link exhausted by a
+	// concurrent join fails here and rolls the added member back with it.
+	if err := handler.chatsPort.UseInviteLink(*link); err != nil {
+		return nil, ErrInviteLinkExpired
+	}
+
+	if err := handler.systemMessagesPort.SendSystemEvent(*savedChat, userId, JoinedViaLinkSystemEvent, nil); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	return savedChat, nil
+}
+
+type RequestChatJoinHandler struct {
+	chatsPort ChatsPort
+}
+
+func (handler *RequestChatJoinHandler) Execute(code string, userId int) (*ChatJoinRequest, error) {
+	link, err := handler.chatsPort.GetInviteLinkByCode(code)
+	if err != nil {
+		return nil, ErrInviteLinkNotFound
+	}
+
+	if link.IsRevoked() || link.IsExpired(time.Now()) || link.IsExhausted() {
+		return nil, ErrInviteLinkExpired
+	}
+	if !link.GetRequiresApproval() {
+		return nil, ErrInviteLinkNoApproval
+	}
+
+	chat, err := handler.chatsPort.GetById(link.GetChatId())
+	if err != nil {
+		return nil, ErrChatNotFound
+	}
+
+	if ValidateUserChatMember(*chat, userId) {
+		return nil, ErrAlreadyChatMember
+	}
+	if err := ValidateUsersNotBanned(handler.chatsPort, chat.GetId(), []int{userId}); err != nil {
+		return nil, err
+	}
+
+	if pendingRequest, err := handler.chatsPort.GetPendingJoinRequest(chat.GetId(), userId); err == nil {
+		return pendingRequest, nil
+	}
+
+	linkId := link.GetId()
+	request := NewChatJoinRequest(0, chat.GetId(), userId, &linkId, PendingJoinRequestStatus, nil, time.Now(), nil)
+	savedRequest, err := handler.chatsPort.SaveJoinRequest(request)
+	if err != nil {
+		return nil, errors.Join(ErrSavingJoinRequest, err)
+	}
+
+	return savedRequest, nil
+}
+
+type GetJoinRequestsHandler struct {
+	chatsPort ChatsPort
+}
+
+func (handler *GetJoinRequestsHandler) Execute(chatId int, userId int) ([]ChatJoinRequest, error) {
+	chat, err := handler.chatsPort.GetByIdForUser(chatId, userId)
+	if err != nil {
+		return nil, ErrChatNotFound
+	}
+
+	if !ValidateUserChatRight(*chat, userId, AddMembersRight) {
+		return nil, ErrNotEnoughRights
+	}
+
+	return handler.chatsPort.GetChatPendingJoinRequests(chatId), nil
+}
+
+type ResolveJoinRequestHandler struct {
+	chatsPort          ChatsPort
+	usersPort          users.UsersPort
+	chatEventsPort     ChatEventsPort
+	systemMessagesPort ChatSystemMessagesPort
+}
+
+func (handler *ResolveJoinRequestHandler) Execute(requestId int, userId int, approve bool) (*ChatJoinRequest, error) {
+	request, err := handler.chatsPort.GetJoinRequestById(requestId)
+	if err != nil {
+		return nil, ErrJoinRequestNotFound
+	}
+
+	chat, err := handler.chatsPort.GetByIdForUser(request.GetChatId(), userId)
+	if err != nil {
+		return nil, ErrJoinRequestNotFound
+	}
+
+	if !ValidateUserChatRight(*chat, userId, AddMembersRight) {
+		return nil, ErrNotEnoughRights
+	}
+	if !request.IsPending() {
+		return nil, ErrJoinRequestResolved
+	}
+
+	status := DeclinedJoinRequestStatus
+	if approve {
+		// The member is added before the request is marked approved, so a
+		// failed add leaves the request pending instead of approved. A deleted
+		// user can't be added, their request stays pending until declined.
+		status = ApprovedJoinRequestStatus
+		if len(handler.usersPort.GetByIds([]int{request.GetUserId()})) == 0 {
+			return nil, ErrFindingUser
+		}
+
+		savedChat, err := addChatMembers(handler.chatsPort, handler.usersPort, handler.chatEventsPort, *chat, []int{request.GetUserId()})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := handler.systemMessagesPort.SendSystemEvent(*savedChat, request.GetUserId(), JoinRequestApprovedSystemEvent, nil); err != nil {
+			return nil, errors.Join(ErrSendingEvent, err)
+		}
+	}
+
+	request.Resolve(status, userId, time.Now())
+	savedRequest, err := handler.chatsPort.SaveJoinRequest(*request)
+	if err != nil {
+		return nil, errors.Join(ErrSavingJoinRequest, err)
+	}
+
+	if err := handler.chatEventsPort.SendJoinRequestResolved(*savedRequest); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	return savedRequest, nil
+}
+
+type TransferChatOwnershipHandler struct {
+	chatsPort      ChatsPort
+	chatEventsPort ChatEventsPort
+}
+
+func (handler *TransferChatOwnershipHandler) Execute(chatId int, userId int, newOwnerId int) (*Chat, error) {
+	chat, err := handler.chatsPort.GetByIdForUser(chatId, userId)
+	if err != nil {
+		return nil, ErrChatNotFound
+	}
+
+	if !isManagedChat(*chat) {
+		return nil, ErrChatNotGroup
+	}
+	if chat.GetOwnerId() != userId {
+		return nil, ErrNotChatOwner
+	}
+	if !ValidateUserChatMember(*chat, newOwnerId) {
+		return nil, ErrNewOwnerNotMember
+	}
+	if newOwnerId == userId {
+		return chat, nil
+	}
+
+	// The previous owner stays in the chat as an admin with full rights.
+	setChatOwner(chat, newOwnerId)
+	if !slices.Contains(chat.GetAdmins(), userId) {
+		chat.SetAdmins(append(chat.GetAdmins(), userId))
+	}
+	chat.SetAdminRights(userId, FullAdminRights())
+	savedChat, err := handler.chatsPort.Save(*chat)
+	if err != nil {
+		return nil, ErrSavingChat
+	}
+
+	if err := handler.chatEventsPort.SendChatChanged(*savedChat); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	return savedChat, nil
+}
+
+type SetChatArchivedHandler struct {
+	chatsPort      ChatsPort
+	chatEventsPort ChatEventsPort
+}
+
+func (handler *SetChatArchivedHandler) Execute(chatId int, userId int, archived bool) (*Chat, error) {
+	chat, err := handler.chatsPort.GetByIdForUser(chatId, userId)
+	if err != nil {
+		return nil, ErrChatNotFound
+	}
+
+	settings := getChatUserSettings(handler.chatsPort, chat.GetId(), userId)
+	settings.SetIsArchived(archived)
+	if err := handler.chatsPort.SaveUserSettings(settings); err != nil {
+		return nil, errors.Join(ErrSavingChatSettings, err)
+	}
+
+	chat.SetUserSettings(settings)
+	if err := handler.chatEventsPort.SendChatArchiveChanged(*chat, userId); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	return chat, nil
+}
+
+type SetChatPinnedHandler struct {
+	chatsPort ChatsPort
+}
+
+func (handler *SetChatPinnedHandler) Execute(chatId int, userId int, pinned bool) (*Chat, error) {
+	chat, err := handler.chatsPort.GetByIdForUser(chatId, userId)
+	if err != nil {
+		return nil, ErrChatNotFound
+	}
+
+	settings := getChatUserSettings(handler.chatsPort, chat.GetId(), userId)
+	if pinned {
+		settings.Pin(time.Now())
+	} else {
+		settings.Unpin()
+	}
+
+	if err := handler.chatsPort.SaveUserSettings(settings); err != nil {
+		return nil, errors.Join(ErrSavingChatSettings, err)
+	}
+
+	chat.SetUserSettings(settings)
+	return chat, nil
+}
+
+type SetChatMutedHandler struct {
+	chatsPort ChatsPort
+}
+
+func (handler *SetChatMutedHandler) Execute(chatId int, userId int, muted bool, mutedUntil *time.Time) (*Chat, error) {
+	chat, err := handler.chatsPort.GetByIdForUser(chatId, userId)
+	if err != nil {
+		return nil, ErrChatNotFound
+	}
+
+	if muted && mutedUntil != nil && !mutedUntil.After(time.Now()) {
+		return nil, ErrIncorrectMutedUntil
+	}
+
+	settings := getChatUserSettings(handler.chatsPort, chat.GetId(), userId)
+	if muted {
+		settings.Mute(mutedUntil)
+	} else {
+		settings.Unmute()
+	}
+
+	if err := handler.chatsPort.SaveUserSettings(settings); err != nil {
+		return nil, errors.Join(ErrSavingChatSettings, err)
+	}
+
+	chat.SetUserSettings(settings)
+	return chat, nil
+}
+
+type GetChatFoldersHandler struct {
+	chatsPort ChatsPort
+}
+
+func (handler *GetChatFoldersHandler) Execute(userId int) []ChatFolder {
+	return handler.chatsPort.GetUserFolders(userId)
+}
+
+type CreateChatFolderHandler struct {
+	chatsPort      ChatsPort
+	chatEventsPort ChatEventsPort
+}
+
+func (handler *CreateChatFolderHandler) Execute(userId int, data ChatFolderData) (*ChatFolder, error) {
+	if err := validateChatFolderData(data); err != nil {
+		return nil, err
+	}
+
+	position := 0
+	for _, folder := range handler.chatsPort.GetUserFolders(userId) {
+		if folder.GetPosition() >= position {
+			position = folder.GetPosition() + 1
+		}
+	}
+
+	folder := NewChatFolder(
+		0,
+		userId,
+		data.GetTitle(),
+		position,
+		data.GetChatIds(),
+		data.GetChatTypes(),
+		data.GetUnreadOnly(),
+		data.GetMutedOnly(),
+	)
+	savedFolder, err := handler.chatsPort.SaveFolder(folder)
+	if err != nil {
+		return nil, errors.Join(ErrSavingChatFolder, err)
+	}
+
+	if err := handler.chatEventsPort.SendFoldersChanged(userId, handler.chatsPort.GetUserFolders(userId)); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	return savedFolder, nil
+}
+
+type UpdateChatFolderHandler struct {
+	chatsPort      ChatsPort
+	chatEventsPort ChatEventsPort
+}
+
+func (handler *UpdateChatFolderHandler) Execute(folderId int, userId int, data ChatFolderData) (*ChatFolder, error) {
+	folder, err := handler.chatsPort.GetFolderForUser(folderId, userId)
+	if err != nil {
+		return nil, ErrChatFolderNotFound
+	}
+
+	if err := validateChatFolderData(data); err != nil {
+		return nil, err
+	}
+
+	folder.Update(data)
+	savedFolder, err := handler.chatsPort.SaveFolder(*folder)
+	if err != nil {
+		return nil, errors.Join(ErrSavingChatFolder, err)
+	}
+
+	if err := handler.chatEventsPort.SendFoldersChanged(userId, handler.chatsPort.GetUserFolders(userId)); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	return savedFolder, nil
+}
+
+type DeleteChatFolderHandler struct {
+	chatsPort      ChatsPort
+	chatEventsPort ChatEventsPort
+}
+
+func (handler *DeleteChatFolderHandler) Execute(folderId int, userId int) error {
+	folder, err := handler.chatsPort.GetFolderForUser(folderId, userId)
+	if err != nil {
+		return ErrChatFolderNotFound
+	}
+
+	handler.chatsPort.DeleteFolder(*folder)
+	if err := handler.chatEventsPort.SendFoldersChanged(userId, handler.chatsPort.GetUserFolders(userId)); err != nil {
+		return errors.Join(ErrSendingEvent, err)
+	}
+	return nil
+}
+
+type ReorderChatFoldersHandler struct {
+	chatsPort      ChatsPort
+	chatEventsPort ChatEventsPort
+}
+
+func (handler *ReorderChatFoldersHandler) Execute(userId int, folderIds []int) ([]ChatFolder, error) {
+	folders := handler.chatsPort.GetUserFolders(userId)
+	if len(folders) != len(folderIds) {
+		return nil, ErrIncorrectFoldersOrder
+	}
+
+	for _, folder := range folders {
+		if !slices.Contains(folderIds, folder.GetId()) {
+			return nil, ErrIncorrectFoldersOrder
+		}
+	}
+
+	if err := handler.chatsPort.SaveFoldersOrder(userId, folderIds); err != nil {
+		return nil, errors.Join(ErrSavingChatFolder, err)
+	}
+
+	reorderedFolders := handler.chatsPort.GetUserFolders(userId)
+	if err := handler.chatEventsPort.SendFoldersChanged(userId, reorderedFolders); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	return reorderedFolders, nil
+}
+
+type GetPublicChannelHandler struct {
+	chatsPort ChatsPort
+}
+
+func (handler *GetPublicChannelHandler) Execute(slug string) (*Chat, error) {
+	chat, err := handler.chatsPort.GetBySlug(slug)
+	if err != nil || chat.GetType() != ChannelChatType {
+		return nil, ErrChannelNotFound
+	}
+
+	return chat, nil
+}
+
+type SubscribeChannelHandler struct {
+	chatsPort      ChatsPort
+	usersPort      users.UsersPort
+	chatEventsPort ChatEventsPort
+}
+
+func (handler *SubscribeChannelHandler) Execute(slug string, userId int) (*Chat, error) {
+	chat, err := handler.chatsPort.GetBySlug(slug)
+	if err != nil || chat.GetType() != ChannelChatType {
+		return nil, ErrChannelNotFound
+	}
+
+	if ValidateUserChatMember(*chat, userId) {
+		return nil, ErrAlreadyChatMember
+	}
+
+	return addChatMembers(handler.chatsPort, handler.usersPort, handler.chatEventsPort, *chat, []int{userId})
+}
+
+type BanChatMemberHandler struct {
+	chatsPort      ChatsPort
+	chatEventsPort ChatEventsPort
+}
+
+// validateUserCanBan checks that userId may ban memberId. Banning an admin
+// also takes the admin rights away, so it requires the manage admins right.
+func validateUserCanBan(chat Chat, userId int, memberId int) error {
+	if !ValidateUserChatRight(chat, userId, RemoveMembersRight) {
+		return ErrNotEnoughRights
+	}
+	if !isManagedChat(chat) {
+		return ErrChatNotGroup
+	}
+	if memberId == userId {
+		return ErrCantBanYourself
+	}
+	if memberId == chat.GetOwnerId() {
+		return ErrCantBanOwner
+	}
+	if slices.Contains(chat.GetAdmins(), memberId) && !ValidateUserChatRight(chat, userId, ManageAdminsRight) {
+		return ErrNotEnoughRights
+	}
+
+	return nil
+}
+
+func (handler *BanChatMemberHandler) Execute(chatId int, userId int, memberId int, expiresAt *time.Time) (*Chat, error) {
+	chat, err := handler.chatsPort.GetByIdForUser(chatId, userId)
+	if err != nil {
+		return nil, ErrChatNotFound
+	}
+
+	if err := validateUserCanBan(*chat, userId, memberId); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, ErrIncorrectBanExpiresAt
+	}
+
+	ban := NewChatBan(0, chat.GetId(), memberId, userId, expiresAt, now)
+	if _, err := handler.chatsPort.SaveBan(ban); err != nil {
+		return nil, errors.Join(ErrSavingChatBan, err)
+	}
+
+	var newMembers []int
+	for _, member := range chat.GetMembers() {
+		if member != memberId {
+			newMembers = append(newMembers, member)
+		}
+	}
+
+	var newAdmins []int
+	for _, admin := range chat.GetAdmins() {
+		if admin != memberId {
+			newAdmins = append(newAdmins, admin)
+		}
+	}
+
+	chat.SetMembers(newMembers)
+	chat.SetAdmins(newAdmins)
+	savedChat, err := handler.chatsPort.Save(*chat)
+	if err != nil {
+		return nil, ErrSavingChat
+	}
+
+	if err := handler.chatEventsPort.SendChatChanged(*savedChat); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	if err := handler.chatEventsPort.SendChatMembersRemoved(*savedChat, []int{memberId}); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	return savedChat, nil
+}
+
+type UnbanChatMemberHandler struct {
+	chatsPort ChatsPort
+}
+
+func (handler *UnbanChatMemberHandler) Execute(chatId int, userId int, memberId int) error {
+	chat, err := handler.chatsPort.GetByIdForUser(chatId, userId)
+	if err != nil {
+		return ErrChatNotFound
+	}
+
+	if !isManagedChat(*chat) {
+		return ErrChatNotGroup
+	}
+	if !ValidateUserChatRight(*chat, userId, RemoveMembersRight) {
+		return ErrNotEnoughRights
+	}
+
+	isBanned := slices.ContainsFunc(handler.chatsPort.GetChatBans(chat.GetId()), func(ban ChatBan) bool {
+		return ban.GetUserId() == memberId
+	})
+	if !isBanned {
+		return ErrUserNotBanned
+	}
+
+	handler.chatsPort.DeleteBan(chat.GetId(), memberId)
+	return nil
+}
+
+type GetBannedMembersHandler struct {
+	chatsPort ChatsPort
+}
+
+func (handler *GetBannedMembersHandler) Execute(chatId int, userId int) ([]ChatBan, error) {
+	chat, err := handler.chatsPort.GetByIdForUser(chatId, userId)
+	if err != nil {
+		return nil, ErrChatNotFound
+	}
+
+	if !ValidateUserChatRight(*chat, userId, RemoveMembersRight) {
+		return nil, ErrNotEnoughRights
+	}
+
+	return handler.chatsPort.GetChatBans(chat.GetId()), nil
+}
+
+// DeleteUserDataHandler cleans up after a deleted account: the user leaves
+// every group and channel, their direct chats are marked and their saved
+// messages chat is removed. Running it again for the same user is a no-op.
+type DeleteUserDataHandler struct {
+	chatsPort       ChatsPort
+	chatEventsPort  ChatEventsPort
+	userActionsPort UserActionsPort
+}
+
+func (handler *DeleteUserDataHandler) Execute(userId int) error {
+	for _, chat := range handler.chatsPort.GetAllByMember(userId) {
+		handler.userActionsPort.RemoveUserFromChatActions(chat, userId)
+
+		switch chat.GetType() {
+		case SavedMessagesChatType:
+			handler.chatsPort.Delete(chat)
+			continue
+		case UserChatType:
+			if chat.GetIsUserDeleted() {
+				continue
+			}
+
+			chat.SetIsUserDeleted(true)
+		default:
+			removeChatParticipant(&chat, userId)
+			if len(chat.GetMembers()) == 0 {
+				handler.chatsPort.Delete(chat)
+				continue
+			}
+		}
+
+		savedChat, err := handler.chatsPort.Save(chat)
+		if err != nil {
+			return errors.Join(ErrSavingChat, err)
+		}
+
+		if err := handler.chatEventsPort.SendChatChanged(*savedChat); err != nil {
+			return errors.Join(ErrSendingEvent, err)
+		}
+	}
+
+	return nil
+}
+
+// UpdateUserDataHandler propagates a changed user profile to the data derived
+// from it: cached action users and the titles of the user's direct chats.
+type UpdateUserDataHandler struct {
+	chatsPort       ChatsPort
+	chatEventsPort  ChatEventsPort
+	usersPort       users.UsersPort
+	userActionsPort UserActionsPort
+}
+
+func (handler *UpdateUserDataHandler) Execute(userId int) error {
+	user, err := handler.usersPort.GetById(userId)
+	if err != nil {
+		return ErrFindingUser
+	}
+
+	for _, chat := range handler.chatsPort.GetAllByMember(userId) {
+		handler.userActionsPort.RefreshChatActionUser(chat, *user)
+		if chat.GetType() != UserChatType {
+			continue
+		}
+
+		// The title of a user chat is the other member's name, so only the
+		// other member sees it change.
+		chat.SetupUserData(user)
+		for _, member := range chat.GetMembers() {
+			if member == userId {
+				continue
+			}
+
+			if err := SendChatChangedForUser(handler.chatsPort, handler.chatEventsPort, chat, member); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package chats
 
 import (
 	"slices"
+	"time"
 
 	"github.com/chack-check/chats-service/domain/files"
 	"github.com/chack-check/chats-service/domain/users"
@@ -24,26 +25,551 @@ var (
 	UserChatType          ChatTypes = "user"
 	GroupChatType         ChatTypes = "group"
 	SavedMessagesChatType ChatTypes = "saved_messages"
+	ChannelChatType       ChatTypes = "channel"
 )
 
+type ChatRights string
+
+const (
+	AddMembersRight     ChatRights = "add_members"
+	RemoveMembersRight  ChatRights = "remove_members"
+	EditInfoRight       ChatRights = "edit_info"
+	PinMessagesRight    ChatRights = "pin_messages"
+	DeleteMessagesRight ChatRights = "delete_messages"
+	ManageAdminsRight   ChatRights = "manage_admins"
+)
+
+type AdminRights struct {
+	canAddMembers     bool
+	canRemoveMembers  bool
+	canEditInfo       bool
+	canPinMessages    bool
+	canDeleteMessages bool
+	canManageAdmins   bool
+}
+
+func (model *AdminRights) GetCanAddMembers() bool {
+	return model.canAddMembers
+}
+
+func (model *AdminRights) GetCanRemoveMembers() bool {
+	return model.canRemoveMembers
+}
+
+func (model *AdminRights) GetCanEditInfo() bool {
+	return model.canEditInfo
+}
+
+func (model *AdminRights) GetCanPinMessages() bool {
+	return model.canPinMessages
+}
+
+func (model *AdminRights) GetCanDeleteMessages() bool {
+	return model.canDeleteMessages
+}
+
+func (model *AdminRights) GetCanManageAdmins() bool {
+	return model.canManageAdmins
+}
+
+func (model *AdminRights) Has(right ChatRights) bool {
+	switch right {
+	case AddMembersRight:
+		return model.canAddMembers
+	case RemoveMembersRight:
+		return model.canRemoveMembers
+	case EditInfoRight:
+		return model.canEditInfo
+	case PinMessagesRight:
+		return model.canPinMessages
+	case DeleteMessagesRight:
+		return model.canDeleteMessages
+	case ManageAdminsRight:
+		return model.canManageAdmins
+	default:
+		return false
+	}
+}
+
+func (model *AdminRights) Contains(rights AdminRights) bool {
+	for _, right := range []ChatRights{AddMembersRight, RemoveMembersRight, EditInfoRight, PinMessagesRight, DeleteMessagesRight, ManageAdminsRight} {
+		if rights.Has(right) && !model.Has(right) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type MemberPermissions struct {
+	canSendMessages bool
+	canSendMedia    bool
+	canAddMembers   bool
+	canPinMessages  bool
+}
+
+func (model *MemberPermissions) GetCanSendMessages() bool {
+	return model.canSendMessages
+}
+
+func (model *MemberPermissions) GetCanSendMedia() bool {
+	return model.canSendMedia
+}
+
+func (model *MemberPermissions) GetCanAddMembers() bool {
+	return model.canAddMembers
+}
+
+func (model *MemberPermissions) GetCanPinMessages() bool {
+	return model.canPinMessages
+}
+
+func (model *MemberPermissions) Has(right ChatRights) bool {
+	switch right {
+	case AddMembersRight:
+		return model.canAddMembers
+	case PinMessagesRight:
+		return model.canPinMessages
+	default:
+		return false
+	}
+}
+
 type ChangeGroupChatData struct {
-	title *string
+	title       *string
+	description *string
+	rules       *string
+	slug        *string
 }
 
 func (model *ChangeGroupChatData) GetTitle() *string {
 	return model.title
 }
 
-type Chat struct {
+func (model *ChangeGroupChatData) GetDescription() *string {
+	return model.description
+}
+
+func (model *ChangeGroupChatData) GetRules() *string {
+	return model.rules
+}
+
+func (model *ChangeGroupChatData) GetSlug() *string {
+	return model.slug
+}
+
+type ChatUserSettings struct {
+	chatId     int
+	userId     int
+	isArchived bool
+	isPinned   bool
+	pinnedAt   *time.Time
+	isMuted    bool
+	mutedUntil *time.Time
+}
+
+func (model *ChatUserSettings) GetChatId() int {
+	return model.chatId
+}
+
+func (model *ChatUserSettings) GetUserId() int {
+	return model.userId
+}
+
+func (model *ChatUserSettings) GetIsArchived() bool {
+	return model.isArchived
+}
+
+func (model *ChatUserSettings) SetIsArchived(isArchived bool) {
+	model.isArchived = isArchived
+}
+
+func (model *ChatUserSettings) GetIsPinned() bool {
+	return model.isPinned
+}
+
+func (model *ChatUserSettings) GetPinnedAt() *time.Time {
+	return model.pinnedAt
+}
+
+func (model *ChatUserSettings) Pin(pinnedAt time.Time) {
+	model.isPinned = true
+	model.pinnedAt = &pinnedAt
+}
+
+func (model *ChatUserSettings) Unpin() {
+	model.isPinned = false
+	model.pinnedAt = nil
+}
+
+func (model *ChatUserSettings) GetIsMuted() bool {
+	return model.isMuted
+}
+
+func (model *ChatUserSettings) GetMutedUntil() *time.Time {
+	return model.mutedUntil
+}
+
+func (model *ChatUserSettings) IsMutedAt(now time.Time) bool {
+	return model.isMuted && (model.mutedUntil == nil || now.Before(*model.mutedUntil))
+}
+
+func (model *ChatUserSettings) Mute(mutedUntil *time.Time) {
+	model.isMuted = true
+	model.mutedUntil = mutedUntil
+}
+
+func (model *ChatUserSettings) Unmute() {
+	model.isMuted = false
+	model.mutedUntil = nil
+}
+
+type ChatFolder struct {
 	id         int
-	avatar     *files.SavedFile
+	userId     int
 	title      string
-	type_      ChatTypes
-	members    []int
-	isArchived bool
-	ownerId    int
-	admins     []int
-	actions    map[ActionTypes][]users.ActionUser
+	position   int
+	chatIds    []int
+	chatTypes  []ChatTypes
+	unreadOnly bool
+	mutedOnly  bool
+}
+
+func (model *ChatFolder) GetId() int {
+	return model.id
+}
+
+func (model *ChatFolder) GetUserId() int {
+	return model.userId
+}
+
+func (model *ChatFolder) GetTitle() string {
+	return model.title
+}
+
+func (model *ChatFolder) GetPosition() int {
+	return model.position
+}
+
+func (model *ChatFolder) SetPosition(position int) {
+	model.position = position
+}
+
+func (model *ChatFolder) GetChatIds() []int {
+	return model.chatIds
+}
+
+func (model *ChatFolder) GetChatTypes() []ChatTypes {
+	return model.chatTypes
+}
+
+func (model *ChatFolder) GetUnreadOnly() bool {
+	return model.unreadOnly
+}
+
+func (model *ChatFolder) GetMutedOnly() bool {
+	return model.mutedOnly
+}
+
+func (model *ChatFolder) HasRules() bool {
+	return len(model.chatTypes) > 0 || model.unreadOnly || model.mutedOnly
+}
+
+func (model *ChatFolder) Update(data ChatFolderData) {
+	model.title = data.GetTitle()
+	model.chatIds = data.GetChatIds()
+	model.chatTypes = data.GetChatTypes()
+	model.unreadOnly = data.GetUnreadOnly()
+	model.mutedOnly = data.GetMutedOnly()
+}
+
+type ChatFolderData struct {
+	title      string
+	chatIds    []int
+	chatTypes  []ChatTypes
+	unreadOnly bool
+	mutedOnly  bool
+}
+
+func (model *ChatFolderData) GetTitle() string {
+	return model.title
+}
+
+func (model *ChatFolderData) GetChatIds() []int {
+	return model.chatIds
+}
+
+func (model *ChatFolderData) GetChatTypes() []ChatTypes {
+	return model.chatTypes
+}
+
+func (model *ChatFolderData) GetUnreadOnly() bool {
+	return model.unreadOnly
+}
+
+func (model *ChatFolderData) GetMutedOnly() bool {
+	return model.mutedOnly
+}
+
+type ChatSystemEvents string
+
+const (
+	JoinedViaLinkSystemEvent       ChatSystemEvents = "joined_via_link"
+	JoinRequestApprovedSystemEvent ChatSystemEvents = "join_request_approved"
+	TitleChangedSystemEvent        ChatSystemEvents = "title_changed"
+	DescriptionChangedSystemEvent  ChatSystemEvents = "description_changed"
+	RulesChangedSystemEvent        ChatSystemEvents = "rules_changed"
+	SlugChangedSystemEvent         ChatSystemEvents = "slug_changed"
+)
+
+type JoinRequestStatuses string
+
+const (
+	PendingJoinRequestStatus  JoinRequestStatuses = "pending"
+	ApprovedJoinRequestStatus JoinRequestStatuses = "approved"
+	DeclinedJoinRequestStatus JoinRequestStatuses = "declined"
+)
+
+type ChatJoinRequest struct {
+	id           int
+	chatId       int
+	userId       int
+	inviteLinkId *int
+	status       JoinRequestStatuses
+	resolvedBy   *int
+	createdAt    time.Time
+	resolvedAt   *time.Time
+}
+
+func (model *ChatJoinRequest) GetId() int {
+	return model.id
+}
+
+func (model *ChatJoinRequest) GetChatId() int {
+	return model.chatId
+}
+
+func (model *ChatJoinRequest) GetUserId() int {
+	return model.userId
+}
+
+func (model *ChatJoinRequest) GetInviteLinkId() *int {
+	return model.inviteLinkId
+}
+
+func (model *ChatJoinRequest) GetStatus() JoinRequestStatuses {
+	return model.status
+}
+
+func (model *ChatJoinRequest) GetResolvedBy() *int {
+	return model.resolvedBy
+}
+
+func (model *ChatJoinRequest) GetCreatedAt() time.Time {
+	return model.createdAt
+}
+
+func (model *ChatJoinRequest) GetResolvedAt() *time.Time {
+	return model.resolvedAt
+}
+
+func (model *ChatJoinRequest) IsPending() bool {
+	return model.status == PendingJoinRequestStatus
+}
+
+func (model *ChatJoinRequest) Resolve(status JoinRequestStatuses, resolvedBy int, resolvedAt time.Time) {
+	model.status = status
+	model.resolvedBy = &resolvedBy
+	model.resolvedAt = &resolvedAt
+}
+
+type ChatBan struct {
+	id        int
+	chatId    int
+	userId    int
+	bannedBy  int
+	expiresAt *time.Time
+	createdAt time.Time
+}
+
+func (model *ChatBan) GetId() int {
+	return model.id
+}
+
+func (model *ChatBan) GetChatId() int {
+	return model.chatId
+}
+
+func (model *ChatBan) GetUserId() int {
+	return model.userId
+}
+
+func (model *ChatBan) GetBannedBy() int {
+	return model.bannedBy
+}
+
+func (model *ChatBan) GetExpiresAt() *time.Time {
+	return model.expiresAt
+}
+
+func (model *ChatBan) GetCreatedAt() time.Time {
+	return model.createdAt
+}
+
+func (model *ChatBan) IsActiveAt(now time.Time) bool {
+	return model.expiresAt == nil || model.expiresAt.After(now)
+}
+
+type ChatInviteLink struct {
+	id               int
+	chatId           int
+	code             string
+	createdBy        int
+	expiresAt        *time.Time
+	usageLimit       *int
+	usageCount       int
+	requiresApproval bool
+	revokedAt        *time.Time
+	createdAt        time.Time
+}
+
+func (model *ChatInviteLink) GetId() int {
+	return model.id
+}
+
+func (model *ChatInviteLink) GetChatId() int {
+	return model.chatId
+}
+
+func (model *ChatInviteLink) GetCode() string {
+	return model.code
+}
+
+func (model *ChatInviteLink) GetCreatedBy() int {
+	return model.createdBy
+}
+
+func (model *ChatInviteLink) GetExpiresAt() *time.Time {
+	return model.expiresAt
+}
+
+func (model *ChatInviteLink) GetUsageLimit() *int {
+	return model.usageLimit
+}
+
+func (model *ChatInviteLink) GetUsageCount() int {
+	return model.usageCount
+}
+
+func (model *ChatInviteLink) GetRequiresApproval() bool {
+	return model.requiresApproval
+}
+
+func (model *ChatInviteLink) GetRevokedAt() *time.Time {
+	return model.revokedAt
+}
+
+func (model *ChatInviteLink) Revoke(revokedAt time.Time) {
+	model.revokedAt = &revokedAt
+}
+
+func (model *ChatInviteLink) GetCreatedAt() time.Time {
+	return model.createdAt
+}
+
+func (model *ChatInviteLink) IsRevoked() bool {
+	return model.revokedAt != nil
+}
+
+func (model *ChatInviteLink) IsExpired(now time.Time) bool {
+	return model.expiresAt != nil && !now.Before(*model.expiresAt)
+}
+
+func (model *ChatInviteLink) IsExhausted() bool {
+	return model.usageLimit != nil && model.usageCount >= *model.usageLimit
+}
+
+type CreateInviteLinkData struct {
+	expiresAt        *time.Time
+	usageLimit       *int
+	requiresApproval bool
+}
+
+func (model *CreateInviteLinkData) GetExpiresAt() *time.Time {
+	return model.expiresAt
+}
+
+func (model *CreateInviteLinkData) GetUsageLimit() *int {
+	return model.usageLimit
+}
+
+func (model *CreateInviteLinkData) GetRequiresApproval() bool {
+	return model.requiresApproval
+}
+
+type ChatPinnedMessage struct {
+	messageId int
+	senderId  int
+	content   *string
+	pinnedBy  int
+	pinnedAt  time.Time
+}
+
+func (model *ChatPinnedMessage) GetMessageId() int {
+	return model.messageId
+}
+
+func (model *ChatPinnedMessage) GetSenderId() int {
+	return model.senderId
+}
+
+func (model *ChatPinnedMessage) GetContent() *string {
+	return model.content
+}
+
+func (model *ChatPinnedMessage) GetPinnedBy() int {
+	return model.pinnedBy
+}
+
+func (model *ChatPinnedMessage) GetPinnedAt() time.Time {
+	return model.pinnedAt
+}
+
+type ChatUnreadCounters struct {
+	unreadCount         int
+	unreadMentionsCount int
+}
+
+func (model *ChatUnreadCounters) GetUnreadCount() int {
+	return model.unreadCount
+}
+
+func (model *ChatUnreadCounters) GetUnreadMentionsCount() int {
+	return model.unreadMentionsCount
+}
+
+type Chat struct {
+	id             int
+	avatar         *files.SavedFile
+	title          string
+	type_          ChatTypes
+	slug           *string
+	description    *string
+	rules          *string
+	members        []int
+	isArchived     bool
+	ownerId        int
+	admins         []int
+	actions        map[ActionTypes][]users.ActionUser
+	pinnedMessage  *ChatPinnedMessage
+	unreadCounters ChatUnreadCounters
+
+	adminsRights      map[int]AdminRights
+	memberPermissions MemberPermissions
+	slowModeInterval  time.Duration
+	isUserDeleted     bool
+
+	userSettings ChatUserSettings
 }
 
 func (model *Chat) GetId() int {
@@ -74,10 +600,38 @@ func (model *Chat) SetType(type_ ChatTypes) {
 	model.type_ = type_
 }
 
+func (model *Chat) GetDescription() *string {
+	return model.description
+}
+
+func (model *Chat) SetDescription(description *string) {
+	model.description = description
+}
+
+func (model *Chat) GetRules() *string {
+	return model.rules
+}
+
+func (model *Chat) SetRules(rules *string) {
+	model.rules = rules
+}
+
+func (model *Chat) GetSlug() *string {
+	return model.slug
+}
+
+func (model *Chat) SetSlug(slug *string) {
+	model.slug = slug
+}
+
 func (model *Chat) GetMembers() []int {
 	return model.members
 }
 
+func (model *Chat) GetSubscribersCount() int {
+	return len(model.members)
+}
+
 func (model *Chat) SetMembers(members []int) {
 	model.members = members
 }
@@ -94,32 +648,21 @@ func (model *Chat) Unarchive() {
 	model.isArchived = false
 }
 
+func (model *Chat) GetUserSettings() ChatUserSettings {
+	return model.userSettings
+}
+
+func (model *Chat) SetUserSettings(settings ChatUserSettings) {
+	model.userSettings = settings
+	model.isArchived = settings.GetIsArchived()
+}
+
 func (model *Chat) GetOwnerId() int {
 	return model.ownerId
 }
 
 func (model *Chat) SetOwnerId(ownerId int) {
-	var newMembers []int
-	for _, member := range model.members {
-		if member == model.ownerId {
-			continue
-		}
-
-		newMembers = append(newMembers, member)
-	}
-
-	var newAdmins []int
-	for _, admin := range model.admins {
-		if admin == model.ownerId {
-			continue
-		}
-
-		newAdmins = append(newAdmins, admin)
-	}
-
 	model.ownerId = ownerId
-	model.members = newMembers
-	model.admins = newAdmins
 }
 
 func (model *Chat) GetAdmins() []int {
@@ -153,12 +696,80 @@ func (model *Chat) SetupActions(actions map[ActionTypes][]users.ActionUser) {
 	model.actions = actions
 }
 
+func (model *Chat) GetPinnedMessage() *ChatPinnedMessage {
+	return model.pinnedMessage
+}
+
+func (model *Chat) SetPinnedMessage(pinnedMessage *ChatPinnedMessage) {
+	model.pinnedMessage = pinnedMessage
+}
+
+func (model *Chat) GetAdminRights(adminId int) AdminRights {
+	if adminId == model.ownerId {
+		return FullAdminRights()
+	}
+
+	return model.adminsRights[adminId]
+}
+
+func (model *Chat) GetAdminsRights() map[int]AdminRights {
+	adminsRights := make(map[int]AdminRights)
+	for _, admin := range model.admins {
+		adminsRights[admin] = model.GetAdminRights(admin)
+	}
+
+	return adminsRights
+}
+
+func (model *Chat) SetAdminRights(adminId int, rights AdminRights) {
+	if model.adminsRights == nil {
+		model.adminsRights = make(map[int]AdminRights)
+	}
+
+	model.adminsRights[adminId] = rights
+}
+
+func (model *Chat) GetMemberPermissions() MemberPermissions {
+	return model.memberPermissions
+}
+
+func (model *Chat) SetMemberPermissions(permissions MemberPermissions) {
+	model.memberPermissions = permissions
+}
+
+func (model *Chat) GetSlowModeInterval() time.Duration {
+	return model.slowModeInterval
+}
+
+func (model *Chat) SetSlowModeInterval(interval time.Duration) {
+	model.slowModeInterval = interval
+}
+
+// GetIsUserDeleted reports that one of the direct chat participants deleted
+// their account. The chat is kept for the other participant as read only.
+func (model *Chat) GetIsUserDeleted() bool {
+	return model.isUserDeleted
+}
+
+func (model *Chat) SetIsUserDeleted(isUserDeleted bool) {
+	model.isUserDeleted = isUserDeleted
+}
+
+func (model *Chat) GetUnreadCounters() ChatUnreadCounters {
+	return model.unreadCounters
+}
+
+func (model *Chat) SetUnreadCounters(unreadCounters ChatUnreadCounters) {
+	model.unreadCounters = unreadCounters
+}
+
 type CreateChatData struct {
 	avatar     *files.UploadingFile
 	title      *string
 	membersIds []int
 	userId     *int
 	type_      ChatTypes
+	slug       *string
 }
 
 func (data *CreateChatData) GetAvatar() *files.UploadingFile {
@@ -181,9 +792,198 @@ func (data *CreateChatData) GetType() ChatTypes {
 	return data.type_
 }
 
-func NewChangeGroupChatData(title *string) ChangeGroupChatData {
+func (data *CreateChatData) GetSlug() *string {
+	return data.slug
+}
+
+func NewChangeGroupChatData(title *string, description *string, rules *string, slug *string) ChangeGroupChatData {
 	return ChangeGroupChatData{
-		title: title,
+		title:       title,
+		description: description,
+		rules:       rules,
+		slug:        slug,
+	}
+}
+
+func NewChatPinnedMessage(messageId int, senderId int, content *string, pinnedBy int, pinnedAt time.Time) ChatPinnedMessage {
+	return ChatPinnedMessage{
+		messageId: messageId,
+		senderId:  senderId,
+		content:   content,
+		pinnedBy:  pinnedBy,
+		pinnedAt:  pinnedAt,
+	}
+}
+
+func NewChatInviteLink(
+	id int,
+	chatId int,
+	code string,
+	createdBy int,
+	expiresAt *time.Time,
+	usageLimit *int,
+	usageCount int,
+	requiresApproval bool,
+	revokedAt *time.Time,
+	createdAt time.Time,
+) ChatInviteLink {
+	return ChatInviteLink{
+		id:               id,
+		chatId:           chatId,
+		code:             code,
+		createdBy:        createdBy,
+		expiresAt:        expiresAt,
+		usageLimit:       usageLimit,
+		usageCount:       usageCount,
+		requiresApproval: requiresApproval,
+		revokedAt:        revokedAt,
+		createdAt:        createdAt,
+	}
+}
+
+func NewChatFolder(
+	id int,
+	userId int,
+	title string,
+	position int,
+	chatIds []int,
+	chatTypes []ChatTypes,
+	unreadOnly bool,
+	mutedOnly bool,
+) ChatFolder {
+	return ChatFolder{
+		id:         id,
+		userId:     userId,
+		title:      title,
+		position:   position,
+		chatIds:    chatIds,
+		chatTypes:  chatTypes,
+		unreadOnly: unreadOnly,
+		mutedOnly:  mutedOnly,
+	}
+}
+
+func NewChatFolderData(title string, chatIds []int, chatTypes []ChatTypes, unreadOnly bool, mutedOnly bool) ChatFolderData {
+	return ChatFolderData{
+		title:      title,
+		chatIds:    chatIds,
+		chatTypes:  chatTypes,
+		unreadOnly: unreadOnly,
+		mutedOnly:  mutedOnly,
+	}
+}
+
+func NewChatUserSettings(
+	chatId int,
+	userId int,
+	isArchived bool,
+	isPinned bool,
+	pinnedAt *time.Time,
+	isMuted bool,
+	mutedUntil *time.Time,
+) ChatUserSettings {
+	return ChatUserSettings{
+		chatId:     chatId,
+		userId:     userId,
+		isArchived: isArchived,
+		isPinned:   isPinned,
+		pinnedAt:   pinnedAt,
+		isMuted:    isMuted,
+		mutedUntil: mutedUntil,
+	}
+}
+
+func NewChatJoinRequest(
+	id int,
+	chatId int,
+	userId int,
+	inviteLinkId *int,
+	status JoinRequestStatuses,
+	resolvedBy *int,
+	createdAt time.Time,
+	resolvedAt *time.Time,
+) ChatJoinRequest {
+	return ChatJoinRequest{
+		id:           id,
+		chatId:       chatId,
+		userId:       userId,
+		inviteLinkId: inviteLinkId,
+		status:       status,
+		resolvedBy:   resolvedBy,
+		createdAt:    createdAt,
+		resolvedAt:   resolvedAt,
+	}
+}
+
+func NewChatBan(
+	id int,
+	chatId int,
+	userId int,
+	bannedBy int,
+	expiresAt *time.Time,
+	createdAt time.Time,
+) ChatBan {
+	return ChatBan{
+		id:        id,
+		chatId:    chatId,
+		userId:    userId,
+		bannedBy:  bannedBy,
+		expiresAt: expiresAt,
+		createdAt: createdAt,
+	}
+}
+
+func NewCreateInviteLinkData(expiresAt *time.Time, usageLimit *int, requiresApproval bool) CreateInviteLinkData {
+	return CreateInviteLinkData{
+		expiresAt:        expiresAt,
+		usageLimit:       usageLimit,
+		requiresApproval: requiresApproval,
+	}
+}
+
+func NewAdminRights(
+	canAddMembers bool,
+	canRemoveMembers bool,
+	canEditInfo bool,
+	canPinMessages bool,
+	canDeleteMessages bool,
+	canManageAdmins bool,
+) AdminRights {
+	return AdminRights{
+		canAddMembers:     canAddMembers,
+		canRemoveMembers:  canRemoveMembers,
+		canEditInfo:       canEditInfo,
+		canPinMessages:    canPinMessages,
+		canDeleteMessages: canDeleteMessages,
+		canManageAdmins:   canManageAdmins,
+	}
+}
+
+func FullAdminRights() AdminRights {
+	return NewAdminRights(true, true, true, true, true, true)
+}
+
+func NoAdminRights() AdminRights {
+	return NewAdminRights(false, false, false, false, false, false)
+}
+
+func NewMemberPermissions(canSendMessages bool, canSendMedia bool, canAddMembers bool, canPinMessages bool) MemberPermissions {
+	return MemberPermissions{
+		canSendMessages: canSendMessages,
+		canSendMedia:    canSendMedia,
+		canAddMembers:   canAddMembers,
+		canPinMessages:  canPinMessages,
+	}
+}
+
+func DefaultMemberPermissions() MemberPermissions {
+	return NewMemberPermissions(true, true, false, false)
+}
+
+func NewChatUnreadCounters(unreadCount int, unreadMentionsCount int) ChatUnreadCounters {
+	return ChatUnreadCounters{
+		unreadCount:         unreadCount,
+		unreadMentionsCount: unreadMentionsCount,
 	}
 }
 
@@ -197,6 +997,8 @@ func NewChat(id int, avatar *files.SavedFile, title string, type_ ChatTypes, mem
 		isArchived: isArchived,
 		ownerId:    ownerId,
 		admins:     admins,
+
+		memberPermissions: DefaultMemberPermissions(),
 	}
 }
 
@@ -206,6 +1008,7 @@ func NewCreateChatData(
 	title *string,
 	membersIds []int,
 	userId *int,
+	slug *string,
 ) CreateChatData {
 	return CreateChatData{
 		type_:      chatType,
@@ -213,5 +1016,6 @@ func NewCreateChatData(
 		title:      title,
 		membersIds: membersIds,
 		userId:     userId,
+		slug:       slug,
 	}
 }
@@ -8,28 +8,67 @@ import (
 
 type ChatsPort interface {
 	GetById(id int) (*Chat, error)
+	GetBySlug(slug string) (*Chat, error)
 	GetByIdForUser(id int, userId int) (*Chat, error)
 	GetByIdsForUser(ids []int, userId int) []Chat
-	GetUserAll(userId int, page int, perPage int) utils.PaginatedResponse[Chat]
+	GetAllByMember(userId int) []Chat
+	GetSavedMessagesChat(userId int) (*Chat, error)
+	GetUserAll(userId int, includeArchived bool, folder *ChatFolder, page int, perPage int) utils.PaginatedResponse[Chat]
+	GetUserArchived(userId int, page int, perPage int) utils.PaginatedResponse[Chat]
 	Save(chat Chat) (*Chat, error)
 	HasDeletedUserChat(chat Chat) bool
 	RestoreChat(chat Chat) (*Chat, error)
 	CheckChatExists(chat Chat) bool
 	Delete(chat Chat)
 	SearchChats(userId int, query string, page int, perPage int) utils.PaginatedResponse[Chat]
+	GetUnreadCounters(chatIds []int, userId int) map[int]ChatUnreadCounters
+	GetTotalUnread(userId int) ChatUnreadCounters
+	GetUserSettings(chatIds []int, userId int) map[int]ChatUserSettings
+	SaveUserSettings(settings ChatUserSettings) error
+	GetUserFolders(userId int) []ChatFolder
+	GetFolderForUser(folderId int, userId int) (*ChatFolder, error)
+	SaveFolder(folder ChatFolder) (*ChatFolder, error)
+	DeleteFolder(folder ChatFolder)
+	SaveFoldersOrder(userId int, folderIds []int) error
+	GetChatBans(chatId int) []ChatBan
+	SaveBan(ban ChatBan) (*ChatBan, error)
+	DeleteBan(chatId int, userId int)
+	GetInviteLinkByCode(code string) (*ChatInviteLink, error)
+	SaveInviteLink(link ChatInviteLink) (*ChatInviteLink, error)
+	UseInviteLink(link ChatInviteLink) error
+	GetJoinRequestById(id int) (*ChatJoinRequest, error)
+	GetPendingJoinRequest(chatId int, userId int) (*ChatJoinRequest, error)
+	GetChatPendingJoinRequests(chatId int) []ChatJoinRequest
+	SaveJoinRequest(request ChatJoinRequest) (*ChatJoinRequest, error)
 }
 
 type ChatEventsPort interface {
-	SendChatCreated(chat Chat)
-	SendChatDeleted(chat Chat)
-	SendChatUserAction(chat Chat)
-	SendChatChanged(chat Chat)
+	SendChatCreated(chat Chat) error
+	SendChatDeleted(chat Chat) error
+	SendChatUserAction(chat Chat) error
+	SendChatChanged(chat Chat) error
+	// SendChatChangedForUser delivers the chat as seen by a single member,
+	// with the member's unread counters, for changes that only this member's
+	// view of the chat reflects.
+	SendChatChangedForUser(chat Chat, userId int) error
+	SendChatArchiveChanged(chat Chat, userId int) error
+	// SendChatMembersRemoved notifies the users that quit or were removed from
+	// the chat, they aren't members anymore and don't get SendChatChanged.
+	SendChatMembersRemoved(chat Chat, userIds []int) error
+	SendJoinRequestResolved(request ChatJoinRequest) error
+	SendFoldersChanged(userId int, folders []ChatFolder) error
+}
+
+type ChatSystemMessagesPort interface {
+	SendSystemEvent(chat Chat, actorId int, event ChatSystemEvents, value *string) error
 }
 
 type UserActionsPort interface {
 	AddChatActionUser(chat Chat, user users.User, actionType ActionTypes) map[ActionTypes][]users.ActionUser
 	RemoveChatActionUser(chat Chat, userId int, actionType ActionTypes) map[ActionTypes][]users.ActionUser
 	GetAllChatActionsUsers(chat Chat) map[ActionTypes][]users.ActionUser
+	RemoveUserFromChatActions(chat Chat, userId int)
+	RefreshChatActionUser(chat Chat, user users.User)
 }
 
 func NewCreateChatHandler(
@@ -175,10 +214,15 @@ func NewQuitChatHandler(chatsPort ChatsPort, chatEventsPort ChatEventsPort) Quit
 	}
 }
 
-func NewChangeGroupChatHandler(chatsPort ChatsPort, chatEventsPort ChatEventsPort) ChangeGroupChatHandler {
+func NewChangeGroupChatHandler(
+	chatsPort ChatsPort,
+	chatEventsPort ChatEventsPort,
+	systemMessagesPort ChatSystemMessagesPort,
+) ChangeGroupChatHandler {
 	return ChangeGroupChatHandler{
-		chatsPort:      chatsPort,
-		chatEventsPort: chatEventsPort,
+		chatsPort:          chatsPort,
+		chatEventsPort:     chatEventsPort,
+		systemMessagesPort: systemMessagesPort,
 	}
 }
 
@@ -190,6 +234,10 @@ func NewUpdateGroupChatAvatar(chatsPort ChatsPort, filesPort files.FilesPort, ch
 	}
 }
 
+func NewGetTotalUnreadHandler(chatsPort ChatsPort) GetTotalUnreadHandler {
+	return GetTotalUnreadHandler{chatsPort: chatsPort}
+}
+
 func NewSearchChatsHandler(chatsPort ChatsPort, usersPort users.UsersPort, userActionsPort UserActionsPort) SearchChatsHandler {
 	return SearchChatsHandler{
 		chatsPort:       chatsPort,
@@ -197,3 +245,261 @@ func NewSearchChatsHandler(chatsPort ChatsPort, usersPort users.UsersPort, userA
 		userActionsPort: userActionsPort,
 	}
 }
+
+func NewSetAdminRightsHandler(
+	chatsPort ChatsPort,
+	chatEventsPort ChatEventsPort,
+) SetAdminRightsHandler {
+	return SetAdminRightsHandler{
+		chatsPort:      chatsPort,
+		chatEventsPort: chatEventsPort,
+	}
+}
+
+func NewSetMemberPermissionsHandler(
+	chatsPort ChatsPort,
+	chatEventsPort ChatEventsPort,
+) SetMemberPermissionsHandler {
+	return SetMemberPermissionsHandler{
+		chatsPort:      chatsPort,
+		chatEventsPort: chatEventsPort,
+	}
+}
+
+func NewSetSlowModeHandler(
+	chatsPort ChatsPort,
+	chatEventsPort ChatEventsPort,
+) SetSlowModeHandler {
+	return SetSlowModeHandler{
+		chatsPort:      chatsPort,
+		chatEventsPort: chatEventsPort,
+	}
+}
+
+func NewCreateInviteLinkHandler(
+	chatsPort ChatsPort,
+) CreateInviteLinkHandler {
+	return CreateInviteLinkHandler{
+		chatsPort: chatsPort,
+	}
+}
+
+func NewRevokeInviteLinkHandler(
+	chatsPort ChatsPort,
+) RevokeInviteLinkHandler {
+	return RevokeInviteLinkHandler{
+		chatsPort: chatsPort,
+	}
+}
+
+func NewJoinChatByInviteHandler(
+	chatsPort ChatsPort,
+	usersPort users.UsersPort,
+	chatEventsPort ChatEventsPort,
+	systemMessagesPort ChatSystemMessagesPort,
+) JoinChatByInviteHandler {
+	return JoinChatByInviteHandler{
+		chatsPort:          chatsPort,
+		usersPort:          usersPort,
+		chatEventsPort:     chatEventsPort,
+		systemMessagesPort: systemMessagesPort,
+	}
+}
+
+func NewRequestChatJoinHandler(
+	chatsPort ChatsPort,
+) RequestChatJoinHandler {
+	return RequestChatJoinHandler{
+		chatsPort: chatsPort,
+	}
+}
+
+func NewGetJoinRequestsHandler(
+	chatsPort ChatsPort,
+) GetJoinRequestsHandler {
+	return GetJoinRequestsHandler{
+		chatsPort: chatsPort,
+	}
+}
+
+func NewResolveJoinRequestHandler(
+	chatsPort ChatsPort,
+	usersPort users.UsersPort,
+	chatEventsPort ChatEventsPort,
+	systemMessagesPort ChatSystemMessagesPort,
+) ResolveJoinRequestHandler {
+	return ResolveJoinRequestHandler{
+		chatsPort:          chatsPort,
+		usersPort:          usersPort,
+		chatEventsPort:     chatEventsPort,
+		systemMessagesPort: systemMessagesPort,
+	}
+}
+
+func NewTransferChatOwnershipHandler(
+	chatsPort ChatsPort,
+	chatEventsPort ChatEventsPort,
+) TransferChatOwnershipHandler {
+	return TransferChatOwnershipHandler{
+		chatsPort:      chatsPort,
+		chatEventsPort: chatEventsPort,
+	}
+}
+
+func NewGetArchivedChatsHandler(
+	chatsPort ChatsPort,
+	usersPort users.UsersPort,
+	userActionsPort UserActionsPort,
+) GetArchivedChatsHandler {
+	return GetArchivedChatsHandler{
+		chatsPort:       chatsPort,
+		usersPort:       usersPort,
+		userActionsPort: userActionsPort,
+	}
+}
+
+func NewSetChatArchivedHandler(
+	chatsPort ChatsPort,
+	chatEventsPort ChatEventsPort,
+) SetChatArchivedHandler {
+	return SetChatArchivedHandler{
+		chatsPort:      chatsPort,
+		chatEventsPort: chatEventsPort,
+	}
+}
+
+func NewSetChatPinnedHandler(
+	chatsPort ChatsPort,
+) SetChatPinnedHandler {
+	return SetChatPinnedHandler{
+		chatsPort: chatsPort,
+	}
+}
+
+func NewSetChatMutedHandler(
+	chatsPort ChatsPort,
+) SetChatMutedHandler {
+	return SetChatMutedHandler{
+		chatsPort: chatsPort,
+	}
+}
+
+func NewGetChatFoldersHandler(
+	chatsPort ChatsPort,
+) GetChatFoldersHandler {
+	return GetChatFoldersHandler{
+		chatsPort: chatsPort,
+	}
+}
+
+func NewCreateChatFolderHandler(
+	chatsPort ChatsPort,
+	chatEventsPort ChatEventsPort,
+) CreateChatFolderHandler {
+	return CreateChatFolderHandler{
+		chatsPort:      chatsPort,
+		chatEventsPort: chatEventsPort,
+	}
+}
+
+func NewUpdateChatFolderHandler(
+	chatsPort ChatsPort,
+	chatEventsPort ChatEventsPort,
+) UpdateChatFolderHandler {
+	return UpdateChatFolderHandler{
+		chatsPort:      chatsPort,
+		chatEventsPort: chatEventsPort,
+	}
+}
+
+func NewDeleteChatFolderHandler(
+	chatsPort ChatsPort,
+	chatEventsPort ChatEventsPort,
+) DeleteChatFolderHandler {
+	return DeleteChatFolderHandler{
+		chatsPort:      chatsPort,
+		chatEventsPort: chatEventsPort,
+	}
+}
+
+func NewReorderChatFoldersHandler(
+	chatsPort ChatsPort,
+	chatEventsPort ChatEventsPort,
+) ReorderChatFoldersHandler {
+	return ReorderChatFoldersHandler{
+		chatsPort:      chatsPort,
+		chatEventsPort: chatEventsPort,
+	}
+}
+
+func NewGetPublicChannelHandler(
+	chatsPort ChatsPort,
+) GetPublicChannelHandler {
+	return GetPublicChannelHandler{
+		chatsPort: chatsPort,
+	}
+}
+
+func NewSubscribeChannelHandler(
+	chatsPort ChatsPort,
+	usersPort users.UsersPort,
+	chatEventsPort ChatEventsPort,
+) SubscribeChannelHandler {
+	return SubscribeChannelHandler{
+		chatsPort:      chatsPort,
+		usersPort:      usersPort,
+		chatEventsPort: chatEventsPort,
+	}
+}
+
+func NewBanChatMemberHandler(
+	chatsPort ChatsPort,
+	chatEventsPort ChatEventsPort,
+) BanChatMemberHandler {
+	return BanChatMemberHandler{
+		chatsPort:      chatsPort,
+		chatEventsPort: chatEventsPort,
+	}
+}
+
+func NewUnbanChatMemberHandler(
+	chatsPort ChatsPort,
+) UnbanChatMemberHandler {
+	return UnbanChatMemberHandler{
+		chatsPort: chatsPort,
+	}
+}
+
+func NewGetBannedMembersHandler(
+	chatsPort ChatsPort,
+) GetBannedMembersHandler {
+	return GetBannedMembersHandler{
+		chatsPort: chatsPort,
+	}
+}
+
+func NewDeleteUserDataHandler(
+	chatsPort ChatsPort,
+	chatEventsPort ChatEventsPort,
+	userActionsPort UserActionsPort,
+) DeleteUserDataHandler {
+	return DeleteUserDataHandler{
+		chatsPort:       chatsPort,
+		chatEventsPort:  chatEventsPort,
+		userActionsPort: userActionsPort,
+	}
+}
+
+func NewUpdateUserDataHandler(
+	chatsPort ChatsPort,
+	chatEventsPort ChatEventsPort,
+	usersPort users.UsersPort,
+	userActionsPort UserActionsPort,
+) UpdateUserDataHandler {
+	return UpdateUserDataHandler{
+		chatsPort:       chatsPort,
+		chatEventsPort:  chatEventsPort,
+		usersPort:       usersPort,
+		userActionsPort: userActionsPort,
+	}
+}
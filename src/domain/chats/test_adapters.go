@@ -1,13 +1,20 @@
 package chats
 
 import (
+	"errors"
+	"fmt"
+	"maps"
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/chack-check/chats-service/domain/files"
 	"github.com/chack-check/chats-service/domain/users"
 	"github.com/chack-check/chats-service/domain/utils"
 )
 
+var errTestNotFound = fmt.Errorf("test record not found")
+
 var existingAvatars = []files.SavedFile{
 	files.NewSavedFile(
 		"original_url_1",
@@ -18,141 +25,597 @@ var existingAvatars = []files.SavedFile{
 }
 
 var deletedChats = []Chat{
-	{
-		id:         3,
-		avatar:     nil,
-		title:      "",
-		type_:      UserChatType,
-		members:    []int{1, 2},
-		isArchived: false,
-		ownerId:    0,
-		admins:     []int{},
-		actions:    map[ActionTypes][]users.ActionUser{},
-	},
+	NewChat(3, nil, "", UserChatType, []int{1, 3}, false, 0, []int{}),
 }
 
 var existingChats = []Chat{
-	{
-		id:         1,
-		avatar:     nil,
-		title:      "",
-		type_:      UserChatType,
-		members:    []int{1, 2},
-		isArchived: false,
-		ownerId:    0,
-		admins:     []int{},
-		actions:    map[ActionTypes][]users.ActionUser{},
-	},
-	{
-		id:         2,
-		avatar:     &existingAvatars[0],
-		title:      "group chat 1",
-		type_:      GroupChatType,
-		members:    []int{1, 2},
-		isArchived: false,
-		ownerId:    1,
-		admins:     []int{1},
-		actions:    map[ActionTypes][]users.ActionUser{},
-	},
-}
-
-type TestChatsAdapter struct{}
+	NewChat(1, nil, "", UserChatType, []int{1, 2}, false, 0, []int{}),
+	NewChat(2, &existingAvatars[0], "group chat 1", GroupChatType, []int{1, 2}, false, 1, []int{1}),
+}
 
-func (adapter *TestChatsAdapter) GetById(id int) (*Chat, error) {
-	var chat *Chat
-	for _, dbChat := range existingChats {
-		if dbChat.GetId() == id {
-			chat = &dbChat
-		}
+var existingUsers = []users.User{
+	users.NewUser(1, nil, "Last 1", "First 1", nil, "user_1"),
+	users.NewUser(2, nil, "Last 2", "First 2", nil, "user_2"),
+	users.NewUser(3, nil, "Last 3", "First 3", nil, "user_3"),
+	users.NewUser(4, nil, "Last 4", "First 4", nil, "user_4"),
+}
+
+// NewTestGroupChat returns group chat 10 owned by user 1, where user 2 is a
+// member and user 3 is an admin who can't remove members or manage admins.
+func NewTestGroupChat() Chat {
+	chat := NewChat(10, nil, "group chat", GroupChatType, []int{1, 2, 3}, false, 1, []int{1, 3})
+	chat.SetAdminRights(3, NewAdminRights(true, false, true, true, true, false))
+	return chat
+}
+
+// cloneChat copies the slices and maps of a chat, so a handler changing the
+// chat it got from the adapter doesn't change the stored one.
+func cloneChat(chat Chat) Chat {
+	chat.members = slices.Clone(chat.members)
+	chat.admins = slices.Clone(chat.admins)
+	chat.adminsRights = maps.Clone(chat.adminsRights)
+	return chat
+}
+
+type TestChatsAdapter struct {
+	chats        []Chat
+	deletedChats []Chat
+	inviteLinks  []ChatInviteLink
+	joinRequests []ChatJoinRequest
+	// unreadCounters holds the counters of every user by chat id.
+	unreadCounters      map[int]map[int]ChatUnreadCounters
+	unreadCountersCalls int
+	userSettings        []ChatUserSettings
+	folders             []ChatFolder
+	bans                []ChatBan
+}
+
+// NewTestChatsAdapter returns an adapter holding the existing chats and the
+// given ones, which replace existing chats with the same id.
+func NewTestChatsAdapter(chats ...Chat) *TestChatsAdapter {
+	adapter := &TestChatsAdapter{}
+	for _, chat := range existingChats {
+		adapter.chats = append(adapter.chats, cloneChat(chat))
+	}
+	for _, chat := range deletedChats {
+		adapter.deletedChats = append(adapter.deletedChats, cloneChat(chat))
+	}
+	for _, chat := range chats {
+		adapter.Save(chat)
 	}
 
-	return chat, nil
+	return adapter
 }
 
-func (adapter *TestChatsAdapter) GetByIdForUser(id int, userId int) (*Chat, error) {
-	var chat *Chat
-	for _, dbChat := range existingChats {
-		if dbChat.GetId() == id && slices.Contains(dbChat.GetMembers(), userId) {
-			chat = &dbChat
+func (adapter *TestChatsAdapter) findChat(predicate func(chat Chat) bool) (*Chat, error) {
+	for _, chat := range adapter.chats {
+		if predicate(chat) {
+			foundChat := cloneChat(chat)
+			return &foundChat, nil
 		}
 	}
 
-	return chat, nil
+	return nil, errTestNotFound
 }
 
-func (adapter *TestChatsAdapter) GetByIdsForUser(ids []int, userId int) []Chat {
+func (adapter *TestChatsAdapter) filterChats(predicate func(chat Chat) bool) []Chat {
 	var chats []Chat
-	for _, dbChat := range existingChats {
-		if slices.Contains(ids, dbChat.GetId()) && slices.Contains(dbChat.GetMembers(), userId) {
-			chats = append(chats, dbChat)
+	for _, chat := range adapter.chats {
+		if predicate(chat) {
+			chats = append(chats, cloneChat(chat))
 		}
 	}
 
 	return chats
 }
 
-func (adapter *TestChatsAdapter) GetUserAll(userId int, page int, perPage int) utils.PaginatedResponse[Chat] {
-	var chats []Chat
-	for _, dbChat := range existingChats {
-		if slices.Contains(dbChat.GetMembers(), userId) {
-			chats = append(chats, dbChat)
+func (adapter *TestChatsAdapter) GetById(id int) (*Chat, error) {
+	return adapter.findChat(func(chat Chat) bool {
+		return chat.GetId() == id
+	})
+}
+
+func (adapter *TestChatsAdapter) GetBySlug(slug string) (*Chat, error) {
+	return adapter.findChat(func(chat Chat) bool {
+		return chat.GetSlug() != nil && *chat.GetSlug() == slug
+	})
+}
+
+func (adapter *TestChatsAdapter) GetByIdForUser(id int, userId int) (*Chat, error) {
+	chat, err := adapter.findChat(func(chat Chat) bool {
+		return chat.GetId() == id && slices.Contains(chat.GetMembers(), userId)
+	})
+	if err != nil {
+		return nil, errors.Join(ErrChatNotFound, err)
+	}
+
+	return chat, nil
+}
+
+func (adapter *TestChatsAdapter) GetByIdsForUser(ids []int, userId int) []Chat {
+	return adapter.filterChats(func(chat Chat) bool {
+		return slices.Contains(ids, chat.GetId()) && slices.Contains(chat.GetMembers(), userId)
+	})
+}
+
+func (adapter *TestChatsAdapter) GetAllByMember(userId int) []Chat {
+	return adapter.filterChats(func(chat Chat) bool {
+		return slices.Contains(chat.GetMembers(), userId)
+	})
+}
+
+func (adapter *TestChatsAdapter) GetSavedMessagesChat(userId int) (*Chat, error) {
+	chat, err := adapter.findChat(func(chat Chat) bool {
+		return chat.GetOwnerId() == userId && chat.GetType() == SavedMessagesChatType
+	})
+	if err != nil {
+		return nil, errors.Join(ErrChatNotFound, err)
+	}
+
+	return chat, nil
+}
+
+func (adapter *TestChatsAdapter) isArchivedForUser(chatId int, userId int) bool {
+	settings, ok := adapter.GetUserSettings([]int{chatId}, userId)[chatId]
+	return ok && settings.GetIsArchived()
+}
+
+// isInFolder mirrors the folder query scope: a chat belongs to the folder if
+// it is listed explicitly or matches every folder rule.
+func (adapter *TestChatsAdapter) isInFolder(chat Chat, folder ChatFolder) bool {
+	if slices.Contains(folder.GetChatIds(), chat.GetId()) {
+		return true
+	}
+	if !folder.HasRules() {
+		return false
+	}
+
+	if chatTypes := folder.GetChatTypes(); len(chatTypes) > 0 && !slices.Contains(chatTypes, chat.GetType()) {
+		return false
+	}
+	if folder.GetUnreadOnly() {
+		counters := adapter.unreadCounters[folder.GetUserId()][chat.GetId()]
+		if counters.GetUnreadCount() == 0 {
+			return false
+		}
+	}
+	if folder.GetMutedOnly() {
+		settings, ok := adapter.GetUserSettings([]int{chat.GetId()}, folder.GetUserId())[chat.GetId()]
+		if !ok || !settings.IsMutedAt(time.Now()) {
+			return false
 		}
 	}
 
+	return true
+}
+
+func (adapter *TestChatsAdapter) GetUserAll(userId int, includeArchived bool, folder *ChatFolder, page int, perPage int) utils.PaginatedResponse[Chat] {
+	chats := adapter.filterChats(func(chat Chat) bool {
+		if !includeArchived && adapter.isArchivedForUser(chat.GetId(), userId) {
+			return false
+		}
+		if folder != nil && !adapter.isInFolder(chat, *folder) {
+			return false
+		}
+
+		return slices.Contains(chat.GetMembers(), userId)
+	})
+
+	return utils.NewPaginatedResponse[Chat](page, perPage, 1, len(chats), chats)
+}
+
+func (adapter *TestChatsAdapter) GetUserArchived(userId int, page int, perPage int) utils.PaginatedResponse[Chat] {
+	chats := adapter.filterChats(func(chat Chat) bool {
+		return slices.Contains(chat.GetMembers(), userId) && adapter.isArchivedForUser(chat.GetId(), userId)
+	})
+
 	return utils.NewPaginatedResponse[Chat](page, perPage, 1, len(chats), chats)
 }
 
 func (adapter *TestChatsAdapter) Save(chat Chat) (*Chat, error) {
-	var chatIds []int
-	for _, dbChat := range existingChats {
-		chatIds = append(chatIds, dbChat.GetId())
+	chat = cloneChat(chat)
+	for i, dbChat := range adapter.chats {
+		if dbChat.GetId() == chat.GetId() {
+			adapter.chats[i] = chat
+			savedChat := cloneChat(chat)
+			return &savedChat, nil
+		}
+	}
+
+	if chat.id == 0 {
+		chatIds := []int{0}
+		for _, dbChat := range append(slices.Clone(adapter.chats), adapter.deletedChats...) {
+			chatIds = append(chatIds, dbChat.GetId())
+		}
+
+		chat.id = slices.Max(chatIds) + 1
 	}
 
-	chat.id = slices.Max(chatIds) + 1
-	existingChats = append(existingChats, chat)
-	savedChat := &chat
-	return savedChat, nil
+	adapter.chats = append(adapter.chats, chat)
+	savedChat := cloneChat(chat)
+	return &savedChat, nil
 }
 
-func (adapter *TestChatsAdapter) HasDeletedUserChat(chat Chat) bool {
-	for _, deletedChat := range deletedChats {
-		if slices.Compare(deletedChat.GetMembers(), chat.GetMembers()) == 0 && deletedChat.GetType() == chat.GetType() {
-			return true
+func (adapter *TestChatsAdapter) findDeletedUserChat(chat Chat) (int, bool) {
+	for i, deletedChat := range adapter.deletedChats {
+		if slices.Equal(deletedChat.GetMembers(), chat.GetMembers()) && deletedChat.GetType() == chat.GetType() {
+			return i, true
 		}
 	}
 
-	return false
+	return 0, false
+}
+
+func (adapter *TestChatsAdapter) HasDeletedUserChat(chat Chat) bool {
+	_, ok := adapter.findDeletedUserChat(chat)
+	return ok
 }
 
 func (adapter *TestChatsAdapter) RestoreChat(chat Chat) (*Chat, error) {
-	restoredChat := &chat
-	return restoredChat, nil
+	i, ok := adapter.findDeletedUserChat(chat)
+	if !ok {
+		return nil, errTestNotFound
+	}
+
+	restoredChat := adapter.deletedChats[i]
+	adapter.deletedChats = slices.Delete(adapter.deletedChats, i, i+1)
+	adapter.chats = append(adapter.chats, restoredChat)
+	restoredChat = cloneChat(restoredChat)
+	return &restoredChat, nil
 }
 
 func (adapter *TestChatsAdapter) CheckChatExists(chat Chat) bool {
-	var chatIds []int
-	for _, dbChat := range existingChats {
-		chatIds = append(chatIds, dbChat.GetId())
-	}
+	members := slices.Clone(chat.GetMembers())
+	slices.Sort(members)
+	_, err := adapter.findChat(func(dbChat Chat) bool {
+		dbMembers := slices.Clone(dbChat.GetMembers())
+		slices.Sort(dbMembers)
+		return dbChat.GetType() == UserChatType && slices.Equal(dbMembers, members)
+	})
 
-	return slices.Contains(chatIds, chat.GetId())
+	return err == nil
 }
 
 func (adapter *TestChatsAdapter) Delete(chat Chat) {
-	var newExistingChats []Chat
-	for _, dbChat := range existingChats {
-		if dbChat.GetId() != chat.GetId() {
-			newExistingChats = append(newExistingChats, dbChat)
+	adapter.chats = slices.DeleteFunc(adapter.chats, func(dbChat Chat) bool {
+		if dbChat.GetId() == chat.GetId() {
+			adapter.deletedChats = append(adapter.deletedChats, dbChat)
+			return true
+		}
+
+		return false
+	})
+}
+
+func (adapter *TestChatsAdapter) SearchChats(userId int, query string, page int, perPage int) utils.PaginatedResponse[Chat] {
+	chats := adapter.filterChats(func(chat Chat) bool {
+		return slices.Contains(chat.GetMembers(), userId) && strings.Contains(strings.ToLower(chat.GetTitle()), strings.ToLower(query))
+	})
+
+	return utils.NewPaginatedResponse[Chat](page, perPage, 1, len(chats), chats)
+}
+
+func (adapter *TestChatsAdapter) GetUnreadCounters(chatIds []int, userId int) map[int]ChatUnreadCounters {
+	adapter.unreadCountersCalls++
+	counters := make(map[int]ChatUnreadCounters)
+	for chatId, chatCounters := range adapter.unreadCounters[userId] {
+		if slices.Contains(chatIds, chatId) {
+			counters[chatId] = chatCounters
+		}
+	}
+
+	return counters
+}
+
+func (adapter *TestChatsAdapter) GetTotalUnread(userId int) ChatUnreadCounters {
+	var unreadCount, unreadMentionsCount int
+	for _, chatCounters := range adapter.unreadCounters[userId] {
+		unreadCount += chatCounters.GetUnreadCount()
+		unreadMentionsCount += chatCounters.GetUnreadMentionsCount()
+	}
+
+	return NewChatUnreadCounters(unreadCount, unreadMentionsCount)
+}
+
+func (adapter *TestChatsAdapter) GetUserSettings(chatIds []int, userId int) map[int]ChatUserSettings {
+	userSettings := make(map[int]ChatUserSettings)
+	for _, settings := range adapter.userSettings {
+		if settings.GetUserId() == userId && slices.Contains(chatIds, settings.GetChatId()) {
+			userSettings[settings.GetChatId()] = settings
 		}
 	}
 
-	existingChats = newExistingChats
+	return userSettings
 }
 
-type TestChatEventsAdapter struct{}
+func (adapter *TestChatsAdapter) SaveUserSettings(settings ChatUserSettings) error {
+	for i, savedSettings := range adapter.userSettings {
+		if savedSettings.GetChatId() == settings.GetChatId() && savedSettings.GetUserId() == settings.GetUserId() {
+			adapter.userSettings[i] = settings
+			return nil
+		}
+	}
 
-func (adapter *TestChatEventsAdapter) SendChatCreated(chat Chat) {}
+	adapter.userSettings = append(adapter.userSettings, settings)
+	return nil
+}
+
+func (adapter *TestChatsAdapter) GetUserFolders(userId int) []ChatFolder {
+	var folders []ChatFolder
+	for _, folder := range adapter.folders {
+		if folder.GetUserId() == userId {
+			folders = append(folders, folder)
+		}
+	}
 
-func (adapter *TestChatEventsAdapter) SendChatDeleted(chat Chat) {}
+	slices.SortFunc(folders, func(a ChatFolder, b ChatFolder) int {
+		return a.GetPosition() - b.GetPosition()
+	})
+	return folders
+}
+
+func (adapter *TestChatsAdapter) GetFolderForUser(folderId int, userId int) (*ChatFolder, error) {
+	for _, folder := range adapter.folders {
+		if folder.GetId() == folderId && folder.GetUserId() == userId {
+			return &folder, nil
+		}
+	}
+
+	return nil, errTestNotFound
+}
+
+func (adapter *TestChatsAdapter) SaveFolder(folder ChatFolder) (*ChatFolder, error) {
+	for i, dbFolder := range adapter.folders {
+		if dbFolder.GetId() == folder.GetId() {
+			adapter.folders[i] = folder
+			return &folder, nil
+		}
+	}
+
+	if folder.id == 0 {
+		folder.id = len(adapter.folders) + 1
+	}
+
+	adapter.folders = append(adapter.folders, folder)
+	return &folder, nil
+}
 
-func (adapter *TestChatEventsAdapter) SendChatUserAction(chat Chat) {}
+func (adapter *TestChatsAdapter) DeleteFolder(folder ChatFolder) {
+	adapter.folders = slices.DeleteFunc(adapter.folders, func(dbFolder ChatFolder) bool {
+		return dbFolder.GetId() == folder.GetId()
+	})
+}
+
+func (adapter *TestChatsAdapter) SaveFoldersOrder(userId int, folderIds []int) error {
+	for i, folder := range adapter.folders {
+		if position := slices.Index(folderIds, folder.GetId()); folder.GetUserId() == userId && position >= 0 {
+			adapter.folders[i].SetPosition(position)
+		}
+	}
+
+	return nil
+}
+
+func (adapter *TestChatsAdapter) GetChatBans(chatId int) []ChatBan {
+	var bans []ChatBan
+	for _, ban := range adapter.bans {
+		if ban.GetChatId() == chatId && ban.IsActiveAt(time.Now()) {
+			bans = append(bans, ban)
+		}
+	}
+
+	return bans
+}
+
+func (adapter *TestChatsAdapter) SaveBan(ban ChatBan) (*ChatBan, error) {
+	adapter.DeleteBan(ban.GetChatId(), ban.GetUserId())
+	ban.id = len(adapter.bans) + 1
+	adapter.bans = append(adapter.bans, ban)
+	return &ban, nil
+}
+
+func (adapter *TestChatsAdapter) DeleteBan(chatId int, userId int) {
+	adapter.bans = slices.DeleteFunc(adapter.bans, func(ban ChatBan) bool {
+		return ban.GetChatId() == chatId && ban.GetUserId() == userId
+	})
+}
+
+func (adapter *TestChatsAdapter) GetInviteLinkByCode(code string) (*ChatInviteLink, error) {
+	for _, link := range adapter.inviteLinks {
+		if link.GetCode() == code {
+			return &link, nil
+		}
+	}
+
+	return nil, errTestNotFound
+}
+
+func (adapter *TestChatsAdapter) SaveInviteLink(link ChatInviteLink) (*ChatInviteLink, error) {
+	for i, dbLink := range adapter.inviteLinks {
+		if dbLink.GetId() == link.GetId() {
+			adapter.inviteLinks[i] = link
+			return &link, nil
+		}
+	}
+
+	if link.id == 0 {
+		link.id = len(adapter.inviteLinks) + 1
+	}
+
+	adapter.inviteLinks = append(adapter.inviteLinks, link)
+	return &link, nil
+}
+
+func (adapter *TestChatsAdapter) UseInviteLink(link ChatInviteLink) error {
+	for i, dbLink := range adapter.inviteLinks {
+		if dbLink.GetId() != link.GetId() {
+			continue
+		}
+		if dbLink.IsRevoked() || dbLink.IsExpired(time.Now()) || dbLink.IsExhausted() {
+			return fmt.Errorf("invite link %d is no longer usable", link.GetId())
+		}
+
+		adapter.inviteLinks[i].usageCount++
+		return nil
+	}
+
+	return errTestNotFound
+}
+
+func (adapter *TestChatsAdapter) GetJoinRequestById(id int) (*ChatJoinRequest, error) {
+	for _, request := range adapter.joinRequests {
+		if request.GetId() == id {
+			return &request, nil
+		}
+	}
+
+	return nil, errTestNotFound
+}
+
+func (adapter *TestChatsAdapter) GetPendingJoinRequest(chatId int, userId int) (*ChatJoinRequest, error) {
+	for _, request := range adapter.joinRequests {
+		if request.GetChatId() == chatId && request.GetUserId() == userId && request.IsPending() {
+			return &request, nil
+		}
+	}
+
+	return nil, errTestNotFound
+}
+
+func (adapter *TestChatsAdapter) GetChatPendingJoinRequests(chatId int) []ChatJoinRequest {
+	var requests []ChatJoinRequest
+	for _, request := range adapter.joinRequests {
+		if request.GetChatId() == chatId && request.IsPending() {
+			requests = append(requests, request)
+		}
+	}
+
+	return requests
+}
+
+func (adapter *TestChatsAdapter) SaveJoinRequest(request ChatJoinRequest) (*ChatJoinRequest, error) {
+	for i, dbRequest := range adapter.joinRequests {
+		if dbRequest.GetId() == request.GetId() {
+			adapter.joinRequests[i] = request
+			return &request, nil
+		}
+	}
+
+	if request.id == 0 {
+		request.id = len(adapter.joinRequests) + 1
+	}
+
+	adapter.joinRequests = append(adapter.joinRequests, request)
+	return &request, nil
+}
+
+// TestChatEventsAdapter records the sent events, with the receiving user for
+// events sent to a single user.
+type TestChatEventsAdapter struct {
+	sentEvents []string
+	sendErr    error
+}
+
+func (adapter *TestChatEventsAdapter) send(event string) error {
+	if adapter.sendErr != nil {
+		return adapter.sendErr
+	}
+
+	adapter.sentEvents = append(adapter.sentEvents, event)
+	return nil
+}
+
+func (adapter *TestChatEventsAdapter) SendChatCreated(chat Chat) error {
+	return adapter.send("chat_created")
+}
+
+func (adapter *TestChatEventsAdapter) SendChatDeleted(chat Chat) error {
+	return adapter.send("chat_deleted")
+}
+
+func (adapter *TestChatEventsAdapter) SendChatUserAction(chat Chat) error {
+	return adapter.send("chat_user_action")
+}
+
+func (adapter *TestChatEventsAdapter) SendChatChanged(chat Chat) error {
+	return adapter.send("chat_changed")
+}
+
+func (adapter *TestChatEventsAdapter) SendChatChangedForUser(chat Chat, userId int) error {
+	return adapter.send(fmt.Sprintf("chat_changed:%d", userId))
+}
+
+func (adapter *TestChatEventsAdapter) SendChatArchiveChanged(chat Chat, userId int) error {
+	return adapter.send(fmt.Sprintf("chat_archive_changed:%d", userId))
+}
+
+func (adapter *TestChatEventsAdapter) SendChatMembersRemoved(chat Chat, userIds []int) error {
+	return adapter.send(fmt.Sprintf("chat_members_removed:%v", userIds))
+}
+
+func (adapter *TestChatEventsAdapter) SendJoinRequestResolved(request ChatJoinRequest) error {
+	return adapter.send("join_request_resolved")
+}
+
+func (adapter *TestChatEventsAdapter) SendFoldersChanged(userId int, folders []ChatFolder) error {
+	return adapter.send(fmt.Sprintf("folders_changed:%d", userId))
+}
+
+type TestChatSystemMessagesAdapter struct {
+	sentEvents []ChatSystemEvents
+	sentValues []*string
+}
+
+func (adapter *TestChatSystemMessagesAdapter) SendSystemEvent(chat Chat, actorId int, event ChatSystemEvents, value *string) error {
+	adapter.sentEvents = append(adapter.sentEvents, event)
+	adapter.sentValues = append(adapter.sentValues, value)
+	return nil
+}
+
+type TestUserActionsAdapter struct {
+	refreshedChats []int
+	cleanedChats   []int
+}
+
+func (adapter *TestUserActionsAdapter) AddChatActionUser(chat Chat, user users.User, actionType ActionTypes) map[ActionTypes][]users.ActionUser {
+	return map[ActionTypes][]users.ActionUser{}
+}
+
+func (adapter *TestUserActionsAdapter) RemoveChatActionUser(chat Chat, userId int, actionType ActionTypes) map[ActionTypes][]users.ActionUser {
+	return map[ActionTypes][]users.ActionUser{}
+}
+
+func (adapter *TestUserActionsAdapter) GetAllChatActionsUsers(chat Chat) map[ActionTypes][]users.ActionUser {
+	return map[ActionTypes][]users.ActionUser{}
+}
+
+func (adapter *TestUserActionsAdapter) RemoveUserFromChatActions(chat Chat, userId int) {
+	adapter.cleanedChats = append(adapter.cleanedChats, chat.GetId())
+}
+
+func (adapter *TestUserActionsAdapter) RefreshChatActionUser(chat Chat, user users.User) {
+	adapter.refreshedChats = append(adapter.refreshedChats, chat.GetId())
+}
+
+type TestUsersAdapter struct{}
+
+func (adapter *TestUsersAdapter) GetById(id int) (*users.User, error) {
+	for _, user := range existingUsers {
+		if user.GetId() == id {
+			return &user, nil
+		}
+	}
+
+	return nil, errTestNotFound
+}
+
+func (adapter *TestUsersAdapter) GetByIds(ids []int) []users.User {
+	var foundUsers []users.User
+	for _, user := range existingUsers {
+		if slices.Contains(ids, user.GetId()) {
+			foundUsers = append(foundUsers, user)
+		}
+	}
+
+	return foundUsers
+}
@@ -1,8 +1,13 @@
 package messages
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/chack-check/chats-service/domain/chats"
 	"github.com/chack-check/chats-service/domain/files"
@@ -16,13 +21,271 @@ var (
 	ErrIncorrectVoiceMessage  = fmt.Errorf("you need to specify voice for voice message")
 	ErrIncorrectTextMessage   = fmt.Errorf("you need to specify content or attachments for text message")
 	ErrSavingMessage          = fmt.Errorf("error saving message")
+	ErrIncorrectReplyTo       = fmt.Errorf("you can reply only to messages from the same chat")
+	ErrCantForwardMessage     = fmt.Errorf("you can't forward event or call messages")
+	ErrCantPinMessage         = fmt.Errorf("you can't pin messages in this chat")
+	ErrIncorrectSendAt        = fmt.Errorf("scheduled message send time must be in the future")
+	ErrCantEditMessage        = fmt.Errorf("you can edit only your own messages")
+	ErrCantEditMessageType    = fmt.Errorf("you can't edit event or call messages")
+	ErrCantEditVotedPoll      = fmt.Errorf("you can't edit a poll that already has votes")
+	ErrDeleteTimeExpired      = fmt.Errorf("the time to delete this message for everyone has expired")
+	ErrIncorrectPollMessage   = fmt.Errorf("you need to specify question and at least two options for poll message")
+	ErrIncorrectPollClosesAt  = fmt.Errorf("poll close time must be in the future")
+	ErrMessageIsNotPoll       = fmt.Errorf("message is not a poll")
+	ErrPollClosed             = fmt.Errorf("poll is closed")
+	ErrIncorrectPollVote      = fmt.Errorf("incorrect poll options")
+	ErrCantClosePoll          = fmt.Errorf("you can't close this poll")
+	ErrIncorrectSearchQuery   = fmt.Errorf("search query must not be empty")
+	ErrIncorrectSearchPeriod  = fmt.Errorf("search period start must be before its end")
+	ErrIncorrectReadMessage   = fmt.Errorf("message does not belong to this chat")
+	ErrCantSendMessages       = fmt.Errorf("you can't send messages in this chat")
+	ErrCantSendMedia          = fmt.Errorf("you can't send media in this chat")
+	ErrOnlyAdminsCanPost      = fmt.Errorf("only channel admins can post in this channel")
+	ErrChatUserDeleted        = fmt.Errorf("user of this chat has been deleted")
+	ErrSendingEvent           = fmt.Errorf("error sending event")
 )
 
+type SlowModeError struct {
+	remainingWait time.Duration
+}
+
+func (err *SlowModeError) GetRemainingWait() time.Duration {
+	return err.remainingWait
+}
+
+func (err *SlowModeError) Error() string {
+	return fmt.Sprintf("slow mode is enabled, you can send next message in %d seconds", int(math.Ceil(err.remainingWait.Seconds())))
+}
+
+// acquireSlowModeSlot reserves the user's next message slot in a slow mode
+// chat. Call it after all other validation so a rejected message doesn't use
+// up the slot.
+func acquireSlowModeSlot(slowModePort SlowModePort, chat chats.Chat, userId int) error {
+	if !isSlowModeApplied(chat, userId) {
+		return nil
+	}
+
+	if remainingWait := slowModePort.Acquire(chat.GetId(), userId, chat.GetSlowModeInterval()); remainingWait > 0 {
+		return &SlowModeError{remainingWait: remainingWait}
+	}
+
+	return nil
+}
+
+// releaseSlowModeSlots frees the slots acquired in the chats when a later step
+// of the same send fails.
+func releaseSlowModeSlots(slowModePort SlowModePort, acquiredChats []chats.Chat, userId int) {
+	for _, chat := range acquiredChats {
+		if isSlowModeApplied(chat, userId) {
+			slowModePort.Release(chat.GetId(), userId)
+		}
+	}
+}
+
+func isSlowModeApplied(chat chats.Chat, userId int) bool {
+	return chat.GetSlowModeInterval() > 0 && !chats.ValidateUserChatAdmin(chat, userId)
+}
+
+func sendMessageCreatedEvents(messagesPort MessagesPort, messageEventsPort MessageEventsPort, message Message) error {
+	if err := messageEventsPort.SendMessageCreated(message); err != nil {
+		return errors.Join(ErrSendingEvent, err)
+	}
+
+	if threadRootId := message.GetThreadRootId(); threadRootId != nil {
+		threadRoot, err := messagesPort.GetById(*threadRootId)
+		if err == nil {
+			if err := messageEventsPort.SendThreadUpdated(*threadRoot); err != nil {
+				return errors.Join(ErrSendingEvent, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func publishScheduledMessage(messagesPort MessagesPort, messageEventsPort MessageEventsPort, message Message) (*Message, error) {
+	publishedMessage, err := messagesPort.PublishScheduled(message)
+	if err != nil {
+		return nil, errors.Join(ErrSavingMessage, err)
+	}
+
+	if err := sendMessageCreatedEvents(messagesPort, messageEventsPort, *publishedMessage); err != nil {
+		return nil, err
+	}
+
+	return publishedMessage, nil
+}
+
+// validateScheduledMessageSender repeats the send checks of CreateMessageHandler
+// at publish time, because the sender may have lost access after scheduling.
+func validateScheduledMessageSender(chatsPort chats.ChatsPort, message Message) (*chats.Chat, error) {
+	messageChat := message.GetChat()
+	chat, err := chatsPort.GetByIdForUser(messageChat.GetId(), message.GetSenderId())
+	if err != nil {
+		if errors.Is(err, chats.ErrChatNotFound) {
+			return nil, chats.ErrChatNotFound
+		}
+
+		return nil, err
+	}
+
+	if err := chats.ValidateUsersNotBanned(chatsPort, chat.GetId(), []int{message.GetSenderId()}); err != nil {
+		return nil, err
+	}
+
+	if err := validateUserCanSendMessage(*chat, message.GetSenderId(), hasMedia(message)); err != nil {
+		return nil, err
+	}
+
+	return chat, nil
+}
+
+// isSenderAccessLost reports whether a validateScheduledMessageSender error
+// means the sender can no longer post in the chat, as opposed to a failed
+// lookup that is worth retrying.
+func isSenderAccessLost(err error) bool {
+	for _, accessErr := range []error{
+		chats.ErrChatNotFound,
+		chats.ErrUserBanned,
+		ErrCantSendMessages,
+		ErrCantSendMedia,
+		ErrOnlyAdminsCanPost,
+		ErrChatUserDeleted,
+	} {
+		if errors.Is(err, accessErr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func createPollDataToPoll(data CreateMessageData) (*MessagePoll, error) {
+	pollData := data.GetPoll()
+	if content := data.GetContent(); content == nil || *content == "" || pollData == nil || len(pollData.GetOptions()) < 2 {
+		return nil, ErrIncorrectPollMessage
+	}
+
+	if closesAt := pollData.GetClosesAt(); closesAt != nil && !closesAt.After(time.Now()) {
+		return nil, ErrIncorrectPollClosesAt
+	}
+
+	var options []MessagePollOption
+	for _, option := range pollData.GetOptions() {
+		if option == "" {
+			return nil, ErrIncorrectPollMessage
+		}
+
+		options = append(options, NewMessagePollOption(0, option, []int{}))
+	}
+
+	poll := NewMessagePoll(options, pollData.GetMultipleChoice(), pollData.GetAnonymous(), pollData.GetClosesAt(), nil)
+	return &poll, nil
+}
+
+func moveReadPointer(messagesPort MessagesPort, message Message, userId int) (*ChatReadPointer, bool, error) {
+	chat := message.GetChat()
+	var readAt time.Time
+	if createdAt := message.GetCreatedAt(); createdAt != nil {
+		readAt = *createdAt
+	}
+
+	currentPointer, err := messagesPort.GetReadPointer(chat.GetId(), userId)
+	if err == nil && !currentPointer.GetReadAt().Before(readAt) {
+		return currentPointer, false, nil
+	}
+
+	pointer := NewChatReadPointer(chat.GetId(), userId, message.GetId(), readAt)
+	if err := messagesPort.SaveReadPointer(pointer); err != nil {
+		return nil, false, errors.Join(ErrSavingMessage, err)
+	}
+
+	return &pointer, true, nil
+}
+
+// isMessagesAdmin reports whether the user manages other members' messages as
+// the chat owner or an admin with the delete messages right. Only the admin
+// list is consulted, so it holds for every chat type that has admins.
+func isMessagesAdmin(chat chats.Chat, userId int) bool {
+	return chats.ValidateUserChatAdmin(chat, userId) && chats.ValidateUserChatRight(chat, userId, chats.DeleteMessagesRight)
+}
+
+func canManageMessage(message Message, userId int) bool {
+	if message.GetSenderId() == userId {
+		return true
+	}
+
+	return isMessagesAdmin(message.GetChat(), userId)
+}
+
+func hasMedia(message Message) bool {
+	return message.GetVoice() != nil || message.GetCircle() != nil || len(message.GetAttachments()) > 0
+}
+
+func validateUserCanSendMessage(chat chats.Chat, userId int, withMedia bool) error {
+	if chat.GetType() == chats.UserChatType && chat.GetIsUserDeleted() {
+		return ErrChatUserDeleted
+	}
+	if !chats.ValidateUserCanSendMessages(chat, userId, false) {
+		if chat.GetType() == chats.ChannelChatType {
+			return ErrOnlyAdminsCanPost
+		}
+
+		return ErrCantSendMessages
+	}
+	if withMedia && !chats.ValidateUserCanSendMessages(chat, userId, true) {
+		return ErrCantSendMedia
+	}
+
+	return nil
+}
+
+// validateUserCanEditAttachments applies the media permission check of
+// CreateMessageHandler when an edit adds attachments to the message.
+func validateUserCanEditAttachments(chatsPort chats.ChatsPort, message Message, userId int, data UpdateMessageData) error {
+	if len(data.GetAttachments()) == 0 {
+		return nil
+	}
+
+	messageChat := message.GetChat()
+	chat, err := chatsPort.GetByIdForUser(messageChat.GetId(), userId)
+	if err != nil {
+		return chats.ErrChatNotFound
+	}
+
+	return validateUserCanSendMessage(*chat, userId, true)
+}
+
+func applyUpdateMessageData(message *Message, data UpdateMessageData, filesPort files.FilesPort) error {
+	if content := data.GetContent(); content != nil {
+		message.SetContent(content)
+	}
+	if attachments := data.GetAttachments(); len(attachments) > 0 {
+		var savedFiles []files.SavedFile
+		for _, attachment := range attachments {
+			if err := files.ValidateUploadingFile(filesPort, &attachment, files.FileInChatFiletype, true); err != nil {
+				return err
+			}
+
+			savedFiles = append(savedFiles, files.UploadingFileToSavedFile(attachment))
+		}
+
+		message.SetAttachments(savedFiles)
+	}
+	if mentioned := data.GetMentioned(); len(mentioned) > 0 {
+		message.SetMentioned(mentioned)
+	}
+
+	return nil
+}
+
 type CreateMessageHandler struct {
 	chatsPort         chats.ChatsPort
 	messagesPort      MessagesPort
 	messageEventsPort MessageEventsPort
 	filesPort         files.FilesPort
+	schedulerPort     MessagesSchedulerPort
+	slowModePort      SlowModePort
 }
 
 func (handler *CreateMessageHandler) Execute(data CreateMessageData, userId int) (*Message, error) {
@@ -31,6 +294,11 @@ func (handler *CreateMessageHandler) Execute(data CreateMessageData, userId int)
 		return nil, chats.ErrChatNotFound
 	}
 
+	withMedia := len(data.GetAttachments()) > 0 || data.GetVoice() != nil || data.GetCircle() != nil
+	if err := validateUserCanSendMessage(*chat, userId, withMedia); err != nil {
+		return nil, err
+	}
+
 	var savedAttachments []files.SavedFile
 	for _, attachment := range data.GetAttachments() {
 		if err := files.ValidateUploadingFile(handler.filesPort, &attachment, files.FileInChatFiletype, true); err != nil {
@@ -72,6 +340,18 @@ func (handler *CreateMessageHandler) Execute(data CreateMessageData, userId int)
 		return nil, ErrIncorrectTextMessage
 	}
 
+	if sendAt := data.GetSendAt(); sendAt != nil && !sendAt.After(time.Now()) {
+		return nil, ErrIncorrectSendAt
+	}
+
+	var poll *MessagePoll
+	if data.GetType() == PollMessageType {
+		poll, err = createPollDataToPoll(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	message := NewMessage(
 		0,
 		userId,
@@ -89,15 +369,143 @@ func (handler *CreateMessageHandler) Execute(data CreateMessageData, userId int)
 		nil,
 	)
 
+	if replyToId := data.GetReplyToId(); replyToId != nil {
+		replyTo, err := handler.messagesPort.GetByIdForUser(*replyToId, userId)
+		if err != nil {
+			return nil, ErrIncorrectReplyTo
+		}
+
+		replyToChat := replyTo.GetChat()
+		if replyToChat.GetId() != chat.GetId() {
+			return nil, ErrIncorrectReplyTo
+		}
+
+		threadRootId := replyTo.GetId()
+		if replyTo.GetThreadRootId() != nil {
+			threadRootId = *replyTo.GetThreadRootId()
+		}
+
+		message.SetThreadRootId(&threadRootId)
+	}
+
+	// Scheduled messages take the slot when they are published.
+	if data.GetSendAt() == nil {
+		if err := acquireSlowModeSlot(handler.slowModePort, *chat, userId); err != nil {
+			return nil, err
+		}
+	}
+
+	message.SetSendAt(data.GetSendAt())
+	message.SetPoll(poll)
 	savedMessage, err := handler.messagesPort.Save(message)
 	if err != nil {
 		return nil, ErrSavingMessage
 	}
 
-	handler.messageEventsPort.SendMessageCreated(*savedMessage)
+	if savedMessage.IsScheduled() {
+		handler.schedulerPort.Schedule(*savedMessage)
+		return savedMessage, nil
+	}
+
+	if err := sendMessageCreatedEvents(handler.messagesPort, handler.messageEventsPort, *savedMessage); err != nil {
+		return nil, err
+	}
+
 	return savedMessage, nil
 }
 
+type ForwardMessagesHandler struct {
+	chatsPort         chats.ChatsPort
+	messagesPort      MessagesPort
+	messageEventsPort MessageEventsPort
+	slowModePort      SlowModePort
+}
+
+func (handler *ForwardMessagesHandler) Execute(messageIds []int, targetChatIds []int, userId int) ([]Message, error) {
+	forwardingMessages := handler.messagesPort.GetByIdsForUser(messageIds, userId)
+	if len(forwardingMessages) == 0 {
+		return nil, ErrMessageNotFound
+	}
+
+	for _, message := range forwardingMessages {
+		if message.GetType() == EventMessageType || message.GetType() == CallMessageType {
+			return nil, ErrCantForwardMessage
+		}
+	}
+
+	slices.SortFunc(forwardingMessages, func(a, b Message) int {
+		return a.GetId() - b.GetId()
+	})
+
+	targetChats := handler.chatsPort.GetByIdsForUser(targetChatIds, userId)
+	if len(targetChats) == 0 {
+		return nil, chats.ErrChatNotFound
+	}
+
+	withMedia := slices.ContainsFunc(forwardingMessages, hasMedia)
+	for _, chat := range targetChats {
+		if err := validateUserCanSendMessage(chat, userId, withMedia); err != nil {
+			return nil, err
+		}
+	}
+
+	var acquiredChats []chats.Chat
+	for _, chat := range targetChats {
+		if err := acquireSlowModeSlot(handler.slowModePort, chat, userId); err != nil {
+			releaseSlowModeSlots(handler.slowModePort, acquiredChats, userId)
+			return nil, err
+		}
+
+		acquiredChats = append(acquiredChats, chat)
+	}
+
+	var forwardedMessages []Message
+	for _, chat := range targetChats {
+		for _, message := range forwardingMessages {
+			forwardedFrom := message.GetForwardedFrom()
+			if forwardedFrom == nil {
+				messageChat := message.GetChat()
+				original := NewMessageForwardedFrom(message.GetId(), message.GetSenderId(), messageChat.GetId())
+				forwardedFrom = &original
+			}
+
+			forwardedMessage := NewMessage(
+				0,
+				userId,
+				chat,
+				message.GetType(),
+				message.GetContent(),
+				message.GetVoice(),
+				message.GetCircle(),
+				message.GetAttachments(),
+				nil,
+				[]int{},
+				[]int{},
+				[]MessageReaction{},
+				[]int{},
+				nil,
+			)
+			forwardedMessage.SetForwardedFrom(forwardedFrom)
+			if poll := message.GetPoll(); poll != nil {
+				forwardedPoll := poll.WithoutVotes()
+				forwardedMessage.SetPoll(&forwardedPoll)
+			}
+
+			savedMessage, err := handler.messagesPort.Save(forwardedMessage)
+			if err != nil {
+				return nil, ErrSavingMessage
+			}
+
+			if err := handler.messageEventsPort.SendMessageCreated(*savedMessage); err != nil {
+				return nil, errors.Join(ErrSendingEvent, err)
+			}
+			forwardedMessages = append(forwardedMessages, *savedMessage)
+		}
+	}
+
+	return forwardedMessages, nil
+}
+
 type GetConcreteMessageHandler struct {
 	messagesPort MessagesPort
 }
@@ -150,6 +558,37 @@ func (handler *GetChatMessagesByCursorHandler) Execute(chatId int, userId int, m
 	return &messages, nil
 }
 
+type GetThreadMessagesHandler struct {
+	messagesPort MessagesPort
+}
+
+func (handler *GetThreadMessagesHandler) Execute(rootMessageId int, userId int, offset int, limit int) (*utils.OffsetResponse[Message], error) {
+	rootMessage, err := handler.messagesPort.GetByIdForUser(rootMessageId, userId)
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+
+	messages := handler.messagesPort.GetThreadAllForUser(rootMessage.GetId(), userId, offset, limit)
+	return &messages, nil
+}
+
+type SearchMessagesHandler struct {
+	messagesPort MessagesPort
+}
+
+func (handler *SearchMessagesHandler) Execute(filter SearchMessagesFilter, userId int, offset int, limit int) (*utils.OffsetResponse[MessageSearchResult], error) {
+	if strings.TrimSpace(filter.GetQuery()) == "" {
+		return nil, ErrIncorrectSearchQuery
+	}
+
+	if from, to := filter.GetFrom(), filter.GetTo(); from != nil && to != nil && from.After(*to) {
+		return nil, ErrIncorrectSearchPeriod
+	}
+
+	results := handler.messagesPort.SearchForUser(filter, userId, offset, limit)
+	return &results, nil
+}
+
 type GetChatsLastMessagesHandler struct {
 	messagesPort MessagesPort
 	chatsPort    chats.ChatsPort
@@ -167,8 +606,10 @@ func (handler *GetChatsLastMessagesHandler) Execute(chatIds []int, userId int) [
 }
 
 type ReadMessageHandler struct {
+	chatsPort         chats.ChatsPort
 	messagesPort      MessagesPort
 	messageEventsPort MessageEventsPort
+	chatEventsPort    chats.ChatEventsPort
 }
 
 func (handler *ReadMessageHandler) Execute(messageId int, userId int) (*Message, error) {
@@ -181,14 +622,67 @@ func (handler *ReadMessageHandler) Execute(messageId int, userId int) (*Message,
 		return message, nil
 	}
 
-	message.Read(userId)
-	savedMessage, err := handler.messagesPort.Save(*message)
+	_, moved, err := moveReadPointer(handler.messagesPort, *message, userId)
 	if err != nil {
-		return nil, ErrSavingMessage
+		return nil, err
 	}
 
-	handler.messageEventsPort.SendMessageReaded(*savedMessage)
-	return savedMessage, nil
+	if !moved {
+		return message, nil
+	}
+
+	readMessage, err := handler.messagesPort.GetByIdForUser(messageId, userId)
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+
+	if err := handler.messageEventsPort.SendMessageReaded(*readMessage); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	if err := chats.SendChatChangedForUser(handler.chatsPort, handler.chatEventsPort, readMessage.GetChat(), userId); err != nil {
+		return nil, err
+	}
+	return readMessage, nil
+}
+
+type ReadChatUntilHandler struct {
+	chatsPort         chats.ChatsPort
+	messagesPort      MessagesPort
+	messageEventsPort MessageEventsPort
+	chatEventsPort    chats.ChatEventsPort
+}
+
+func (handler *ReadChatUntilHandler) Execute(chatId int, messageId int, userId int) (*ChatReadPointer, error) {
+	chat, err := handler.chatsPort.GetByIdForUser(chatId, userId)
+	if err != nil {
+		return nil, chats.ErrChatNotFound
+	}
+
+	message, err := handler.messagesPort.GetByIdForUser(messageId, userId)
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+
+	messageChat := message.GetChat()
+	if messageChat.GetId() != chat.GetId() {
+		return nil, ErrIncorrectReadMessage
+	}
+
+	pointer, moved, err := moveReadPointer(handler.messagesPort, *message, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if moved {
+		if err := handler.messageEventsPort.SendChatRead(*chat, *pointer); err != nil {
+			return nil, errors.Join(ErrSendingEvent, err)
+		}
+		if err := chats.SendChatChangedForUser(handler.chatsPort, handler.chatEventsPort, *chat, userId); err != nil {
+			return nil, err
+		}
+	}
+
+	return pointer, nil
 }
 
 type ReactMessageHandler struct {
@@ -214,7 +708,9 @@ func (handler *ReactMessageHandler) Execute(messageId int, userId int, content s
 		return nil, ErrSavingMessage
 	}
 
-	handler.messageEventsPort.SendMessageReacted(*savedMessage)
+	if err := handler.messageEventsPort.SendMessageReacted(*savedMessage); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
 	return savedMessage, nil
 }
 
@@ -246,11 +742,14 @@ func (handler *DeleteMessageReactionHandler) Execute(messageId int, userId int)
 		return nil, ErrSavingMessage
 	}
 
-	handler.messageEventsPort.SendReactionDeleted(*savedMessage)
+	if err := handler.messageEventsPort.SendReactionDeleted(*savedMessage); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
 	return savedMessage, nil
 }
 
 type UpdateMessageHandler struct {
+	chatsPort         chats.ChatsPort
 	messagesPort      MessagesPort
 	messageEventsPort MessageEventsPort
 	filesPort         files.FilesPort
@@ -262,40 +761,91 @@ func (handler *UpdateMessageHandler) Execute(messageId int, userId int, data Upd
 		return nil, ErrMessageNotFound
 	}
 
-	if content := data.GetContent(); content != nil {
-		message.SetContent(content)
+	if message.GetSenderId() != userId {
+		return nil, ErrCantEditMessage
 	}
-	if attachments := data.GetAttachments(); len(attachments) > 0 {
-		var savedFiles []files.SavedFile
-		for _, attachment := range attachments {
-			if err := files.ValidateUploadingFile(handler.filesPort, &attachment, files.FileInChatFiletype, true); err != nil {
-				return nil, err
-			}
-
-			savedFiles = append(savedFiles, files.UploadingFileToSavedFile(attachment))
-		}
-
-		message.SetAttachments(savedFiles)
+	if message.GetType() == EventMessageType || message.GetType() == CallMessageType {
+		return nil, ErrCantEditMessageType
 	}
-	if mentioned := data.GetMentioned(); len(mentioned) > 0 {
-		message.SetMentioned(mentioned)
+	if poll := message.GetPoll(); poll != nil && poll.GetTotalVoters() > 0 && data.GetContent() != nil {
+		return nil, ErrCantEditVotedPoll
+	}
+	if err := validateUserCanEditAttachments(handler.chatsPort, *message, userId, data); err != nil {
+		return nil, err
+	}
+
+	revision := message.GetRevision()
+	if err := applyUpdateMessageData(message, data, handler.filesPort); err != nil {
+		return nil, err
 	}
 
+	editedAt := time.Now()
+	message.SetEditedAt(&editedAt)
 	savedMessage, err := handler.messagesPort.Save(*message)
 	if err != nil {
 		return nil, ErrSavingMessage
 	}
 
-	handler.messageEventsPort.SendMessageUpdated(*savedMessage)
+	if err := handler.messagesPort.SaveRevision(revision); err != nil {
+		return nil, errors.Join(ErrSavingMessage, err)
+	}
+
+	if err := handler.messageEventsPort.SendMessageUpdated(*savedMessage); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
 	return savedMessage, nil
 }
 
-type DeleteMessageHandler struct {
-	messagesPort      MessagesPort
-	messageEventsPort MessageEventsPort
+type GetMessageHistoryHandler struct {
+	messagesPort MessagesPort
 }
 
-func (handler *DeleteMessageHandler) Execute(messageId int, userId int) error {
+func (handler *GetMessageHistoryHandler) Execute(messageId int, userId int) ([]MessageRevision, error) {
+	message, err := handler.messagesPort.GetByIdForUser(messageId, userId)
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+
+	return handler.messagesPort.GetRevisions(*message), nil
+}
+
+type DeleteMessageHandler struct {
+	messagesPort            MessagesPort
+	messageEventsPort       MessageEventsPort
+	deleteForEveryoneWindow time.Duration
+}
+
+func (handler *DeleteMessageHandler) deleteForUser(message Message, userId int) error {
+	if err := handler.messagesPort.DeleteForUser(message, userId); err != nil {
+		return errors.Join(ErrSavingMessage, err)
+	}
+
+	message.DeleteFor([]int{userId})
+	if err := handler.messageEventsPort.SendMessageDeletedForUser(message, userId); err != nil {
+		return errors.Join(ErrSendingEvent, err)
+	}
+	return nil
+}
+
+func (handler *DeleteMessageHandler) deleteForEveryone(message Message, userId int) error {
+	if !canManageMessage(message, userId) {
+		return ErrCantDeleteMessage
+	}
+
+	if !isMessagesAdmin(message.GetChat(), userId) && handler.deleteForEveryoneWindow > 0 && message.GetCreatedAt() != nil {
+		if time.Since(*message.GetCreatedAt()) > handler.deleteForEveryoneWindow {
+			return ErrDeleteTimeExpired
+		}
+	}
+
+	handler.messagesPort.Delete(message)
+	if err := handler.messageEventsPort.SendMessageDeleted(message); err != nil {
+		return errors.Join(ErrSendingEvent, err)
+	}
+	return nil
+}
+
+func (handler *DeleteMessageHandler) Execute(messageId int, userId int, forEveryone bool) error {
 	message, err := handler.messagesPort.GetByIdForUser(messageId, userId)
 	if err != nil {
 		return ErrMessageNotFound
@@ -306,11 +856,376 @@ func (handler *DeleteMessageHandler) Execute(messageId int, userId int) error {
 		return ErrCantDeleteMessage
 	}
 
+	if forEveryone {
+		return handler.deleteForEveryone(*message, userId)
+	}
+
+	return handler.deleteForUser(*message, userId)
+}
+
+type VotePollHandler struct {
+	messagesPort      MessagesPort
+	messageEventsPort MessageEventsPort
+}
+
+func (handler *VotePollHandler) Execute(messageId int, userId int, optionIds []int) (*Message, error) {
+	message, err := handler.messagesPort.GetByIdForUser(messageId, userId)
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+
+	poll := message.GetPoll()
+	if poll == nil {
+		return nil, ErrMessageIsNotPoll
+	}
+
+	if poll.IsClosed() {
+		return nil, ErrPollClosed
+	}
+
+	votes := slices.Clone(optionIds)
+	slices.Sort(votes)
+	votes = slices.Compact(votes)
+	if len(votes) == 0 || (!poll.GetMultipleChoice() && len(votes) > 1) {
+		return nil, ErrIncorrectPollVote
+	}
+
+	for _, optionId := range votes {
+		if !poll.HasOption(optionId) {
+			return nil, ErrIncorrectPollVote
+		}
+	}
+
+	if err := handler.messagesPort.VotePoll(*message, userId, votes); err != nil {
+		return nil, errors.Join(ErrSavingMessage, err)
+	}
+
+	votedMessage, err := handler.messagesPort.GetById(message.GetId())
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+
+	if err := handler.messageEventsPort.SendPollUpdated(*votedMessage); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	return votedMessage, nil
+}
+
+type RetractPollVoteHandler struct {
+	messagesPort      MessagesPort
+	messageEventsPort MessageEventsPort
+}
+
+func (handler *RetractPollVoteHandler) Execute(messageId int, userId int) (*Message, error) {
+	message, err := handler.messagesPort.GetByIdForUser(messageId, userId)
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+
+	poll := message.GetPoll()
+	if poll == nil {
+		return nil, ErrMessageIsNotPoll
+	}
+
+	if poll.IsClosed() {
+		return nil, ErrPollClosed
+	}
+
+	if err := handler.messagesPort.RetractPollVote(*message, userId); err != nil {
+		return nil, errors.Join(ErrSavingMessage, err)
+	}
+
+	retractedMessage, err := handler.messagesPort.GetById(message.GetId())
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+
+	if err := handler.messageEventsPort.SendPollUpdated(*retractedMessage); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	return retractedMessage, nil
+}
+
+type ClosePollHandler struct {
+	messagesPort      MessagesPort
+	messageEventsPort MessageEventsPort
+}
+
+func (handler *ClosePollHandler) Execute(messageId int, userId int) (*Message, error) {
+	message, err := handler.messagesPort.GetByIdForUser(messageId, userId)
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+
+	poll := message.GetPoll()
+	if poll == nil {
+		return nil, ErrMessageIsNotPoll
+	}
+
+	if !canManageMessage(*message, userId) {
+		return nil, ErrCantClosePoll
+	}
+
+	if poll.GetClosedAt() != nil {
+		return message, nil
+	}
+
+	if err := handler.messagesPort.ClosePoll(*message, time.Now()); err != nil {
+		return nil, errors.Join(ErrSavingMessage, err)
+	}
+
+	closedMessage, err := handler.messagesPort.GetById(message.GetId())
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+
+	if err := handler.messageEventsPort.SendPollUpdated(*closedMessage); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	return closedMessage, nil
+}
+
+type PinMessageHandler struct {
+	messagesPort      MessagesPort
+	messageEventsPort MessageEventsPort
+}
+
+func (handler *PinMessageHandler) Execute(messageId int, userId int) (*Message, error) {
+	message, err := handler.messagesPort.GetByIdForUser(messageId, userId)
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+
+	if !chats.ValidateUserCanPinMessages(message.GetChat(), userId) {
+		return nil, ErrCantPinMessage
+	}
+
+	if err := handler.messagesPort.Pin(*message, userId); err != nil {
+		return nil, errors.Join(ErrSavingMessage, err)
+	}
+
+	if err := handler.messageEventsPort.SendMessagePinned(*message); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	return message, nil
+}
+
+type UnpinMessageHandler struct {
+	messagesPort      MessagesPort
+	messageEventsPort MessageEventsPort
+}
+
+func (handler *UnpinMessageHandler) Execute(messageId int, userId int) (*Message, error) {
+	message, err := handler.messagesPort.GetByIdForUser(messageId, userId)
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+
+	if !chats.ValidateUserCanPinMessages(message.GetChat(), userId) {
+		return nil, ErrCantPinMessage
+	}
+
+	if err := handler.messagesPort.Unpin(*message); err != nil {
+		return nil, errors.Join(ErrSavingMessage, err)
+	}
+
+	if err := handler.messageEventsPort.SendMessageUnpinned(*message); err != nil {
+		return nil, errors.Join(ErrSendingEvent, err)
+	}
+	return message, nil
+}
+
+type GetPinnedMessagesHandler struct {
+	chatsPort    chats.ChatsPort
+	messagesPort MessagesPort
+}
+
+func (handler *GetPinnedMessagesHandler) Execute(chatId int, userId int) ([]Message, error) {
+	chat, err := handler.chatsPort.GetByIdForUser(chatId, userId)
+	if err != nil {
+		return nil, chats.ErrChatNotFound
+	}
+
+	return handler.messagesPort.GetChatPinned(chat.GetId(), userId), nil
+}
+
+type GetScheduledMessagesHandler struct {
+	chatsPort    chats.ChatsPort
+	messagesPort MessagesPort
+}
+
+func (handler *GetScheduledMessagesHandler) Execute(chatId int, userId int) ([]Message, error) {
+	chat, err := handler.chatsPort.GetByIdForUser(chatId, userId)
+	if err != nil {
+		return nil, chats.ErrChatNotFound
+	}
+
+	return handler.messagesPort.GetChatScheduledForUser(chat.GetId(), userId), nil
+}
+
+type UpdateScheduledMessageHandler struct {
+	chatsPort    chats.ChatsPort
+	messagesPort MessagesPort
+	filesPort    files.FilesPort
+}
+
+func (handler *UpdateScheduledMessageHandler) Execute(messageId int, userId int, data UpdateMessageData) (*Message, error) {
+	message, err := handler.messagesPort.GetScheduledByIdForUser(messageId, userId)
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+
+	if err := validateUserCanEditAttachments(handler.chatsPort, *message, userId, data); err != nil {
+		return nil, err
+	}
+	if err := applyUpdateMessageData(message, data, handler.filesPort); err != nil {
+		return nil, err
+	}
+
+	savedMessage, err := handler.messagesPort.Save(*message)
+	if err != nil {
+		return nil, ErrSavingMessage
+	}
+
+	return savedMessage, nil
+}
+
+type RescheduleMessageHandler struct {
+	messagesPort  MessagesPort
+	schedulerPort MessagesSchedulerPort
+}
+
+func (handler *RescheduleMessageHandler) Execute(messageId int, userId int, sendAt time.Time) (*Message, error) {
+	if !sendAt.After(time.Now()) {
+		return nil, ErrIncorrectSendAt
+	}
+
+	message, err := handler.messagesPort.GetScheduledByIdForUser(messageId, userId)
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+
+	message.SetSendAt(&sendAt)
+	savedMessage, err := handler.messagesPort.Save(*message)
+	if err != nil {
+		return nil, ErrSavingMessage
+	}
+
+	handler.schedulerPort.Schedule(*savedMessage)
+	return savedMessage, nil
+}
+
+type CancelScheduledMessageHandler struct {
+	messagesPort  MessagesPort
+	schedulerPort MessagesSchedulerPort
+}
+
+func (handler *CancelScheduledMessageHandler) Execute(messageId int, userId int) error {
+	message, err := handler.messagesPort.GetScheduledByIdForUser(messageId, userId)
+	if err != nil {
+		return ErrMessageNotFound
+	}
+
+	handler.schedulerPort.Cancel(*message)
 	handler.messagesPort.Delete(*message)
-	handler.messageEventsPort.SendMessageDeleted(*message)
 	return nil
 }
 
+type SendScheduledMessageNowHandler struct {
+	chatsPort         chats.ChatsPort
+	messagesPort      MessagesPort
+	messageEventsPort MessageEventsPort
+	schedulerPort     MessagesSchedulerPort
+	slowModePort      SlowModePort
+}
+
+func (handler *SendScheduledMessageNowHandler) Execute(messageId int, userId int) (*Message, error) {
+	message, err := handler.messagesPort.GetScheduledByIdForUser(messageId, userId)
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+
+	chat, err := validateScheduledMessageSender(handler.chatsPort, *message)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := acquireSlowModeSlot(handler.slowModePort, *chat, userId); err != nil {
+		return nil, err
+	}
+
+	handler.schedulerPort.Cancel(*message)
+	return publishScheduledMessage(handler.messagesPort, handler.messageEventsPort, *message)
+}
+
+type PublishScheduledMessageHandler struct {
+	chatsPort         chats.ChatsPort
+	messagesPort      MessagesPort
+	messageEventsPort MessageEventsPort
+	schedulerPort     MessagesSchedulerPort
+	slowModePort      SlowModePort
+}
+
+// Execute publishes a due scheduled message. The message is dropped only when
+// the sender has definitely lost access to the chat; any other error is
+// returned so the caller can retry, and a busy slow mode slot moves the
+// message to the end of the wait.
+func (handler *PublishScheduledMessageHandler) Execute(messageId int) error {
+	message, err := handler.messagesPort.GetById(messageId)
+	if err != nil {
+		if errors.Is(err, ErrMessageNotFound) {
+			return ErrMessageNotFound
+		}
+
+		return err
+	}
+
+	if !message.IsScheduled() {
+		return nil
+	}
+
+	chat, err := validateScheduledMessageSender(handler.chatsPort, *message)
+	if err != nil {
+		if !isSenderAccessLost(err) {
+			return err
+		}
+
+		handler.messagesPort.Delete(*message)
+		if err := handler.messageEventsPort.SendScheduledMessageDropped(*message); err != nil {
+			return errors.Join(ErrSendingEvent, err)
+		}
+		return nil
+	}
+
+	err = acquireSlowModeSlot(handler.slowModePort, *chat, message.GetSenderId())
+	var slowModeErr *SlowModeError
+	if errors.As(err, &slowModeErr) {
+		sendAt := time.Now().Add(slowModeErr.GetRemainingWait())
+		message.SetSendAt(&sendAt)
+		savedMessage, err := handler.messagesPort.Save(*message)
+		if err != nil {
+			return errors.Join(ErrSavingMessage, err)
+		}
+
+		handler.schedulerPort.Schedule(*savedMessage)
+		return nil
+	}
+
+	_, err = publishScheduledMessage(handler.messagesPort, handler.messageEventsPort, *message)
+	return err
+}
+
+type RestoreScheduledMessagesHandler struct {
+	messagesPort  MessagesPort
+	schedulerPort MessagesSchedulerPort
+}
+
+func (handler *RestoreScheduledMessagesHandler) Execute() {
+	for _, message := range handler.messagesPort.GetAllScheduled() {
+		handler.schedulerPort.Schedule(message)
+	}
+}
+
 type RecognizeMessageHandler struct {
 	messagesPort      MessagesPort
 	messageEventsPort MessageEventsPort
@@ -328,6 +1243,50 @@ func (handler *RecognizeMessageHandler) Execute(messageId int, content string) e
 		return ErrSavingMessage
 	}
 
-	handler.messageEventsPort.SendMessageUpdated(*message)
+	if err := handler.messageEventsPort.SendMessageUpdated(*message); err != nil {
+		return errors.Join(ErrSendingEvent, err)
+	}
 	return nil
 }
+
+type ChatSystemMessagesHandler struct {
+	messagesPort      MessagesPort
+	messageEventsPort MessageEventsPort
+}
+
+type systemEventContent struct {
+	Event string  `json:"event"`
+	Value *string `json:"value,omitempty"`
+}
+
+func (handler *ChatSystemMessagesHandler) SendSystemEvent(chat chats.Chat, actorId int, event chats.ChatSystemEvents, value *string) error {
+	encodedContent, err := json.Marshal(systemEventContent{Event: string(event), Value: value})
+	if err != nil {
+		return err
+	}
+
+	content := string(encodedContent)
+	message := NewMessage(
+		0,
+		actorId,
+		chat,
+		EventMessageType,
+		&content,
+		nil,
+		nil,
+		[]files.SavedFile{},
+		nil,
+		[]int{},
+		[]int{},
+		[]MessageReaction{},
+		[]int{},
+		nil,
+	)
+
+	savedMessage, err := handler.messagesPort.Save(message)
+	if err != nil {
+		return errors.Join(ErrSavingMessage, err)
+	}
+
+	return sendMessageCreatedEvents(handler.messagesPort, handler.messageEventsPort, *savedMessage)
+}
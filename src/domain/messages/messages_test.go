@@ -0,0 +1,1553 @@
+package messages
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/chack-check/chats-service/domain/chats"
+	"github.com/chack-check/chats-service/domain/files"
+)
+
+func newTestMessage(id int, senderId int, chat chats.Chat, threadRootId *int) Message {
+	content := "message"
+	message := NewMessage(id, senderId, chat, TextMessageType, &content, nil, nil, nil, nil, []int{}, []int{}, nil, []int{}, nil)
+	message.SetThreadRootId(threadRootId)
+	return message
+}
+
+func newTestPoll(multipleChoice bool, closesAt *time.Time, closedAt *time.Time) *MessagePoll {
+	poll := NewMessagePoll(
+		[]MessagePollOption{
+			NewMessagePollOption(1, "first", []int{}),
+			NewMessagePollOption(2, "second", []int{2}),
+			NewMessagePollOption(3, "third", []int{}),
+		},
+		multipleChoice,
+		false,
+		closesAt,
+		closedAt,
+	)
+	return &poll
+}
+
+func newTestPollMessage(id int, senderId int, chat chats.Chat, poll *MessagePoll) Message {
+	message := newTestMessage(id, senderId, chat, nil)
+	if poll != nil {
+		message.type_ = PollMessageType
+		message.SetPoll(poll)
+	}
+
+	return message
+}
+
+func getOptionVoters(message Message, optionId int) []int {
+	for _, option := range message.GetPoll().GetOptions() {
+		if option.GetId() == optionId {
+			return option.GetVoters()
+		}
+	}
+
+	return nil
+}
+
+func TestCreateMessageHandlerThreads(t *testing.T) {
+	rootId := 1
+	groupChat := chats.NewTestGroupChat()
+	otherChat := chats.NewChat(2, nil, "group chat 1", chats.GroupChatType, []int{1, 2}, false, 1, []int{1})
+	existingMessages := []Message{
+		newTestMessage(1, 1, groupChat, nil),
+		newTestMessage(2, 2, groupChat, &rootId),
+		newTestMessage(3, 1, otherChat, nil),
+	}
+	tests := []struct {
+		name                 string
+		replyToId            int
+		expectedErr          error
+		expectedThreadRootId int
+	}{
+		{name: "reply to a message starts its thread", replyToId: 1, expectedThreadRootId: 1},
+		{name: "reply to a thread reply stays in the thread", replyToId: 2, expectedThreadRootId: 1},
+		{name: "reply to a message from another chat", replyToId: 3, expectedErr: ErrIncorrectReplyTo},
+		{name: "reply to a missing message", replyToId: 10, expectedErr: ErrIncorrectReplyTo},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			messagesAdapter := NewTestMessagesAdapter(existingMessages, nil)
+			eventsAdapter := &TestMessageEventsAdapter{}
+			handler := NewCreateMessageHandler(chats.NewTestChatsAdapter(groupChat), messagesAdapter, eventsAdapter, nil, &TestMessagesSchedulerAdapter{}, &TestSlowModeAdapter{})
+
+			content := "reply"
+			data := NewCreateMessageData(groupChat.GetId(), TextMessageType, &content, nil, nil, &test.replyToId, nil, nil, nil, nil)
+			message, err := handler.Execute(data, 3)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("error = %v, expected %v", err, test.expectedErr)
+			}
+			if test.expectedErr != nil {
+				if len(eventsAdapter.sentEvents) != 0 {
+					t.Errorf("sent events = %v, expected none", eventsAdapter.sentEvents)
+				}
+				return
+			}
+
+			if threadRootId := message.GetThreadRootId(); threadRootId == nil || *threadRootId != test.expectedThreadRootId {
+				t.Errorf("thread root id = %v, expected %d", threadRootId, test.expectedThreadRootId)
+			}
+			if expectedEvents := []string{"message_created", "thread_updated"}; !slices.Equal(eventsAdapter.sentEvents, expectedEvents) {
+				t.Errorf("sent events = %v, expected %v", eventsAdapter.sentEvents, expectedEvents)
+			}
+		})
+	}
+}
+
+func TestGetThreadMessagesHandler(t *testing.T) {
+	rootId := 1
+	groupChat := chats.NewTestGroupChat()
+	deletedReply := newTestMessage(4, 1, groupChat, &rootId)
+	deletedReply.DeleteFor([]int{2})
+	existingMessages := []Message{
+		newTestMessage(1, 1, groupChat, nil),
+		newTestMessage(2, 2, groupChat, &rootId),
+		newTestMessage(3, 3, groupChat, nil),
+		deletedReply,
+		newTestMessage(5, 3, groupChat, &rootId),
+	}
+	tests := []struct {
+		name        string
+		rootId      int
+		userId      int
+		expectedErr error
+		expectedIds []int
+	}{
+		{name: "thread replies", rootId: 1, userId: 1, expectedIds: []int{2, 4, 5}},
+		{name: "replies deleted for the user are hidden", rootId: 1, userId: 2, expectedIds: []int{2, 5}},
+		{name: "message without replies", rootId: 3, userId: 1, expectedIds: nil},
+		{name: "root isn't visible for the user", rootId: 1, userId: 4, expectedErr: ErrMessageNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler := NewGetThreadMessagesHandler(NewTestMessagesAdapter(existingMessages, nil))
+			messages, err := handler.Execute(test.rootId, test.userId, 0, 10)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("error = %v, expected %v", err, test.expectedErr)
+			}
+			if test.expectedErr != nil {
+				return
+			}
+
+			var ids []int
+			for _, message := range messages.GetData() {
+				ids = append(ids, message.GetId())
+			}
+			if !slices.Equal(ids, test.expectedIds) {
+				t.Errorf("thread messages = %v, expected %v", ids, test.expectedIds)
+			}
+		})
+	}
+}
+
+func TestForwardMessagesHandler(t *testing.T) {
+	groupChat := chats.NewTestGroupChat()
+	userChat := chats.NewChat(1, nil, "", chats.UserChatType, []int{1, 2}, false, 0, []int{})
+	forwardedMessage := newTestMessage(3, 1, userChat, nil)
+	forwardedFrom := NewMessageForwardedFrom(20, 4, 30)
+	forwardedMessage.SetForwardedFrom(&forwardedFrom)
+	eventMessage := newTestMessage(4, 1, groupChat, nil)
+	eventMessage.type_ = EventMessageType
+	existingMessages := []Message{
+		newTestMessage(1, 2, groupChat, nil),
+		newTestMessage(2, 3, groupChat, nil),
+		forwardedMessage,
+		eventMessage,
+	}
+	tests := []struct {
+		name                  string
+		messageIds            []int
+		targetChatIds         []int
+		expectedErr           error
+		expectedChatIds       []int
+		expectedForwardedFrom []MessageForwardedFrom
+	}{
+		{
+			name:            "messages go to every chat in sending order",
+			messageIds:      []int{2, 1},
+			targetChatIds:   []int{1, 10},
+			expectedChatIds: []int{1, 1, 10, 10},
+			expectedForwardedFrom: []MessageForwardedFrom{
+				NewMessageForwardedFrom(1, 2, 10),
+				NewMessageForwardedFrom(2, 3, 10),
+				NewMessageForwardedFrom(1, 2, 10),
+				NewMessageForwardedFrom(2, 3, 10),
+			},
+		},
+		{
+			name:                  "forwarded message keeps its origin",
+			messageIds:            []int{3},
+			targetChatIds:         []int{10},
+			expectedChatIds:       []int{10},
+			expectedForwardedFrom: []MessageForwardedFrom{forwardedFrom},
+		},
+		{name: "event message", messageIds: []int{1, 4}, targetChatIds: []int{10}, expectedErr: ErrCantForwardMessage},
+		{name: "missing messages", messageIds: []int{100}, targetChatIds: []int{10}, expectedErr: ErrMessageNotFound},
+		{name: "chats of other users", messageIds: []int{1}, targetChatIds: []int{3}, expectedErr: chats.ErrChatNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eventsAdapter := &TestMessageEventsAdapter{}
+			handler := NewForwardMessagesHandler(chats.NewTestChatsAdapter(groupChat), NewTestMessagesAdapter(existingMessages, nil), eventsAdapter, &TestSlowModeAdapter{})
+
+			messages, err := handler.Execute(test.messageIds, test.targetChatIds, 1)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("error = %v, expected %v", err, test.expectedErr)
+			}
+			if test.expectedErr != nil {
+				return
+			}
+
+			var chatIds []int
+			var forwardedFrom []MessageForwardedFrom
+			for _, message := range messages {
+				chat := message.GetChat()
+				chatIds = append(chatIds, chat.GetId())
+				forwardedFrom = append(forwardedFrom, *message.GetForwardedFrom())
+				if message.GetSenderId() != 1 {
+					t.Errorf("sender id = %d, expected the forwarding user", message.GetSenderId())
+				}
+			}
+			if !slices.Equal(chatIds, test.expectedChatIds) {
+				t.Errorf("chat ids = %v, expected %v", chatIds, test.expectedChatIds)
+			}
+			if !slices.Equal(forwardedFrom, test.expectedForwardedFrom) {
+				t.Errorf("forwarded from = %v, expected %v", forwardedFrom, test.expectedForwardedFrom)
+			}
+			if len(eventsAdapter.sentEvents) != len(messages) {
+				t.Errorf("sent events = %v, expected one per forwarded message", eventsAdapter.sentEvents)
+			}
+		})
+	}
+}
+
+func TestPinMessageHandlers(t *testing.T) {
+	groupChat := chats.NewTestGroupChat()
+	userChat := chats.NewChat(1, nil, "", chats.UserChatType, []int{1, 2}, false, 0, []int{})
+	existingMessages := []Message{
+		newTestMessage(1, 2, groupChat, nil),
+		newTestMessage(2, 1, userChat, nil),
+	}
+	tests := []struct {
+		name              string
+		messageId         int
+		userId            int
+		unpin             bool
+		expectedErr       error
+		expectedPinnedIds []int
+		expectedEvents    []string
+	}{
+		{name: "admin pins in group chat", messageId: 1, userId: 3, expectedPinnedIds: []int{1}, expectedEvents: []string{"message_pinned"}},
+		{name: "member can't pin in group chat", messageId: 1, userId: 2, expectedErr: ErrCantPinMessage, expectedPinnedIds: []int{1}},
+		{name: "member pins in user chat", messageId: 2, userId: 2, expectedPinnedIds: []int{1, 2}, expectedEvents: []string{"message_pinned"}},
+		{name: "member can't unpin in group chat", messageId: 1, userId: 2, unpin: true, expectedErr: ErrCantPinMessage, expectedPinnedIds: []int{1}},
+		{name: "owner unpins in group chat", messageId: 1, userId: 1, unpin: true, expectedEvents: []string{"message_unpinned"}},
+		{name: "message of another chat", messageId: 2, userId: 3, expectedErr: ErrMessageNotFound, expectedPinnedIds: []int{1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			messagesAdapter := NewTestMessagesAdapter(existingMessages, nil)
+			messagesAdapter.Pin(existingMessages[0], 1)
+			eventsAdapter := &TestMessageEventsAdapter{}
+
+			var err error
+			if test.unpin {
+				handler := NewUnpinMessageHandler(messagesAdapter, eventsAdapter)
+				_, err = handler.Execute(test.messageId, test.userId)
+			} else {
+				handler := NewPinMessageHandler(messagesAdapter, eventsAdapter)
+				_, err = handler.Execute(test.messageId, test.userId)
+			}
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("error = %v, expected %v", err, test.expectedErr)
+			}
+
+			if !slices.Equal(messagesAdapter.pinnedIds, test.expectedPinnedIds) {
+				t.Errorf("pinned messages = %v, expected %v", messagesAdapter.pinnedIds, test.expectedPinnedIds)
+			}
+			if !slices.Equal(eventsAdapter.sentEvents, test.expectedEvents) {
+				t.Errorf("sent events = %v, expected %v", eventsAdapter.sentEvents, test.expectedEvents)
+			}
+		})
+	}
+}
+
+func TestGetPinnedMessagesHandler(t *testing.T) {
+	groupChat := chats.NewTestGroupChat()
+	messagesAdapter := NewTestMessagesAdapter([]Message{newTestMessage(1, 2, groupChat, nil)}, nil)
+	messagesAdapter.Pin(newTestMessage(1, 2, groupChat, nil), 1)
+	handler := NewGetPinnedMessagesHandler(chats.NewTestChatsAdapter(groupChat), messagesAdapter)
+
+	if _, err := handler.Execute(groupChat.GetId(), 4); !errors.Is(err, chats.ErrChatNotFound) {
+		t.Errorf("error for non member = %v, expected %v", err, chats.ErrChatNotFound)
+	}
+
+	messages, err := handler.Execute(groupChat.GetId(), 2)
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if len(messages) != 1 || messages[0].GetId() != 1 {
+		t.Errorf("pinned messages = %v, expected message 1", messages)
+	}
+}
+
+func newTestScheduledMessage(id int, senderId int, chat chats.Chat) Message {
+	sendAt := time.Now().Add(time.Hour)
+	message := newTestMessage(id, senderId, chat, nil)
+	message.SetSendAt(&sendAt)
+	return message
+}
+
+func TestCreateScheduledMessageHandler(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+	tests := []struct {
+		name              string
+		sendAt            *time.Time
+		expectedErr       error
+		expectedScheduled []int
+		expectedEvents    []string
+	}{
+		{name: "message without send time is sent", expectedEvents: []string{"message_created"}},
+		{name: "message with future send time is scheduled", sendAt: &future, expectedScheduled: []int{1}},
+		{name: "message with past send time", sendAt: &past, expectedErr: ErrIncorrectSendAt},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			groupChat := chats.NewTestGroupChat()
+			eventsAdapter := &TestMessageEventsAdapter{}
+			schedulerAdapter := &TestMessagesSchedulerAdapter{}
+			handler := NewCreateMessageHandler(chats.NewTestChatsAdapter(groupChat), NewTestMessagesAdapter(nil, nil), eventsAdapter, nil, schedulerAdapter, &TestSlowModeAdapter{})
+
+			content := "message"
+			data := NewCreateMessageData(groupChat.GetId(), TextMessageType, &content, nil, nil, nil, nil, nil, test.sendAt, nil)
+			message, err := handler.Execute(data, 2)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("error = %v, expected %v", err, test.expectedErr)
+			}
+			if test.expectedErr == nil && message.IsScheduled() != (test.sendAt != nil) {
+				t.Errorf("IsScheduled() = %v, expected %v", message.IsScheduled(), test.sendAt != nil)
+			}
+
+			if !slices.Equal(schedulerAdapter.scheduledMessages, test.expectedScheduled) {
+				t.Errorf("scheduled messages = %v, expected %v", schedulerAdapter.scheduledMessages, test.expectedScheduled)
+			}
+			if !slices.Equal(eventsAdapter.sentEvents, test.expectedEvents) {
+				t.Errorf("sent events = %v, expected %v", eventsAdapter.sentEvents, test.expectedEvents)
+			}
+		})
+	}
+}
+
+func TestScheduledMessageHandlers(t *testing.T) {
+	groupChat := chats.NewTestGroupChat()
+	existingMessages := []Message{
+		newTestScheduledMessage(1, 2, groupChat),
+		newTestMessage(2, 2, groupChat, nil),
+	}
+	tests := []struct {
+		name              string
+		senderLeft        bool
+		execute           func(chatsAdapter chats.ChatsPort, messagesAdapter MessagesPort, eventsAdapter MessageEventsPort, schedulerAdapter MessagesSchedulerPort) error
+		expectedErr       error
+		expectedScheduled []int
+		expectedEvents    []string
+		expectedMessages  []int
+	}{
+		{
+			name: "reschedule to the future",
+			execute: func(chatsAdapter chats.ChatsPort, messagesAdapter MessagesPort, eventsAdapter MessageEventsPort, schedulerAdapter MessagesSchedulerPort) error {
+				handler := NewRescheduleMessageHandler(messagesAdapter, schedulerAdapter)
+				_, err := handler.Execute(1, 2, time.Now().Add(2*time.Hour))
+				return err
+			},
+			expectedScheduled: []int{1, 1},
+			expectedMessages:  []int{1, 2},
+		},
+		{
+			name: "reschedule to the past",
+			execute: func(chatsAdapter chats.ChatsPort, messagesAdapter MessagesPort, eventsAdapter MessageEventsPort, schedulerAdapter MessagesSchedulerPort) error {
+				handler := NewRescheduleMessageHandler(messagesAdapter, schedulerAdapter)
+				_, err := handler.Execute(1, 2, time.Now().Add(-time.Hour))
+				return err
+			},
+			expectedErr:       ErrIncorrectSendAt,
+			expectedScheduled: []int{1},
+			expectedMessages:  []int{1, 2},
+		},
+		{
+			name: "reschedule message of another user",
+			execute: func(chatsAdapter chats.ChatsPort, messagesAdapter MessagesPort, eventsAdapter MessageEventsPort, schedulerAdapter MessagesSchedulerPort) error {
+				handler := NewRescheduleMessageHandler(messagesAdapter, schedulerAdapter)
+				_, err := handler.Execute(1, 1, time.Now().Add(2*time.Hour))
+				return err
+			},
+			expectedErr:       ErrMessageNotFound,
+			expectedScheduled: []int{1},
+			expectedMessages:  []int{1, 2},
+		},
+		{
+			name: "cancel drops the message",
+			execute: func(chatsAdapter chats.ChatsPort, messagesAdapter MessagesPort, eventsAdapter MessageEventsPort, schedulerAdapter MessagesSchedulerPort) error {
+				handler := NewCancelScheduledMessageHandler(messagesAdapter, schedulerAdapter)
+				return handler.Execute(1, 2)
+			},
+			expectedMessages: []int{2},
+		},
+		{
+			name: "cancel sent message",
+			execute: func(chatsAdapter chats.ChatsPort, messagesAdapter MessagesPort, eventsAdapter MessageEventsPort, schedulerAdapter MessagesSchedulerPort) error {
+				handler := NewCancelScheduledMessageHandler(messagesAdapter, schedulerAdapter)
+				return handler.Execute(2, 2)
+			},
+			expectedErr:       ErrMessageNotFound,
+			expectedScheduled: []int{1},
+			expectedMessages:  []int{1, 2},
+		},
+		{
+			name: "send now publishes the message",
+			execute: func(chatsAdapter chats.ChatsPort, messagesAdapter MessagesPort, eventsAdapter MessageEventsPort, schedulerAdapter MessagesSchedulerPort) error {
+				handler := NewSendScheduledMessageNowHandler(chatsAdapter, messagesAdapter, eventsAdapter, schedulerAdapter, &TestSlowModeAdapter{})
+				message, err := handler.Execute(1, 2)
+				if err == nil && message.IsScheduled() {
+					return fmt.Errorf("message is still scheduled")
+				}
+				return err
+			},
+			expectedEvents:   []string{"message_created"},
+			expectedMessages: []int{1, 2},
+		},
+		{
+			name: "publishing a sent message does nothing",
+			execute: func(chatsAdapter chats.ChatsPort, messagesAdapter MessagesPort, eventsAdapter MessageEventsPort, schedulerAdapter MessagesSchedulerPort) error {
+				handler := NewPublishScheduledMessageHandler(chatsAdapter, messagesAdapter, eventsAdapter, schedulerAdapter, &TestSlowModeAdapter{})
+				return handler.Execute(2)
+			},
+			expectedScheduled: []int{1},
+			expectedMessages:  []int{1, 2},
+		},
+		{
+			name:       "send now rejects a sender who left the chat",
+			senderLeft: true,
+			execute: func(chatsAdapter chats.ChatsPort, messagesAdapter MessagesPort, eventsAdapter MessageEventsPort, schedulerAdapter MessagesSchedulerPort) error {
+				handler := NewSendScheduledMessageNowHandler(chatsAdapter, messagesAdapter, eventsAdapter, schedulerAdapter, &TestSlowModeAdapter{})
+				_, err := handler.Execute(1, 2)
+				return err
+			},
+			expectedErr:       chats.ErrChatNotFound,
+			expectedScheduled: []int{1},
+			expectedMessages:  []int{1, 2},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chat := chats.NewTestGroupChat()
+			if test.senderLeft {
+				chat.SetMembers([]int{1, 3})
+			}
+			chatsAdapter := chats.NewTestChatsAdapter(chat)
+			messagesAdapter := NewTestMessagesAdapter(existingMessages, nil)
+			eventsAdapter := &TestMessageEventsAdapter{}
+			schedulerAdapter := &TestMessagesSchedulerAdapter{}
+			restoreHandler := NewRestoreScheduledMessagesHandler(messagesAdapter, schedulerAdapter)
+			restoreHandler.Execute()
+
+			err := test.execute(chatsAdapter, messagesAdapter, eventsAdapter, schedulerAdapter)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("error = %v, expected %v", err, test.expectedErr)
+			}
+
+			if !slices.Equal(schedulerAdapter.scheduledMessages, test.expectedScheduled) {
+				t.Errorf("scheduled messages = %v, expected %v", schedulerAdapter.scheduledMessages, test.expectedScheduled)
+			}
+			if !slices.Equal(eventsAdapter.sentEvents, test.expectedEvents) {
+				t.Errorf("sent events = %v, expected %v", eventsAdapter.sentEvents, test.expectedEvents)
+			}
+			var messageIds []int
+			for _, message := range messagesAdapter.messages {
+				messageIds = append(messageIds, message.GetId())
+			}
+			if !slices.Equal(messageIds, test.expectedMessages) {
+				t.Errorf("messages = %v, expected %v", messageIds, test.expectedMessages)
+			}
+		})
+	}
+}
+
+func TestUpdateMessageHandler(t *testing.T) {
+	newContent := "edited"
+	attachment := files.NewUploadingFile(files.NewUploadingFileMeta("url", "photo.png", "signature", files.FileInChatFiletype), nil)
+	pollWithoutVotes := newTestPoll(false, nil, nil).WithoutVotes()
+	tests := []struct {
+		name           string
+		messageType    MessageTypes
+		poll           *MessagePoll
+		userId         int
+		attachments    []files.UploadingFile
+		mediaForbidden bool
+		expectedErr    error
+	}{
+		{name: "sender edits the message", userId: 2},
+		{name: "event message can't be edited", messageType: EventMessageType, userId: 2, expectedErr: ErrCantEditMessageType},
+		{name: "call message can't be edited", messageType: CallMessageType, userId: 2, expectedErr: ErrCantEditMessageType},
+		{name: "sender edits the text without media permission", userId: 2, mediaForbidden: true},
+		{
+			name:           "sender can't add attachments without media permission",
+			userId:         2,
+			attachments:    []files.UploadingFile{attachment},
+			mediaForbidden: true,
+			expectedErr:    ErrCantSendMedia,
+		},
+		{name: "poll without votes can be edited", messageType: PollMessageType, poll: &pollWithoutVotes, userId: 2},
+		{name: "poll with votes can't be edited", messageType: PollMessageType, poll: newTestPoll(false, nil, nil), userId: 2, expectedErr: ErrCantEditVotedPoll},
+		{name: "other member can't edit the message", userId: 3, expectedErr: ErrCantEditMessage},
+		{name: "non member can't see the message", userId: 4, expectedErr: ErrMessageNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chat := chats.NewTestGroupChat()
+			if test.mediaForbidden {
+				chat.SetMemberPermissions(chats.NewMemberPermissions(true, false, false, false))
+			}
+			message := newTestMessage(1, 2, chat, nil)
+			if test.messageType != "" {
+				message.type_ = test.messageType
+			}
+			message.SetPoll(test.poll)
+			messagesAdapter := NewTestMessagesAdapter([]Message{message}, nil)
+			eventsAdapter := &TestMessageEventsAdapter{}
+			handler := NewUpdateMessageHandler(chats.NewTestChatsAdapter(chat), messagesAdapter, eventsAdapter, nil)
+
+			_, err := handler.Execute(1, test.userId, NewUpdateMessageData(&newContent, test.attachments, nil))
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("error = %v, expected %v", err, test.expectedErr)
+			}
+
+			savedMessage, _ := messagesAdapter.GetById(1)
+			if test.expectedErr != nil {
+				if *savedMessage.GetContent() != "message" || savedMessage.GetEditedAt() != nil || len(messagesAdapter.revisions) != 0 || len(eventsAdapter.sentEvents) != 0 {
+					t.Errorf("rejected edit changed the message: content = %s, editedAt = %v, revisions = %d, events = %v", *savedMessage.GetContent(), savedMessage.GetEditedAt(), len(messagesAdapter.revisions), eventsAdapter.sentEvents)
+				}
+				return
+			}
+
+			if *savedMessage.GetContent() != newContent || savedMessage.GetEditedAt() == nil {
+				t.Errorf("content = %s, editedAt = %v, expected edited content and editedAt", *savedMessage.GetContent(), savedMessage.GetEditedAt())
+			}
+			if !slices.Equal(eventsAdapter.sentEvents, []string{"message_updated"}) {
+				t.Errorf("sent events = %v, expected [message_updated]", eventsAdapter.sentEvents)
+			}
+
+			historyHandler := NewGetMessageHistoryHandler(messagesAdapter)
+			revisions, err := historyHandler.Execute(1, 3)
+			if err != nil {
+				t.Fatalf("history error = %v", err)
+			}
+			if len(revisions) != 1 || *revisions[0].GetContent() != "message" {
+				t.Errorf("revisions = %+v, expected the original content", revisions)
+			}
+		})
+	}
+}
+
+func TestDeleteMessageHandler(t *testing.T) {
+	tests := []struct {
+		name            string
+		userId          int
+		forEveryone     bool
+		createdAt       time.Time
+		adminRights     chats.AdminRights
+		expectedErr     error
+		expectedDeleted bool
+		expectedEvents  []string
+	}{
+		{
+			name:           "member deletes the message for themselves",
+			userId:         3,
+			createdAt:      time.Now().Add(-2 * time.Hour),
+			expectedEvents: []string{"message_deleted_for_user"},
+		},
+		{
+			name:            "sender deletes the message for everyone",
+			userId:          2,
+			forEveryone:     true,
+			createdAt:       time.Now(),
+			expectedDeleted: true,
+			expectedEvents:  []string{"message_deleted"},
+		},
+		{
+			name:        "sender can't delete for everyone after the window",
+			userId:      2,
+			forEveryone: true,
+			createdAt:   time.Now().Add(-2 * time.Hour),
+			expectedErr: ErrDeleteTimeExpired,
+		},
+		{
+			name:            "admin deletes for everyone after the window",
+			userId:          3,
+			forEveryone:     true,
+			createdAt:       time.Now().Add(-2 * time.Hour),
+			adminRights:     chats.NewAdminRights(false, false, false, false, true, false),
+			expectedDeleted: true,
+			expectedEvents:  []string{"message_deleted"},
+		},
+		{
+			name:        "admin without the delete messages right",
+			userId:      3,
+			forEveryone: true,
+			createdAt:   time.Now().Add(-2 * time.Hour),
+			expectedErr: ErrCantDeleteMessage,
+		},
+		{
+			name:        "member can't delete another member's message for everyone",
+			userId:      1,
+			forEveryone: true,
+			createdAt:   time.Now(),
+			expectedErr: ErrCantDeleteMessage,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chat := chats.NewChat(10, nil, "group chat", chats.GroupChatType, []int{1, 2, 3}, false, 4, []int{3})
+			chat.SetAdminRights(3, test.adminRights)
+			message := newTestMessage(1, 2, chat, nil)
+			message.createdAt = &test.createdAt
+			messagesAdapter := NewTestMessagesAdapter([]Message{message}, nil)
+			messagesAdapter.Pin(message, 3)
+			eventsAdapter := &TestMessageEventsAdapter{}
+			handler := NewDeleteMessageHandler(messagesAdapter, eventsAdapter, time.Hour)
+
+			if err := handler.Execute(1, test.userId, test.forEveryone); !errors.Is(err, test.expectedErr) {
+				t.Fatalf("error = %v, expected %v", err, test.expectedErr)
+			}
+
+			_, err := messagesAdapter.GetById(1)
+			if deleted := err != nil; deleted != test.expectedDeleted {
+				t.Errorf("message deleted = %v, expected %v", deleted, test.expectedDeleted)
+			}
+			if !slices.Equal(eventsAdapter.sentEvents, test.expectedEvents) {
+				t.Errorf("sent events = %v, expected %v", eventsAdapter.sentEvents, test.expectedEvents)
+			}
+			if test.expectedErr == nil && !test.forEveryone {
+				if _, err := messagesAdapter.GetByIdForUser(1, test.userId); err == nil {
+					t.Errorf("message is still visible for the user")
+				}
+				if pinned := messagesAdapter.GetChatPinned(chat.GetId(), test.userId); len(pinned) != 0 {
+					t.Errorf("pinned messages = %v, expected the deleted message hidden", pinned)
+				}
+			}
+		})
+	}
+}
+func TestMessagePollIsClosed(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Minute)
+	tests := []struct {
+		name     string
+		closesAt *time.Time
+		closedAt *time.Time
+		expected bool
+	}{
+		{name: "open without close time", expected: false},
+		{name: "close time in the future", closesAt: &future, expected: false},
+		{name: "close time in the past", closesAt: &past, expected: true},
+		{name: "closed manually", closedAt: &past, expected: true},
+		{name: "closed manually before close time", closesAt: &future, closedAt: &past, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			poll := newTestPoll(false, test.closesAt, test.closedAt)
+			if closed := poll.IsClosed(); closed != test.expected {
+				t.Errorf("IsClosed() = %v, expected %v", closed, test.expected)
+			}
+		})
+	}
+}
+
+func TestVotePollHandler(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name           string
+		poll           *MessagePoll
+		userId         int
+		optionIds      []int
+		expectedErr    error
+		expectedVoters map[int][]int
+	}{
+		{
+			name:           "single choice vote",
+			poll:           newTestPoll(false, nil, nil),
+			userId:         3,
+			optionIds:      []int{1},
+			expectedVoters: map[int][]int{1: {3}, 2: {2}, 3: {}},
+		},
+		{
+			name:           "revote moves the vote",
+			poll:           newTestPoll(false, nil, nil),
+			userId:         2,
+			optionIds:      []int{3},
+			expectedVoters: map[int][]int{1: {}, 2: {}, 3: {2}},
+		},
+		{
+			name:           "multiple choice vote with duplicates",
+			poll:           newTestPoll(true, nil, nil),
+			userId:         3,
+			optionIds:      []int{3, 1, 3},
+			expectedVoters: map[int][]int{1: {3}, 2: {2}, 3: {3}},
+		},
+		{
+			name:        "several options in single choice poll",
+			poll:        newTestPoll(false, nil, nil),
+			userId:      3,
+			optionIds:   []int{1, 3},
+			expectedErr: ErrIncorrectPollVote,
+		},
+		{
+			name:        "no options",
+			poll:        newTestPoll(true, nil, nil),
+			userId:      3,
+			optionIds:   []int{},
+			expectedErr: ErrIncorrectPollVote,
+		},
+		{
+			name:        "unknown option",
+			poll:        newTestPoll(true, nil, nil),
+			userId:      3,
+			optionIds:   []int{1, 4},
+			expectedErr: ErrIncorrectPollVote,
+		},
+		{
+			name:        "closed poll",
+			poll:        newTestPoll(false, nil, &past),
+			userId:      3,
+			optionIds:   []int{1},
+			expectedErr: ErrPollClosed,
+		},
+		{
+			name:        "expired poll",
+			poll:        newTestPoll(false, &past, nil),
+			userId:      3,
+			optionIds:   []int{1},
+			expectedErr: ErrPollClosed,
+		},
+		{
+			name:        "not a poll",
+			userId:      3,
+			optionIds:   []int{1},
+			expectedErr: ErrMessageIsNotPoll,
+		},
+		{
+			name:        "not a chat member",
+			poll:        newTestPoll(false, nil, nil),
+			userId:      4,
+			optionIds:   []int{1},
+			expectedErr: ErrMessageNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			messagesAdapter := NewTestMessagesAdapter([]Message{newTestPollMessage(1, 1, chats.NewTestGroupChat(), test.poll)}, nil)
+			eventsAdapter := &TestMessageEventsAdapter{}
+			handler := NewVotePollHandler(messagesAdapter, eventsAdapter)
+
+			message, err := handler.Execute(1, test.userId, test.optionIds)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+			if test.expectedErr != nil {
+				if len(eventsAdapter.sentEvents) != 0 {
+					t.Errorf("sent events %v for a rejected vote", eventsAdapter.sentEvents)
+				}
+				return
+			}
+
+			for optionId, expectedVoters := range test.expectedVoters {
+				if voters := getOptionVoters(*message, optionId); !slices.Equal(voters, expectedVoters) {
+					t.Errorf("option %d voters = %v, expected %v", optionId, voters, expectedVoters)
+				}
+			}
+			if !slices.Equal(eventsAdapter.sentEvents, []string{"poll_updated"}) {
+				t.Errorf("sent events = %v, expected poll_updated", eventsAdapter.sentEvents)
+			}
+		})
+	}
+}
+
+func TestRetractPollVoteHandler(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name        string
+		poll        *MessagePoll
+		expectedErr error
+	}{
+		{name: "open poll", poll: newTestPoll(false, nil, nil)},
+		{name: "closed poll", poll: newTestPoll(false, nil, &past), expectedErr: ErrPollClosed},
+		{name: "not a poll", expectedErr: ErrMessageIsNotPoll},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			messagesAdapter := NewTestMessagesAdapter([]Message{newTestPollMessage(1, 1, chats.NewTestGroupChat(), test.poll)}, nil)
+			handler := NewRetractPollVoteHandler(messagesAdapter, &TestMessageEventsAdapter{})
+
+			message, err := handler.Execute(1, 2)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+			if test.expectedErr == nil && message.GetPoll().GetTotalVoters() != 0 {
+				t.Errorf("poll still has %d voters after retracting", message.GetPoll().GetTotalVoters())
+			}
+		})
+	}
+}
+
+func TestClosePollHandler(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name           string
+		poll           *MessagePoll
+		userId         int
+		expectedErr    error
+		expectedEvents []string
+	}{
+		{name: "sender closes", poll: newTestPoll(false, nil, nil), userId: 1, expectedEvents: []string{"poll_updated"}},
+		{name: "admin closes", poll: newTestPoll(false, nil, nil), userId: 3, expectedEvents: []string{"poll_updated"}},
+		{name: "member can't close", poll: newTestPoll(false, nil, nil), userId: 2, expectedErr: ErrCantClosePoll},
+		{name: "already closed", poll: newTestPoll(false, nil, &past), userId: 1},
+		{name: "not a poll", userId: 1, expectedErr: ErrMessageIsNotPoll},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			messagesAdapter := NewTestMessagesAdapter([]Message{newTestPollMessage(1, 1, chats.NewTestGroupChat(), test.poll)}, nil)
+			eventsAdapter := &TestMessageEventsAdapter{}
+			handler := NewClosePollHandler(messagesAdapter, eventsAdapter)
+
+			message, err := handler.Execute(1, test.userId)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+			if test.expectedErr == nil && !message.GetPoll().IsClosed() {
+				t.Errorf("poll is not closed")
+			}
+			if !slices.Equal(eventsAdapter.sentEvents, test.expectedEvents) {
+				t.Errorf("sent events = %v, expected %v", eventsAdapter.sentEvents, test.expectedEvents)
+			}
+		})
+	}
+}
+
+func newTestCreatePollData(options []string, closesAt *time.Time) *CreatePollData {
+	poll := NewCreatePollData(options, false, false, closesAt)
+	return &poll
+}
+
+func TestCreatePollMessageHandler(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	question := "question"
+	empty := ""
+	tests := []struct {
+		name            string
+		content         *string
+		poll            *CreatePollData
+		expectedErr     error
+		expectedOptions []string
+	}{
+		{
+			name:            "poll with options",
+			content:         &question,
+			poll:            newTestCreatePollData([]string{"first", "second"}, nil),
+			expectedOptions: []string{"first", "second"},
+		},
+		{name: "poll without question", content: &empty, poll: newTestCreatePollData([]string{"first", "second"}, nil), expectedErr: ErrIncorrectPollMessage},
+		{name: "poll with one option", content: &question, poll: newTestCreatePollData([]string{"first"}, nil), expectedErr: ErrIncorrectPollMessage},
+		{name: "poll with empty option", content: &question, poll: newTestCreatePollData([]string{"first", ""}, nil), expectedErr: ErrIncorrectPollMessage},
+		{name: "poll without options", content: &question, expectedErr: ErrIncorrectPollMessage},
+		{
+			name:        "poll closing in the past",
+			content:     &question,
+			poll:        newTestCreatePollData([]string{"first", "second"}, &past),
+			expectedErr: ErrIncorrectPollClosesAt,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			groupChat := chats.NewTestGroupChat()
+			handler := NewCreateMessageHandler(chats.NewTestChatsAdapter(groupChat), NewTestMessagesAdapter(nil, nil), &TestMessageEventsAdapter{}, nil, &TestMessagesSchedulerAdapter{}, &TestSlowModeAdapter{})
+
+			data := NewCreateMessageData(groupChat.GetId(), PollMessageType, test.content, nil, nil, nil, nil, nil, nil, test.poll)
+			message, err := handler.Execute(data, 2)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("error = %v, expected %v", err, test.expectedErr)
+			}
+			if test.expectedErr != nil {
+				return
+			}
+
+			var options []string
+			for _, option := range message.GetPoll().GetOptions() {
+				options = append(options, option.GetContent())
+			}
+			if !slices.Equal(options, test.expectedOptions) {
+				t.Errorf("poll options = %v, expected %v", options, test.expectedOptions)
+			}
+		})
+	}
+}
+
+func TestSearchMessagesHandler(t *testing.T) {
+	groupChat := chats.NewTestGroupChat()
+	otherChat := chats.NewChat(2, nil, "group chat 1", chats.GroupChatType, []int{1, 2}, false, 1, []int{1})
+	hiddenMessage := newTestMessage(3, 1, groupChat, nil)
+	hiddenMessage.DeleteFor([]int{1})
+	existingMessages := []Message{
+		newTestMessage(1, 2, groupChat, nil),
+		newTestMessage(2, 3, otherChat, nil),
+		hiddenMessage,
+		newTestMessage(4, 1, groupChat, nil),
+	}
+	earlier := time.Now().Add(-time.Hour)
+	later := time.Now()
+	chatId := groupChat.GetId()
+	senderId := 2
+	tests := []struct {
+		name        string
+		filter      SearchMessagesFilter
+		userId      int
+		expectedErr error
+		expectedIds []int
+	}{
+		{name: "messages visible for the user", filter: NewSearchMessagesFilter("MESSAGE", nil, nil, nil, nil, nil), userId: 1, expectedIds: []int{1, 2, 4}},
+		{name: "messages of the chat", filter: NewSearchMessagesFilter("message", &chatId, nil, nil, nil, nil), userId: 3, expectedIds: []int{1, 3, 4}},
+		{name: "messages of the sender", filter: NewSearchMessagesFilter("message", nil, &senderId, nil, nil, nil), userId: 1, expectedIds: []int{1}},
+		{name: "blank query", filter: NewSearchMessagesFilter("  ", nil, nil, nil, nil, nil), userId: 1, expectedErr: ErrIncorrectSearchQuery},
+		{name: "period ending before it starts", filter: NewSearchMessagesFilter("message", nil, nil, nil, &later, &earlier), userId: 1, expectedErr: ErrIncorrectSearchPeriod},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler := NewSearchMessagesHandler(NewTestMessagesAdapter(existingMessages, nil))
+			results, err := handler.Execute(test.filter, test.userId, 0, 10)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("error = %v, expected %v", err, test.expectedErr)
+			}
+			if test.expectedErr != nil {
+				return
+			}
+
+			var ids []int
+			for _, result := range results.GetData() {
+				message := result.GetMessage()
+				ids = append(ids, message.GetId())
+			}
+			if !slices.Equal(ids, test.expectedIds) {
+				t.Errorf("found messages = %v, expected %v", ids, test.expectedIds)
+			}
+		})
+	}
+}
+
+func TestMoveReadPointer(t *testing.T) {
+	createdAt := time.Now()
+	tests := []struct {
+		name              string
+		pointers          []ChatReadPointer
+		expectedMoved     bool
+		expectedMessageId int
+	}{
+		{
+			name:              "no pointer yet",
+			expectedMoved:     true,
+			expectedMessageId: 5,
+		},
+		{
+			name:              "pointer behind the message",
+			pointers:          []ChatReadPointer{NewChatReadPointer(10, 2, 4, createdAt.Add(-time.Second))},
+			expectedMoved:     true,
+			expectedMessageId: 5,
+		},
+		{
+			name:              "pointer at the message",
+			pointers:          []ChatReadPointer{NewChatReadPointer(10, 2, 5, createdAt)},
+			expectedMoved:     false,
+			expectedMessageId: 5,
+		},
+		{
+			name:              "pointer ahead of the message",
+			pointers:          []ChatReadPointer{NewChatReadPointer(10, 2, 6, createdAt.Add(time.Second))},
+			expectedMoved:     false,
+			expectedMessageId: 6,
+		},
+		{
+			name:              "pointer of another user",
+			pointers:          []ChatReadPointer{NewChatReadPointer(10, 3, 6, createdAt.Add(time.Second))},
+			expectedMoved:     true,
+			expectedMessageId: 5,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			message := newTestMessage(5, 1, chats.NewTestGroupChat(), nil)
+			message.createdAt = &createdAt
+			messagesAdapter := NewTestMessagesAdapter([]Message{message}, test.pointers)
+
+			pointer, moved, err := moveReadPointer(messagesAdapter, message, 2)
+			if err != nil {
+				t.Fatalf("moveReadPointer() error = %v", err)
+			}
+			if moved != test.expectedMoved {
+				t.Errorf("moved = %v, expected %v", moved, test.expectedMoved)
+			}
+			if pointer.GetMessageId() != test.expectedMessageId {
+				t.Errorf("pointer message id = %d, expected %d", pointer.GetMessageId(), test.expectedMessageId)
+			}
+			if saved := messagesAdapter.savedPointer != nil; saved != test.expectedMoved {
+				t.Errorf("pointer saved = %v, expected %v", saved, test.expectedMoved)
+			}
+		})
+	}
+}
+
+// unreadChatsAdapter reports the same unread counters for every chat.
+type unreadChatsAdapter struct {
+	*chats.TestChatsAdapter
+	counters chats.ChatUnreadCounters
+}
+
+func (adapter unreadChatsAdapter) GetUnreadCounters(chatIds []int, userId int) map[int]chats.ChatUnreadCounters {
+	counters := make(map[int]chats.ChatUnreadCounters)
+	for _, chatId := range chatIds {
+		counters[chatId] = adapter.counters
+	}
+
+	return counters
+}
+
+// unreadChatEventsAdapter records per-user chat events with their unread
+// counters next to the message events, to check the order of both.
+type unreadChatEventsAdapter struct {
+	*chats.TestChatEventsAdapter
+	messageEvents *TestMessageEventsAdapter
+}
+
+func (adapter *unreadChatEventsAdapter) SendChatChangedForUser(chat chats.Chat, userId int) error {
+	counters := chat.GetUnreadCounters()
+	return adapter.messageEvents.send(fmt.Sprintf("chat_changed:%d unread=%d mentions=%d", userId, counters.GetUnreadCount(), counters.GetUnreadMentionsCount()))
+}
+
+func TestMessageReadByPointers(t *testing.T) {
+	sentAt := time.Now().Add(-time.Hour)
+	publishedAt := time.Now()
+	// The scheduled message got id 5 when it was scheduled and was published
+	// after message 10, so its id is lower while it is newer.
+	sentMessage := newTestMessage(10, 1, chats.NewTestGroupChat(), nil)
+	sentMessage.createdAt = &sentAt
+	publishedMessage := newTestMessage(5, 1, chats.NewTestGroupChat(), nil)
+	publishedMessage.createdAt = &publishedAt
+
+	tests := []struct {
+		name             string
+		message          Message
+		pointers         []ChatReadPointer
+		expectedReadedBy []int
+	}{
+		{
+			name:             "pointer on the published message reads the older message",
+			message:          sentMessage,
+			pointers:         []ChatReadPointer{NewChatReadPointer(1, 2, 5, publishedAt)},
+			expectedReadedBy: []int{2},
+		},
+		{
+			name:             "pointer on the published message reads it",
+			message:          publishedMessage,
+			pointers:         []ChatReadPointer{NewChatReadPointer(1, 2, 5, publishedAt)},
+			expectedReadedBy: []int{2},
+		},
+		{
+			name:     "pointer on a higher id doesn't read the newer published message",
+			message:  publishedMessage,
+			pointers: []ChatReadPointer{NewChatReadPointer(1, 2, 10, sentAt)},
+		},
+		{
+			name:     "sender pointer doesn't read own message",
+			message:  sentMessage,
+			pointers: []ChatReadPointer{NewChatReadPointer(1, 1, 10, sentAt)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			message := test.message
+			message.readedBy = nil
+			message.ReadByPointers(test.pointers)
+			if !slices.Equal(message.GetReadedBy(), test.expectedReadedBy) {
+				t.Errorf("readedBy = %v, expected %v", message.GetReadedBy(), test.expectedReadedBy)
+			}
+		})
+	}
+}
+
+func TestReadMessageHandler(t *testing.T) {
+	createdAt := time.Now()
+	tests := []struct {
+		name           string
+		readedBy       []int
+		pointers       []ChatReadPointer
+		expectedEvents []string
+	}{
+		{name: "unread message", expectedEvents: []string{"message_readed", "chat_changed:2 unread=4 mentions=1"}},
+		{name: "already read message", readedBy: []int{2}},
+		{name: "message behind the pointer", pointers: []ChatReadPointer{NewChatReadPointer(10, 2, 6, createdAt.Add(time.Second))}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			message := newTestMessage(5, 1, chats.NewTestGroupChat(), nil)
+			message.createdAt = &createdAt
+			message.readedBy = test.readedBy
+			messagesAdapter := NewTestMessagesAdapter([]Message{message}, test.pointers)
+			eventsAdapter := &TestMessageEventsAdapter{}
+			chatEventsAdapter := &unreadChatEventsAdapter{messageEvents: eventsAdapter}
+			handler := NewReadMessageHandler(
+				unreadChatsAdapter{TestChatsAdapter: chats.NewTestChatsAdapter(), counters: chats.NewChatUnreadCounters(4, 1)},
+				messagesAdapter,
+				eventsAdapter,
+				chatEventsAdapter,
+			)
+
+			if _, err := handler.Execute(5, 2); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if !slices.Equal(eventsAdapter.sentEvents, test.expectedEvents) {
+				t.Errorf("sent events = %v, expected %v", eventsAdapter.sentEvents, test.expectedEvents)
+			}
+		})
+	}
+}
+
+func TestReadChatUntilHandler(t *testing.T) {
+	createdAt := time.Now()
+	groupChat := chats.NewTestGroupChat()
+	otherChat := chats.NewChat(2, nil, "group chat 1", chats.GroupChatType, []int{1, 2}, false, 1, []int{1})
+	tests := []struct {
+		name           string
+		messageId      int
+		pointers       []ChatReadPointer
+		expectedErr    error
+		expectedEvents []string
+	}{
+		{name: "pointer moves", messageId: 5, expectedEvents: []string{"chat_read", "chat_changed:2 unread=0 mentions=0"}},
+		{name: "pointer already ahead", messageId: 5, pointers: []ChatReadPointer{NewChatReadPointer(10, 2, 6, createdAt.Add(time.Second))}},
+		{name: "message of another chat", messageId: 7, expectedErr: ErrIncorrectReadMessage},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			message := newTestMessage(5, 1, groupChat, nil)
+			message.createdAt = &createdAt
+			otherMessage := newTestMessage(7, 1, otherChat, nil)
+			otherMessage.createdAt = &createdAt
+			eventsAdapter := &TestMessageEventsAdapter{}
+			handler := NewReadChatUntilHandler(
+				unreadChatsAdapter{TestChatsAdapter: chats.NewTestChatsAdapter(groupChat)},
+				NewTestMessagesAdapter([]Message{message, otherMessage}, test.pointers),
+				eventsAdapter,
+				&unreadChatEventsAdapter{messageEvents: eventsAdapter},
+			)
+
+			if _, err := handler.Execute(groupChat.GetId(), test.messageId, 2); !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+			if !slices.Equal(eventsAdapter.sentEvents, test.expectedEvents) {
+				t.Errorf("sent events = %v, expected %v", eventsAdapter.sentEvents, test.expectedEvents)
+			}
+		})
+	}
+}
+
+func TestCreateMessageHandlerPermissions(t *testing.T) {
+	voice := files.NewUploadingFile(files.NewUploadingFileMeta("url", "voice.ogg", "signature", files.VoiceFiletype), nil)
+	tests := []struct {
+		name        string
+		permissions chats.MemberPermissions
+		userId      int
+		voice       *files.UploadingFile
+		expectedErr error
+	}{
+		{name: "member can't send messages", permissions: chats.NewMemberPermissions(false, false, false, false), userId: 2, expectedErr: ErrCantSendMessages},
+		{name: "member can't send media", permissions: chats.NewMemberPermissions(true, false, false, false), userId: 2, voice: &voice, expectedErr: ErrCantSendMedia},
+		{name: "member sends text without media permission", permissions: chats.NewMemberPermissions(true, false, false, false), userId: 2},
+		{name: "admin isn't limited by member permissions", permissions: chats.NewMemberPermissions(false, false, false, false), userId: 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			groupChat := chats.NewTestGroupChat()
+			groupChat.SetMemberPermissions(test.permissions)
+			handler := NewCreateMessageHandler(chats.NewTestChatsAdapter(groupChat), NewTestMessagesAdapter(nil, nil), &TestMessageEventsAdapter{}, nil, &TestMessagesSchedulerAdapter{}, &TestSlowModeAdapter{})
+
+			type_ := TextMessageType
+			if test.voice != nil {
+				type_ = VoiceMessageType
+			}
+			content := "message"
+			data := NewCreateMessageData(groupChat.GetId(), type_, &content, test.voice, nil, nil, nil, nil, nil, nil)
+			if _, err := handler.Execute(data, test.userId); !errors.Is(err, test.expectedErr) {
+				t.Errorf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestCreateMessageHandlerInChannel(t *testing.T) {
+	tests := []struct {
+		name        string
+		userId      int
+		expectedErr error
+	}{
+		{name: "owner posts", userId: 1},
+		{name: "subscriber can't post", userId: 2, expectedErr: ErrOnlyAdminsCanPost},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			channel := chats.NewChat(10, nil, "channel", chats.ChannelChatType, []int{1, 2}, false, 1, []int{1})
+			messagesAdapter := NewTestMessagesAdapter(nil, nil)
+			handler := NewCreateMessageHandler(chats.NewTestChatsAdapter(channel), messagesAdapter, &TestMessageEventsAdapter{}, nil, &TestMessagesSchedulerAdapter{}, &TestSlowModeAdapter{})
+
+			content := "post"
+			data := NewCreateMessageData(channel.GetId(), TextMessageType, &content, nil, nil, nil, nil, nil, nil, nil)
+			if _, err := handler.Execute(data, test.userId); !errors.Is(err, test.expectedErr) {
+				t.Errorf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestCreateMessageHandlerSlowMode(t *testing.T) {
+	content := "message"
+	sendAt := time.Now().Add(time.Hour)
+	tests := []struct {
+		name             string
+		userId           int
+		data             CreateMessageData
+		remainingWait    time.Duration
+		expectedWait     time.Duration
+		expectedErr      error
+		expectedAcquired bool
+		expectedSaved    bool
+	}{
+		{
+			name:             "message takes the slot",
+			userId:           2,
+			data:             NewCreateMessageData(10, TextMessageType, &content, nil, nil, nil, nil, nil, nil, nil),
+			expectedAcquired: true,
+			expectedSaved:    true,
+		},
+		{
+			name:             "busy slot rejects the message",
+			userId:           2,
+			data:             NewCreateMessageData(10, TextMessageType, &content, nil, nil, nil, nil, nil, nil, nil),
+			remainingWait:    20 * time.Second,
+			expectedWait:     20 * time.Second,
+			expectedAcquired: true,
+		},
+		{
+			name:          "admin is exempt",
+			userId:        3,
+			data:          NewCreateMessageData(10, TextMessageType, &content, nil, nil, nil, nil, nil, nil, nil),
+			remainingWait: time.Second,
+			expectedSaved: true,
+		},
+		{
+			name:          "scheduled message takes the slot only when published",
+			userId:        2,
+			data:          NewCreateMessageData(10, TextMessageType, &content, nil, nil, nil, nil, nil, &sendAt, nil),
+			remainingWait: time.Second,
+			expectedSaved: true,
+		},
+		{
+			name:        "invalid message doesn't take the slot",
+			userId:      2,
+			data:        NewCreateMessageData(10, TextMessageType, nil, nil, nil, nil, nil, nil, nil, nil),
+			expectedErr: ErrIncorrectTextMessage,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			groupChat := chats.NewTestGroupChat()
+			groupChat.SetSlowModeInterval(time.Minute)
+			messagesAdapter := NewTestMessagesAdapter(nil, nil)
+			slowModeAdapter := &TestSlowModeAdapter{remainingWait: test.remainingWait}
+			handler := NewCreateMessageHandler(
+				chats.NewTestChatsAdapter(groupChat),
+				messagesAdapter,
+				&TestMessageEventsAdapter{},
+				nil,
+				&TestMessagesSchedulerAdapter{},
+				slowModeAdapter,
+			)
+
+			_, err := handler.Execute(test.data, test.userId)
+			var slowModeErr *SlowModeError
+			switch {
+			case test.expectedWait > 0:
+				if !errors.As(err, &slowModeErr) || slowModeErr.GetRemainingWait() != test.expectedWait {
+					t.Fatalf("Execute() error = %v, expected slow mode error with %s wait", err, test.expectedWait)
+				}
+			case !errors.Is(err, test.expectedErr):
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+
+			if acquired := slices.Equal(slowModeAdapter.acquiredBy, []int{test.userId}); acquired != test.expectedAcquired {
+				t.Errorf("slot acquired = %v, expected %v", acquired, test.expectedAcquired)
+			}
+			if saved := len(messagesAdapter.messages) > 0; saved != test.expectedSaved {
+				t.Errorf("message saved = %v, expected %v", saved, test.expectedSaved)
+			}
+		})
+	}
+}
+
+func TestForwardMessagesHandlerSlowMode(t *testing.T) {
+	tests := []struct {
+		name                  string
+		chatRemainingWaits    map[int]time.Duration
+		expectedReleasedChats []int
+		expectedForwarded     int
+	}{
+		{name: "slots are free in every chat", expectedForwarded: 2},
+		{name: "second chat is rate limited", chatRemainingWaits: map[int]time.Duration{11: time.Second}, expectedReleasedChats: []int{10}},
+		{name: "first chat is rate limited", chatRemainingWaits: map[int]time.Duration{10: time.Second}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			firstChat := chats.NewTestGroupChat()
+			firstChat.SetSlowModeInterval(time.Minute)
+			secondChat := chats.NewChat(11, nil, "second chat", chats.GroupChatType, []int{1, 2}, false, 1, []int{1})
+			secondChat.SetSlowModeInterval(time.Minute)
+			messagesAdapter := NewTestMessagesAdapter([]Message{newTestMessage(5, 2, firstChat, nil)}, nil)
+			slowModeAdapter := &TestSlowModeAdapter{chatRemainingWaits: test.chatRemainingWaits}
+			handler := NewForwardMessagesHandler(chats.NewTestChatsAdapter(firstChat, secondChat), messagesAdapter, &TestMessageEventsAdapter{}, slowModeAdapter)
+
+			forwardedMessages, err := handler.Execute([]int{5}, []int{10, 11}, 2)
+			var slowModeErr *SlowModeError
+			if test.chatRemainingWaits == nil && err != nil {
+				t.Fatalf("Execute() error = %v, expected nil", err)
+			}
+			if test.chatRemainingWaits != nil && !errors.As(err, &slowModeErr) {
+				t.Fatalf("Execute() error = %v, expected slow mode error", err)
+			}
+
+			if !slices.Equal(slowModeAdapter.releasedChats, test.expectedReleasedChats) {
+				t.Errorf("released chats = %v, expected %v", slowModeAdapter.releasedChats, test.expectedReleasedChats)
+			}
+			if len(forwardedMessages) != test.expectedForwarded {
+				t.Errorf("forwarded messages = %d, expected %d", len(forwardedMessages), test.expectedForwarded)
+			}
+			if savedMessages := len(messagesAdapter.messages) - 1; savedMessages != test.expectedForwarded {
+				t.Errorf("saved messages = %d, expected %d", savedMessages, test.expectedForwarded)
+			}
+		})
+	}
+}
+
+var errTestConnection = errors.New("test connection refused")
+
+// unavailableChatsAdapter fails chat lookups the way a lost database
+// connection does.
+type unavailableChatsAdapter struct {
+	*chats.TestChatsAdapter
+}
+
+func (adapter unavailableChatsAdapter) GetByIdForUser(id int, userId int) (*chats.Chat, error) {
+	return nil, errTestConnection
+}
+
+func TestPublishScheduledMessageHandler(t *testing.T) {
+	tests := []struct {
+		name              string
+		senderLeft        bool
+		unavailable       bool
+		remainingWait     time.Duration
+		expectedErr       error
+		expectedEvents    []string
+		expectedPublished bool
+		expectedDeleted   bool
+	}{
+		{name: "publishes the message", expectedEvents: []string{"message_created"}, expectedPublished: true},
+		{
+			name:            "drops the message of a sender who left the chat",
+			senderLeft:      true,
+			expectedEvents:  []string{"scheduled_message_dropped"},
+			expectedDeleted: true,
+		},
+		{name: "keeps the message when the chat can't be loaded", unavailable: true, expectedErr: errTestConnection},
+		{name: "moves the message after a busy slow mode slot", remainingWait: time.Minute},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chat := chats.NewTestGroupChat()
+			chat.SetSlowModeInterval(time.Minute)
+			if test.senderLeft {
+				chat.SetMembers([]int{1, 3})
+			}
+			testChatsAdapter := chats.NewTestChatsAdapter(chat)
+			var chatsAdapter chats.ChatsPort = testChatsAdapter
+			if test.unavailable {
+				chatsAdapter = unavailableChatsAdapter{TestChatsAdapter: testChatsAdapter}
+			}
+
+			sendAt := time.Now().Add(-time.Second)
+			message := newTestMessage(1, 2, chat, nil)
+			message.SetSendAt(&sendAt)
+			messagesAdapter := NewTestMessagesAdapter([]Message{message}, nil)
+			eventsAdapter := &TestMessageEventsAdapter{}
+			schedulerAdapter := &TestMessagesSchedulerAdapter{}
+			handler := NewPublishScheduledMessageHandler(
+				chatsAdapter,
+				messagesAdapter,
+				eventsAdapter,
+				schedulerAdapter,
+				&TestSlowModeAdapter{remainingWait: test.remainingWait},
+			)
+
+			if err := handler.Execute(1); !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+
+			if !slices.Equal(eventsAdapter.sentEvents, test.expectedEvents) {
+				t.Errorf("sent events = %v, expected %v", eventsAdapter.sentEvents, test.expectedEvents)
+			}
+
+			savedMessage, err := messagesAdapter.GetById(1)
+			if deleted := err != nil; deleted != test.expectedDeleted {
+				t.Fatalf("message deleted = %v, expected %v", deleted, test.expectedDeleted)
+			}
+			if test.expectedDeleted {
+				return
+			}
+
+			if published := !savedMessage.IsScheduled(); published != test.expectedPublished {
+				t.Errorf("message published = %v, expected %v", published, test.expectedPublished)
+			}
+			if test.remainingWait > 0 {
+				if !savedMessage.GetSendAt().After(time.Now()) || !slices.Equal(schedulerAdapter.scheduledMessages, []int{1}) {
+					t.Errorf("message sendAt = %v, scheduled = %v, expected it rescheduled after the slow mode wait", savedMessage.GetSendAt(), schedulerAdapter.scheduledMessages)
+				}
+			}
+		})
+	}
+}
+
+func TestChatSystemMessagesHandler(t *testing.T) {
+	title := "new title"
+	tests := []struct {
+		name            string
+		event           chats.ChatSystemEvents
+		value           *string
+		expectedContent string
+	}{
+		{name: "event without value", event: chats.JoinedViaLinkSystemEvent, expectedContent: `{"event":"joined_via_link"}`},
+		{name: "event with value", event: chats.TitleChangedSystemEvent, value: &title, expectedContent: `{"event":"title_changed","value":"new title"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			messagesAdapter := NewTestMessagesAdapter(nil, nil)
+			eventsAdapter := &TestMessageEventsAdapter{}
+			handler := NewChatSystemMessagesHandler(messagesAdapter, eventsAdapter)
+
+			handler.SendSystemEvent(chats.NewTestGroupChat(), 1, test.event, test.value)
+
+			if len(messagesAdapter.messages) != 1 {
+				t.Fatalf("saved messages = %d, expected 1", len(messagesAdapter.messages))
+			}
+			message := messagesAdapter.messages[0]
+			if message.GetType() != EventMessageType || message.GetContent() == nil || *message.GetContent() != test.expectedContent {
+				t.Errorf("message type = %s, content = %v, expected event message %s", message.GetType(), message.GetContent(), test.expectedContent)
+			}
+			if !slices.Equal(eventsAdapter.sentEvents, []string{"message_created"}) {
+				t.Errorf("sent events = %v, expected message_created", eventsAdapter.sentEvents)
+			}
+		})
+	}
+}
+
+func TestDeleteMessageHandlerForEveryone(t *testing.T) {
+	channel := chats.NewChat(1, nil, "channel", chats.ChannelChatType, []int{1, 2, 3, 4}, false, 1, []int{2, 3})
+	channel.SetAdminRights(2, chats.NewAdminRights(false, false, false, false, true, false))
+	userChat := chats.NewChat(1, nil, "", chats.UserChatType, []int{2, 4}, false, 0, []int{})
+	tests := []struct {
+		name        string
+		chat        chats.Chat
+		userId      int
+		expectedErr error
+	}{
+		{
+			name:   "channel admin with the right deletes an old message",
+			chat:   channel,
+			userId: 2,
+		},
+		{
+			name:        "channel admin without the right can't delete",
+			chat:        channel,
+			userId:      3,
+			expectedErr: ErrCantDeleteMessage,
+		},
+		{
+			name:        "sender can't delete after the window",
+			chat:        channel,
+			userId:      4,
+			expectedErr: ErrDeleteTimeExpired,
+		},
+		{
+			name:        "user chat member can't delete the other member's message",
+			chat:        userChat,
+			userId:      2,
+			expectedErr: ErrCantDeleteMessage,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			content := "message"
+			createdAt := time.Now().Add(-2 * time.Hour)
+			message := NewMessage(1, 4, test.chat, TextMessageType, &content, nil, nil, nil, nil, []int{}, []int{}, nil, []int{}, &createdAt)
+			messagesAdapter := NewTestMessagesAdapter([]Message{message}, nil)
+			handler := NewDeleteMessageHandler(messagesAdapter, &TestMessageEventsAdapter{}, time.Hour)
+
+			if err := handler.Execute(1, test.userId, true); !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Execute() error = %v, expected %v", err, test.expectedErr)
+			}
+
+			_, err := messagesAdapter.GetById(1)
+			if deleted := err != nil; deleted != (test.expectedErr == nil) {
+				t.Errorf("message deleted = %v, expected %v", deleted, test.expectedErr == nil)
+			}
+		})
+	}
+}
@@ -16,8 +16,32 @@ const (
 	CallMessageType   MessageTypes = "call"
 	VoiceMessageType  MessageTypes = "voice"
 	CircleMessageType MessageTypes = "circle"
+	PollMessageType   MessageTypes = "poll"
 )
 
+type CreatePollData struct {
+	options        []string
+	multipleChoice bool
+	anonymous      bool
+	closesAt       *time.Time
+}
+
+func (model *CreatePollData) GetOptions() []string {
+	return model.options
+}
+
+func (model *CreatePollData) GetMultipleChoice() bool {
+	return model.multipleChoice
+}
+
+func (model *CreatePollData) GetAnonymous() bool {
+	return model.anonymous
+}
+
+func (model *CreatePollData) GetClosesAt() *time.Time {
+	return model.closesAt
+}
+
 type CreateMessageData struct {
 	chatId      int
 	type_       MessageTypes
@@ -27,6 +51,8 @@ type CreateMessageData struct {
 	replyToId   *int
 	mentioned   []int
 	circle      *files.UploadingFile
+	sendAt      *time.Time
+	poll        *CreatePollData
 }
 
 func (model *CreateMessageData) GetChatId() int {
@@ -61,6 +87,60 @@ func (model *CreateMessageData) GetCircle() *files.UploadingFile {
 	return model.circle
 }
 
+func (model *CreateMessageData) GetSendAt() *time.Time {
+	return model.sendAt
+}
+
+func (model *CreateMessageData) GetPoll() *CreatePollData {
+	return model.poll
+}
+
+type SearchMessagesFilter struct {
+	query    string
+	chatId   *int
+	senderId *int
+	type_    *MessageTypes
+	from     *time.Time
+	to       *time.Time
+}
+
+func (model *SearchMessagesFilter) GetQuery() string {
+	return model.query
+}
+
+func (model *SearchMessagesFilter) GetChatId() *int {
+	return model.chatId
+}
+
+func (model *SearchMessagesFilter) GetSenderId() *int {
+	return model.senderId
+}
+
+func (model *SearchMessagesFilter) GetType() *MessageTypes {
+	return model.type_
+}
+
+func (model *SearchMessagesFilter) GetFrom() *time.Time {
+	return model.from
+}
+
+func (model *SearchMessagesFilter) GetTo() *time.Time {
+	return model.to
+}
+
+type MessageSearchResult struct {
+	message Message
+	snippet string
+}
+
+func (model *MessageSearchResult) GetMessage() Message {
+	return model.message
+}
+
+func (model *MessageSearchResult) GetSnippet() string {
+	return model.snippet
+}
+
 type UpdateMessageData struct {
 	content     *string
 	attachments []files.UploadingFile
@@ -92,6 +172,200 @@ func (model *MessageReaction) GetContent() string {
 	return model.content
 }
 
+type MessageThreadReply struct {
+	messageId int
+	senderId  int
+	createdAt time.Time
+}
+
+func (model *MessageThreadReply) GetMessageId() int {
+	return model.messageId
+}
+
+func (model *MessageThreadReply) GetSenderId() int {
+	return model.senderId
+}
+
+func (model *MessageThreadReply) GetCreatedAt() time.Time {
+	return model.createdAt
+}
+
+type MessageThread struct {
+	repliesCount int
+	lastReply    *MessageThreadReply
+}
+
+func (model *MessageThread) GetRepliesCount() int {
+	return model.repliesCount
+}
+
+func (model *MessageThread) GetLastReply() *MessageThreadReply {
+	return model.lastReply
+}
+
+type MessageForwardedFrom struct {
+	messageId int
+	senderId  int
+	chatId    int
+}
+
+func (model *MessageForwardedFrom) GetMessageId() int {
+	return model.messageId
+}
+
+func (model *MessageForwardedFrom) GetSenderId() int {
+	return model.senderId
+}
+
+func (model *MessageForwardedFrom) GetChatId() int {
+	return model.chatId
+}
+
+type MessagePollOption struct {
+	id      int
+	content string
+	voters  []int
+}
+
+func (model *MessagePollOption) GetId() int {
+	return model.id
+}
+
+func (model *MessagePollOption) GetContent() string {
+	return model.content
+}
+
+func (model *MessagePollOption) GetVoters() []int {
+	return model.voters
+}
+
+func (model *MessagePollOption) GetVotesCount() int {
+	return len(model.voters)
+}
+
+type MessagePoll struct {
+	options        []MessagePollOption
+	multipleChoice bool
+	anonymous      bool
+	closesAt       *time.Time
+	closedAt       *time.Time
+}
+
+func (model *MessagePoll) GetOptions() []MessagePollOption {
+	return model.options
+}
+
+func (model *MessagePoll) GetMultipleChoice() bool {
+	return model.multipleChoice
+}
+
+func (model *MessagePoll) GetAnonymous() bool {
+	return model.anonymous
+}
+
+func (model *MessagePoll) GetClosesAt() *time.Time {
+	return model.closesAt
+}
+
+func (model *MessagePoll) GetClosedAt() *time.Time {
+	return model.closedAt
+}
+
+func (model *MessagePoll) SetClosedAt(closedAt *time.Time) {
+	model.closedAt = closedAt
+}
+
+func (model *MessagePoll) IsClosed() bool {
+	if model.closedAt != nil {
+		return true
+	}
+
+	return model.closesAt != nil && !model.closesAt.After(time.Now())
+}
+
+func (model *MessagePoll) HasOption(optionId int) bool {
+	for _, option := range model.options {
+		if option.GetId() == optionId {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (model *MessagePoll) GetTotalVoters() int {
+	var voters []int
+	for _, option := range model.options {
+		for _, voter := range option.GetVoters() {
+			if !slices.Contains(voters, voter) {
+				voters = append(voters, voter)
+			}
+		}
+	}
+
+	return len(voters)
+}
+
+func (model *MessagePoll) WithoutVotes() MessagePoll {
+	var options []MessagePollOption
+	for _, option := range model.options {
+		options = append(options, NewMessagePollOption(0, option.GetContent(), []int{}))
+	}
+
+	return NewMessagePoll(options, model.multipleChoice, model.anonymous, model.closesAt, nil)
+}
+
+type MessageRevision struct {
+	messageId   int
+	content     *string
+	attachments []files.SavedFile
+	mentioned   []int
+	createdAt   time.Time
+}
+
+func (model *MessageRevision) GetMessageId() int {
+	return model.messageId
+}
+
+func (model *MessageRevision) GetContent() *string {
+	return model.content
+}
+
+func (model *MessageRevision) GetAttachments() []files.SavedFile {
+	return model.attachments
+}
+
+func (model *MessageRevision) GetMentioned() []int {
+	return model.mentioned
+}
+
+func (model *MessageRevision) GetCreatedAt() time.Time {
+	return model.createdAt
+}
+
+type ChatReadPointer struct {
+	chatId    int
+	userId    int
+	messageId int
+	readAt    time.Time
+}
+
+func (model *ChatReadPointer) GetChatId() int {
+	return model.chatId
+}
+
+func (model *ChatReadPointer) GetUserId() int {
+	return model.userId
+}
+
+func (model *ChatReadPointer) GetMessageId() int {
+	return model.messageId
+}
+
+func (model *ChatReadPointer) GetReadAt() time.Time {
+	return model.readAt
+}
+
 type Message struct {
 	id            int
 	senderId      int
@@ -102,11 +376,17 @@ type Message struct {
 	circle        *files.SavedFile
 	attachments   []files.SavedFile
 	replyToId     *int
+	threadRootId  *int
+	thread        MessageThread
+	forwardedFrom *MessageForwardedFrom
 	mentioned     []int
 	readedBy      []int
 	reactions     []MessageReaction
 	deletedForIds []int
 	createdAt     *time.Time
+	sendAt        *time.Time
+	editedAt      *time.Time
+	poll          *MessagePoll
 }
 
 func (model *Message) GetId() int {
@@ -117,6 +397,10 @@ func (model *Message) GetSenderId() int {
 	return model.senderId
 }
 
+func (model *Message) SetChat(chat chats.Chat) {
+	model.chat = chat
+}
+
 func (model *Message) GetChat() chats.Chat {
 	return model.chat
 }
@@ -153,6 +437,30 @@ func (model *Message) GetReplyToId() *int {
 	return model.replyToId
 }
 
+func (model *Message) GetThreadRootId() *int {
+	return model.threadRootId
+}
+
+func (model *Message) SetThreadRootId(threadRootId *int) {
+	model.threadRootId = threadRootId
+}
+
+func (model *Message) GetThread() MessageThread {
+	return model.thread
+}
+
+func (model *Message) SetThread(thread MessageThread) {
+	model.thread = thread
+}
+
+func (model *Message) GetForwardedFrom() *MessageForwardedFrom {
+	return model.forwardedFrom
+}
+
+func (model *Message) SetForwardedFrom(forwardedFrom *MessageForwardedFrom) {
+	model.forwardedFrom = forwardedFrom
+}
+
 func (model *Message) GetMentioned() []int {
 	return model.mentioned
 }
@@ -173,6 +481,21 @@ func (model *Message) Read(userId int) {
 	model.readedBy = append(model.readedBy, userId)
 }
 
+// ReadByPointers marks the message as read by every member whose read pointer
+// reaches its creation time. Pointers are compared by time and not by message
+// id, because a published scheduled message keeps its older, lower id.
+func (model *Message) ReadByPointers(pointers []ChatReadPointer) {
+	if model.createdAt == nil {
+		return
+	}
+
+	for _, pointer := range pointers {
+		if pointer.GetUserId() != model.senderId && !pointer.GetReadAt().Before(*model.createdAt) {
+			model.Read(pointer.GetUserId())
+		}
+	}
+}
+
 func (model *Message) Unread(userId int) {
 	var newReadedBy []int
 	for _, user := range model.readedBy {
@@ -231,6 +554,108 @@ func (model *Message) GetCreatedAt() *time.Time {
 	return model.createdAt
 }
 
+func (model *Message) GetSendAt() *time.Time {
+	return model.sendAt
+}
+
+func (model *Message) SetSendAt(sendAt *time.Time) {
+	model.sendAt = sendAt
+}
+
+func (model *Message) IsScheduled() bool {
+	return model.sendAt != nil
+}
+
+func (model *Message) GetEditedAt() *time.Time {
+	return model.editedAt
+}
+
+func (model *Message) SetEditedAt(editedAt *time.Time) {
+	model.editedAt = editedAt
+}
+
+func (model *Message) GetPoll() *MessagePoll {
+	return model.poll
+}
+
+func (model *Message) SetPoll(poll *MessagePoll) {
+	model.poll = poll
+}
+
+func (model *Message) GetRevision() MessageRevision {
+	createdAt := model.editedAt
+	if createdAt == nil {
+		createdAt = model.createdAt
+	}
+
+	var revisionCreatedAt time.Time
+	if createdAt != nil {
+		revisionCreatedAt = *createdAt
+	}
+
+	return NewMessageRevision(model.id, model.content, model.attachments, model.mentioned, revisionCreatedAt)
+}
+
+func NewMessageThreadReply(messageId int, senderId int, createdAt time.Time) MessageThreadReply {
+	return MessageThreadReply{
+		messageId: messageId,
+		senderId:  senderId,
+		createdAt: createdAt,
+	}
+}
+
+func NewMessageThread(repliesCount int, lastReply *MessageThreadReply) MessageThread {
+	return MessageThread{
+		repliesCount: repliesCount,
+		lastReply:    lastReply,
+	}
+}
+
+func NewMessageForwardedFrom(messageId int, senderId int, chatId int) MessageForwardedFrom {
+	return MessageForwardedFrom{
+		messageId: messageId,
+		senderId:  senderId,
+		chatId:    chatId,
+	}
+}
+
+func NewMessagePollOption(id int, content string, voters []int) MessagePollOption {
+	return MessagePollOption{
+		id:      id,
+		content: content,
+		voters:  voters,
+	}
+}
+
+func NewMessagePoll(options []MessagePollOption, multipleChoice bool, anonymous bool, closesAt *time.Time, closedAt *time.Time) MessagePoll {
+	return MessagePoll{
+		options:        options,
+		multipleChoice: multipleChoice,
+		anonymous:      anonymous,
+		closesAt:       closesAt,
+		closedAt:       closedAt,
+	}
+}
+
+func NewMessageRevision(messageId int, content *string, attachments []files.SavedFile, mentioned []int, createdAt time.Time) MessageRevision {
+	return MessageRevision{
+		messageId:   messageId,
+		content:     content,
+		attachments: attachments,
+		mentioned:   mentioned,
+		createdAt:   createdAt,
+	}
+}
+
+func NewChatReadPointer(chatId int, userId int, messageId int, readAt time.Time) ChatReadPointer {
+	return ChatReadPointer{
+		chatId:    chatId,
+		userId:    userId,
+		messageId: messageId,
+		readAt:    readAt,
+	}
+}
+
 func NewMessageReaction(userId int, content string) MessageReaction {
 	return MessageReaction{
 		userId:  userId,
@@ -281,6 +706,8 @@ func NewCreateMessageData(
 	replyToId *int,
 	mentioned []int,
 	circle *files.UploadingFile,
+	sendAt *time.Time,
+	poll *CreatePollData,
 ) CreateMessageData {
 	return CreateMessageData{
 		chatId:      chatId,
@@ -291,6 +718,42 @@ func NewCreateMessageData(
 		replyToId:   replyToId,
 		mentioned:   mentioned,
 		circle:      circle,
+		sendAt:      sendAt,
+		poll:        poll,
+	}
+}
+
+func NewCreatePollData(options []string, multipleChoice bool, anonymous bool, closesAt *time.Time) CreatePollData {
+	return CreatePollData{
+		options:        options,
+		multipleChoice: multipleChoice,
+		anonymous:      anonymous,
+		closesAt:       closesAt,
+	}
+}
+
+func NewSearchMessagesFilter(
+	query string,
+	chatId *int,
+	senderId *int,
+	type_ *MessageTypes,
+	from *time.Time,
+	to *time.Time,
+) SearchMessagesFilter {
+	return SearchMessagesFilter{
+		query:    query,
+		chatId:   chatId,
+		senderId: senderId,
+		type_:    type_,
+		from:     from,
+		to:       to,
+	}
+}
+
+func NewMessageSearchResult(message Message, snippet string) MessageSearchResult {
+	return MessageSearchResult{
+		message: message,
+		snippet: snippet,
 	}
 }
 
@@ -1,6 +1,8 @@
 package messages
 
 import (
+	"time"
+
 	"github.com/chack-check/chats-service/domain/chats"
 	"github.com/chack-check/chats-service/domain/files"
 	"github.com/chack-check/chats-service/domain/utils"
@@ -9,21 +11,58 @@ import (
 type MessagesPort interface {
 	GetChatAllForUser(chatId int, userId int, offset int, limit int) utils.OffsetResponse[Message]
 	GetChatCursorAllForUser(chatId int, userId int, messageId int, aroundOffset int) utils.OffsetResponse[Message]
+	GetThreadAllForUser(rootMessageId int, userId int, offset int, limit int) utils.OffsetResponse[Message]
+	SearchForUser(filter SearchMessagesFilter, userId int, offset int, limit int) utils.OffsetResponse[MessageSearchResult]
 	GetChatsLast(chatIds []int, userId int) []Message
 	GetByIdForUser(messageId int, userId int) (*Message, error)
 	GetByIdsForUser(messageIds []int, userId int) []Message
 	GetById(messageId int) (*Message, error)
 	Save(message Message) (*Message, error)
 	Delete(message Message)
+	DeleteForUser(message Message, userId int) error
+	Pin(message Message, pinnedBy int) error
+	Unpin(message Message) error
+	GetChatPinned(chatId int, userId int) []Message
+	GetChatScheduledForUser(chatId int, userId int) []Message
+	GetScheduledByIdForUser(messageId int, userId int) (*Message, error)
+	GetAllScheduled() []Message
+	PublishScheduled(message Message) (*Message, error)
+	SaveRevision(revision MessageRevision) error
+	GetRevisions(message Message) []MessageRevision
+	VotePoll(message Message, userId int, optionIds []int) error
+	RetractPollVote(message Message, userId int) error
+	ClosePoll(message Message, closedAt time.Time) error
+	GetReadPointer(chatId int, userId int) (*ChatReadPointer, error)
+	SaveReadPointer(pointer ChatReadPointer) error
 }
 
 type MessageEventsPort interface {
-	SendMessageReacted(message Message)
-	SendReactionDeleted(message Message)
-	SendMessageReaded(message Message)
-	SendMessageDeleted(message Message)
-	SendMessageUpdated(message Message)
-	SendMessageCreated(message Message)
+	SendMessageReacted(message Message) error
+	SendReactionDeleted(message Message) error
+	SendMessageReaded(message Message) error
+	SendMessageDeleted(message Message) error
+	SendMessageDeletedForUser(message Message, userId int) error
+	SendMessageUpdated(message Message) error
+	SendMessageCreated(message Message) error
+	SendThreadUpdated(message Message) error
+	SendMessagePinned(message Message) error
+	SendMessageUnpinned(message Message) error
+	SendPollUpdated(message Message) error
+	SendChatRead(chat chats.Chat, pointer ChatReadPointer) error
+	SendScheduledMessageDropped(message Message) error
+}
+
+type MessagesSchedulerPort interface {
+	Schedule(message Message)
+	Cancel(message Message)
+}
+
+type SlowModePort interface {
+	// Acquire reserves the user's next message slot in the chat and returns
+	// the remaining wait when the previous slot is still active.
+	Acquire(chatId int, userId int, interval time.Duration) time.Duration
+	// Release frees the user's active slot in the chat.
+	Release(chatId int, userId int)
 }
 
 func NewCreateMessageHandler(
@@ -31,21 +70,41 @@ func NewCreateMessageHandler(
 	messagesPort MessagesPort,
 	messageEventsPort MessageEventsPort,
 	filesPort files.FilesPort,
+	schedulerPort MessagesSchedulerPort,
+	slowModePort SlowModePort,
 ) CreateMessageHandler {
 	return CreateMessageHandler{
 		chatsPort:         chatsPort,
 		messagesPort:      messagesPort,
 		messageEventsPort: messageEventsPort,
 		filesPort:         filesPort,
+		schedulerPort:     schedulerPort,
+		slowModePort:      slowModePort,
+	}
+}
+
+func NewForwardMessagesHandler(
+	chatsPort chats.ChatsPort,
+	messagesPort MessagesPort,
+	messageEventsPort MessageEventsPort,
+	slowModePort SlowModePort,
+) ForwardMessagesHandler {
+	return ForwardMessagesHandler{
+		chatsPort:         chatsPort,
+		messagesPort:      messagesPort,
+		messageEventsPort: messageEventsPort,
+		slowModePort:      slowModePort,
 	}
 }
 
 func NewUpdateMessageHandler(
+	chatsPort chats.ChatsPort,
 	messagesPort MessagesPort,
 	messageEventsPort MessageEventsPort,
 	filesPort files.FilesPort,
 ) UpdateMessageHandler {
 	return UpdateMessageHandler{
+		chatsPort:         chatsPort,
 		messagesPort:      messagesPort,
 		messageEventsPort: messageEventsPort,
 		filesPort:         filesPort,
@@ -53,12 +112,30 @@ func NewUpdateMessageHandler(
 }
 
 func NewReadMessageHandler(
+	chatsPort chats.ChatsPort,
 	messagesPort MessagesPort,
 	messageEventsPort MessageEventsPort,
+	chatEventsPort chats.ChatEventsPort,
 ) ReadMessageHandler {
 	return ReadMessageHandler{
+		chatsPort:         chatsPort,
 		messagesPort:      messagesPort,
 		messageEventsPort: messageEventsPort,
+		chatEventsPort:    chatEventsPort,
+	}
+}
+
+func NewReadChatUntilHandler(
+	chatsPort chats.ChatsPort,
+	messagesPort MessagesPort,
+	messageEventsPort MessageEventsPort,
+	chatEventsPort chats.ChatEventsPort,
+) ReadChatUntilHandler {
+	return ReadChatUntilHandler{
+		chatsPort:         chatsPort,
+		messagesPort:      messagesPort,
+		messageEventsPort: messageEventsPort,
+		chatEventsPort:    chatEventsPort,
 	}
 }
 
@@ -85,10 +162,12 @@ func NewDeleteMessageReactionHandler(
 func NewDeleteMessageHandler(
 	messagesPort MessagesPort,
 	messageEventsPort MessageEventsPort,
+	deleteForEveryoneWindow time.Duration,
 ) DeleteMessageHandler {
 	return DeleteMessageHandler{
-		messagesPort:      messagesPort,
-		messageEventsPort: messageEventsPort,
+		messagesPort:            messagesPort,
+		messageEventsPort:       messageEventsPort,
+		deleteForEveryoneWindow: deleteForEveryoneWindow,
 	}
 }
 
@@ -112,6 +191,22 @@ func NewGetChatMessagesByCursorHandler(
 	}
 }
 
+func NewGetThreadMessagesHandler(
+	messagesPort MessagesPort,
+) GetThreadMessagesHandler {
+	return GetThreadMessagesHandler{
+		messagesPort: messagesPort,
+	}
+}
+
+func NewSearchMessagesHandler(
+	messagesPort MessagesPort,
+) SearchMessagesHandler {
+	return SearchMessagesHandler{
+		messagesPort: messagesPort,
+	}
+}
+
 func NewGetChatsLastMessagesHandler(
 	chatsPort chats.ChatsPort,
 	messagesPort MessagesPort,
@@ -141,3 +236,165 @@ func NewGetMessagesByidsHandler(
 func NewRecognizeMessageHandler(messagesPort MessagesPort, messageEventsPort MessageEventsPort) RecognizeMessageHandler {
 	return RecognizeMessageHandler{messagesPort: messagesPort, messageEventsPort: messageEventsPort}
 }
+
+func NewPinMessageHandler(
+	messagesPort MessagesPort,
+	messageEventsPort MessageEventsPort,
+) PinMessageHandler {
+	return PinMessageHandler{
+		messagesPort:      messagesPort,
+		messageEventsPort: messageEventsPort,
+	}
+}
+
+func NewUnpinMessageHandler(
+	messagesPort MessagesPort,
+	messageEventsPort MessageEventsPort,
+) UnpinMessageHandler {
+	return UnpinMessageHandler{
+		messagesPort:      messagesPort,
+		messageEventsPort: messageEventsPort,
+	}
+}
+
+func NewGetPinnedMessagesHandler(
+	chatsPort chats.ChatsPort,
+	messagesPort MessagesPort,
+) GetPinnedMessagesHandler {
+	return GetPinnedMessagesHandler{
+		chatsPort:    chatsPort,
+		messagesPort: messagesPort,
+	}
+}
+
+func NewGetScheduledMessagesHandler(
+	chatsPort chats.ChatsPort,
+	messagesPort MessagesPort,
+) GetScheduledMessagesHandler {
+	return GetScheduledMessagesHandler{
+		chatsPort:    chatsPort,
+		messagesPort: messagesPort,
+	}
+}
+
+func NewUpdateScheduledMessageHandler(
+	chatsPort chats.ChatsPort,
+	messagesPort MessagesPort,
+	filesPort files.FilesPort,
+) UpdateScheduledMessageHandler {
+	return UpdateScheduledMessageHandler{
+		chatsPort:    chatsPort,
+		messagesPort: messagesPort,
+		filesPort:    filesPort,
+	}
+}
+
+func NewRescheduleMessageHandler(
+	messagesPort MessagesPort,
+	schedulerPort MessagesSchedulerPort,
+) RescheduleMessageHandler {
+	return RescheduleMessageHandler{
+		messagesPort:  messagesPort,
+		schedulerPort: schedulerPort,
+	}
+}
+
+func NewCancelScheduledMessageHandler(
+	messagesPort MessagesPort,
+	schedulerPort MessagesSchedulerPort,
+) CancelScheduledMessageHandler {
+	return CancelScheduledMessageHandler{
+		messagesPort:  messagesPort,
+		schedulerPort: schedulerPort,
+	}
+}
+
+func NewSendScheduledMessageNowHandler(
+	chatsPort chats.ChatsPort,
+	messagesPort MessagesPort,
+	messageEventsPort MessageEventsPort,
+	schedulerPort MessagesSchedulerPort,
+	slowModePort SlowModePort,
+) SendScheduledMessageNowHandler {
+	return SendScheduledMessageNowHandler{
+		chatsPort:         chatsPort,
+		messagesPort:      messagesPort,
+		messageEventsPort: messageEventsPort,
+		schedulerPort:     schedulerPort,
+		slowModePort:      slowModePort,
+	}
+}
+
+func NewPublishScheduledMessageHandler(
+	chatsPort chats.ChatsPort,
+	messagesPort MessagesPort,
+	messageEventsPort MessageEventsPort,
+	schedulerPort MessagesSchedulerPort,
+	slowModePort SlowModePort,
+) PublishScheduledMessageHandler {
+	return PublishScheduledMessageHandler{
+		chatsPort:         chatsPort,
+		messagesPort:      messagesPort,
+		messageEventsPort: messageEventsPort,
+		schedulerPort:     schedulerPort,
+		slowModePort:      slowModePort,
+	}
+}
+
+func NewRestoreScheduledMessagesHandler(
+	messagesPort MessagesPort,
+	schedulerPort MessagesSchedulerPort,
+) RestoreScheduledMessagesHandler {
+	return RestoreScheduledMessagesHandler{
+		messagesPort:  messagesPort,
+		schedulerPort: schedulerPort,
+	}
+}
+
+func NewGetMessageHistoryHandler(
+	messagesPort MessagesPort,
+) GetMessageHistoryHandler {
+	return GetMessageHistoryHandler{
+		messagesPort: messagesPort,
+	}
+}
+
+func NewVotePollHandler(
+	messagesPort MessagesPort,
+	messageEventsPort MessageEventsPort,
+) VotePollHandler {
+	return VotePollHandler{
+		messagesPort:      messagesPort,
+		messageEventsPort: messageEventsPort,
+	}
+}
+
+func NewRetractPollVoteHandler(
+	messagesPort MessagesPort,
+	messageEventsPort MessageEventsPort,
+) RetractPollVoteHandler {
+	return RetractPollVoteHandler{
+		messagesPort:      messagesPort,
+		messageEventsPort: messageEventsPort,
+	}
+}
+
+func NewClosePollHandler(
+	messagesPort MessagesPort,
+	messageEventsPort MessageEventsPort,
+) ClosePollHandler {
+	return ClosePollHandler{
+		messagesPort:      messagesPort,
+		messageEventsPort: messageEventsPort,
+	}
+}
+
+func NewChatSystemMessagesHandler(
+	messagesPort MessagesPort,
+	messageEventsPort MessageEventsPort,
+) chats.ChatSystemMessagesPort {
+	return &ChatSystemMessagesHandler{
+		messagesPort:      messagesPort,
+		messageEventsPort: messageEventsPort,
+	}
+}
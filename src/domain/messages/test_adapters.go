@@ -0,0 +1,402 @@
+package messages
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chack-check/chats-service/domain/chats"
+	"github.com/chack-check/chats-service/domain/utils"
+)
+
+var errTestMessageNotFound = fmt.Errorf("test message not found")
+
+type TestMessagesAdapter struct {
+	messages     []Message
+	pinnedIds    []int
+	revisions    []MessageRevision
+	pointers     []ChatReadPointer
+	savedPointer *ChatReadPointer
+}
+
+func NewTestMessagesAdapter(messages []Message, pointers []ChatReadPointer) *TestMessagesAdapter {
+	return &TestMessagesAdapter{
+		messages: slices.Clone(messages),
+		pointers: slices.Clone(pointers),
+	}
+}
+
+func (adapter *TestMessagesAdapter) findMessage(messageId int) (int, bool) {
+	for i, message := range adapter.messages {
+		if message.GetId() == messageId {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+func (adapter *TestMessagesAdapter) isVisibleForUser(message Message, userId int) bool {
+	chat := message.GetChat()
+	return slices.Contains(chat.GetMembers(), userId) && !slices.Contains(message.GetDeletedForIds(), userId)
+}
+
+func (adapter *TestMessagesAdapter) GetChatAllForUser(chatId int, userId int, offset int, limit int) utils.OffsetResponse[Message] {
+	return utils.OffsetResponse[Message]{}
+}
+
+func (adapter *TestMessagesAdapter) GetChatCursorAllForUser(chatId int, userId int, messageId int, aroundOffset int) utils.OffsetResponse[Message] {
+	return utils.OffsetResponse[Message]{}
+}
+
+func (adapter *TestMessagesAdapter) GetThreadAllForUser(rootMessageId int, userId int, offset int, limit int) utils.OffsetResponse[Message] {
+	var messages []Message
+	for _, message := range adapter.messages {
+		threadRootId := message.GetThreadRootId()
+		if threadRootId != nil && *threadRootId == rootMessageId && adapter.isVisibleForUser(message, userId) {
+			messages = append(messages, message)
+		}
+	}
+
+	response := utils.OffsetResponse[Message]{}
+	response.SetOffset(offset)
+	response.SetLimit(limit)
+	response.SetTotal(len(messages))
+	if offset < len(messages) {
+		response.SetData(messages[offset:])
+	}
+	if limit < len(response.GetData()) {
+		response.SetData(response.GetData()[:limit])
+	}
+	return response
+}
+
+func (adapter *TestMessagesAdapter) SearchForUser(filter SearchMessagesFilter, userId int, offset int, limit int) utils.OffsetResponse[MessageSearchResult] {
+	var results []MessageSearchResult
+	for _, message := range adapter.messages {
+		chat := message.GetChat()
+		content := message.GetContent()
+		if !adapter.isVisibleForUser(message, userId) || content == nil || !strings.Contains(strings.ToLower(*content), strings.ToLower(filter.GetQuery())) {
+			continue
+		}
+		if chatId := filter.GetChatId(); chatId != nil && *chatId != chat.GetId() {
+			continue
+		}
+		if senderId := filter.GetSenderId(); senderId != nil && *senderId != message.GetSenderId() {
+			continue
+		}
+
+		results = append(results, NewMessageSearchResult(message, *content))
+	}
+
+	response := utils.OffsetResponse[MessageSearchResult]{}
+	response.SetOffset(offset)
+	response.SetLimit(limit)
+	response.SetTotal(len(results))
+	response.SetData(results)
+	return response
+}
+
+func (adapter *TestMessagesAdapter) GetChatsLast(chatIds []int, userId int) []Message {
+	return nil
+}
+
+func (adapter *TestMessagesAdapter) GetByIdForUser(messageId int, userId int) (*Message, error) {
+	i, ok := adapter.findMessage(messageId)
+	if !ok || !adapter.isVisibleForUser(adapter.messages[i], userId) {
+		return nil, errTestMessageNotFound
+	}
+
+	message := adapter.messages[i]
+	return &message, nil
+}
+
+func (adapter *TestMessagesAdapter) GetByIdsForUser(messageIds []int, userId int) []Message {
+	var messages []Message
+	for _, message := range adapter.messages {
+		if slices.Contains(messageIds, message.GetId()) && adapter.isVisibleForUser(message, userId) {
+			messages = append(messages, message)
+		}
+	}
+
+	return messages
+}
+
+func (adapter *TestMessagesAdapter) GetById(messageId int) (*Message, error) {
+	i, ok := adapter.findMessage(messageId)
+	if !ok {
+		return nil, errors.Join(ErrMessageNotFound, errTestMessageNotFound)
+	}
+
+	message := adapter.messages[i]
+	return &message, nil
+}
+
+func (adapter *TestMessagesAdapter) Save(message Message) (*Message, error) {
+	if i, ok := adapter.findMessage(message.GetId()); ok {
+		adapter.messages[i] = message
+		return &message, nil
+	}
+
+	message.id = len(adapter.messages) + 1
+	adapter.messages = append(adapter.messages, message)
+	return &message, nil
+}
+
+func (adapter *TestMessagesAdapter) Delete(message Message) {
+	adapter.messages = slices.DeleteFunc(adapter.messages, func(dbMessage Message) bool {
+		return dbMessage.GetId() == message.GetId()
+	})
+}
+
+func (adapter *TestMessagesAdapter) DeleteForUser(message Message, userId int) error {
+	i, ok := adapter.findMessage(message.GetId())
+	if !ok {
+		return errTestMessageNotFound
+	}
+
+	adapter.messages[i].DeleteFor([]int{userId})
+	return nil
+}
+
+func (adapter *TestMessagesAdapter) Pin(message Message, pinnedBy int) error {
+	adapter.Unpin(message)
+	adapter.pinnedIds = append(adapter.pinnedIds, message.GetId())
+	return nil
+}
+
+func (adapter *TestMessagesAdapter) Unpin(message Message) error {
+	adapter.pinnedIds = slices.DeleteFunc(adapter.pinnedIds, func(messageId int) bool {
+		return messageId == message.GetId()
+	})
+	return nil
+}
+
+func (adapter *TestMessagesAdapter) GetChatPinned(chatId int, userId int) []Message {
+	var messages []Message
+	for _, messageId := range adapter.pinnedIds {
+		if i, ok := adapter.findMessage(messageId); ok {
+			chat := adapter.messages[i].GetChat()
+			if chat.GetId() == chatId && adapter.isVisibleForUser(adapter.messages[i], userId) {
+				messages = append(messages, adapter.messages[i])
+			}
+		}
+	}
+
+	return messages
+}
+
+func (adapter *TestMessagesAdapter) filterScheduled(predicate func(message Message) bool) []Message {
+	var messages []Message
+	for _, message := range adapter.messages {
+		if message.IsScheduled() && predicate(message) {
+			messages = append(messages, message)
+		}
+	}
+
+	return messages
+}
+
+func (adapter *TestMessagesAdapter) GetChatScheduledForUser(chatId int, userId int) []Message {
+	return adapter.filterScheduled(func(message Message) bool {
+		chat := message.GetChat()
+		return chat.GetId() == chatId && message.GetSenderId() == userId
+	})
+}
+
+func (adapter *TestMessagesAdapter) GetScheduledByIdForUser(messageId int, userId int) (*Message, error) {
+	messages := adapter.filterScheduled(func(message Message) bool {
+		return message.GetId() == messageId && message.GetSenderId() == userId
+	})
+	if len(messages) == 0 {
+		return nil, errTestMessageNotFound
+	}
+
+	return &messages[0], nil
+}
+
+func (adapter *TestMessagesAdapter) GetAllScheduled() []Message {
+	return adapter.filterScheduled(func(message Message) bool {
+		return true
+	})
+}
+
+func (adapter *TestMessagesAdapter) PublishScheduled(message Message) (*Message, error) {
+	message.SetSendAt(nil)
+	return adapter.Save(message)
+}
+
+func (adapter *TestMessagesAdapter) SaveRevision(revision MessageRevision) error {
+	adapter.revisions = append(adapter.revisions, revision)
+	return nil
+}
+
+func (adapter *TestMessagesAdapter) GetRevisions(message Message) []MessageRevision {
+	var revisions []MessageRevision
+	for _, revision := range adapter.revisions {
+		if revision.GetMessageId() == message.GetId() {
+			revisions = append(revisions, revision)
+		}
+	}
+
+	return revisions
+}
+
+func (adapter *TestMessagesAdapter) updatePoll(message Message, update func(poll MessagePoll) MessagePoll) error {
+	i, ok := adapter.findMessage(message.GetId())
+	if !ok || adapter.messages[i].GetPoll() == nil {
+		return errTestMessageNotFound
+	}
+
+	poll := update(*adapter.messages[i].GetPoll())
+	adapter.messages[i].SetPoll(&poll)
+	return nil
+}
+
+func (adapter *TestMessagesAdapter) VotePoll(message Message, userId int, optionIds []int) error {
+	return adapter.updatePoll(message, func(poll MessagePoll) MessagePoll {
+		var options []MessagePollOption
+		for _, option := range poll.GetOptions() {
+			voters := slices.DeleteFunc(slices.Clone(option.GetVoters()), func(voter int) bool {
+				return voter == userId
+			})
+			if slices.Contains(optionIds, option.GetId()) {
+				voters = append(voters, userId)
+			}
+
+			options = append(options, NewMessagePollOption(option.GetId(), option.GetContent(), voters))
+		}
+
+		return NewMessagePoll(options, poll.GetMultipleChoice(), poll.GetAnonymous(), poll.GetClosesAt(), poll.GetClosedAt())
+	})
+}
+
+func (adapter *TestMessagesAdapter) RetractPollVote(message Message, userId int) error {
+	return adapter.VotePoll(message, userId, nil)
+}
+
+func (adapter *TestMessagesAdapter) ClosePoll(message Message, closedAt time.Time) error {
+	return adapter.updatePoll(message, func(poll MessagePoll) MessagePoll {
+		poll.SetClosedAt(&closedAt)
+		return poll
+	})
+}
+
+func (adapter *TestMessagesAdapter) GetReadPointer(chatId int, userId int) (*ChatReadPointer, error) {
+	for _, pointer := range adapter.pointers {
+		if pointer.GetChatId() == chatId && pointer.GetUserId() == userId {
+			return &pointer, nil
+		}
+	}
+
+	return nil, fmt.Errorf("test read pointer not found")
+}
+
+func (adapter *TestMessagesAdapter) SaveReadPointer(pointer ChatReadPointer) error {
+	adapter.pointers = slices.DeleteFunc(adapter.pointers, func(dbPointer ChatReadPointer) bool {
+		return dbPointer.GetChatId() == pointer.GetChatId() && dbPointer.GetUserId() == pointer.GetUserId()
+	})
+	adapter.pointers = append(adapter.pointers, pointer)
+	adapter.savedPointer = &pointer
+	return nil
+}
+
+type TestMessageEventsAdapter struct {
+	sentEvents []string
+}
+
+func (adapter *TestMessageEventsAdapter) send(event string) error {
+	adapter.sentEvents = append(adapter.sentEvents, event)
+	return nil
+}
+
+func (adapter *TestMessageEventsAdapter) SendMessageReacted(message Message) error {
+	return adapter.send("message_reacted")
+}
+
+func (adapter *TestMessageEventsAdapter) SendReactionDeleted(message Message) error {
+	return adapter.send("reaction_deleted")
+}
+
+func (adapter *TestMessageEventsAdapter) SendMessageReaded(message Message) error {
+	return adapter.send("message_readed")
+}
+
+func (adapter *TestMessageEventsAdapter) SendMessageDeleted(message Message) error {
+	return adapter.send("message_deleted")
+}
+
+func (adapter *TestMessageEventsAdapter) SendMessageDeletedForUser(message Message, userId int) error {
+	return adapter.send("message_deleted_for_user")
+}
+
+func (adapter *TestMessageEventsAdapter) SendMessageUpdated(message Message) error {
+	return adapter.send("message_updated")
+}
+
+func (adapter *TestMessageEventsAdapter) SendMessageCreated(message Message) error {
+	return adapter.send("message_created")
+}
+
+func (adapter *TestMessageEventsAdapter) SendThreadUpdated(message Message) error {
+	return adapter.send("thread_updated")
+}
+
+func (adapter *TestMessageEventsAdapter) SendMessagePinned(message Message) error {
+	return adapter.send("message_pinned")
+}
+
+func (adapter *TestMessageEventsAdapter) SendMessageUnpinned(message Message) error {
+	return adapter.send("message_unpinned")
+}
+
+func (adapter *TestMessageEventsAdapter) SendPollUpdated(message Message) error {
+	return adapter.send("poll_updated")
+}
+
+func (adapter *TestMessageEventsAdapter) SendChatRead(chat chats.Chat, pointer ChatReadPointer) error {
+	return adapter.send("chat_read")
+}
+
+func (adapter *TestMessageEventsAdapter) SendScheduledMessageDropped(message Message) error {
+	return adapter.send("scheduled_message_dropped")
+}
+
+type TestMessagesSchedulerAdapter struct {
+	scheduledMessages []int
+}
+
+func (adapter *TestMessagesSchedulerAdapter) Schedule(message Message) {
+	adapter.scheduledMessages = append(adapter.scheduledMessages, message.GetId())
+}
+
+func (adapter *TestMessagesSchedulerAdapter) Cancel(message Message) {
+	adapter.scheduledMessages = slices.DeleteFunc(adapter.scheduledMessages, func(messageId int) bool {
+		return messageId == message.GetId()
+	})
+}
+
+// TestSlowModeAdapter records the users that acquired a slot and the chats
+// where a slot was released. It reports remainingWait for every acquire, or
+// the chat's entry in chatRemainingWaits when there is one.
+type TestSlowModeAdapter struct {
+	remainingWait      time.Duration
+	chatRemainingWaits map[int]time.Duration
+	acquiredBy         []int
+	releasedChats      []int
+}
+
+func (adapter *TestSlowModeAdapter) Acquire(chatId int, userId int, interval time.Duration) time.Duration {
+	adapter.acquiredBy = append(adapter.acquiredBy, userId)
+	if remainingWait, ok := adapter.chatRemainingWaits[chatId]; ok {
+		return remainingWait
+	}
+
+	return adapter.remainingWait
+}
+
+func (adapter *TestSlowModeAdapter) Release(chatId int, userId int) {
+	adapter.releasedChats = append(adapter.releasedChats, chatId)
+}
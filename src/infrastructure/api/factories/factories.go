@@ -1,6 +1,9 @@
 package factories
 
 import (
+	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/chack-check/chats-service/domain/chats"
@@ -11,6 +14,17 @@ import (
 	"github.com/chack-check/chats-service/infrastructure/api/graph/model"
 )
 
+var ErrIncorrectDatetime = fmt.Errorf("incorrect datetime format, expected RFC3339")
+
+func ParseDatetime(value string) (*time.Time, error) {
+	datetime, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, ErrIncorrectDatetime
+	}
+
+	return &datetime, nil
+}
+
 func UploadingFileMetaToModel(meta model.UploadingFileMeta) files.UploadingFileMeta {
 	return files.NewUploadingFileMeta(
 		meta.URL,
@@ -34,7 +48,7 @@ func UploadingFileToModel(file model.UploadingFile) files.UploadingFile {
 	)
 }
 
-func CreateMessageRequestToModel(request model.CreateMessageRequest) messages.CreateMessageData {
+func CreateMessageRequestToModel(request model.CreateMessageRequest) (*messages.CreateMessageData, error) {
 	var voice *files.UploadingFile
 	if request.Voice != nil {
 		file := UploadingFileToModel(*request.Voice)
@@ -53,7 +67,27 @@ func CreateMessageRequestToModel(request model.CreateMessageRequest) messages.Cr
 		attachments = append(attachments, file)
 	}
 
-	return messages.NewCreateMessageData(
+	var sendAt *time.Time
+	if request.SendAt != nil {
+		datetime, err := ParseDatetime(*request.SendAt)
+		if err != nil {
+			return nil, err
+		}
+
+		sendAt = datetime
+	}
+
+	var poll *messages.CreatePollData
+	if request.Poll != nil {
+		pollData, err := CreatePollRequestToModel(*request.Poll)
+		if err != nil {
+			return nil, err
+		}
+
+		poll = pollData
+	}
+
+	data := messages.NewCreateMessageData(
 		request.ChatID,
 		messages.MessageTypes(request.Type),
 		request.Content,
@@ -62,7 +96,30 @@ func CreateMessageRequestToModel(request model.CreateMessageRequest) messages.Cr
 		request.ReplyToID,
 		request.Mentioned,
 		circle,
+		sendAt,
+		poll,
 	)
+	return &data, nil
+}
+
+func CreatePollRequestToModel(request model.CreatePollRequest) (*messages.CreatePollData, error) {
+	var closesAt *time.Time
+	if request.ClosesAt != nil {
+		datetime, err := ParseDatetime(*request.ClosesAt)
+		if err != nil {
+			return nil, err
+		}
+
+		closesAt = datetime
+	}
+
+	data := messages.NewCreatePollData(
+		request.Options,
+		request.MultipleChoice != nil && *request.MultipleChoice,
+		request.Anonymous != nil && *request.Anonymous,
+		closesAt,
+	)
+	return &data, nil
 }
 
 func UpdateMessageRequestToModel(request model.ChangeMessageRequest) messages.UpdateMessageData {
@@ -100,6 +157,61 @@ func ReactionModelToResponse(reaction messages.MessageReaction) model.Reaction {
 	}
 }
 
+func ThreadReplyModelToResponse(reply messages.MessageThreadReply) model.ThreadReply {
+	return model.ThreadReply{
+		MessageID: reply.GetMessageId(),
+		SenderID:  reply.GetSenderId(),
+		CreatedAt: reply.GetCreatedAt().Format(time.RFC3339),
+	}
+}
+
+func ForwardedFromModelToResponse(forwardedFrom messages.MessageForwardedFrom) model.ForwardedFrom {
+	return model.ForwardedFrom{
+		MessageID: forwardedFrom.GetMessageId(),
+		SenderID:  forwardedFrom.GetSenderId(),
+		ChatID:    forwardedFrom.GetChatId(),
+	}
+}
+
+func PollModelToResponse(poll messages.MessagePoll) model.Poll {
+	var options []*model.PollOption
+	for _, option := range poll.GetOptions() {
+		var voters []int
+		if !poll.GetAnonymous() {
+			voters = option.GetVoters()
+		}
+
+		options = append(options, &model.PollOption{
+			ID:         option.GetId(),
+			Content:    option.GetContent(),
+			VotesCount: option.GetVotesCount(),
+			Voters:     voters,
+		})
+	}
+
+	var closesAt *string
+	if dt := poll.GetClosesAt(); dt != nil {
+		isodt := dt.Format(time.RFC3339)
+		closesAt = &isodt
+	}
+
+	var closedAt *string
+	if dt := poll.GetClosedAt(); dt != nil {
+		isodt := dt.Format(time.RFC3339)
+		closedAt = &isodt
+	}
+
+	return model.Poll{
+		Options:        options,
+		MultipleChoice: poll.GetMultipleChoice(),
+		Anonymous:      poll.GetAnonymous(),
+		ClosesAt:       closesAt,
+		ClosedAt:       closedAt,
+		IsClosed:       poll.IsClosed(),
+		TotalVoters:    poll.GetTotalVoters(),
+	}
+}
+
 func MessageModelToResponse(message messages.Message) model.Message {
 	chat := message.GetChat()
 	var voice *model.SavedFile
@@ -126,20 +238,82 @@ func MessageModelToResponse(message messages.Message) model.Message {
 		reactions = append(reactions, &reactionResponse)
 	}
 
+	thread := message.GetThread()
+	var threadLastReply *model.ThreadReply
+	if lastReply := thread.GetLastReply(); lastReply != nil {
+		reply := ThreadReplyModelToResponse(*lastReply)
+		threadLastReply = &reply
+	}
+
+	var forwardedFrom *model.ForwardedFrom
+	if messageForwardedFrom := message.GetForwardedFrom(); messageForwardedFrom != nil {
+		response := ForwardedFromModelToResponse(*messageForwardedFrom)
+		forwardedFrom = &response
+	}
+
+	var sendAt *string
+	if dt := message.GetSendAt(); dt != nil {
+		isodt := dt.Format(time.RFC3339)
+		sendAt = &isodt
+	}
+
+	var editedAt *string
+	if dt := message.GetEditedAt(); dt != nil {
+		isodt := dt.Format(time.RFC3339)
+		editedAt = &isodt
+	}
+
+	var poll *model.Poll
+	if messagePoll := message.GetPoll(); messagePoll != nil {
+		response := PollModelToResponse(*messagePoll)
+		poll = &response
+	}
+
 	return model.Message{
-		ID:          message.GetId(),
-		Type:        model.MessageType(string(message.GetType())),
-		SenderID:    message.GetSenderId(),
-		ChatID:      chat.GetId(),
-		Content:     message.GetContent(),
-		Voice:       voice,
-		Circle:      circle,
-		ReplyToID:   message.GetReplyToId(),
-		ReadedBy:    message.GetReadedBy(),
-		Reactions:   reactions,
+		ID:                 message.GetId(),
+		Type:               model.MessageType(string(message.GetType())),
+		SenderID:           message.GetSenderId(),
+		ChatID:             chat.GetId(),
+		Content:            message.GetContent(),
+		Voice:              voice,
+		Circle:             circle,
+		ReplyToID:          message.GetReplyToId(),
+		ThreadRootID:       message.GetThreadRootId(),
+		ThreadRepliesCount: thread.GetRepliesCount(),
+		ThreadLastReply:    threadLastReply,
+		ForwardedFrom:      forwardedFrom,
+		SendAt:             sendAt,
+		EditedAt:           editedAt,
+		Poll:               poll,
+		ReadedBy:           message.GetReadedBy(),
+		Reactions:          reactions,
+		Attachments:        attachments,
+		Mentioned:          message.GetMentioned(),
+		CreatedAt:          message.GetCreatedAt().Format(time.RFC3339),
+	}
+}
+
+func ChatReadPointerModelToResponse(pointer messages.ChatReadPointer) model.ChatReadPointer {
+	return model.ChatReadPointer{
+		ChatID:    pointer.GetChatId(),
+		UserID:    pointer.GetUserId(),
+		MessageID: pointer.GetMessageId(),
+		ReadAt:    pointer.GetReadAt().Format(time.RFC3339),
+	}
+}
+
+func MessageRevisionModelToResponse(revision messages.MessageRevision) model.MessageRevision {
+	var attachments []*model.SavedFile
+	for _, attachment := range revision.GetAttachments() {
+		file := SavedFileToResponse(attachment)
+		attachments = append(attachments, &file)
+	}
+
+	return model.MessageRevision{
+		Content:     revision.GetContent(),
 		Attachments: attachments,
-		Mentioned:   message.GetMentioned(),
-		CreatedAt:   message.GetCreatedAt().Format(time.RFC3339),
+		Mentioned:   revision.GetMentioned(),
+		CreatedAt:   revision.GetCreatedAt().Format(time.RFC3339),
 	}
 }
 
@@ -160,6 +334,60 @@ func OffsetMessagesToResponse(messages utils.OffsetResponse[messages.Message], c
 	}
 }
 
+func MessageSearchResultModelToResponse(result messages.MessageSearchResult) model.MessageSearchResult {
+	message := MessageModelToResponse(result.GetMessage())
+	return model.MessageSearchResult{
+		Message: &message,
+		Snippet: result.GetSnippet(),
+	}
+}
+
+func OffsetMessageSearchResultsToResponse(results utils.OffsetResponse[messages.MessageSearchResult]) model.PaginatedMessageSearchResults {
+	data := []*model.MessageSearchResult{}
+	for _, result := range results.GetData() {
+		resultResponse := MessageSearchResultModelToResponse(result)
+		data = append(data, &resultResponse)
+	}
+
+	return model.PaginatedMessageSearchResults{
+		Offset: results.GetOffset(),
+		Limit:  results.GetLimit(),
+		Total:  results.GetTotal(),
+		Data:   data,
+	}
+}
+
+func SearchMessagesRequestToModel(query string, chatId *int, senderId *int, messageType *model.MessageType, from *string, to *string) (*messages.SearchMessagesFilter, error) {
+	var type_ *messages.MessageTypes
+	if messageType != nil {
+		filterType := messages.MessageTypes(string(*messageType))
+		type_ = &filterType
+	}
+
+	var fromDatetime *time.Time
+	if from != nil {
+		datetime, err := ParseDatetime(*from)
+		if err != nil {
+			return nil, err
+		}
+
+		fromDatetime = datetime
+	}
+
+	var toDatetime *time.Time
+	if to != nil {
+		datetime, err := ParseDatetime(*to)
+		if err != nil {
+			return nil, err
+		}
+
+		toDatetime = datetime
+	}
+
+	filter := messages.NewSearchMessagesFilter(query, chatId, senderId, type_, fromDatetime, toDatetime)
+	return &filter, nil
+}
+
 func CreateChatRequestToModel(request model.CreateChatRequest, chatType chats.ChatTypes) chats.CreateChatData {
 	var avatar *files.UploadingFile
 	if request.Avatar != nil {
@@ -173,6 +401,33 @@ func CreateChatRequestToModel(request model.CreateChatRequest, chatType chats.Ch
 		request.Title,
 		request.Members,
 		request.User,
+		nil,
+	)
+}
+
+func ChangeGroupChatRequestToModel(request model.ChangeGroupChatData) chats.ChangeGroupChatData {
+	return chats.NewChangeGroupChatData(
+		request.Title,
+		request.Description,
+		request.Rules,
+		request.Slug,
+	)
+}
+
+func CreateChannelRequestToModel(request model.CreateChannelRequest) chats.CreateChatData {
+	var avatar *files.UploadingFile
+	if request.Avatar != nil {
+		file := UploadingFileToModel(*request.Avatar)
+		avatar = &file
+	}
+
+	return chats.NewCreateChatData(
+		chats.ChannelChatType,
+		avatar,
+		&request.Title,
+		request.Members,
+		nil,
+		request.Slug,
 	)
 }
 
@@ -183,13 +438,185 @@ func ActionUserModelToResponse(user users.ActionUser) model.ChatActionUser {
 	}
 }
 
-func ChatModelToResponse(chat chats.Chat) model.Chat {
-	var avatar *model.SavedFile
-	if chatAvatar := chat.GetAvatar(); chatAvatar != nil {
-		file := SavedFileToResponse(*chatAvatar)
-		avatar = &file
+func PinnedMessageModelToResponse(pinnedMessage chats.ChatPinnedMessage) model.PinnedMessage {
+	return model.PinnedMessage{
+		MessageID: pinnedMessage.GetMessageId(),
+		SenderID:  pinnedMessage.GetSenderId(),
+		Content:   pinnedMessage.GetContent(),
+		PinnedBy:  pinnedMessage.GetPinnedBy(),
+		PinnedAt:  pinnedMessage.GetPinnedAt().Format(time.RFC3339),
+	}
+}
+
+func AdminRightsModelToResponse(adminId int, rights chats.AdminRights) model.AdminRights {
+	return model.AdminRights{
+		UserID:            adminId,
+		CanAddMembers:     rights.GetCanAddMembers(),
+		CanRemoveMembers:  rights.GetCanRemoveMembers(),
+		CanEditInfo:       rights.GetCanEditInfo(),
+		CanPinMessages:    rights.GetCanPinMessages(),
+		CanDeleteMessages: rights.GetCanDeleteMessages(),
+		CanManageAdmins:   rights.GetCanManageAdmins(),
+	}
+}
+
+func MemberPermissionsModelToResponse(permissions chats.MemberPermissions) model.MemberPermissions {
+	return model.MemberPermissions{
+		CanSendMessages: permissions.GetCanSendMessages(),
+		CanSendMedia:    permissions.GetCanSendMedia(),
+		CanAddMembers:   permissions.GetCanAddMembers(),
+		CanPinMessages:  permissions.GetCanPinMessages(),
+	}
+}
+
+func AdminRightsRequestToModel(request model.AdminRightsRequest) chats.AdminRights {
+	return chats.NewAdminRights(
+		request.CanAddMembers,
+		request.CanRemoveMembers,
+		request.CanEditInfo,
+		request.CanPinMessages,
+		request.CanDeleteMessages,
+		request.CanManageAdmins,
+	)
+}
+
+func MemberPermissionsRequestToModel(request model.MemberPermissionsRequest) chats.MemberPermissions {
+	return chats.NewMemberPermissions(
+		request.CanSendMessages,
+		request.CanSendMedia,
+		request.CanAddMembers,
+		request.CanPinMessages,
+	)
+}
+
+func CreateInviteLinkRequestToModel(expiresAt *string, usageLimit *int, requiresApproval *bool) (*chats.CreateInviteLinkData, error) {
+	var expiresAtDatetime *time.Time
+	if expiresAt != nil {
+		datetime, err := ParseDatetime(*expiresAt)
+		if err != nil {
+			return nil, err
+		}
+
+		expiresAtDatetime = datetime
+	}
+
+	data := chats.NewCreateInviteLinkData(expiresAtDatetime, usageLimit, requiresApproval != nil && *requiresApproval)
+	return &data, nil
+}
+
+func ChatInviteLinkModelToResponse(link chats.ChatInviteLink) model.ChatInviteLink {
+	var expiresAt *string
+	if dt := link.GetExpiresAt(); dt != nil {
+		isodt := dt.Format(time.RFC3339)
+		expiresAt = &isodt
 	}
 
+	var revokedAt *string
+	if dt := link.GetRevokedAt(); dt != nil {
+		isodt := dt.Format(time.RFC3339)
+		revokedAt = &isodt
+	}
+
+	return model.ChatInviteLink{
+		Code:             link.GetCode(),
+		ChatID:           link.GetChatId(),
+		CreatedBy:        link.GetCreatedBy(),
+		ExpiresAt:        expiresAt,
+		UsageLimit:       link.GetUsageLimit(),
+		UsageCount:       link.GetUsageCount(),
+		RequiresApproval: link.GetRequiresApproval(),
+		RevokedAt:        revokedAt,
+		CreatedAt:        link.GetCreatedAt().Format(time.RFC3339),
+	}
+}
+
+func SlowModeErrorToResponse(err messages.SlowModeError) model.SlowModeErrorResponse {
+	return model.SlowModeErrorResponse{
+		Message:    err.Error(),
+		RetryAfter: int(math.Ceil(err.GetRemainingWait().Seconds())),
+	}
+}
+
+func ChatBanModelToResponse(ban chats.ChatBan) model.ChatBan {
+	var expiresAt *string
+	if dt := ban.GetExpiresAt(); dt != nil {
+		isodt := dt.Format(time.RFC3339)
+		expiresAt = &isodt
+	}
+
+	return model.ChatBan{
+		ChatID:    ban.GetChatId(),
+		UserID:    ban.GetUserId(),
+		BannedBy:  ban.GetBannedBy(),
+		ExpiresAt: expiresAt,
+		CreatedAt: ban.GetCreatedAt().Format(time.RFC3339),
+	}
+}
+
+func JoinRequestModelToResponse(request chats.ChatJoinRequest) model.JoinRequest {
+	var resolvedAt *string
+	if dt := request.GetResolvedAt(); dt != nil {
+		isodt := dt.Format(time.RFC3339)
+		resolvedAt = &isodt
+	}
+
+	return model.JoinRequest{
+		ID:         request.GetId(),
+		ChatID:     request.GetChatId(),
+		UserID:     request.GetUserId(),
+		Status:     model.JoinRequestStatus(request.GetStatus()),
+		ResolvedBy: request.GetResolvedBy(),
+		CreatedAt:  request.GetCreatedAt().Format(time.RFC3339),
+		ResolvedAt: resolvedAt,
+	}
+}
+
+func ChatFolderRequestToModel(request model.ChatFolderRequest) chats.ChatFolderData {
+	var chatTypes []chats.ChatTypes
+	for _, chatType := range request.ChatTypes {
+		chatTypes = append(chatTypes, chats.ChatTypes(chatType))
+	}
+
+	return chats.NewChatFolderData(
+		strings.TrimSpace(request.Title),
+		request.ChatIds,
+		chatTypes,
+		request.UnreadOnly != nil && *request.UnreadOnly,
+		request.MutedOnly != nil && *request.MutedOnly,
+	)
+}
+
+func ChatFolderModelToResponse(folder chats.ChatFolder) model.ChatFolder {
+	chatIds := []int{}
+	chatIds = append(chatIds, folder.GetChatIds()...)
+
+	chatTypes := []model.ChatType{}
+	for _, chatType := range folder.GetChatTypes() {
+		chatTypes = append(chatTypes, model.ChatType(chatType))
+	}
+
+	return model.ChatFolder{
+		ID:         folder.GetId(),
+		Title:      folder.GetTitle(),
+		Position:   folder.GetPosition(),
+		ChatIds:    chatIds,
+		ChatTypes:  chatTypes,
+		UnreadOnly: folder.GetUnreadOnly(),
+		MutedOnly:  folder.GetMutedOnly(),
+	}
+}
+
+func ChatFoldersToResponse(folders []chats.ChatFolder) model.ChatFoldersArray {
+	var foldersResponse []*model.ChatFolder
+	for _, folder := range folders {
+		folderResponse := ChatFolderModelToResponse(folder)
+		foldersResponse = append(foldersResponse, &folderResponse)
+	}
+
+	return model.ChatFoldersArray{Folders: foldersResponse}
+}
+
+func ChatActionsModelToResponse(chat chats.Chat) []*model.ChatAction {
 	var actions []*model.ChatAction
 	for key, value := range chat.GetActions() {
 		var actionUsers []*model.ChatActionUser
@@ -205,16 +632,72 @@ func ChatModelToResponse(chat chats.Chat) model.Chat {
 		actions = append(actions, &action)
 	}
 
+	return actions
+}
+
+func ChatModelToResponse(chat chats.Chat) model.Chat {
+	var avatar *model.SavedFile
+	if chatAvatar := chat.GetAvatar(); chatAvatar != nil {
+		file := SavedFileToResponse(*chatAvatar)
+		avatar = &file
+	}
+
+	actions := ChatActionsModelToResponse(chat)
+
+	var pinnedMessage *model.PinnedMessage
+	if chatPinnedMessage := chat.GetPinnedMessage(); chatPinnedMessage != nil {
+		response := PinnedMessageModelToResponse(*chatPinnedMessage)
+		pinnedMessage = &response
+	}
+
+	var adminRights []*model.AdminRights
+	for _, admin := range chat.GetAdmins() {
+		rights := AdminRightsModelToResponse(admin, chat.GetAdminRights(admin))
+		adminRights = append(adminRights, &rights)
+	}
+
+	userSettings := chat.GetUserSettings()
+	var mutedUntil *string
+	if dt := userSettings.GetMutedUntil(); dt != nil && userSettings.IsMutedAt(time.Now()) {
+		isodt := dt.Format(time.RFC3339)
+		mutedUntil = &isodt
+	}
+
+	memberPermissions := MemberPermissionsModelToResponse(chat.GetMemberPermissions())
+	unreadCounters := chat.GetUnreadCounters()
 	return model.Chat{
-		ID:         chat.GetId(),
-		Avatar:     avatar,
-		Title:      chat.GetTitle(),
-		Type:       model.ChatType(string(chat.GetType())),
-		Members:    chat.GetMembers(),
-		IsArchived: chat.GetIsArchived(),
-		OwnerID:    chat.GetOwnerId(),
-		Admins:     chat.GetAdmins(),
-		Actions:    actions,
+		ID:            chat.GetId(),
+		Avatar:        avatar,
+		Title:         chat.GetTitle(),
+		Type:          model.ChatType(string(chat.GetType())),
+		Slug:          chat.GetSlug(),
+		Description:   chat.GetDescription(),
+		Rules:         chat.GetRules(),
+		Members:       chat.GetMembers(),
+		IsArchived:    chat.GetIsArchived(),
+		IsPinned:      userSettings.GetIsPinned(),
+		IsMuted:       userSettings.IsMutedAt(time.Now()),
+		MutedUntil:    mutedUntil,
+		OwnerID:       chat.GetOwnerId(),
+		Admins:        chat.GetAdmins(),
+		Actions:       actions,
+		PinnedMessage: pinnedMessage,
+
+		AdminRights:       adminRights,
+		MemberPermissions: &memberPermissions,
+
+		SubscribersCount:    chat.GetSubscribersCount(),
+		SlowModeInterval:    int(chat.GetSlowModeInterval().Seconds()),
+		IsUserDeleted:       chat.GetIsUserDeleted(),
+		UnreadCount:         unreadCounters.GetUnreadCount(),
+		UnreadMentionsCount: unreadCounters.GetUnreadMentionsCount(),
+	}
+}
+
+func ChatUnreadCountersToTotalUnreadResponse(counters chats.ChatUnreadCounters) model.TotalUnread {
+	return model.TotalUnread{
+		UnreadCount:         counters.GetUnreadCount(),
+		UnreadMentionsCount: counters.GetUnreadMentionsCount(),
 	}
 }
 
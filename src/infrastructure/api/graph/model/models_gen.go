@@ -12,14 +12,46 @@ type BooleanResultErrorResponse interface {
 	IsBooleanResultErrorResponse()
 }
 
+type ChatBansArrayErrorResponse interface {
+	IsChatBansArrayErrorResponse()
+}
+
 type ChatErrorResponse interface {
 	IsChatErrorResponse()
 }
 
+type ChatFolderErrorResponse interface {
+	IsChatFolderErrorResponse()
+}
+
+type ChatFoldersArrayErrorResponse interface {
+	IsChatFoldersArrayErrorResponse()
+}
+
+type ChatInviteLinkErrorResponse interface {
+	IsChatInviteLinkErrorResponse()
+}
+
+type ChatReadPointerErrorResponse interface {
+	IsChatReadPointerErrorResponse()
+}
+
+type JoinRequestErrorResponse interface {
+	IsJoinRequestErrorResponse()
+}
+
+type JoinRequestsArrayErrorResponse interface {
+	IsJoinRequestsArrayErrorResponse()
+}
+
 type MessageErrorResponse interface {
 	IsMessageErrorResponse()
 }
 
+type MessageRevisionsArrayErrorResponse interface {
+	IsMessageRevisionsArrayErrorResponse()
+}
+
 type MessagesArrayErrorResponse interface {
 	IsMessagesArrayErrorResponse()
 }
@@ -28,10 +60,37 @@ type PaginatedChatsErrorResponse interface {
 	IsPaginatedChatsErrorResponse()
 }
 
+type PaginatedMessageSearchResultsErrorResponse interface {
+	IsPaginatedMessageSearchResultsErrorResponse()
+}
+
 type PaginatedMessagesErrorResponse interface {
 	IsPaginatedMessagesErrorResponse()
 }
 
+type TotalUnreadErrorResponse interface {
+	IsTotalUnreadErrorResponse()
+}
+
+type AdminRights struct {
+	UserID            int  `json:"userId"`
+	CanAddMembers     bool `json:"canAddMembers"`
+	CanRemoveMembers  bool `json:"canRemoveMembers"`
+	CanEditInfo       bool `json:"canEditInfo"`
+	CanPinMessages    bool `json:"canPinMessages"`
+	CanDeleteMessages bool `json:"canDeleteMessages"`
+	CanManageAdmins   bool `json:"canManageAdmins"`
+}
+
+type AdminRightsRequest struct {
+	CanAddMembers     bool `json:"canAddMembers"`
+	CanRemoveMembers  bool `json:"canRemoveMembers"`
+	CanEditInfo       bool `json:"canEditInfo"`
+	CanPinMessages    bool `json:"canPinMessages"`
+	CanDeleteMessages bool `json:"canDeleteMessages"`
+	CanManageAdmins   bool `json:"canManageAdmins"`
+}
+
 type BooleanResult struct {
 	Result bool `json:"result"`
 }
@@ -39,7 +98,10 @@ type BooleanResult struct {
 func (BooleanResult) IsBooleanResultErrorResponse() {}
 
 type ChangeGroupChatData struct {
-	Title *string `json:"title,omitempty"`
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Rules       *string `json:"rules,omitempty"`
+	Slug        *string `json:"slug,omitempty"`
 }
 
 type ChangeMessageRequest struct {
@@ -49,15 +111,29 @@ type ChangeMessageRequest struct {
 }
 
 type Chat struct {
-	ID         int           `json:"id"`
-	Avatar     *SavedFile    `json:"avatar,omitempty"`
-	Title      string        `json:"title"`
-	Type       ChatType      `json:"type"`
-	Members    []int         `json:"members"`
-	IsArchived bool          `json:"isArchived"`
-	OwnerID    int           `json:"ownerId"`
-	Admins     []int         `json:"admins"`
-	Actions    []*ChatAction `json:"actions"`
+	ID                  int                `json:"id"`
+	Avatar              *SavedFile         `json:"avatar,omitempty"`
+	Title               string             `json:"title"`
+	Type                ChatType           `json:"type"`
+	Slug                *string            `json:"slug,omitempty"`
+	Description         *string            `json:"description,omitempty"`
+	Rules               *string            `json:"rules,omitempty"`
+	Members             []int              `json:"members"`
+	SubscribersCount    int                `json:"subscribersCount"`
+	SlowModeInterval    int                `json:"slowModeInterval"`
+	IsUserDeleted       bool               `json:"isUserDeleted"`
+	IsArchived          bool               `json:"isArchived"`
+	IsPinned            bool               `json:"isPinned"`
+	IsMuted             bool               `json:"isMuted"`
+	MutedUntil          *string            `json:"mutedUntil,omitempty"`
+	OwnerID             int                `json:"ownerId"`
+	Admins              []int              `json:"admins"`
+	AdminRights         []*AdminRights     `json:"adminRights"`
+	MemberPermissions   *MemberPermissions `json:"memberPermissions"`
+	Actions             []*ChatAction      `json:"actions"`
+	PinnedMessage       *PinnedMessage     `json:"pinnedMessage,omitempty"`
+	UnreadCount         int                `json:"unreadCount"`
+	UnreadMentionsCount int                `json:"unreadMentionsCount"`
 }
 
 func (Chat) IsChatErrorResponse() {}
@@ -72,6 +148,81 @@ type ChatActionUser struct {
 	ID       int    `json:"id"`
 }
 
+type ChatActions struct {
+	ChatID  int           `json:"chatId"`
+	Actions []*ChatAction `json:"actions"`
+}
+
+type ChatBan struct {
+	ChatID    int     `json:"chatId"`
+	UserID    int     `json:"userId"`
+	BannedBy  int     `json:"bannedBy"`
+	ExpiresAt *string `json:"expiresAt,omitempty"`
+	CreatedAt string  `json:"createdAt"`
+}
+
+type ChatBansArray struct {
+	Bans []*ChatBan `json:"bans"`
+}
+
+func (ChatBansArray) IsChatBansArrayErrorResponse() {}
+
+type ChatFolder struct {
+	ID         int        `json:"id"`
+	Title      string     `json:"title"`
+	Position   int        `json:"position"`
+	ChatIds    []int      `json:"chatIds"`
+	ChatTypes  []ChatType `json:"chatTypes"`
+	UnreadOnly bool       `json:"unreadOnly"`
+	MutedOnly  bool       `json:"mutedOnly"`
+}
+
+func (ChatFolder) IsChatFolderErrorResponse() {}
+
+type ChatFolderRequest struct {
+	Title      string     `json:"title"`
+	ChatIds    []int      `json:"chatIds,omitempty"`
+	ChatTypes  []ChatType `json:"chatTypes,omitempty"`
+	UnreadOnly *bool      `json:"unreadOnly,omitempty"`
+	MutedOnly  *bool      `json:"mutedOnly,omitempty"`
+}
+
+type ChatFoldersArray struct {
+	Folders []*ChatFolder `json:"folders"`
+}
+
+func (ChatFoldersArray) IsChatFoldersArrayErrorResponse() {}
+
+type ChatInviteLink struct {
+	Code             string  `json:"code"`
+	ChatID           int     `json:"chatId"`
+	CreatedBy        int     `json:"createdBy"`
+	ExpiresAt        *string `json:"expiresAt,omitempty"`
+	UsageLimit       *int    `json:"usageLimit,omitempty"`
+	UsageCount       int     `json:"usageCount"`
+	RequiresApproval bool    `json:"requiresApproval"`
+	RevokedAt        *string `json:"revokedAt,omitempty"`
+	CreatedAt        string  `json:"createdAt"`
+}
+
+func (ChatInviteLink) IsChatInviteLinkErrorResponse() {}
+
+type ChatReadPointer struct {
+	ChatID    int    `json:"chatId"`
+	UserID    int    `json:"userId"`
+	MessageID int    `json:"messageId"`
+	ReadAt    string `json:"readAt"`
+}
+
+func (ChatReadPointer) IsChatReadPointerErrorResponse() {}
+
+type CreateChannelRequest struct {
+	Avatar  *UploadingFile `json:"avatar,omitempty"`
+	Title   string         `json:"title"`
+	Slug    *string        `json:"slug,omitempty"`
+	Members []int          `json:"members,omitempty"`
+}
+
 type CreateChatRequest struct {
 	Avatar  *UploadingFile `json:"avatar,omitempty"`
 	Title   *string        `json:"title,omitempty"`
@@ -80,14 +231,23 @@ type CreateChatRequest struct {
 }
 
 type CreateMessageRequest struct {
-	ChatID      int              `json:"chatId"`
-	Type        MessageType      `json:"type"`
-	Content     *string          `json:"content,omitempty"`
-	Voice       *UploadingFile   `json:"voice,omitempty"`
-	Attachments []*UploadingFile `json:"attachments,omitempty"`
-	ReplyToID   *int             `json:"replyToId,omitempty"`
-	Mentioned   []int            `json:"mentioned,omitempty"`
-	Circle      *UploadingFile   `json:"circle,omitempty"`
+	ChatID      int                `json:"chatId"`
+	Type        MessageType        `json:"type"`
+	Content     *string            `json:"content,omitempty"`
+	Voice       *UploadingFile     `json:"voice,omitempty"`
+	Attachments []*UploadingFile   `json:"attachments,omitempty"`
+	ReplyToID   *int               `json:"replyToId,omitempty"`
+	Mentioned   []int              `json:"mentioned,omitempty"`
+	Circle      *UploadingFile     `json:"circle,omitempty"`
+	SendAt      *string            `json:"sendAt,omitempty"`
+	Poll        *CreatePollRequest `json:"poll,omitempty"`
+}
+
+type CreatePollRequest struct {
+	Options        []string `json:"options"`
+	MultipleChoice *bool    `json:"multipleChoice,omitempty"`
+	Anonymous      *bool    `json:"anonymous,omitempty"`
+	ClosesAt       *string  `json:"closesAt,omitempty"`
 }
 
 type CreateReactionRequest struct {
@@ -101,33 +261,116 @@ type ErrorResponse struct {
 
 func (ErrorResponse) IsPaginatedMessagesErrorResponse() {}
 
+func (ErrorResponse) IsPaginatedMessageSearchResultsErrorResponse() {}
+
 func (ErrorResponse) IsPaginatedChatsErrorResponse() {}
 
 func (ErrorResponse) IsChatErrorResponse() {}
 
+func (ErrorResponse) IsChatInviteLinkErrorResponse() {}
+
+func (ErrorResponse) IsJoinRequestErrorResponse() {}
+
+func (ErrorResponse) IsJoinRequestsArrayErrorResponse() {}
+
+func (ErrorResponse) IsChatBansArrayErrorResponse() {}
+
+func (ErrorResponse) IsChatFolderErrorResponse() {}
+
+func (ErrorResponse) IsChatFoldersArrayErrorResponse() {}
+
+func (ErrorResponse) IsChatReadPointerErrorResponse() {}
+
+func (ErrorResponse) IsTotalUnreadErrorResponse() {}
+
 func (ErrorResponse) IsMessagesArrayErrorResponse() {}
 
+func (ErrorResponse) IsMessageRevisionsArrayErrorResponse() {}
+
 func (ErrorResponse) IsMessageErrorResponse() {}
 
 func (ErrorResponse) IsBooleanResultErrorResponse() {}
 
+type ForwardedFrom struct {
+	MessageID int `json:"messageId"`
+	SenderID  int `json:"senderId"`
+	ChatID    int `json:"chatId"`
+}
+
+type JoinRequest struct {
+	ID         int               `json:"id"`
+	ChatID     int               `json:"chatId"`
+	UserID     int               `json:"userId"`
+	Status     JoinRequestStatus `json:"status"`
+	ResolvedBy *int              `json:"resolvedBy,omitempty"`
+	CreatedAt  string            `json:"createdAt"`
+	ResolvedAt *string           `json:"resolvedAt,omitempty"`
+}
+
+func (JoinRequest) IsJoinRequestErrorResponse() {}
+
+type JoinRequestsArray struct {
+	Requests []*JoinRequest `json:"requests"`
+}
+
+func (JoinRequestsArray) IsJoinRequestsArrayErrorResponse() {}
+
+type MemberPermissions struct {
+	CanSendMessages bool `json:"canSendMessages"`
+	CanSendMedia    bool `json:"canSendMedia"`
+	CanAddMembers   bool `json:"canAddMembers"`
+	CanPinMessages  bool `json:"canPinMessages"`
+}
+
+type MemberPermissionsRequest struct {
+	CanSendMessages bool `json:"canSendMessages"`
+	CanSendMedia    bool `json:"canSendMedia"`
+	CanAddMembers   bool `json:"canAddMembers"`
+	CanPinMessages  bool `json:"canPinMessages"`
+}
+
 type Message struct {
-	ID          int          `json:"id"`
-	Type        MessageType  `json:"type"`
-	SenderID    int          `json:"senderId"`
-	ChatID      int          `json:"chatId"`
+	ID                 int            `json:"id"`
+	Type               MessageType    `json:"type"`
+	SenderID           int            `json:"senderId"`
+	ChatID             int            `json:"chatId"`
+	Content            *string        `json:"content,omitempty"`
+	Voice              *SavedFile     `json:"voice,omitempty"`
+	Circle             *SavedFile     `json:"circle,omitempty"`
+	ReplyToID          *int           `json:"replyToId,omitempty"`
+	ThreadRootID       *int           `json:"threadRootId,omitempty"`
+	ThreadRepliesCount int            `json:"threadRepliesCount"`
+	ThreadLastReply    *ThreadReply   `json:"threadLastReply,omitempty"`
+	ForwardedFrom      *ForwardedFrom `json:"forwardedFrom,omitempty"`
+	SendAt             *string        `json:"sendAt,omitempty"`
+	ReadedBy           []int          `json:"readedBy"`
+	Reactions          []*Reaction    `json:"reactions"`
+	Attachments        []*SavedFile   `json:"attachments"`
+	Mentioned          []int          `json:"mentioned"`
+	CreatedAt          string         `json:"createdAt"`
+	EditedAt           *string        `json:"editedAt,omitempty"`
+	Poll               *Poll          `json:"poll,omitempty"`
+}
+
+func (Message) IsMessageErrorResponse() {}
+
+type MessageRevision struct {
 	Content     *string      `json:"content,omitempty"`
-	Voice       *SavedFile   `json:"voice,omitempty"`
-	Circle      *SavedFile   `json:"circle,omitempty"`
-	ReplyToID   *int         `json:"replyToId,omitempty"`
-	ReadedBy    []int        `json:"readedBy"`
-	Reactions   []*Reaction  `json:"reactions"`
 	Attachments []*SavedFile `json:"attachments"`
 	Mentioned   []int        `json:"mentioned"`
 	CreatedAt   string       `json:"createdAt"`
 }
 
-func (Message) IsMessageErrorResponse() {}
+type MessageRevisionsArray struct {
+	Revisions []*MessageRevision `json:"revisions"`
+}
+
+func (MessageRevisionsArray) IsMessageRevisionsArrayErrorResponse() {}
+
+type MessageSearchResult struct {
+	Message *Message `json:"message"`
+	Snippet string   `json:"snippet"`
+}
 
 type MessagesArray struct {
 	Messages []*Message `json:"messages"`
@@ -145,6 +388,15 @@ type PaginatedChats struct {
 
 func (PaginatedChats) IsPaginatedChatsErrorResponse() {}
 
+type PaginatedMessageSearchResults struct {
+	Offset int                    `json:"offset"`
+	Limit  int                    `json:"limit"`
+	Total  int                    `json:"total"`
+	Data   []*MessageSearchResult `json:"data"`
+}
+
+func (PaginatedMessageSearchResults) IsPaginatedMessageSearchResultsErrorResponse() {}
+
 type PaginatedMessages struct {
 	Offset int        `json:"offset"`
 	Limit  int        `json:"limit"`
@@ -155,6 +407,31 @@ type PaginatedMessages struct {
 
 func (PaginatedMessages) IsPaginatedMessagesErrorResponse() {}
 
+type PinnedMessage struct {
+	MessageID int     `json:"messageId"`
+	SenderID  int     `json:"senderId"`
+	Content   *string `json:"content,omitempty"`
+	PinnedBy  int     `json:"pinnedBy"`
+	PinnedAt  string  `json:"pinnedAt"`
+}
+
+type Poll struct {
+	Options        []*PollOption `json:"options"`
+	MultipleChoice bool          `json:"multipleChoice"`
+	Anonymous      bool          `json:"anonymous"`
+	ClosesAt       *string       `json:"closesAt,omitempty"`
+	ClosedAt       *string       `json:"closedAt,omitempty"`
+	IsClosed       bool          `json:"isClosed"`
+	TotalVoters    int           `json:"totalVoters"`
+}
+
+type PollOption struct {
+	ID         int    `json:"id"`
+	Content    string `json:"content"`
+	VotesCount int    `json:"votesCount"`
+	Voters     []int  `json:"voters,omitempty"`
+}
+
 type Reaction struct {
 	Content string `json:"content"`
 	UserID  int    `json:"userId"`
@@ -167,6 +444,28 @@ type SavedFile struct {
 	ConvertedFilename *string `json:"convertedFilename,omitempty"`
 }
 
+type SlowModeErrorResponse struct {
+	Message    string `json:"message"`
+	RetryAfter int    `json:"retryAfter"`
+}
+
+func (SlowModeErrorResponse) IsMessagesArrayErrorResponse() {}
+
+func (SlowModeErrorResponse) IsMessageErrorResponse() {}
+
+type ThreadReply struct {
+	MessageID int    `json:"messageId"`
+	SenderID  int    `json:"senderId"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type TotalUnread struct {
+	UnreadCount         int `json:"unreadCount"`
+	UnreadMentionsCount int `json:"unreadMentionsCount"`
+}
+
+func (TotalUnread) IsTotalUnreadErrorResponse() {}
+
 type UploadingFile struct {
 	Original  *UploadingFileMeta `json:"original"`
 	Converted *UploadingFileMeta `json:"converted,omitempty"`
@@ -234,17 +533,19 @@ const (
 	ChatTypeGroup         ChatType = "group"
 	ChatTypeUser          ChatType = "user"
 	ChatTypeSavedMessages ChatType = "saved_messages"
+	ChatTypeChannel       ChatType = "channel"
 )
 
 var AllChatType = []ChatType{
 	ChatTypeGroup,
 	ChatTypeUser,
 	ChatTypeSavedMessages,
+	ChatTypeChannel,
 }
 
 func (e ChatType) IsValid() bool {
 	switch e {
-	case ChatTypeGroup, ChatTypeUser, ChatTypeSavedMessages:
+	case ChatTypeGroup, ChatTypeUser, ChatTypeSavedMessages, ChatTypeChannel:
 		return true
 	}
 	return false
@@ -320,6 +621,49 @@ func (e FileType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type JoinRequestStatus string
+
+const (
+	JoinRequestStatusPending  JoinRequestStatus = "pending"
+	JoinRequestStatusApproved JoinRequestStatus = "approved"
+	JoinRequestStatusDeclined JoinRequestStatus = "declined"
+)
+
+var AllJoinRequestStatus = []JoinRequestStatus{
+	JoinRequestStatusPending,
+	JoinRequestStatusApproved,
+	JoinRequestStatusDeclined,
+}
+
+func (e JoinRequestStatus) IsValid() bool {
+	switch e {
+	case JoinRequestStatusPending, JoinRequestStatusApproved, JoinRequestStatusDeclined:
+		return true
+	}
+	return false
+}
+
+func (e JoinRequestStatus) String() string {
+	return string(e)
+}
+
+func (e *JoinRequestStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = JoinRequestStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid JoinRequestStatus", str)
+	}
+	return nil
+}
+
+func (e JoinRequestStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type MessageType string
 
 const (
@@ -328,6 +672,7 @@ const (
 	MessageTypeCall   MessageType = "call"
 	MessageTypeVoice  MessageType = "voice"
 	MessageTypeCircle MessageType = "circle"
+	MessageTypePoll   MessageType = "poll"
 )
 
 var AllMessageType = []MessageType{
@@ -336,11 +681,12 @@ var AllMessageType = []MessageType{
 	MessageTypeCall,
 	MessageTypeVoice,
 	MessageTypeCircle,
+	MessageTypePoll,
 }
 
 func (e MessageType) IsValid() bool {
 	switch e {
-	case MessageTypeText, MessageTypeEvent, MessageTypeCall, MessageTypeVoice, MessageTypeCircle:
+	case MessageTypeText, MessageTypeEvent, MessageTypeCall, MessageTypeVoice, MessageTypeCircle, MessageTypePoll:
 		return true
 	}
 	return false
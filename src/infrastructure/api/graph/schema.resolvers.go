@@ -6,19 +6,25 @@ package graph
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/chack-check/chats-service/domain/chats"
 	"github.com/chack-check/chats-service/domain/messages"
 	"github.com/chack-check/chats-service/infrastructure/api/factories"
 	"github.com/chack-check/chats-service/infrastructure/api/graph/model"
 	"github.com/chack-check/chats-service/infrastructure/api/middlewares"
+	"github.com/chack-check/chats-service/infrastructure/api/settings"
 	"github.com/chack-check/chats-service/infrastructure/api/utils"
+	"github.com/chack-check/chats-service/infrastructure/broker"
 	"github.com/chack-check/chats-service/infrastructure/database"
 	"github.com/chack-check/chats-service/infrastructure/filesservice"
 	"github.com/chack-check/chats-service/infrastructure/grpc_service/usersproto"
 	"github.com/chack-check/chats-service/infrastructure/rabbit"
 	"github.com/chack-check/chats-service/infrastructure/redisdb"
+	"github.com/chack-check/chats-service/infrastructure/scheduler"
 	jwt "github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
 )
 
 // CreateMessage is the resolver for the createMessage field.
@@ -33,15 +39,29 @@ func (r *mutationResolver) CreateMessage(ctx context.Context, request model.Crea
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	messagesHandler := messages.NewCreateMessageHandler(
-		database.NewChatsAdapter(*database.DatabaseConnection),
-		database.NewMessagesAdapter(*database.DatabaseConnection),
-		rabbit.NewMessageEventsAdapter(*rabbit.EventsRabbitConnection),
-		filesservice.NewFilesAdapter(),
-	)
+	data, err := factories.CreateMessageRequestToModel(request)
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
 
-	data := factories.CreateMessageRequestToModel(request)
-	message, err := messagesHandler.Execute(data, tokenSubject.UserId)
+	var message *messages.Message
+	err = database.Transaction(func(tx gorm.DB) error {
+		messagesHandler := messages.NewCreateMessageHandler(
+			database.NewChatsAdapter(tx),
+			database.NewMessagesAdapter(tx),
+			rabbit.NewMessageEventsAdapter(ctx, tx),
+			filesservice.NewFilesAdapter(),
+			scheduler.NewMessagesSchedulerAdapter(scheduler.Scheduler),
+			redisdb.NewSlowModeAdapter(redisdb.RedisConnection),
+		)
+
+		message, err = messagesHandler.Execute(*data, tokenSubject.UserId)
+		return err
+	})
+	var slowModeErr *messages.SlowModeError
+	if errors.As(err, &slowModeErr) {
+		return factories.SlowModeErrorToResponse(*slowModeErr), nil
+	}
 	if err != nil {
 		return model.ErrorResponse{Message: err.Error()}, nil
 	}
@@ -62,14 +82,18 @@ func (r *mutationResolver) EditMessage(ctx context.Context, messageID int, reque
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	messagesHandler := messages.NewUpdateMessageHandler(
-		database.NewMessagesAdapter(*database.DatabaseConnection),
-		rabbit.NewMessageEventsAdapter(*rabbit.EventsRabbitConnection),
-		filesservice.NewFilesAdapter(),
-	)
-
 	data := factories.UpdateMessageRequestToModel(request)
-	message, err := messagesHandler.Execute(messageID, tokenSubject.UserId, data)
+	var message *messages.Message
+	err = database.Transaction(func(tx gorm.DB) error {
+		messagesHandler := messages.NewUpdateMessageHandler(
+			database.NewChatsAdapter(tx),
+			database.NewMessagesAdapter(tx),
+			rabbit.NewMessageEventsAdapter(ctx, tx),
+			filesservice.NewFilesAdapter(),
+		)
+		message, err = messagesHandler.Execute(messageID, tokenSubject.UserId, data)
+		return err
+	})
 	if err != nil {
 		return model.ErrorResponse{Message: err.Error()}, nil
 	}
@@ -78,6 +102,46 @@ func (r *mutationResolver) EditMessage(ctx context.Context, messageID int, reque
 	return &messageResponse, nil
 }
 
+// ForwardMessages is the resolver for the forwardMessages field.
+func (r *mutationResolver) ForwardMessages(ctx context.Context, messageIds []int, targetChatIds []int) (model.MessagesArrayErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var forwarded []messages.Message
+	err = database.Transaction(func(tx gorm.DB) error {
+		messagesHandler := messages.NewForwardMessagesHandler(
+			database.NewChatsAdapter(tx),
+			database.NewMessagesAdapter(tx),
+			rabbit.NewMessageEventsAdapter(ctx, tx),
+			redisdb.NewSlowModeAdapter(redisdb.RedisConnection),
+		)
+
+		forwarded, err = messagesHandler.Execute(messageIds, targetChatIds, tokenSubject.UserId)
+		return err
+	})
+	var slowModeErr *messages.SlowModeError
+	if errors.As(err, &slowModeErr) {
+		return factories.SlowModeErrorToResponse(*slowModeErr), nil
+	}
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	var response []*model.Message
+	for _, message := range forwarded {
+		messageResponse := factories.MessageModelToResponse(message)
+		response = append(response, &messageResponse)
+	}
+	return model.MessagesArray{Messages: response}, nil
+}
+
 // CreateChat is the resolver for the createChat field.
 func (r *mutationResolver) CreateChat(ctx context.Context, request model.CreateChatRequest) (model.ChatErrorResponse, error) {
 	token, _ := ctx.Value("token").(*jwt.Token)
@@ -90,13 +154,6 @@ func (r *mutationResolver) CreateChat(ctx context.Context, request model.CreateC
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	chatsHandler := chats.NewCreateChatHandler(
-		database.NewChatsAdapter(*database.DatabaseConnection),
-		rabbit.NewChatEventsAdapter(*rabbit.EventsRabbitConnection),
-		usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
-		filesservice.NewFilesAdapter(),
-	)
-
 	var chatType chats.ChatTypes
 	if request.User != nil {
 		chatType = chats.UserChatType
@@ -105,7 +162,17 @@ func (r *mutationResolver) CreateChat(ctx context.Context, request model.CreateC
 	}
 
 	data := factories.CreateChatRequestToModel(request, chatType)
-	chat, err := chatsHandler.Execute(data, tokenSubject.UserId)
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewCreateChatHandler(
+			database.NewChatsAdapter(tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+			usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
+			filesservice.NewFilesAdapter(),
+		)
+		chat, err = chatsHandler.Execute(data, tokenSubject.UserId)
+		return err
+	})
 	if err != nil {
 		return model.ErrorResponse{Message: err.Error()}, nil
 	}
@@ -114,8 +181,8 @@ func (r *mutationResolver) CreateChat(ctx context.Context, request model.CreateC
 	return &chatResponse, nil
 }
 
-// ReadMessage is the resolver for the readMessage field.
-func (r *mutationResolver) ReadMessage(ctx context.Context, messageID int) (model.MessageErrorResponse, error) {
+// CreateChannel is the resolver for the createChannel field.
+func (r *mutationResolver) CreateChannel(ctx context.Context, request model.CreateChannelRequest) (model.ChatErrorResponse, error) {
 	token, _ := ctx.Value("token").(*jwt.Token)
 	if err := utils.UserRequired(token); err != nil {
 		return model.ErrorResponse{Message: "Token required"}, nil
@@ -126,22 +193,28 @@ func (r *mutationResolver) ReadMessage(ctx context.Context, messageID int) (mode
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	messagesHandler := messages.NewReadMessageHandler(
-		database.NewMessagesAdapter(*database.DatabaseConnection),
-		rabbit.NewMessageEventsAdapter(*rabbit.EventsRabbitConnection),
-	)
-
-	message, err := messagesHandler.Execute(messageID, tokenSubject.UserId)
+	data := factories.CreateChannelRequestToModel(request)
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewCreateChatHandler(
+			database.NewChatsAdapter(tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+			usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
+			filesservice.NewFilesAdapter(),
+		)
+		chat, err = chatsHandler.Execute(data, tokenSubject.UserId)
+		return err
+	})
 	if err != nil {
 		return model.ErrorResponse{Message: err.Error()}, nil
 	}
 
-	messageResponse := factories.MessageModelToResponse(*message)
-	return &messageResponse, nil
+	chatResponse := factories.ChatModelToResponse(*chat)
+	return &chatResponse, nil
 }
 
-// ReactMessage is the resolver for the reactMessage field.
-func (r *mutationResolver) ReactMessage(ctx context.Context, messageID int, content string) (model.MessageErrorResponse, error) {
+// SubscribeChannel is the resolver for the subscribeChannel field.
+func (r *mutationResolver) SubscribeChannel(ctx context.Context, slug string) (model.ChatErrorResponse, error) {
 	token, _ := ctx.Value("token").(*jwt.Token)
 	if err := utils.UserRequired(token); err != nil {
 		return model.ErrorResponse{Message: "Token required"}, nil
@@ -152,22 +225,25 @@ func (r *mutationResolver) ReactMessage(ctx context.Context, messageID int, cont
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	messagesHandler := messages.NewReactMessageHandler(
-		database.NewMessagesAdapter(*database.DatabaseConnection),
-		rabbit.NewMessageEventsAdapter(*rabbit.EventsRabbitConnection),
-	)
-
-	message, err := messagesHandler.Execute(messageID, tokenSubject.UserId, content)
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewSubscribeChannelHandler(
+			database.NewChatsAdapter(tx),
+			usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		chat, err = chatsHandler.Execute(slug, tokenSubject.UserId)
+		return err
+	})
 	if err != nil {
 		return model.ErrorResponse{Message: err.Error()}, nil
 	}
 
-	messageResponse := factories.MessageModelToResponse(*message)
-	return &messageResponse, nil
+	return factories.ChatModelToResponse(*chat), nil
 }
 
-// DeleteMessageReaction is the resolver for the deleteMessageReaction field.
-func (r *mutationResolver) DeleteMessageReaction(ctx context.Context, messageID int) (model.MessageErrorResponse, error) {
+// ReadMessage is the resolver for the readMessage field.
+func (r *mutationResolver) ReadMessage(ctx context.Context, messageID int) (model.MessageErrorResponse, error) {
 	token, _ := ctx.Value("token").(*jwt.Token)
 	if err := utils.UserRequired(token); err != nil {
 		return model.ErrorResponse{Message: "Token required"}, nil
@@ -178,12 +254,17 @@ func (r *mutationResolver) DeleteMessageReaction(ctx context.Context, messageID
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	messagesHandler := messages.NewDeleteMessageReactionHandler(
-		database.NewMessagesAdapter(*database.DatabaseConnection),
-		rabbit.NewMessageEventsAdapter(*rabbit.EventsRabbitConnection),
-	)
-
-	message, err := messagesHandler.Execute(messageID, tokenSubject.UserId)
+	var message *messages.Message
+	err = database.Transaction(func(tx gorm.DB) error {
+		messagesHandler := messages.NewReadMessageHandler(
+			database.NewChatsAdapter(tx),
+			database.NewMessagesAdapter(tx),
+			rabbit.NewMessageEventsAdapter(ctx, tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		message, err = messagesHandler.Execute(messageID, tokenSubject.UserId)
+		return err
+	})
 	if err != nil {
 		return model.ErrorResponse{Message: err.Error()}, nil
 	}
@@ -192,8 +273,8 @@ func (r *mutationResolver) DeleteMessageReaction(ctx context.Context, messageID
 	return &messageResponse, nil
 }
 
-// DeleteMessage is the resolver for the deleteMessage field.
-func (r *mutationResolver) DeleteMessage(ctx context.Context, messageID int) (model.BooleanResultErrorResponse, error) {
+// ReadChatUntil is the resolver for the readChatUntil field.
+func (r *mutationResolver) ReadChatUntil(ctx context.Context, chatID int, messageID int) (model.ChatReadPointerErrorResponse, error) {
 	token, _ := ctx.Value("token").(*jwt.Token)
 	if err := utils.UserRequired(token); err != nil {
 		return model.ErrorResponse{Message: "Token required"}, nil
@@ -204,21 +285,27 @@ func (r *mutationResolver) DeleteMessage(ctx context.Context, messageID int) (mo
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	messagesHandler := messages.NewDeleteMessageHandler(
-		database.NewMessagesAdapter(*database.DatabaseConnection),
-		rabbit.NewMessageEventsAdapter(*rabbit.EventsRabbitConnection),
-	)
-
-	err = messagesHandler.Execute(messageID, tokenSubject.UserId)
+	var pointer *messages.ChatReadPointer
+	err = database.Transaction(func(tx gorm.DB) error {
+		messagesHandler := messages.NewReadChatUntilHandler(
+			database.NewChatsAdapter(tx),
+			database.NewMessagesAdapter(tx),
+			rabbit.NewMessageEventsAdapter(ctx, tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		pointer, err = messagesHandler.Execute(chatID, messageID, tokenSubject.UserId)
+		return err
+	})
 	if err != nil {
 		return model.ErrorResponse{Message: err.Error()}, nil
 	}
 
-	return model.BooleanResult{Result: true}, nil
+	response := factories.ChatReadPointerModelToResponse(*pointer)
+	return &response, nil
 }
 
-// DeleteChat is the resolver for the deleteChat field.
-func (r *mutationResolver) DeleteChat(ctx context.Context, chatID int) (model.BooleanResultErrorResponse, error) {
+// ReactMessage is the resolver for the reactMessage field.
+func (r *mutationResolver) ReactMessage(ctx context.Context, messageID int, content string) (model.MessageErrorResponse, error) {
 	token, _ := ctx.Value("token").(*jwt.Token)
 	if err := utils.UserRequired(token); err != nil {
 		return model.ErrorResponse{Message: "Token required"}, nil
@@ -229,21 +316,25 @@ func (r *mutationResolver) DeleteChat(ctx context.Context, chatID int) (model.Bo
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	chatsHandler := chats.NewDeleteChatHandler(
-		database.NewChatsAdapter(*database.DatabaseConnection),
-		rabbit.NewChatEventsAdapter(*rabbit.EventsRabbitConnection),
-	)
-
-	err = chatsHandler.Execute(chatID, tokenSubject.UserId)
+	var message *messages.Message
+	err = database.Transaction(func(tx gorm.DB) error {
+		messagesHandler := messages.NewReactMessageHandler(
+			database.NewMessagesAdapter(tx),
+			rabbit.NewMessageEventsAdapter(ctx, tx),
+		)
+		message, err = messagesHandler.Execute(messageID, tokenSubject.UserId, content)
+		return err
+	})
 	if err != nil {
 		return model.ErrorResponse{Message: err.Error()}, nil
 	}
 
-	return model.BooleanResult{Result: true}, nil
+	messageResponse := factories.MessageModelToResponse(*message)
+	return &messageResponse, nil
 }
 
-// SendUserAction is the resolver for the sendUserAction field.
-func (r *mutationResolver) SendUserAction(ctx context.Context, chatID int, actionType model.ActionTypes) (model.BooleanResultErrorResponse, error) {
+// DeleteMessageReaction is the resolver for the deleteMessageReaction field.
+func (r *mutationResolver) DeleteMessageReaction(ctx context.Context, messageID int) (model.MessageErrorResponse, error) {
 	token, _ := ctx.Value("token").(*jwt.Token)
 	if err := utils.UserRequired(token); err != nil {
 		return model.ErrorResponse{Message: "Token required"}, nil
@@ -254,23 +345,25 @@ func (r *mutationResolver) SendUserAction(ctx context.Context, chatID int, actio
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	chatsHandler := chats.NewUserActionHandler(
-		database.NewChatsAdapter(*database.DatabaseConnection),
-		rabbit.NewChatEventsAdapter(*rabbit.EventsRabbitConnection),
-		usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
-		redisdb.NewUserActionsAdapter(redisdb.RedisConnection),
-	)
-
-	_, err = chatsHandler.Execute(chatID, tokenSubject.UserId, chats.ActionTypes(actionType.String()))
+	var message *messages.Message
+	err = database.Transaction(func(tx gorm.DB) error {
+		messagesHandler := messages.NewDeleteMessageReactionHandler(
+			database.NewMessagesAdapter(tx),
+			rabbit.NewMessageEventsAdapter(ctx, tx),
+		)
+		message, err = messagesHandler.Execute(messageID, tokenSubject.UserId)
+		return err
+	})
 	if err != nil {
 		return model.ErrorResponse{Message: err.Error()}, nil
 	}
 
-	return model.BooleanResult{Result: true}, nil
+	messageResponse := factories.MessageModelToResponse(*message)
+	return &messageResponse, nil
 }
 
-// StopUserAction is the resolver for the stopUserAction field.
-func (r *mutationResolver) StopUserAction(ctx context.Context, chatID int, actionType model.ActionTypes) (model.BooleanResultErrorResponse, error) {
+// DeleteMessage is the resolver for the deleteMessage field.
+func (r *mutationResolver) DeleteMessage(ctx context.Context, messageID int, forEveryone *bool) (model.BooleanResultErrorResponse, error) {
 	token, _ := ctx.Value("token").(*jwt.Token)
 	if err := utils.UserRequired(token); err != nil {
 		return model.ErrorResponse{Message: "Token required"}, nil
@@ -281,13 +374,15 @@ func (r *mutationResolver) StopUserAction(ctx context.Context, chatID int, actio
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	chatsHandler := chats.NewStopUserActionHandler(
-		database.NewChatsAdapter(*database.DatabaseConnection),
-		rabbit.NewChatEventsAdapter(*rabbit.EventsRabbitConnection),
-		redisdb.NewUserActionsAdapter(redisdb.RedisConnection),
-	)
-
-	_, err = chatsHandler.Execute(chatID, tokenSubject.UserId, chats.ActionTypes(actionType.String()))
+	err = database.Transaction(func(tx gorm.DB) error {
+		messagesHandler := messages.NewDeleteMessageHandler(
+			database.NewMessagesAdapter(tx),
+			rabbit.NewMessageEventsAdapter(ctx, tx),
+			time.Duration(settings.Settings.APP_DELETE_FOR_EVERYONE_WINDOW_SECONDS)*time.Second,
+		)
+		err = messagesHandler.Execute(messageID, tokenSubject.UserId, forEveryone != nil && *forEveryone)
+		return err
+	})
 	if err != nil {
 		return model.ErrorResponse{Message: err.Error()}, nil
 	}
@@ -295,8 +390,8 @@ func (r *mutationResolver) StopUserAction(ctx context.Context, chatID int, actio
 	return model.BooleanResult{Result: true}, nil
 }
 
-// AddMembers is the resolver for the addMembers field.
-func (r *mutationResolver) AddMembers(ctx context.Context, chatID int, members []int) (model.ChatErrorResponse, error) {
+// PinMessage is the resolver for the pinMessage field.
+func (r *mutationResolver) PinMessage(ctx context.Context, messageID int) (model.MessageErrorResponse, error) {
 	token, _ := ctx.Value("token").(*jwt.Token)
 	if err := utils.UserRequired(token); err != nil {
 		return model.ErrorResponse{Message: "Token required"}, nil
@@ -307,22 +402,25 @@ func (r *mutationResolver) AddMembers(ctx context.Context, chatID int, members [
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	chatsHandler := chats.NewAddChatsMembersHandler(
-		database.NewChatsAdapter(*database.DatabaseConnection),
-		usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
-		rabbit.NewChatEventsAdapter(*rabbit.EventsRabbitConnection),
-	)
-
-	chat, err := chatsHandler.Execute(chatID, tokenSubject.UserId, members)
+	var message *messages.Message
+	err = database.Transaction(func(tx gorm.DB) error {
+		messagesHandler := messages.NewPinMessageHandler(
+			database.NewMessagesAdapter(tx),
+			rabbit.NewMessageEventsAdapter(ctx, tx),
+		)
+		message, err = messagesHandler.Execute(messageID, tokenSubject.UserId)
+		return err
+	})
 	if err != nil {
 		return model.ErrorResponse{Message: err.Error()}, nil
 	}
 
-	return factories.ChatModelToResponse(*chat), nil
+	messageResponse := factories.MessageModelToResponse(*message)
+	return &messageResponse, nil
 }
 
-// AddAdmins is the resolver for the addAdmins field.
-func (r *mutationResolver) AddAdmins(ctx context.Context, chatID int, admins []int) (model.ChatErrorResponse, error) {
+// UnpinMessage is the resolver for the unpinMessage field.
+func (r *mutationResolver) UnpinMessage(ctx context.Context, messageID int) (model.MessageErrorResponse, error) {
 	token, _ := ctx.Value("token").(*jwt.Token)
 	if err := utils.UserRequired(token); err != nil {
 		return model.ErrorResponse{Message: "Token required"}, nil
@@ -333,22 +431,25 @@ func (r *mutationResolver) AddAdmins(ctx context.Context, chatID int, admins []i
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	chatsHandler := chats.NewAddChatsAdminsHandler(
-		database.NewChatsAdapter(*database.DatabaseConnection),
-		usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
-		rabbit.NewChatEventsAdapter(*rabbit.EventsRabbitConnection),
-	)
-
-	chat, err := chatsHandler.Execute(chatID, tokenSubject.UserId, admins)
+	var message *messages.Message
+	err = database.Transaction(func(tx gorm.DB) error {
+		messagesHandler := messages.NewUnpinMessageHandler(
+			database.NewMessagesAdapter(tx),
+			rabbit.NewMessageEventsAdapter(ctx, tx),
+		)
+		message, err = messagesHandler.Execute(messageID, tokenSubject.UserId)
+		return err
+	})
 	if err != nil {
 		return model.ErrorResponse{Message: err.Error()}, nil
 	}
 
-	return factories.ChatModelToResponse(*chat), nil
+	messageResponse := factories.MessageModelToResponse(*message)
+	return &messageResponse, nil
 }
 
-// RemoveMembers is the resolver for the removeMembers field.
-func (r *mutationResolver) RemoveMembers(ctx context.Context, chatID int, members []int) (model.ChatErrorResponse, error) {
+// EditScheduledMessage is the resolver for the editScheduledMessage field.
+func (r *mutationResolver) EditScheduledMessage(ctx context.Context, messageID int, request model.ChangeMessageRequest) (model.MessageErrorResponse, error) {
 	token, _ := ctx.Value("token").(*jwt.Token)
 	if err := utils.UserRequired(token); err != nil {
 		return model.ErrorResponse{Message: "Token required"}, nil
@@ -359,21 +460,27 @@ func (r *mutationResolver) RemoveMembers(ctx context.Context, chatID int, member
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	chatsHandler := chats.NewRemoveChatMembersHandler(
-		database.NewChatsAdapter(*database.DatabaseConnection),
-		rabbit.NewChatEventsAdapter(*rabbit.EventsRabbitConnection),
-	)
-
-	chat, err := chatsHandler.Execute(chatID, tokenSubject.UserId, members)
+	data := factories.UpdateMessageRequestToModel(request)
+	var message *messages.Message
+	err = database.Transaction(func(tx gorm.DB) error {
+		messagesHandler := messages.NewUpdateScheduledMessageHandler(
+			database.NewChatsAdapter(tx),
+			database.NewMessagesAdapter(tx),
+			filesservice.NewFilesAdapter(),
+		)
+		message, err = messagesHandler.Execute(messageID, tokenSubject.UserId, data)
+		return err
+	})
 	if err != nil {
 		return model.ErrorResponse{Message: err.Error()}, nil
 	}
 
-	return factories.ChatModelToResponse(*chat), nil
+	messageResponse := factories.MessageModelToResponse(*message)
+	return &messageResponse, nil
 }
 
-// RemoveAdmins is the resolver for the removeAdmins field.
-func (r *mutationResolver) RemoveAdmins(ctx context.Context, chatID int, admins []int) (model.ChatErrorResponse, error) {
+// RescheduleMessage is the resolver for the rescheduleMessage field.
+func (r *mutationResolver) RescheduleMessage(ctx context.Context, messageID int, sendAt string) (model.MessageErrorResponse, error) {
 	token, _ := ctx.Value("token").(*jwt.Token)
 	if err := utils.UserRequired(token); err != nil {
 		return model.ErrorResponse{Message: "Token required"}, nil
@@ -384,21 +491,30 @@ func (r *mutationResolver) RemoveAdmins(ctx context.Context, chatID int, admins
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	chatsHandler := chats.NewRemoveChatAdminsHandler(
-		database.NewChatsAdapter(*database.DatabaseConnection),
-		rabbit.NewChatEventsAdapter(*rabbit.EventsRabbitConnection),
-	)
+	sendAtValue, err := factories.ParseDatetime(sendAt)
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
 
-	chat, err := chatsHandler.Execute(chatID, tokenSubject.UserId, admins)
+	var message *messages.Message
+	err = database.Transaction(func(tx gorm.DB) error {
+		messagesHandler := messages.NewRescheduleMessageHandler(
+			database.NewMessagesAdapter(tx),
+			scheduler.NewMessagesSchedulerAdapter(scheduler.Scheduler),
+		)
+		message, err = messagesHandler.Execute(messageID, tokenSubject.UserId, *sendAtValue)
+		return err
+	})
 	if err != nil {
 		return model.ErrorResponse{Message: err.Error()}, nil
 	}
 
-	return factories.ChatModelToResponse(*chat), nil
+	messageResponse := factories.MessageModelToResponse(*message)
+	return &messageResponse, nil
 }
 
-// QuitChat is the resolver for the quitChat field.
-func (r *mutationResolver) QuitChat(ctx context.Context, chatID int) (model.ChatErrorResponse, error) {
+// CancelScheduledMessage is the resolver for the cancelScheduledMessage field.
+func (r *mutationResolver) CancelScheduledMessage(ctx context.Context, messageID int) (model.BooleanResultErrorResponse, error) {
 	token, _ := ctx.Value("token").(*jwt.Token)
 	if err := utils.UserRequired(token); err != nil {
 		return model.ErrorResponse{Message: "Token required"}, nil
@@ -409,21 +525,23 @@ func (r *mutationResolver) QuitChat(ctx context.Context, chatID int) (model.Chat
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	chatsHandler := chats.NewQuitChatHandler(
-		database.NewChatsAdapter(*database.DatabaseConnection),
-		rabbit.NewChatEventsAdapter(*rabbit.EventsRabbitConnection),
-	)
-
-	chat, err := chatsHandler.Execute(chatID, tokenSubject.UserId)
+	err = database.Transaction(func(tx gorm.DB) error {
+		messagesHandler := messages.NewCancelScheduledMessageHandler(
+			database.NewMessagesAdapter(tx),
+			scheduler.NewMessagesSchedulerAdapter(scheduler.Scheduler),
+		)
+		err = messagesHandler.Execute(messageID, tokenSubject.UserId)
+		return err
+	})
 	if err != nil {
 		return model.ErrorResponse{Message: err.Error()}, nil
 	}
 
-	return factories.ChatModelToResponse(*chat), nil
+	return model.BooleanResult{Result: true}, nil
 }
 
-// ChangeGroupChat is the resolver for the changeGroupChat field.
-func (r *mutationResolver) ChangeGroupChat(ctx context.Context, chatID int, chatData model.ChangeGroupChatData) (model.ChatErrorResponse, error) {
+// SendScheduledMessageNow is the resolver for the sendScheduledMessageNow field.
+func (r *mutationResolver) SendScheduledMessageNow(ctx context.Context, messageID int) (model.MessageErrorResponse, error) {
 	token, _ := ctx.Value("token").(*jwt.Token)
 	if err := utils.UserRequired(token); err != nil {
 		return model.ErrorResponse{Message: "Token required"}, nil
@@ -434,21 +552,33 @@ func (r *mutationResolver) ChangeGroupChat(ctx context.Context, chatID int, chat
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	chatsHandler := chats.NewChangeGroupChatHandler(
-		database.NewChatsAdapter(*database.DatabaseConnection),
-		rabbit.NewChatEventsAdapter(*rabbit.EventsRabbitConnection),
-	)
+	var message *messages.Message
+	err = database.Transaction(func(tx gorm.DB) error {
+		messagesHandler := messages.NewSendScheduledMessageNowHandler(
+			database.NewChatsAdapter(tx),
+			database.NewMessagesAdapter(tx),
+			rabbit.NewMessageEventsAdapter(ctx, tx),
+			scheduler.NewMessagesSchedulerAdapter(scheduler.Scheduler),
+			redisdb.NewSlowModeAdapter(redisdb.RedisConnection),
+		)
 
-	chat, err := chatsHandler.Execute(chatID, tokenSubject.UserId, chats.NewChangeGroupChatData(chatData.Title))
+		message, err = messagesHandler.Execute(messageID, tokenSubject.UserId)
+		return err
+	})
+	var slowModeErr *messages.SlowModeError
+	if errors.As(err, &slowModeErr) {
+		return factories.SlowModeErrorToResponse(*slowModeErr), nil
+	}
 	if err != nil {
 		return model.ErrorResponse{Message: err.Error()}, nil
 	}
 
-	return factories.ChatModelToResponse(*chat), nil
+	messageResponse := factories.MessageModelToResponse(*message)
+	return &messageResponse, nil
 }
 
-// UpdateGroupChatAvatar is the resolver for the updateGroupChatAvatar field.
-func (r *mutationResolver) UpdateGroupChatAvatar(ctx context.Context, chatID int, avatar model.UploadingFile) (model.ChatErrorResponse, error) {
+// VotePoll is the resolver for the votePoll field.
+func (r *mutationResolver) VotePoll(ctx context.Context, messageID int, optionIds []int) (model.MessageErrorResponse, error) {
 	token, _ := ctx.Value("token").(*jwt.Token)
 	if err := utils.UserRequired(token); err != nil {
 		return model.ErrorResponse{Message: "Token required"}, nil
@@ -459,22 +589,25 @@ func (r *mutationResolver) UpdateGroupChatAvatar(ctx context.Context, chatID int
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	chatsHandler := chats.NewUpdateGroupChatAvatar(
-		database.NewChatsAdapter(*database.DatabaseConnection),
-		filesservice.NewFilesAdapter(),
-		rabbit.NewChatEventsAdapter(*rabbit.EventsRabbitConnection),
-	)
-
-	chat, err := chatsHandler.Execute(chatID, tokenSubject.UserId, factories.UploadingFileToModel(avatar))
+	var message *messages.Message
+	err = database.Transaction(func(tx gorm.DB) error {
+		messagesHandler := messages.NewVotePollHandler(
+			database.NewMessagesAdapter(tx),
+			rabbit.NewMessageEventsAdapter(ctx, tx),
+		)
+		message, err = messagesHandler.Execute(messageID, tokenSubject.UserId, optionIds)
+		return err
+	})
 	if err != nil {
 		return model.ErrorResponse{Message: err.Error()}, nil
 	}
 
-	return factories.ChatModelToResponse(*chat), nil
+	messageResponse := factories.MessageModelToResponse(*message)
+	return &messageResponse, nil
 }
 
-// GetChatMessages is the resolver for the getChatMessages field.
-func (r *queryResolver) GetChatMessages(ctx context.Context, chatID int, offset *int, limit *int) (model.PaginatedMessagesErrorResponse, error) {
+// RetractVote is the resolver for the retractVote field.
+func (r *mutationResolver) RetractVote(ctx context.Context, messageID int) (model.MessageErrorResponse, error) {
 	token, _ := ctx.Value("token").(*jwt.Token)
 	if err := utils.UserRequired(token); err != nil {
 		return model.ErrorResponse{Message: "Token required"}, nil
@@ -485,36 +618,54 @@ func (r *queryResolver) GetChatMessages(ctx context.Context, chatID int, offset
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	messagesHandler := messages.NewGetChatMessagesHandler(
-		database.NewChatsAdapter(*database.DatabaseConnection),
-		database.NewMessagesAdapter(*database.DatabaseConnection),
-	)
+	var message *messages.Message
+	err = database.Transaction(func(tx gorm.DB) error {
+		messagesHandler := messages.NewRetractPollVoteHandler(
+			database.NewMessagesAdapter(tx),
+			rabbit.NewMessageEventsAdapter(ctx, tx),
+		)
+		message, err = messagesHandler.Execute(messageID, tokenSubject.UserId)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
 
-	var offsetValue int
-	if offset != nil && *offset > 0 {
-		offsetValue = *offset
-	} else {
-		offsetValue = 0
+	messageResponse := factories.MessageModelToResponse(*message)
+	return &messageResponse, nil
+}
+
+// ClosePoll is the resolver for the closePoll field.
+func (r *mutationResolver) ClosePoll(ctx context.Context, messageID int) (model.MessageErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
 	}
 
-	var limitValue int
-	if limit != nil && *limit > 0 {
-		limitValue = *limit
-	} else {
-		limitValue = 100
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	messages, err := messagesHandler.Execute(chatID, tokenSubject.UserId, offsetValue, limitValue)
+	var message *messages.Message
+	err = database.Transaction(func(tx gorm.DB) error {
+		messagesHandler := messages.NewClosePollHandler(
+			database.NewMessagesAdapter(tx),
+			rabbit.NewMessageEventsAdapter(ctx, tx),
+		)
+		message, err = messagesHandler.Execute(messageID, tokenSubject.UserId)
+		return err
+	})
 	if err != nil {
 		return model.ErrorResponse{Message: err.Error()}, nil
 	}
 
-	response := factories.OffsetMessagesToResponse(*messages, chatID)
-	return &response, nil
+	messageResponse := factories.MessageModelToResponse(*message)
+	return &messageResponse, nil
 }
 
-// GetChatMessagesByCursor is the resolver for the getChatMessagesByCursor field.
-func (r *queryResolver) GetChatMessagesByCursor(ctx context.Context, chatID int, messageID int, aroundOffset *int) (model.PaginatedMessagesErrorResponse, error) {
+// DeleteChat is the resolver for the deleteChat field.
+func (r *mutationResolver) DeleteChat(ctx context.Context, chatID int) (model.BooleanResultErrorResponse, error) {
 	token, _ := ctx.Value("token").(*jwt.Token)
 	if err := utils.UserRequired(token); err != nil {
 		return model.ErrorResponse{Message: "Token required"}, nil
@@ -525,29 +676,23 @@ func (r *queryResolver) GetChatMessagesByCursor(ctx context.Context, chatID int,
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	messagesHandler := messages.NewGetChatMessagesByCursorHandler(
-		database.NewChatsAdapter(*database.DatabaseConnection),
-		database.NewMessagesAdapter(*database.DatabaseConnection),
-	)
-
-	var aroundOffsetValue int
-	if aroundOffset != nil && *aroundOffset > 0 {
-		aroundOffsetValue = *aroundOffset
-	} else {
-		aroundOffsetValue = 50
-	}
-
-	messages, err := messagesHandler.Execute(chatID, tokenSubject.UserId, messageID, aroundOffsetValue)
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewDeleteChatHandler(
+			database.NewChatsAdapter(tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		err = chatsHandler.Execute(chatID, tokenSubject.UserId)
+		return err
+	})
 	if err != nil {
 		return model.ErrorResponse{Message: err.Error()}, nil
 	}
 
-	response := factories.OffsetMessagesToResponse(*messages, chatID)
-	return &response, nil
+	return model.BooleanResult{Result: true}, nil
 }
 
-// GetChats is the resolver for the getChats field.
-func (r *queryResolver) GetChats(ctx context.Context, page *int, perPage *int) (model.PaginatedChatsErrorResponse, error) {
+// SendUserAction is the resolver for the sendUserAction field.
+func (r *mutationResolver) SendUserAction(ctx context.Context, chatID int, actionType model.ActionTypes) (model.BooleanResultErrorResponse, error) {
 	token, _ := ctx.Value("token").(*jwt.Token)
 	if err := utils.UserRequired(token); err != nil {
 		return model.ErrorResponse{Message: "Token required"}, nil
@@ -558,33 +703,25 @@ func (r *queryResolver) GetChats(ctx context.Context, page *int, perPage *int) (
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	chatsHandler := chats.NewGetChatsHandler(
-		database.NewChatsAdapter(*database.DatabaseConnection),
-		usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
-		redisdb.NewUserActionsAdapter(redisdb.RedisConnection),
-	)
-
-	var pageValue int
-	var perPageValue int
-	if page != nil && *page > 0 {
-		pageValue = *page
-	} else {
-		pageValue = 1
-	}
-
-	if perPage != nil && *perPage > 0 {
-		perPageValue = *perPage
-	} else {
-		perPageValue = 20
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewUserActionHandler(
+			database.NewChatsAdapter(tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+			usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
+			redisdb.NewUserActionsAdapter(redisdb.RedisConnection),
+		)
+		_, err = chatsHandler.Execute(chatID, tokenSubject.UserId, chats.ActionTypes(actionType.String()))
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
 	}
 
-	chats := chatsHandler.Execute(tokenSubject.UserId, pageValue, perPageValue)
-	chatsResponse := factories.PaginatedChatsToResponse(chats)
-	return &chatsResponse, nil
+	return model.BooleanResult{Result: true}, nil
 }
 
-// GetChat is the resolver for the getChat field.
-func (r *queryResolver) GetChat(ctx context.Context, chatID int) (model.ChatErrorResponse, error) {
+// StopUserAction is the resolver for the stopUserAction field.
+func (r *mutationResolver) StopUserAction(ctx context.Context, chatID int, actionType model.ActionTypes) (model.BooleanResultErrorResponse, error) {
 	token, _ := ctx.Value("token").(*jwt.Token)
 	if err := utils.UserRequired(token); err != nil {
 		return model.ErrorResponse{Message: "Token required"}, nil
@@ -595,23 +732,24 @@ func (r *queryResolver) GetChat(ctx context.Context, chatID int) (model.ChatErro
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	chatsHandler := chats.NewGetChatHandler(
-		database.NewChatsAdapter(*database.DatabaseConnection),
-		usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
-		redisdb.NewUserActionsAdapter(redisdb.RedisConnection),
-	)
-
-	chat, err := chatsHandler.Execute(tokenSubject.UserId, chatID)
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewStopUserActionHandler(
+			database.NewChatsAdapter(tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+			redisdb.NewUserActionsAdapter(redisdb.RedisConnection),
+		)
+		_, err = chatsHandler.Execute(chatID, tokenSubject.UserId, chats.ActionTypes(actionType.String()))
+		return err
+	})
 	if err != nil {
 		return model.ErrorResponse{Message: err.Error()}, nil
 	}
 
-	chatResponse := factories.ChatModelToResponse(*chat)
-	return &chatResponse, nil
+	return model.BooleanResult{Result: true}, nil
 }
 
-// GetLastMessagesForChats is the resolver for the getLastMessagesForChats field.
-func (r *queryResolver) GetLastMessagesForChats(ctx context.Context, chatIds []int) (model.MessagesArrayErrorResponse, error) {
+// AddMembers is the resolver for the addMembers field.
+func (r *mutationResolver) AddMembers(ctx context.Context, chatID int, members []int) (model.ChatErrorResponse, error) {
 	token, _ := ctx.Value("token").(*jwt.Token)
 	if err := utils.UserRequired(token); err != nil {
 		return model.ErrorResponse{Message: "Token required"}, nil
@@ -622,7 +760,1106 @@ func (r *queryResolver) GetLastMessagesForChats(ctx context.Context, chatIds []i
 		return model.ErrorResponse{Message: "Incorrect token"}, nil
 	}
 
-	messagesHandler := messages.NewGetChatsLastMessagesHandler(
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewAddChatsMembersHandler(
+			database.NewChatsAdapter(tx),
+			usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		chat, err = chatsHandler.Execute(chatID, tokenSubject.UserId, members)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatModelToResponse(*chat), nil
+}
+
+// AddAdmins is the resolver for the addAdmins field.
+func (r *mutationResolver) AddAdmins(ctx context.Context, chatID int, admins []int) (model.ChatErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewAddChatsAdminsHandler(
+			database.NewChatsAdapter(tx),
+			usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		chat, err = chatsHandler.Execute(chatID, tokenSubject.UserId, admins)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatModelToResponse(*chat), nil
+}
+
+// RemoveMembers is the resolver for the removeMembers field.
+func (r *mutationResolver) RemoveMembers(ctx context.Context, chatID int, members []int) (model.ChatErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewRemoveChatMembersHandler(
+			database.NewChatsAdapter(tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		chat, err = chatsHandler.Execute(chatID, tokenSubject.UserId, members)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatModelToResponse(*chat), nil
+}
+
+// RemoveAdmins is the resolver for the removeAdmins field.
+func (r *mutationResolver) RemoveAdmins(ctx context.Context, chatID int, admins []int) (model.ChatErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewRemoveChatAdminsHandler(
+			database.NewChatsAdapter(tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		chat, err = chatsHandler.Execute(chatID, tokenSubject.UserId, admins)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatModelToResponse(*chat), nil
+}
+
+// SetAdminRights is the resolver for the setAdminRights field.
+func (r *mutationResolver) SetAdminRights(ctx context.Context, chatID int, adminID int, rights model.AdminRightsRequest) (model.ChatErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewSetAdminRightsHandler(
+			database.NewChatsAdapter(tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		chat, err = chatsHandler.Execute(chatID, tokenSubject.UserId, adminID, factories.AdminRightsRequestToModel(rights))
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatModelToResponse(*chat), nil
+}
+
+// SetMemberPermissions is the resolver for the setMemberPermissions field.
+func (r *mutationResolver) SetMemberPermissions(ctx context.Context, chatID int, permissions model.MemberPermissionsRequest) (model.ChatErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewSetMemberPermissionsHandler(
+			database.NewChatsAdapter(tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		chat, err = chatsHandler.Execute(chatID, tokenSubject.UserId, factories.MemberPermissionsRequestToModel(permissions))
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatModelToResponse(*chat), nil
+}
+
+// QuitChat is the resolver for the quitChat field.
+func (r *mutationResolver) QuitChat(ctx context.Context, chatID int) (model.ChatErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewQuitChatHandler(
+			database.NewChatsAdapter(tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		chat, err = chatsHandler.Execute(chatID, tokenSubject.UserId)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatModelToResponse(*chat), nil
+}
+
+// TransferChatOwnership is the resolver for the transferChatOwnership field.
+func (r *mutationResolver) TransferChatOwnership(ctx context.Context, chatID int, newOwnerID int) (model.ChatErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewTransferChatOwnershipHandler(
+			database.NewChatsAdapter(tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		chat, err = chatsHandler.Execute(chatID, tokenSubject.UserId, newOwnerID)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatModelToResponse(*chat), nil
+}
+
+// ArchiveChat is the resolver for the archiveChat field.
+func (r *mutationResolver) ArchiveChat(ctx context.Context, chatID int) (model.ChatErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewSetChatArchivedHandler(
+			database.NewChatsAdapter(tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		chat, err = chatsHandler.Execute(chatID, tokenSubject.UserId, true)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatModelToResponse(*chat), nil
+}
+
+// UnarchiveChat is the resolver for the unarchiveChat field.
+func (r *mutationResolver) UnarchiveChat(ctx context.Context, chatID int) (model.ChatErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewSetChatArchivedHandler(
+			database.NewChatsAdapter(tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		chat, err = chatsHandler.Execute(chatID, tokenSubject.UserId, false)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatModelToResponse(*chat), nil
+}
+
+// PinChat is the resolver for the pinChat field.
+func (r *mutationResolver) PinChat(ctx context.Context, chatID int) (model.ChatErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewSetChatPinnedHandler(
+			database.NewChatsAdapter(tx),
+		)
+		chat, err = chatsHandler.Execute(chatID, tokenSubject.UserId, true)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatModelToResponse(*chat), nil
+}
+
+// UnpinChat is the resolver for the unpinChat field.
+func (r *mutationResolver) UnpinChat(ctx context.Context, chatID int) (model.ChatErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewSetChatPinnedHandler(
+			database.NewChatsAdapter(tx),
+		)
+		chat, err = chatsHandler.Execute(chatID, tokenSubject.UserId, false)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatModelToResponse(*chat), nil
+}
+
+// MuteChat is the resolver for the muteChat field.
+func (r *mutationResolver) MuteChat(ctx context.Context, chatID int, until *string) (model.ChatErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var mutedUntil *time.Time
+	if until != nil {
+		mutedUntil, err = factories.ParseDatetime(*until)
+		if err != nil {
+			return model.ErrorResponse{Message: err.Error()}, nil
+		}
+	}
+
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewSetChatMutedHandler(
+			database.NewChatsAdapter(tx),
+		)
+		chat, err = chatsHandler.Execute(chatID, tokenSubject.UserId, true, mutedUntil)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatModelToResponse(*chat), nil
+}
+
+// UnmuteChat is the resolver for the unmuteChat field.
+func (r *mutationResolver) UnmuteChat(ctx context.Context, chatID int) (model.ChatErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewSetChatMutedHandler(
+			database.NewChatsAdapter(tx),
+		)
+		chat, err = chatsHandler.Execute(chatID, tokenSubject.UserId, false, nil)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatModelToResponse(*chat), nil
+}
+
+// CreateChatFolder is the resolver for the createChatFolder field.
+func (r *mutationResolver) CreateChatFolder(ctx context.Context, request model.ChatFolderRequest) (model.ChatFolderErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var folder *chats.ChatFolder
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewCreateChatFolderHandler(
+			database.NewChatsAdapter(tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		folder, err = chatsHandler.Execute(tokenSubject.UserId, factories.ChatFolderRequestToModel(request))
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatFolderModelToResponse(*folder), nil
+}
+
+// UpdateChatFolder is the resolver for the updateChatFolder field.
+func (r *mutationResolver) UpdateChatFolder(ctx context.Context, folderID int, request model.ChatFolderRequest) (model.ChatFolderErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var folder *chats.ChatFolder
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewUpdateChatFolderHandler(
+			database.NewChatsAdapter(tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		folder, err = chatsHandler.Execute(folderID, tokenSubject.UserId, factories.ChatFolderRequestToModel(request))
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatFolderModelToResponse(*folder), nil
+}
+
+// DeleteChatFolder is the resolver for the deleteChatFolder field.
+func (r *mutationResolver) DeleteChatFolder(ctx context.Context, folderID int) (model.BooleanResultErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewDeleteChatFolderHandler(
+			database.NewChatsAdapter(tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		return chatsHandler.Execute(folderID, tokenSubject.UserId)
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return model.BooleanResult{Result: true}, nil
+}
+
+// ReorderChatFolders is the resolver for the reorderChatFolders field.
+func (r *mutationResolver) ReorderChatFolders(ctx context.Context, folderIds []int) (model.ChatFoldersArrayErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var folders []chats.ChatFolder
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewReorderChatFoldersHandler(
+			database.NewChatsAdapter(tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		folders, err = chatsHandler.Execute(tokenSubject.UserId, folderIds)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatFoldersToResponse(folders), nil
+}
+
+// SetSlowMode is the resolver for the setSlowMode field.
+func (r *mutationResolver) SetSlowMode(ctx context.Context, chatID int, interval int) (model.ChatErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewSetSlowModeHandler(
+			database.NewChatsAdapter(tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		chat, err = chatsHandler.Execute(chatID, tokenSubject.UserId, time.Duration(interval)*time.Second)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatModelToResponse(*chat), nil
+}
+
+// BanMember is the resolver for the banMember field.
+func (r *mutationResolver) BanMember(ctx context.Context, chatID int, userID int, expiresAt *string) (model.ChatErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var banExpiresAt *time.Time
+	if expiresAt != nil {
+		banExpiresAt, err = factories.ParseDatetime(*expiresAt)
+		if err != nil {
+			return model.ErrorResponse{Message: err.Error()}, nil
+		}
+	}
+
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewBanChatMemberHandler(
+			database.NewChatsAdapter(tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		chat, err = chatsHandler.Execute(chatID, tokenSubject.UserId, userID, banExpiresAt)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatModelToResponse(*chat), nil
+}
+
+// UnbanMember is the resolver for the unbanMember field.
+func (r *mutationResolver) UnbanMember(ctx context.Context, chatID int, userID int) (model.BooleanResultErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewUnbanChatMemberHandler(
+			database.NewChatsAdapter(tx),
+		)
+		return chatsHandler.Execute(chatID, tokenSubject.UserId, userID)
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return model.BooleanResult{Result: true}, nil
+}
+
+// CreateInviteLink is the resolver for the createInviteLink field.
+func (r *mutationResolver) CreateInviteLink(ctx context.Context, chatID int, expiresAt *string, usageLimit *int, requiresApproval *bool) (model.ChatInviteLinkErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	data, err := factories.CreateInviteLinkRequestToModel(expiresAt, usageLimit, requiresApproval)
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	var link *chats.ChatInviteLink
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewCreateInviteLinkHandler(
+			database.NewChatsAdapter(tx),
+		)
+		link, err = chatsHandler.Execute(chatID, tokenSubject.UserId, *data)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatInviteLinkModelToResponse(*link), nil
+}
+
+// RevokeInviteLink is the resolver for the revokeInviteLink field.
+func (r *mutationResolver) RevokeInviteLink(ctx context.Context, code string) (model.ChatInviteLinkErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var link *chats.ChatInviteLink
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewRevokeInviteLinkHandler(
+			database.NewChatsAdapter(tx),
+		)
+		link, err = chatsHandler.Execute(code, tokenSubject.UserId)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatInviteLinkModelToResponse(*link), nil
+}
+
+// JoinChatByInvite is the resolver for the joinChatByInvite field.
+func (r *mutationResolver) JoinChatByInvite(ctx context.Context, code string) (model.ChatErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewJoinChatByInviteHandler(
+			database.NewChatsAdapter(tx),
+			usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+			messages.NewChatSystemMessagesHandler(
+				database.NewMessagesAdapter(tx),
+				rabbit.NewMessageEventsAdapter(ctx, tx),
+			),
+		)
+		chat, err = chatsHandler.Execute(code, tokenSubject.UserId)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatModelToResponse(*chat), nil
+}
+
+// RequestChatJoin is the resolver for the requestChatJoin field.
+func (r *mutationResolver) RequestChatJoin(ctx context.Context, code string) (model.JoinRequestErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var request *chats.ChatJoinRequest
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewRequestChatJoinHandler(
+			database.NewChatsAdapter(tx),
+		)
+		request, err = chatsHandler.Execute(code, tokenSubject.UserId)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.JoinRequestModelToResponse(*request), nil
+}
+
+// ApproveJoinRequest is the resolver for the approveJoinRequest field.
+func (r *mutationResolver) ApproveJoinRequest(ctx context.Context, requestID int) (model.JoinRequestErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var request *chats.ChatJoinRequest
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewResolveJoinRequestHandler(
+			database.NewChatsAdapter(tx),
+			usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+			messages.NewChatSystemMessagesHandler(
+				database.NewMessagesAdapter(tx),
+				rabbit.NewMessageEventsAdapter(ctx, tx),
+			),
+		)
+		request, err = chatsHandler.Execute(requestID, tokenSubject.UserId, true)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.JoinRequestModelToResponse(*request), nil
+}
+
+// DeclineJoinRequest is the resolver for the declineJoinRequest field.
+func (r *mutationResolver) DeclineJoinRequest(ctx context.Context, requestID int) (model.JoinRequestErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var request *chats.ChatJoinRequest
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewResolveJoinRequestHandler(
+			database.NewChatsAdapter(tx),
+			usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+			messages.NewChatSystemMessagesHandler(
+				database.NewMessagesAdapter(tx),
+				rabbit.NewMessageEventsAdapter(ctx, tx),
+			),
+		)
+		request, err = chatsHandler.Execute(requestID, tokenSubject.UserId, false)
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.JoinRequestModelToResponse(*request), nil
+}
+
+// ChangeGroupChat is the resolver for the changeGroupChat field.
+func (r *mutationResolver) ChangeGroupChat(ctx context.Context, chatID int, chatData model.ChangeGroupChatData) (model.ChatErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewChangeGroupChatHandler(
+			database.NewChatsAdapter(tx),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+			messages.NewChatSystemMessagesHandler(
+				database.NewMessagesAdapter(tx),
+				rabbit.NewMessageEventsAdapter(ctx, tx),
+			),
+		)
+		chat, err = chatsHandler.Execute(chatID, tokenSubject.UserId, factories.ChangeGroupChatRequestToModel(chatData))
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatModelToResponse(*chat), nil
+}
+
+// UpdateGroupChatAvatar is the resolver for the updateGroupChatAvatar field.
+func (r *mutationResolver) UpdateGroupChatAvatar(ctx context.Context, chatID int, avatar model.UploadingFile) (model.ChatErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	var chat *chats.Chat
+	err = database.Transaction(func(tx gorm.DB) error {
+		chatsHandler := chats.NewUpdateGroupChatAvatar(
+			database.NewChatsAdapter(tx),
+			filesservice.NewFilesAdapter(),
+			rabbit.NewChatEventsAdapter(ctx, tx),
+		)
+		chat, err = chatsHandler.Execute(chatID, tokenSubject.UserId, factories.UploadingFileToModel(avatar))
+		return err
+	})
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatModelToResponse(*chat), nil
+}
+
+// GetChatMessages is the resolver for the getChatMessages field.
+func (r *queryResolver) GetChatMessages(ctx context.Context, chatID int, offset *int, limit *int) (model.PaginatedMessagesErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	messagesHandler := messages.NewGetChatMessagesHandler(
+		database.NewChatsAdapter(*database.DatabaseConnection),
+		database.NewMessagesAdapter(*database.DatabaseConnection),
+	)
+
+	var offsetValue int
+	if offset != nil && *offset > 0 {
+		offsetValue = *offset
+	} else {
+		offsetValue = 0
+	}
+
+	var limitValue int
+	if limit != nil && *limit > 0 {
+		limitValue = *limit
+	} else {
+		limitValue = 100
+	}
+
+	messages, err := messagesHandler.Execute(chatID, tokenSubject.UserId, offsetValue, limitValue)
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	response := factories.OffsetMessagesToResponse(*messages, chatID)
+	return &response, nil
+}
+
+// GetChatMessagesByCursor is the resolver for the getChatMessagesByCursor field.
+func (r *queryResolver) GetChatMessagesByCursor(ctx context.Context, chatID int, messageID int, aroundOffset *int) (model.PaginatedMessagesErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	messagesHandler := messages.NewGetChatMessagesByCursorHandler(
+		database.NewChatsAdapter(*database.DatabaseConnection),
+		database.NewMessagesAdapter(*database.DatabaseConnection),
+	)
+
+	var aroundOffsetValue int
+	if aroundOffset != nil && *aroundOffset > 0 {
+		aroundOffsetValue = *aroundOffset
+	} else {
+		aroundOffsetValue = 50
+	}
+
+	messages, err := messagesHandler.Execute(chatID, tokenSubject.UserId, messageID, aroundOffsetValue)
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	response := factories.OffsetMessagesToResponse(*messages, chatID)
+	return &response, nil
+}
+
+// GetThreadMessages is the resolver for the getThreadMessages field.
+func (r *queryResolver) GetThreadMessages(ctx context.Context, rootMessageID int, offset *int, limit *int) (model.PaginatedMessagesErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	messagesHandler := messages.NewGetThreadMessagesHandler(
+		database.NewMessagesAdapter(*database.DatabaseConnection),
+	)
+
+	var offsetValue int
+	if offset != nil && *offset > 0 {
+		offsetValue = *offset
+	} else {
+		offsetValue = 0
+	}
+
+	var limitValue int
+	if limit != nil && *limit > 0 {
+		limitValue = *limit
+	} else {
+		limitValue = 100
+	}
+
+	messages, err := messagesHandler.Execute(rootMessageID, tokenSubject.UserId, offsetValue, limitValue)
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	response := factories.OffsetMessagesToResponse(*messages, rootMessageID)
+	return &response, nil
+}
+
+// GetChats is the resolver for the getChats field.
+func (r *queryResolver) GetChats(ctx context.Context, page *int, perPage *int, includeArchived *bool, folderID *int) (model.PaginatedChatsErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	chatsHandler := chats.NewGetChatsHandler(
+		database.NewChatsAdapter(*database.DatabaseConnection),
+		usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
+		redisdb.NewUserActionsAdapter(redisdb.RedisConnection),
+	)
+
+	var pageValue int
+	var perPageValue int
+	if page != nil && *page > 0 {
+		pageValue = *page
+	} else {
+		pageValue = 1
+	}
+
+	if perPage != nil && *perPage > 0 {
+		perPageValue = *perPage
+	} else {
+		perPageValue = 20
+	}
+
+	chats, err := chatsHandler.Execute(tokenSubject.UserId, includeArchived != nil && *includeArchived, folderID, pageValue, perPageValue)
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	chatsResponse := factories.PaginatedChatsToResponse(*chats)
+	return &chatsResponse, nil
+}
+
+// GetChatFolders is the resolver for the getChatFolders field.
+func (r *queryResolver) GetChatFolders(ctx context.Context) (model.ChatFoldersArrayErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	chatsHandler := chats.NewGetChatFoldersHandler(
+		database.NewChatsAdapter(*database.DatabaseConnection),
+	)
+
+	folders := chatsHandler.Execute(tokenSubject.UserId)
+	return factories.ChatFoldersToResponse(folders), nil
+}
+
+// GetArchivedChats is the resolver for the getArchivedChats field.
+func (r *queryResolver) GetArchivedChats(ctx context.Context, page *int, perPage *int) (model.PaginatedChatsErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	chatsHandler := chats.NewGetArchivedChatsHandler(
+		database.NewChatsAdapter(*database.DatabaseConnection),
+		usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
+		redisdb.NewUserActionsAdapter(redisdb.RedisConnection),
+	)
+
+	var pageValue int
+	var perPageValue int
+	if page != nil && *page > 0 {
+		pageValue = *page
+	} else {
+		pageValue = 1
+	}
+
+	if perPage != nil && *perPage > 0 {
+		perPageValue = *perPage
+	} else {
+		perPageValue = 20
+	}
+
+	chats := chatsHandler.Execute(tokenSubject.UserId, pageValue, perPageValue)
+	chatsResponse := factories.PaginatedChatsToResponse(chats)
+	return &chatsResponse, nil
+}
+
+// GetChat is the resolver for the getChat field.
+func (r *queryResolver) GetChat(ctx context.Context, chatID int) (model.ChatErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	chatsHandler := chats.NewGetChatHandler(
+		database.NewChatsAdapter(*database.DatabaseConnection),
+		usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
+		redisdb.NewUserActionsAdapter(redisdb.RedisConnection),
+	)
+
+	chat, err := chatsHandler.Execute(tokenSubject.UserId, chatID)
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	chatResponse := factories.ChatModelToResponse(*chat)
+	return &chatResponse, nil
+}
+
+// GetChannelBySlug is the resolver for the getChannelBySlug field.
+func (r *queryResolver) GetChannelBySlug(ctx context.Context, slug string) (model.ChatErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	chatsHandler := chats.NewGetPublicChannelHandler(
+		database.NewChatsAdapter(*database.DatabaseConnection),
+	)
+
+	chat, err := chatsHandler.Execute(slug)
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	return factories.ChatModelToResponse(*chat), nil
+}
+
+// GetLastMessagesForChats is the resolver for the getLastMessagesForChats field.
+func (r *queryResolver) GetLastMessagesForChats(ctx context.Context, chatIds []int) (model.MessagesArrayErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	messagesHandler := messages.NewGetChatsLastMessagesHandler(
 		database.NewChatsAdapter(*database.DatabaseConnection),
 		database.NewMessagesAdapter(*database.DatabaseConnection),
 	)
@@ -676,11 +1913,363 @@ func (r *queryResolver) SearchChats(ctx context.Context, query string, page *int
 	return model.PaginatedChats{Page: chats.GetPage(), NumPages: chats.GetPagesCount(), PerPage: chats.GetPerPage(), Total: chats.GetTotal(), Data: response}, nil
 }
 
+// GetTotalUnread is the resolver for the getTotalUnread field.
+func (r *queryResolver) GetTotalUnread(ctx context.Context) (model.TotalUnreadErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	chatsHandler := chats.NewGetTotalUnreadHandler(
+		database.NewChatsAdapter(*database.DatabaseConnection),
+	)
+
+	counters := chatsHandler.Execute(tokenSubject.UserId)
+	response := factories.ChatUnreadCountersToTotalUnreadResponse(counters)
+	return &response, nil
+}
+
+// SearchMessages is the resolver for the searchMessages field.
+func (r *queryResolver) SearchMessages(ctx context.Context, query string, chatID *int, senderID *int, typeArg *model.MessageType, from *string, to *string, offset *int, limit *int) (model.PaginatedMessageSearchResultsErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	filter, err := factories.SearchMessagesRequestToModel(query, chatID, senderID, typeArg, from, to)
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	var offsetValue int
+	if offset != nil && *offset > 0 {
+		offsetValue = *offset
+	} else {
+		offsetValue = 0
+	}
+
+	var limitValue int
+	if limit != nil && *limit > 0 && *limit <= 100 {
+		limitValue = *limit
+	} else {
+		limitValue = 100
+	}
+
+	messagesHandler := messages.NewSearchMessagesHandler(
+		database.NewMessagesAdapter(*database.DatabaseConnection),
+	)
+
+	results, err := messagesHandler.Execute(*filter, tokenSubject.UserId, offsetValue, limitValue)
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	response := factories.OffsetMessageSearchResultsToResponse(*results)
+	return &response, nil
+}
+
+// GetPinnedMessages is the resolver for the getPinnedMessages field.
+func (r *queryResolver) GetPinnedMessages(ctx context.Context, chatID int) (model.MessagesArrayErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	messagesHandler := messages.NewGetPinnedMessagesHandler(
+		database.NewChatsAdapter(*database.DatabaseConnection),
+		database.NewMessagesAdapter(*database.DatabaseConnection),
+	)
+
+	messages, err := messagesHandler.Execute(chatID, tokenSubject.UserId)
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	var response []*model.Message
+	for _, message := range messages {
+		messageResponse := factories.MessageModelToResponse(message)
+		response = append(response, &messageResponse)
+	}
+	return model.MessagesArray{Messages: response}, nil
+}
+
+// GetScheduledMessages is the resolver for the getScheduledMessages field.
+func (r *queryResolver) GetScheduledMessages(ctx context.Context, chatID int) (model.MessagesArrayErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	messagesHandler := messages.NewGetScheduledMessagesHandler(
+		database.NewChatsAdapter(*database.DatabaseConnection),
+		database.NewMessagesAdapter(*database.DatabaseConnection),
+	)
+
+	messages, err := messagesHandler.Execute(chatID, tokenSubject.UserId)
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	var response []*model.Message
+	for _, message := range messages {
+		messageResponse := factories.MessageModelToResponse(message)
+		response = append(response, &messageResponse)
+	}
+	return model.MessagesArray{Messages: response}, nil
+}
+
+// GetMessageHistory is the resolver for the getMessageHistory field.
+func (r *queryResolver) GetMessageHistory(ctx context.Context, messageID int) (model.MessageRevisionsArrayErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	messagesHandler := messages.NewGetMessageHistoryHandler(
+		database.NewMessagesAdapter(*database.DatabaseConnection),
+	)
+
+	revisions, err := messagesHandler.Execute(messageID, tokenSubject.UserId)
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	var response []*model.MessageRevision
+	for _, revision := range revisions {
+		revisionResponse := factories.MessageRevisionModelToResponse(revision)
+		response = append(response, &revisionResponse)
+	}
+	return model.MessageRevisionsArray{Revisions: response}, nil
+}
+
+// GetJoinRequests is the resolver for the getJoinRequests field.
+func (r *queryResolver) GetJoinRequests(ctx context.Context, chatID int) (model.JoinRequestsArrayErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	chatsHandler := chats.NewGetJoinRequestsHandler(
+		database.NewChatsAdapter(*database.DatabaseConnection),
+	)
+
+	requests, err := chatsHandler.Execute(chatID, tokenSubject.UserId)
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	var response []*model.JoinRequest
+	for _, request := range requests {
+		requestResponse := factories.JoinRequestModelToResponse(request)
+		response = append(response, &requestResponse)
+	}
+	return model.JoinRequestsArray{Requests: response}, nil
+}
+
+// GetBannedMembers is the resolver for the getBannedMembers field.
+func (r *queryResolver) GetBannedMembers(ctx context.Context, chatID int) (model.ChatBansArrayErrorResponse, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return model.ErrorResponse{Message: "Token required"}, nil
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return model.ErrorResponse{Message: "Incorrect token"}, nil
+	}
+
+	chatsHandler := chats.NewGetBannedMembersHandler(
+		database.NewChatsAdapter(*database.DatabaseConnection),
+	)
+
+	bans, err := chatsHandler.Execute(chatID, tokenSubject.UserId)
+	if err != nil {
+		return model.ErrorResponse{Message: err.Error()}, nil
+	}
+
+	var response []*model.ChatBan
+	for _, ban := range bans {
+		banResponse := factories.ChatBanModelToResponse(ban)
+		response = append(response, &banResponse)
+	}
+	return model.ChatBansArray{Bans: response}, nil
+}
+
+// MessageCreated is the resolver for the messageCreated field.
+func (r *subscriptionResolver) MessageCreated(ctx context.Context, chatID int) (<-chan *model.Message, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return nil, err
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return nil, middlewares.ErrIncorrectToken
+	}
+
+	chatsHandler := chats.NewGetChatHandler(
+		database.NewChatsAdapter(*database.DatabaseConnection),
+		usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
+		redisdb.NewUserActionsAdapter(redisdb.RedisConnection),
+	)
+
+	if _, err := chatsHandler.Execute(tokenSubject.UserId, chatID); err != nil {
+		return nil, err
+	}
+
+	events, unsubscribe := broker.EventsBroker.Subscribe(ctx, broker.ChatMessagesTopic(chatID))
+	messagesChannel := make(chan *model.Message)
+	go func() {
+		defer unsubscribe()
+		defer close(messagesChannel)
+		for event := range events {
+			message, ok := event.(messages.Message)
+			if !ok {
+				continue
+			}
+
+			if chat := message.GetChat(); !chats.ValidateUserChatMember(chat, tokenSubject.UserId) {
+				return
+			}
+
+			messageResponse := factories.MessageModelToResponse(message)
+			select {
+			case messagesChannel <- &messageResponse:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return messagesChannel, nil
+}
+
+// ChatUpdated is the resolver for the chatUpdated field.
+func (r *subscriptionResolver) ChatUpdated(ctx context.Context) (<-chan *model.Chat, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return nil, err
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return nil, middlewares.ErrIncorrectToken
+	}
+
+	events, unsubscribe := broker.EventsBroker.Subscribe(ctx, broker.UserChatsTopic(tokenSubject.UserId))
+	chatsChannel := make(chan *model.Chat)
+	go func() {
+		defer unsubscribe()
+		defer close(chatsChannel)
+		for event := range events {
+			chat, ok := event.(chats.Chat)
+			if !ok {
+				continue
+			}
+
+			chatResponse := factories.ChatModelToResponse(chat)
+			select {
+			case chatsChannel <- &chatResponse:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return chatsChannel, nil
+}
+
+// UserActions is the resolver for the userActions field.
+func (r *subscriptionResolver) UserActions(ctx context.Context, chatID int) (<-chan *model.ChatActions, error) {
+	token, _ := ctx.Value("token").(*jwt.Token)
+	if err := utils.UserRequired(token); err != nil {
+		return nil, err
+	}
+
+	tokenSubject, err := middlewares.GetTokenSubject(token)
+	if err != nil {
+		return nil, middlewares.ErrIncorrectToken
+	}
+
+	chatsHandler := chats.NewGetChatHandler(
+		database.NewChatsAdapter(*database.DatabaseConnection),
+		usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
+		redisdb.NewUserActionsAdapter(redisdb.RedisConnection),
+	)
+
+	if _, err := chatsHandler.Execute(tokenSubject.UserId, chatID); err != nil {
+		return nil, err
+	}
+
+	events, unsubscribe := broker.EventsBroker.Subscribe(ctx, broker.ChatActionsTopic(chatID))
+	actionsChannel := make(chan *model.ChatActions)
+	go func() {
+		defer unsubscribe()
+		defer close(actionsChannel)
+		for event := range events {
+			chat, ok := event.(chats.Chat)
+			if !ok {
+				continue
+			}
+
+			if !chats.ValidateUserChatMember(chat, tokenSubject.UserId) {
+				return
+			}
+
+			actionsResponse := model.ChatActions{
+				ChatID:  chat.GetId(),
+				Actions: factories.ChatActionsModelToResponse(chat),
+			}
+			select {
+			case actionsChannel <- &actionsResponse:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return actionsChannel, nil
+}
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
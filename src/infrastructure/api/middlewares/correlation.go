@@ -0,0 +1,22 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/chack-check/chats-service/infrastructure/requestcontext"
+)
+
+// CorrelationMiddleware reuses the caller's correlation id or starts a new one
+// and echoes it back, so events produced by the request can be traced to it.
+func CorrelationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		correlationId := r.Header.Get(requestcontext.CorrelationIdHeader)
+		if correlationId == "" {
+			correlationId = requestcontext.NewCorrelationId()
+		}
+
+		w.Header().Set(requestcontext.CorrelationIdHeader, correlationId)
+		ctx := requestcontext.WithCorrelationId(r.Context(), correlationId)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -9,7 +9,13 @@ import (
 func CorsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", settings.Settings.APP_ALLOW_ORIGINS)
-		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, Accept")
+		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, Accept, X-Correlation-Id")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Correlation-Id")
 		next.ServeHTTP(w, r)
 	})
 }
+
+func CheckWebsocketOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	return origin == "" || settings.Settings.APP_ALLOW_ORIGINS == "*" || origin == settings.Settings.APP_ALLOW_ORIGINS
+}
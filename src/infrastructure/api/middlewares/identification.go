@@ -3,15 +3,20 @@ package middlewares
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"strings"
 
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/chack-check/chats-service/infrastructure/api/settings"
+	"github.com/chack-check/chats-service/infrastructure/requestcontext"
 	"github.com/getsentry/sentry-go"
 	"github.com/golang-jwt/jwt/v5"
 )
 
+var ErrIncorrectToken = fmt.Errorf("incorrect token")
+
 type TokenSubject struct {
 	UserId   int    `json:"user_id"`
 	Username string `json:"username"`
@@ -27,6 +32,12 @@ func GetTokenFromString(tokenString string) (*jwt.Token, error) {
 	return token, err
 }
 
+func GetTokenFromAuthorization(authorization string) (*jwt.Token, error) {
+	tokenString := strings.Replace(authorization, "Bearer ", "", 1)
+	log.Printf("Parsing token: %s", tokenString)
+	return GetTokenFromString(tokenString)
+}
+
 func UserMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authorization := r.Header["Authorization"]
@@ -34,12 +45,13 @@ func UserMiddleware(next http.Handler) http.Handler {
 		ctx := r.Context()
 
 		if len(authorization) != 0 {
-			tokenString := strings.Replace(r.Header["Authorization"][0], "Bearer ", "", 1)
-			log.Printf("Parsing token: %s", tokenString)
-			token, err := GetTokenFromString(tokenString)
+			token, err := GetTokenFromAuthorization(authorization[0])
 			if err == nil && token.Valid {
 				log.Printf("Successfully parsd token: %v", token)
 				ctx = context.WithValue(r.Context(), "token", token)
+				if tokenSubject, err := GetTokenSubject(token); err == nil {
+					ctx = requestcontext.WithActorId(ctx, tokenSubject.UserId)
+				}
 				next.ServeHTTP(w, r.WithContext(ctx))
 				return
 			}
@@ -53,6 +65,27 @@ func UserMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// Browsers can't set headers on websocket connections, so subscription
+// clients may pass the token in the connection_init payload instead.
+func UserWebsocketInit(ctx context.Context, initPayload transport.InitPayload) (context.Context, error) {
+	authorization := initPayload.Authorization()
+	if authorization == "" {
+		return ctx, nil
+	}
+
+	token, err := GetTokenFromAuthorization(authorization)
+	if err != nil || !token.Valid {
+		log.Printf("Error validating websocket token: %v", err)
+		return ctx, ErrIncorrectToken
+	}
+
+	ctx = context.WithValue(ctx, "token", token)
+	if tokenSubject, err := GetTokenSubject(token); err == nil {
+		ctx = requestcontext.WithActorId(ctx, tokenSubject.UserId)
+	}
+	return ctx, nil
+}
+
 func GetTokenSubject(token *jwt.Token) (TokenSubject, error) {
 	tokenSubject := TokenSubject{}
 
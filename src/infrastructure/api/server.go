@@ -4,30 +4,50 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/chack-check/chats-service/infrastructure/api/graph"
 	"github.com/chack-check/chats-service/infrastructure/api/middlewares"
 	"github.com/chack-check/chats-service/infrastructure/api/settings"
-	"github.com/chack-check/chats-service/infrastructure/database"
 	"github.com/chack-check/chats-service/infrastructure/rabbit"
 	"github.com/chack-check/chats-service/infrastructure/redisdb"
+	"github.com/chack-check/chats-service/infrastructure/scheduler"
 	"github.com/go-chi/chi"
+	"github.com/gorilla/websocket"
 )
 
 func RunApi() {
 	defer rabbit.EventsRabbitConnection.Close()
 	defer redisdb.RedisConnection.Close()
 
-	database.DatabaseConnection.AutoMigrate(&database.Chat{}, &database.Message{}, &database.SavedFile{}, database.Reaction{})
+	scheduler.RestoreScheduledMessages()
 
 	router := chi.NewRouter()
 
+	router.Use(middlewares.CorrelationMiddleware)
 	router.Use(middlewares.UserMiddleware)
 	router.Use(middlewares.CorsMiddleware)
 
-	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}))
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}))
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		InitFunc:              middlewares.UserWebsocketInit,
+		Upgrader: websocket.Upgrader{
+			CheckOrigin: middlewares.CheckWebsocketOrigin,
+		},
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+	srv.SetQueryCache(lru.New(1000))
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{Cache: lru.New(100)})
 
 	router.Handle("/api/v1/chats", playground.Handler("GraphQL playground", "/api/v1/chats/query"))
 	router.Handle("/api/v1/chats/query", srv)
@@ -10,6 +10,8 @@ type SettingsSchema struct {
 	APP_PORT          int
 	APP_SECRET_KEY    string
 	APP_ALLOW_ORIGINS string
+
+	APP_DELETE_FOR_EVERYONE_WINDOW_SECONDS int
 }
 
 func InitSettings() SettingsSchema {
@@ -32,10 +34,21 @@ func InitSettings() SettingsSchema {
 		allowOrigins = "*"
 	}
 
+	deleteForEveryoneWindow := os.Getenv("APP_DELETE_FOR_EVERYONE_WINDOW_SECONDS")
+	if deleteForEveryoneWindow == "" {
+		deleteForEveryoneWindow = "0"
+	}
+	deleteForEveryoneWindowInt, err := strconv.Atoi(deleteForEveryoneWindow)
+	if err != nil {
+		panic(fmt.Errorf("error parsing delete for everyone window. Please specify the correct number of seconds"))
+	}
+
 	return SettingsSchema{
 		APP_PORT:          portInt,
 		APP_SECRET_KEY:    secretKey,
 		APP_ALLOW_ORIGINS: allowOrigins,
+
+		APP_DELETE_FOR_EVERYONE_WINDOW_SECONDS: deleteForEveryoneWindowInt,
 	}
 }
 
@@ -0,0 +1,186 @@
+package broker
+
+import (
+	"github.com/chack-check/chats-service/domain/chats"
+	"github.com/chack-check/chats-service/domain/messages"
+)
+
+type ChatEventsAdapter struct {
+	broker      *Broker
+	afterCommit func(publish func())
+	adapter     chats.ChatEventsPort
+}
+
+func (adapter ChatEventsAdapter) publishChatUpdated(chat chats.Chat) {
+	adapter.publishChatToUsers(chat, chat.GetMembers())
+}
+
+// publishChatToUsers delivers the chat to the users' chat topics. Users that
+// left the chat or whose chat was deleted get a chat they aren't a member of,
+// so subscribers can drop it from their lists.
+func (adapter ChatEventsAdapter) publishChatToUsers(chat chats.Chat, userIds []int) {
+	adapter.afterCommit(func() {
+		for _, userId := range userIds {
+			adapter.broker.Publish(UserChatsTopic(userId), chat)
+		}
+	})
+}
+
+func (adapter ChatEventsAdapter) SendChatCreated(chat chats.Chat) error {
+	if err := adapter.adapter.SendChatCreated(chat); err != nil {
+		return err
+	}
+
+	adapter.publishChatUpdated(chat)
+	return nil
+}
+
+func (adapter ChatEventsAdapter) SendChatDeleted(chat chats.Chat) error {
+	if err := adapter.adapter.SendChatDeleted(chat); err != nil {
+		return err
+	}
+
+	deletedChat := chat
+	deletedChat.SetMembers([]int{})
+	adapter.publishChatToUsers(deletedChat, chat.GetMembers())
+	return nil
+}
+
+func (adapter ChatEventsAdapter) SendChatUserAction(chat chats.Chat) error {
+	if err := adapter.adapter.SendChatUserAction(chat); err != nil {
+		return err
+	}
+
+	adapter.afterCommit(func() {
+		adapter.broker.Publish(ChatActionsTopic(chat.GetId()), chat)
+	})
+	return nil
+}
+
+func (adapter ChatEventsAdapter) SendChatChanged(chat chats.Chat) error {
+	if err := adapter.adapter.SendChatChanged(chat); err != nil {
+		return err
+	}
+
+	adapter.publishChatUpdated(chat)
+	return nil
+}
+
+func (adapter ChatEventsAdapter) SendChatChangedForUser(chat chats.Chat, userId int) error {
+	if err := adapter.adapter.SendChatChangedForUser(chat, userId); err != nil {
+		return err
+	}
+
+	adapter.afterCommit(func() {
+		adapter.broker.Publish(UserChatsTopic(userId), chat)
+	})
+	return nil
+}
+
+func (adapter ChatEventsAdapter) SendChatArchiveChanged(chat chats.Chat, userId int) error {
+	if err := adapter.adapter.SendChatArchiveChanged(chat, userId); err != nil {
+		return err
+	}
+
+	adapter.afterCommit(func() {
+		adapter.broker.Publish(UserChatsTopic(userId), chat)
+	})
+	return nil
+}
+
+func (adapter ChatEventsAdapter) SendChatMembersRemoved(chat chats.Chat, userIds []int) error {
+	if err := adapter.adapter.SendChatMembersRemoved(chat, userIds); err != nil {
+		return err
+	}
+
+	adapter.publishChatToUsers(chat, userIds)
+	return nil
+}
+
+func (adapter ChatEventsAdapter) SendJoinRequestResolved(request chats.ChatJoinRequest) error {
+	return adapter.adapter.SendJoinRequestResolved(request)
+}
+
+func (adapter ChatEventsAdapter) SendFoldersChanged(userId int, folders []chats.ChatFolder) error {
+	return adapter.adapter.SendFoldersChanged(userId, folders)
+}
+
+type MessageEventsAdapter struct {
+	broker      *Broker
+	afterCommit func(publish func())
+	adapter     messages.MessageEventsPort
+}
+
+func (adapter MessageEventsAdapter) SendMessageReacted(message messages.Message) error {
+	return adapter.adapter.SendMessageReacted(message)
+}
+
+func (adapter MessageEventsAdapter) SendReactionDeleted(message messages.Message) error {
+	return adapter.adapter.SendReactionDeleted(message)
+}
+
+func (adapter MessageEventsAdapter) SendMessageReaded(message messages.Message) error {
+	return adapter.adapter.SendMessageReaded(message)
+}
+
+func (adapter MessageEventsAdapter) SendMessageDeleted(message messages.Message) error {
+	return adapter.adapter.SendMessageDeleted(message)
+}
+
+func (adapter MessageEventsAdapter) SendMessageDeletedForUser(message messages.Message, userId int) error {
+	return adapter.adapter.SendMessageDeletedForUser(message, userId)
+}
+
+func (adapter MessageEventsAdapter) SendMessageUpdated(message messages.Message) error {
+	return adapter.adapter.SendMessageUpdated(message)
+}
+
+func (adapter MessageEventsAdapter) SendMessageCreated(message messages.Message) error {
+	if err := adapter.adapter.SendMessageCreated(message); err != nil {
+		return err
+	}
+
+	chat := message.GetChat()
+	adapter.afterCommit(func() {
+		adapter.broker.Publish(ChatMessagesTopic(chat.GetId()), message)
+	})
+	return nil
+}
+
+func (adapter MessageEventsAdapter) SendThreadUpdated(message messages.Message) error {
+	return adapter.adapter.SendThreadUpdated(message)
+}
+
+func (adapter MessageEventsAdapter) SendMessagePinned(message messages.Message) error {
+	return adapter.adapter.SendMessagePinned(message)
+}
+
+func (adapter MessageEventsAdapter) SendMessageUnpinned(message messages.Message) error {
+	return adapter.adapter.SendMessageUnpinned(message)
+}
+
+func (adapter MessageEventsAdapter) SendPollUpdated(message messages.Message) error {
+	return adapter.adapter.SendPollUpdated(message)
+}
+
+func (adapter MessageEventsAdapter) SendChatRead(chat chats.Chat, pointer messages.ChatReadPointer) error {
+	return adapter.adapter.SendChatRead(chat, pointer)
+}
+
+func (adapter MessageEventsAdapter) SendScheduledMessageDropped(message messages.Message) error {
+	return adapter.adapter.SendScheduledMessageDropped(message)
+}
+
+// NewChatEventsAdapter publishes chat events to in-process subscribers and
+// forwards them to the wrapped adapter. Subscribers are notified through
+// afterCommit, so they never see changes of a rolled back transaction.
+func NewChatEventsAdapter(broker *Broker, afterCommit func(publish func()), adapter chats.ChatEventsPort) chats.ChatEventsPort {
+	return ChatEventsAdapter{broker: broker, afterCommit: afterCommit, adapter: adapter}
+}
+
+// NewMessageEventsAdapter publishes message events to in-process subscribers
+// and forwards them to the wrapped adapter. Subscribers are notified through
+// afterCommit, so they never see changes of a rolled back transaction.
+func NewMessageEventsAdapter(broker *Broker, afterCommit func(publish func()), adapter messages.MessageEventsPort) messages.MessageEventsPort {
+	return MessageEventsAdapter{broker: broker, afterCommit: afterCommit, adapter: adapter}
+}
@@ -0,0 +1,85 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+)
+
+const subscriberBufferSize = 16
+
+// Broker delivers events to in-process subscribers. Publishing never blocks:
+// events for subscribers that don't keep up are dropped.
+type Broker struct {
+	mutex       sync.RWMutex
+	lastId      int
+	subscribers map[string]map[int]chan interface{}
+}
+
+// Subscribe registers a subscriber for the topic until ctx is done or the
+// returned unsubscribe function is called, the events channel is closed then.
+func (broker *Broker) Subscribe(ctx context.Context, topic string) (<-chan interface{}, func()) {
+	ctx, unsubscribe := context.WithCancel(ctx)
+	events := make(chan interface{}, subscriberBufferSize)
+
+	broker.mutex.Lock()
+	broker.lastId++
+	subscriberId := broker.lastId
+	if broker.subscribers[topic] == nil {
+		broker.subscribers[topic] = make(map[int]chan interface{})
+	}
+	broker.subscribers[topic][subscriberId] = events
+	broker.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		broker.unsubscribe(topic, subscriberId)
+	}()
+
+	return events, unsubscribe
+}
+
+func (broker *Broker) unsubscribe(topic string, subscriberId int) {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	if events, ok := broker.subscribers[topic][subscriberId]; ok {
+		close(events)
+		delete(broker.subscribers[topic], subscriberId)
+	}
+	if len(broker.subscribers[topic]) == 0 {
+		delete(broker.subscribers, topic)
+	}
+}
+
+func (broker *Broker) Publish(topic string, event interface{}) {
+	broker.mutex.RLock()
+	defer broker.mutex.RUnlock()
+
+	for subscriberId, events := range broker.subscribers[topic] {
+		select {
+		case events <- event:
+		default:
+			log.Printf("dropping event for slow subscriber: topic=%s, subscriberId=%d", topic, subscriberId)
+		}
+	}
+}
+
+func ChatMessagesTopic(chatId int) string {
+	return fmt.Sprintf("chat:%d:messages", chatId)
+}
+
+func ChatActionsTopic(chatId int) string {
+	return fmt.Sprintf("chat:%d:actions", chatId)
+}
+
+func UserChatsTopic(userId int) string {
+	return fmt.Sprintf("user:%d:chats", userId)
+}
+
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[string]map[int]chan interface{})}
+}
+
+var EventsBroker *Broker = NewBroker()
@@ -0,0 +1,212 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/chack-check/chats-service/domain/chats"
+	"github.com/chack-check/chats-service/domain/messages"
+)
+
+func waitClosed(t *testing.T, events <-chan interface{}) {
+	t.Helper()
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("subscription wasn't closed")
+		}
+	}
+}
+
+func TestBrokerSubscription(t *testing.T) {
+	tests := []struct {
+		name string
+		end  func(cancel context.CancelFunc, unsubscribe func())
+	}{
+		{name: "done context ends the subscription", end: func(cancel context.CancelFunc, unsubscribe func()) { cancel() }},
+		{name: "unsubscribe ends the subscription", end: func(cancel context.CancelFunc, unsubscribe func()) { unsubscribe() }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			broker := NewBroker()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			events, unsubscribe := broker.Subscribe(ctx, UserChatsTopic(1))
+			otherEvents, _ := broker.Subscribe(context.Background(), UserChatsTopic(2))
+
+			broker.Publish(UserChatsTopic(1), "event")
+			if event := <-events; event != "event" {
+				t.Fatalf("received %v, expected the published event", event)
+			}
+			select {
+			case event := <-otherEvents:
+				t.Fatalf("another topic received %v", event)
+			default:
+			}
+
+			test.end(cancel, unsubscribe)
+			waitClosed(t, events)
+			broker.mutex.RLock()
+			defer broker.mutex.RUnlock()
+			if _, ok := broker.subscribers[UserChatsTopic(1)]; ok {
+				t.Errorf("subscribers = %v, expected the topic to be removed", broker.subscribers)
+			}
+		})
+	}
+}
+
+func TestBrokerDropsEventsForSlowSubscribers(t *testing.T) {
+	broker := NewBroker()
+	events, _ := broker.Subscribe(context.Background(), UserChatsTopic(1))
+
+	for i := 0; i < subscriberBufferSize+1; i++ {
+		broker.Publish(UserChatsTopic(1), i)
+	}
+
+	if len(events) != subscriberBufferSize {
+		t.Errorf("buffered events = %d, expected %d", len(events), subscriberBufferSize)
+	}
+	if event := <-events; event != 0 {
+		t.Errorf("first event = %v, expected the oldest one to be kept", event)
+	}
+}
+
+// failingChatEventsAdapter fails chat changes the way an unavailable outbox
+// does.
+type failingChatEventsAdapter struct {
+	*chats.TestChatEventsAdapter
+	err error
+}
+
+func (adapter failingChatEventsAdapter) SendChatChanged(chat chats.Chat) error {
+	if adapter.err != nil {
+		return adapter.err
+	}
+
+	return adapter.TestChatEventsAdapter.SendChatChanged(chat)
+}
+
+func TestChatEventsAdapterPublishesAfterCommit(t *testing.T) {
+	tests := []struct {
+		name            string
+		send            func(adapter chats.ChatEventsPort, chat chats.Chat) error
+		sendErr         error
+		expectedUsers   []int
+		expectedMembers []int
+	}{
+		{
+			name:            "committed change goes to members",
+			send:            func(adapter chats.ChatEventsPort, chat chats.Chat) error { return adapter.SendChatChanged(chat) },
+			expectedUsers:   []int{1, 2},
+			expectedMembers: []int{1, 2},
+		},
+		{
+			name: "change for a user goes only to this user",
+			send: func(adapter chats.ChatEventsPort, chat chats.Chat) error {
+				return adapter.SendChatChangedForUser(chat, 2)
+			},
+			expectedUsers: []int{2},
+		},
+		{
+			name: "archive change goes only to the archiving user",
+			send: func(adapter chats.ChatEventsPort, chat chats.Chat) error {
+				return adapter.SendChatArchiveChanged(chat, 1)
+			},
+			expectedUsers: []int{1},
+		},
+		{
+			name: "removed members get the chat without them",
+			send: func(adapter chats.ChatEventsPort, chat chats.Chat) error {
+				return adapter.SendChatMembersRemoved(chat, []int{3})
+			},
+			expectedUsers:   []int{3},
+			expectedMembers: []int{1, 2},
+		},
+		{
+			name:            "deleted chat goes to former members without members",
+			send:            func(adapter chats.ChatEventsPort, chat chats.Chat) error { return adapter.SendChatDeleted(chat) },
+			expectedUsers:   []int{1, 2},
+			expectedMembers: []int{},
+		},
+		{
+			name:    "failed event isn't published",
+			send:    func(adapter chats.ChatEventsPort, chat chats.Chat) error { return adapter.SendChatChanged(chat) },
+			sendErr: errors.New("outbox is down"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			broker := NewBroker()
+			subscriptions := make(map[int]<-chan interface{})
+			for _, userId := range []int{1, 2, 3} {
+				subscriptions[userId], _ = broker.Subscribe(context.Background(), UserChatsTopic(userId))
+			}
+			var pending []func()
+			afterCommit := func(publish func()) { pending = append(pending, publish) }
+			adapter := NewChatEventsAdapter(broker, afterCommit, failingChatEventsAdapter{&chats.TestChatEventsAdapter{}, test.sendErr})
+
+			err := test.send(adapter, chats.NewChat(10, nil, "group chat", chats.GroupChatType, []int{1, 2}, false, 1, []int{1}))
+			if !errors.Is(err, test.sendErr) {
+				t.Fatalf("send error = %v, expected %v", err, test.sendErr)
+			}
+			for _, userId := range []int{1, 2, 3} {
+				if len(subscriptions[userId]) != 0 {
+					t.Fatalf("user %d received the event before the commit", userId)
+				}
+			}
+
+			for _, publish := range pending {
+				publish()
+			}
+			var receivedUsers []int
+			for _, userId := range []int{1, 2, 3} {
+				select {
+				case event := <-subscriptions[userId]:
+					receivedUsers = append(receivedUsers, userId)
+					publishedChat := event.(chats.Chat)
+					if test.expectedMembers != nil && !slices.Equal(publishedChat.GetMembers(), test.expectedMembers) {
+						t.Errorf("published members = %v, expected %v", publishedChat.GetMembers(), test.expectedMembers)
+					}
+				default:
+				}
+			}
+			if !slices.Equal(receivedUsers, test.expectedUsers) {
+				t.Errorf("received by %v, expected %v", receivedUsers, test.expectedUsers)
+			}
+		})
+	}
+}
+
+func TestMessageEventsAdapterPublishesToChat(t *testing.T) {
+	broker := NewBroker()
+	chatEvents, _ := broker.Subscribe(context.Background(), ChatMessagesTopic(10))
+	otherChatEvents, _ := broker.Subscribe(context.Background(), ChatMessagesTopic(11))
+	adapter := NewMessageEventsAdapter(broker, func(publish func()) { publish() }, &messages.TestMessageEventsAdapter{})
+
+	chat := chats.NewChat(10, nil, "group chat", chats.GroupChatType, []int{1, 2}, false, 1, []int{1})
+	content := "message"
+	message := messages.NewMessage(1, 1, chat, messages.TextMessageType, &content, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	adapter.SendMessageCreated(message)
+
+	select {
+	case event := <-chatEvents:
+		if publishedMessage := event.(messages.Message); publishedMessage.GetId() != 1 {
+			t.Errorf("published message = %d, expected 1", publishedMessage.GetId())
+		}
+	default:
+		t.Errorf("chat subscriber didn't receive the message")
+	}
+	if len(otherChatEvents) != 0 {
+		t.Errorf("another chat subscriber received the message")
+	}
+}
@@ -2,15 +2,21 @@ package database
 
 import (
 	"fmt"
+	"html"
 	"log"
 	"math"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/chack-check/chats-service/domain/chats"
 	"github.com/chack-check/chats-service/domain/files"
 	"github.com/chack-check/chats-service/domain/messages"
 	"github.com/chack-check/chats-service/domain/utils"
+	"github.com/chack-check/chats-service/infrastructure/database/scopes"
 	"github.com/lib/pq"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func GetOrCreateFile(file *files.SavedFile, db gorm.DB) SavedFile {
@@ -58,6 +64,18 @@ func (adapter ChatsLoggingAdapter) GetById(id int) (*chats.Chat, error) {
 	return chat, err
 }
 
+func (adapter ChatsLoggingAdapter) GetBySlug(slug string) (*chats.Chat, error) {
+	log.Printf("fetching chat by slug: %s", slug)
+	chat, err := adapter.adapter.GetBySlug(slug)
+	if err != nil {
+		log.Printf("error fetching chat by slug: %v", err)
+		return chat, err
+	}
+
+	log.Printf("fetched chat by slug: %+v", chat)
+	return chat, err
+}
+
 func (adapter ChatsLoggingAdapter) GetByIdForUser(id int, userId int) (*chats.Chat, error) {
 	log.Printf("fetching chat by id for user: id=%d, userId=%d", id, userId)
 	chat, err := adapter.adapter.GetByIdForUser(id, userId)
@@ -77,13 +95,58 @@ func (adapter ChatsLoggingAdapter) GetByIdsForUser(ids []int, userId int) []chat
 	return chats
 }
 
-func (adapter ChatsLoggingAdapter) GetUserAll(userId int, page int, perPage int) utils.PaginatedResponse[chats.Chat] {
-	log.Printf("fetching all chats for user: userId=%d, page=%d, perPage=%d", userId, page, perPage)
-	chats := adapter.adapter.GetUserAll(userId, page, perPage)
+func (adapter ChatsLoggingAdapter) GetAllByMember(userId int) []chats.Chat {
+	log.Printf("fetching all chats by member: userId=%d", userId)
+	chats := adapter.adapter.GetAllByMember(userId)
+	log.Printf("fetched chats by member: %+v", chats)
+	return chats
+}
+
+func (adapter ChatsLoggingAdapter) GetSavedMessagesChat(userId int) (*chats.Chat, error) {
+	log.Printf("fetching saved messages chat: userId=%d", userId)
+	chat, err := adapter.adapter.GetSavedMessagesChat(userId)
+	if err != nil {
+		log.Printf("error fetching saved messages chat: %v", err)
+		return chat, err
+	}
+
+	log.Printf("fetched saved messages chat: %+v", chat)
+	return chat, err
+}
+
+func (adapter ChatsLoggingAdapter) GetUserAll(userId int, includeArchived bool, folder *chats.ChatFolder, page int, perPage int) utils.PaginatedResponse[chats.Chat] {
+	log.Printf("fetching all chats for user: userId=%d, includeArchived=%t, folder=%+v, page=%d, perPage=%d", userId, includeArchived, folder, page, perPage)
+	chats := adapter.adapter.GetUserAll(userId, includeArchived, folder, page, perPage)
 	log.Printf("fetched all chats for user: %+v", chats)
 	return chats
 }
 
+func (adapter ChatsLoggingAdapter) GetUserArchived(userId int, page int, perPage int) utils.PaginatedResponse[chats.Chat] {
+	log.Printf("fetching archived chats for user: userId=%d, page=%d, perPage=%d", userId, page, perPage)
+	chats := adapter.adapter.GetUserArchived(userId, page, perPage)
+	log.Printf("fetched archived chats for user: %+v", chats)
+	return chats
+}
+
+func (adapter ChatsLoggingAdapter) GetUserSettings(chatIds []int, userId int) map[int]chats.ChatUserSettings {
+	log.Printf("fetching chats user settings: chatIds=%v, userId=%d", chatIds, userId)
+	settings := adapter.adapter.GetUserSettings(chatIds, userId)
+	log.Printf("fetched chats user settings: %+v", settings)
+	return settings
+}
+
+func (adapter ChatsLoggingAdapter) SaveUserSettings(settings chats.ChatUserSettings) error {
+	log.Printf("saving chat user settings: %+v", settings)
+	err := adapter.adapter.SaveUserSettings(settings)
+	if err != nil {
+		log.Printf("error saving chat user settings: %v", err)
+		return err
+	}
+
+	log.Printf("chat user settings saved")
+	return nil
+}
+
 func (adapter ChatsLoggingAdapter) Save(chat chats.Chat) (*chats.Chat, error) {
 	log.Printf("saving chat: %+v", chat)
 	savedChat, err := adapter.adapter.Save(chat)
@@ -128,6 +191,20 @@ func (adapter ChatsLoggingAdapter) Delete(chat chats.Chat) {
 	log.Printf("deleted chat")
 }
 
+func (adapter ChatsLoggingAdapter) GetUnreadCounters(chatIds []int, userId int) map[int]chats.ChatUnreadCounters {
+	log.Printf("fetching chats unread counters: chatIds=%v, userId=%d", chatIds, userId)
+	counters := adapter.adapter.GetUnreadCounters(chatIds, userId)
+	log.Printf("fetched chats unread counters: %+v", counters)
+	return counters
+}
+
+func (adapter ChatsLoggingAdapter) GetTotalUnread(userId int) chats.ChatUnreadCounters {
+	log.Printf("fetching total unread counters: userId=%d", userId)
+	counters := adapter.adapter.GetTotalUnread(userId)
+	log.Printf("fetched total unread counters: %+v", counters)
+	return counters
+}
+
 func (adapter ChatsLoggingAdapter) SearchChats(userId int, query string, page int, perPage int) utils.PaginatedResponse[chats.Chat] {
 	log.Printf("searching chats: query=%s, page=%d, perPage=%d", query, page, perPage)
 	chats := adapter.adapter.SearchChats(userId, query, page, perPage)
@@ -135,10 +212,540 @@ func (adapter ChatsLoggingAdapter) SearchChats(userId int, query string, page in
 	return chats
 }
 
+func (adapter ChatsLoggingAdapter) GetInviteLinkByCode(code string) (*chats.ChatInviteLink, error) {
+	log.Printf("fetching invite link by code")
+	link, err := adapter.adapter.GetInviteLinkByCode(code)
+	if err != nil {
+		log.Printf("error fetching invite link: %v", err)
+		return link, err
+	}
+
+	log.Printf("fetched invite link: id=%d, chatId=%d", link.GetId(), link.GetChatId())
+	return link, err
+}
+
+func (adapter ChatsLoggingAdapter) SaveInviteLink(link chats.ChatInviteLink) (*chats.ChatInviteLink, error) {
+	log.Printf("saving invite link: id=%d, chatId=%d", link.GetId(), link.GetChatId())
+	savedLink, err := adapter.adapter.SaveInviteLink(link)
+	if err != nil {
+		log.Printf("error saving invite link: %v", err)
+		return savedLink, err
+	}
+
+	log.Printf("saved invite link: id=%d", savedLink.GetId())
+	return savedLink, err
+}
+
+func (adapter ChatsLoggingAdapter) UseInviteLink(link chats.ChatInviteLink) error {
+	log.Printf("using invite link: id=%d", link.GetId())
+	err := adapter.adapter.UseInviteLink(link)
+	if err != nil {
+		log.Printf("error using invite link: %v", err)
+		return err
+	}
+
+	log.Printf("invite link used")
+	return nil
+}
+
+func (adapter ChatsLoggingAdapter) GetJoinRequestById(id int) (*chats.ChatJoinRequest, error) {
+	log.Printf("fetching join request by id: %d", id)
+	request, err := adapter.adapter.GetJoinRequestById(id)
+	if err != nil {
+		log.Printf("error fetching join request: %v", err)
+		return request, err
+	}
+
+	log.Printf("fetched join request: %+v", request)
+	return request, err
+}
+
+func (adapter ChatsLoggingAdapter) GetPendingJoinRequest(chatId int, userId int) (*chats.ChatJoinRequest, error) {
+	log.Printf("fetching pending join request: chatId=%d, userId=%d", chatId, userId)
+	request, err := adapter.adapter.GetPendingJoinRequest(chatId, userId)
+	if err != nil {
+		log.Printf("error fetching pending join request: %v", err)
+		return request, err
+	}
+
+	log.Printf("fetched pending join request: %+v", request)
+	return request, err
+}
+
+func (adapter ChatsLoggingAdapter) GetChatPendingJoinRequests(chatId int) []chats.ChatJoinRequest {
+	log.Printf("fetching chat pending join requests: chatId=%d", chatId)
+	requests := adapter.adapter.GetChatPendingJoinRequests(chatId)
+	log.Printf("fetched chat pending join requests count: %d", len(requests))
+	return requests
+}
+
+func (adapter ChatsLoggingAdapter) SaveJoinRequest(request chats.ChatJoinRequest) (*chats.ChatJoinRequest, error) {
+	log.Printf("saving join request: %+v", request)
+	savedRequest, err := adapter.adapter.SaveJoinRequest(request)
+	if err != nil {
+		log.Printf("error saving join request: %v", err)
+		return savedRequest, err
+	}
+
+	log.Printf("saved join request: %+v", savedRequest)
+	return savedRequest, err
+}
+
+func (adapter ChatsLoggingAdapter) GetUserFolders(userId int) []chats.ChatFolder {
+	log.Printf("fetching chat folders for user: userId=%d", userId)
+	folders := adapter.adapter.GetUserFolders(userId)
+	log.Printf("fetched chat folders count: %d", len(folders))
+	return folders
+}
+
+func (adapter ChatsLoggingAdapter) GetFolderForUser(folderId int, userId int) (*chats.ChatFolder, error) {
+	log.Printf("fetching chat folder: folderId=%d, userId=%d", folderId, userId)
+	folder, err := adapter.adapter.GetFolderForUser(folderId, userId)
+	if err != nil {
+		log.Printf("error fetching chat folder: %v", err)
+		return folder, err
+	}
+
+	log.Printf("fetched chat folder: %+v", folder)
+	return folder, err
+}
+
+func (adapter ChatsLoggingAdapter) SaveFolder(folder chats.ChatFolder) (*chats.ChatFolder, error) {
+	log.Printf("saving chat folder: %+v", folder)
+	savedFolder, err := adapter.adapter.SaveFolder(folder)
+	if err != nil {
+		log.Printf("error saving chat folder: %v", err)
+		return savedFolder, err
+	}
+
+	log.Printf("saved chat folder: %+v", savedFolder)
+	return savedFolder, err
+}
+
+func (adapter ChatsLoggingAdapter) DeleteFolder(folder chats.ChatFolder) {
+	log.Printf("deleting chat folder: %+v", folder)
+	adapter.adapter.DeleteFolder(folder)
+	log.Printf("deleted chat folder")
+}
+
+func (adapter ChatsLoggingAdapter) SaveFoldersOrder(userId int, folderIds []int) error {
+	log.Printf("saving chat folders order: userId=%d, folderIds=%v", userId, folderIds)
+	err := adapter.adapter.SaveFoldersOrder(userId, folderIds)
+	if err != nil {
+		log.Printf("error saving chat folders order: %v", err)
+		return err
+	}
+
+	log.Printf("chat folders order saved")
+	return nil
+}
+
+func (adapter ChatsLoggingAdapter) GetChatBans(chatId int) []chats.ChatBan {
+	log.Printf("fetching chat bans: chatId=%d", chatId)
+	bans := adapter.adapter.GetChatBans(chatId)
+	log.Printf("fetched chat bans count: %d", len(bans))
+	return bans
+}
+
+func (adapter ChatsLoggingAdapter) SaveBan(ban chats.ChatBan) (*chats.ChatBan, error) {
+	log.Printf("saving chat ban: %+v", ban)
+	savedBan, err := adapter.adapter.SaveBan(ban)
+	if err != nil {
+		log.Printf("error saving chat ban: %v", err)
+		return savedBan, err
+	}
+
+	log.Printf("saved chat ban: %+v", savedBan)
+	return savedBan, err
+}
+
+func (adapter ChatsLoggingAdapter) DeleteBan(chatId int, userId int) {
+	log.Printf("deleting chat ban: chatId=%d, userId=%d", chatId, userId)
+	adapter.adapter.DeleteBan(chatId, userId)
+	log.Printf("deleted chat ban")
+}
+
 type ChatsAdapter struct {
 	db gorm.DB
 }
 
+type chatPinnedMessageRow struct {
+	ChatId    uint
+	MessageId uint
+	SenderId  uint
+	Content   string
+	PinnedBy  uint
+	PinnedAt  time.Time
+}
+
+type chatUnreadCountersRow struct {
+	ChatId              uint
+	UnreadCount         int
+	UnreadMentionsCount int
+}
+
+func (adapter ChatsAdapter) GetUnreadCounters(chatIds []int, userId int) map[int]chats.ChatUnreadCounters {
+	counters := make(map[int]chats.ChatUnreadCounters)
+	if len(chatIds) == 0 {
+		return counters
+	}
+
+	var rows []chatUnreadCountersRow
+	adapter.db.Model(&Message{}).Scopes(scopes.UnreadCountersByChat(chatIds, userId)).Scan(&rows)
+
+	for _, row := range rows {
+		counters[int(row.ChatId)] = chats.NewChatUnreadCounters(row.UnreadCount, row.UnreadMentionsCount)
+	}
+
+	return counters
+}
+
+func (adapter ChatsAdapter) GetTotalUnread(userId int) chats.ChatUnreadCounters {
+	var row chatUnreadCountersRow
+	adapter.db.Model(&Message{}).Scopes(scopes.TotalUnread(userId)).Scan(&row)
+
+	return chats.NewChatUnreadCounters(row.UnreadCount, row.UnreadMentionsCount)
+}
+
+func (adapter ChatsAdapter) getPinnedMessages(chatIds []uint, viewerId int) map[uint]chats.ChatPinnedMessage {
+	pinnedMessages := make(map[uint]chats.ChatPinnedMessage)
+	if len(chatIds) == 0 {
+		return pinnedMessages
+	}
+
+	var rows []chatPinnedMessageRow
+	adapter.db.Raw(
+		`SELECT DISTINCT ON (pinned_messages.chat_id)
+			pinned_messages.chat_id,
+			pinned_messages.message_id,
+			messages.sender_id,
+			messages.content,
+			pinned_messages.pinned_by,
+			pinned_messages.created_at AS pinned_at
+		FROM pinned_messages
+		JOIN messages ON messages.id = pinned_messages.message_id
+		WHERE pinned_messages.chat_id IN ? AND pinned_messages.deleted_at IS NULL AND messages.deleted_at IS NULL
+			AND NOT (? = ANY(COALESCE(messages.deleted_for, '{}')))
+		ORDER BY pinned_messages.chat_id, pinned_messages.created_at DESC`,
+		chatIds,
+		viewerId,
+	).Scan(&rows)
+
+	for _, row := range rows {
+		content := row.Content
+		pinnedMessages[row.ChatId] = chats.NewChatPinnedMessage(
+			int(row.MessageId),
+			int(row.SenderId),
+			&content,
+			int(row.PinnedBy),
+			row.PinnedAt,
+		)
+	}
+
+	return pinnedMessages
+}
+
+func getChatsRights(db gorm.DB, chatIds []uint) (map[uint][]ChatAdminRights, map[uint]ChatPermissions) {
+	adminsRights := make(map[uint][]ChatAdminRights)
+	permissions := make(map[uint]ChatPermissions)
+	if len(chatIds) == 0 {
+		return adminsRights, permissions
+	}
+
+	var dbAdminsRights []ChatAdminRights
+	db.Where("chat_id IN ?", chatIds).Find(&dbAdminsRights)
+	for _, rights := range dbAdminsRights {
+		adminsRights[rights.ChatId] = append(adminsRights[rights.ChatId], rights)
+	}
+
+	var dbPermissions []ChatPermissions
+	db.Where("chat_id IN ?", chatIds).Find(&dbPermissions)
+	for _, chatPermissions := range dbPermissions {
+		permissions[chatPermissions.ChatId] = chatPermissions
+	}
+
+	return adminsRights, permissions
+}
+
+func setupChatRights(chat *chats.Chat, chatId uint, adminsRights map[uint][]ChatAdminRights, permissions map[uint]ChatPermissions) {
+	for _, rights := range adminsRights[chatId] {
+		chat.SetAdminRights(int(rights.UserId), DbChatAdminRightsToModel(rights))
+	}
+
+	if chatPermissions, ok := permissions[chatId]; ok {
+		chat.SetMemberPermissions(DbChatPermissionsToModel(chatPermissions))
+	}
+}
+
+func (adapter ChatsAdapter) saveChatRights(chat chats.Chat, chatId uint) error {
+	var admins []uint
+	var dbAdminsRights []ChatAdminRights
+	for adminId, rights := range chat.GetAdminsRights() {
+		admins = append(admins, uint(adminId))
+		dbAdminsRights = append(dbAdminsRights, ModelToDbChatAdminRights(rights, chatId, uint(adminId)))
+	}
+
+	staleRights := adapter.db.Unscoped().Where("chat_id = ?", chatId)
+	if len(admins) > 0 {
+		staleRights = staleRights.Where("user_id NOT IN ?", admins)
+	}
+	if result := staleRights.Delete(&ChatAdminRights{}); result.Error != nil {
+		return result.Error
+	}
+
+	if len(dbAdminsRights) > 0 {
+		result := adapter.db.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "chat_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"can_add_members",
+				"can_remove_members",
+				"can_edit_info",
+				"can_pin_messages",
+				"can_delete_messages",
+				"can_manage_admins",
+				"updated_at",
+			}),
+		}).Create(&dbAdminsRights)
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+
+	dbPermissions := ModelToDbChatPermissions(chat.GetMemberPermissions(), chatId)
+	result := adapter.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chat_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"can_send_messages", "can_send_media", "can_add_members", "can_pin_messages", "updated_at"}),
+	}).Create(&dbPermissions)
+	return result.Error
+}
+
+func (adapter ChatsAdapter) GetInviteLinkByCode(code string) (*chats.ChatInviteLink, error) {
+	var dbLink ChatInviteLink
+	result := adapter.db.Where("code = ?", code).First(&dbLink)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	link := DbChatInviteLinkToModel(dbLink)
+	return &link, nil
+}
+
+func (adapter ChatsAdapter) SaveInviteLink(link chats.ChatInviteLink) (*chats.ChatInviteLink, error) {
+	dbLink := ModelToDbChatInviteLink(link)
+	result := adapter.db.Save(&dbLink)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	savedLink := DbChatInviteLinkToModel(dbLink)
+	return &savedLink, nil
+}
+
+func (adapter ChatsAdapter) UseInviteLink(link chats.ChatInviteLink) error {
+	result := adapter.db.Model(&ChatInviteLink{}).Where(
+		"id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?) AND (usage_limit IS NULL OR usage_count < usage_limit)",
+		link.GetId(),
+		time.Now(),
+	).UpdateColumn("usage_count", gorm.Expr("usage_count + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("invite link %d is no longer usable", link.GetId())
+	}
+
+	return nil
+}
+
+func (adapter ChatsAdapter) GetJoinRequestById(id int) (*chats.ChatJoinRequest, error) {
+	var dbRequest JoinRequest
+	result := adapter.db.Where("id = ?", id).First(&dbRequest)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	request := DbJoinRequestToModel(dbRequest)
+	return &request, nil
+}
+
+func (adapter ChatsAdapter) GetPendingJoinRequest(chatId int, userId int) (*chats.ChatJoinRequest, error) {
+	var dbRequest JoinRequest
+	result := adapter.db.Where(
+		"chat_id = ? AND user_id = ? AND status = ?", chatId, userId, string(chats.PendingJoinRequestStatus),
+	).First(&dbRequest)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	request := DbJoinRequestToModel(dbRequest)
+	return &request, nil
+}
+
+func (adapter ChatsAdapter) GetChatPendingJoinRequests(chatId int) []chats.ChatJoinRequest {
+	var dbRequests []JoinRequest
+	result := adapter.db.Where(
+		"chat_id = ? AND status = ?", chatId, string(chats.PendingJoinRequestStatus),
+	).Order("created_at").Find(&dbRequests)
+	if result.Error != nil {
+		return []chats.ChatJoinRequest{}
+	}
+
+	var requests []chats.ChatJoinRequest
+	for _, dbRequest := range dbRequests {
+		requests = append(requests, DbJoinRequestToModel(dbRequest))
+	}
+
+	return requests
+}
+
+func (adapter ChatsAdapter) SaveJoinRequest(request chats.ChatJoinRequest) (*chats.ChatJoinRequest, error) {
+	dbRequest := ModelToDbJoinRequest(request)
+	result := adapter.db.Save(&dbRequest)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	savedRequest := DbJoinRequestToModel(dbRequest)
+	return &savedRequest, nil
+}
+
+func (adapter ChatsAdapter) GetUserFolders(userId int) []chats.ChatFolder {
+	var dbFolders []ChatFolder
+	result := adapter.db.Where("user_id = ?", userId).Order("position, id").Find(&dbFolders)
+	if result.Error != nil {
+		return []chats.ChatFolder{}
+	}
+
+	var folders []chats.ChatFolder
+	for _, dbFolder := range dbFolders {
+		folders = append(folders, DbChatFolderToModel(dbFolder))
+	}
+
+	return folders
+}
+
+func (adapter ChatsAdapter) GetFolderForUser(folderId int, userId int) (*chats.ChatFolder, error) {
+	var dbFolder ChatFolder
+	result := adapter.db.Where("id = ? AND user_id = ?", folderId, userId).First(&dbFolder)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	folder := DbChatFolderToModel(dbFolder)
+	return &folder, nil
+}
+
+func (adapter ChatsAdapter) SaveFolder(folder chats.ChatFolder) (*chats.ChatFolder, error) {
+	dbFolder := ModelToDbChatFolder(folder)
+	result := adapter.db.Save(&dbFolder)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	savedFolder := DbChatFolderToModel(dbFolder)
+	return &savedFolder, nil
+}
+
+func (adapter ChatsAdapter) DeleteFolder(folder chats.ChatFolder) {
+	adapter.db.Where("id = ?", folder.GetId()).Delete(&ChatFolder{})
+}
+
+func (adapter ChatsAdapter) SaveFoldersOrder(userId int, folderIds []int) error {
+	return adapter.db.Transaction(func(tx *gorm.DB) error {
+		for position, folderId := range folderIds {
+			result := tx.Model(&ChatFolder{}).Where("id = ? AND user_id = ?", folderId, userId).Update("position", position)
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+
+		return nil
+	})
+}
+
+func (adapter ChatsAdapter) GetChatBans(chatId int) []chats.ChatBan {
+	var dbBans []ChatBan
+	result := adapter.db.Where(
+		"chat_id = ? AND (expires_at IS NULL OR expires_at > ?)", chatId, time.Now(),
+	).Order("created_at").Find(&dbBans)
+	if result.Error != nil {
+		return []chats.ChatBan{}
+	}
+
+	var bans []chats.ChatBan
+	for _, dbBan := range dbBans {
+		bans = append(bans, DbChatBanToModel(dbBan))
+	}
+
+	return bans
+}
+
+func (adapter ChatsAdapter) SaveBan(ban chats.ChatBan) (*chats.ChatBan, error) {
+	dbBan := ModelToDbChatBan(ban)
+	result := adapter.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chat_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"banned_by", "expires_at", "created_at", "updated_at"}),
+	}).Create(&dbBan)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	savedBan := DbChatBanToModel(dbBan)
+	return &savedBan, nil
+}
+
+func (adapter ChatsAdapter) DeleteBan(chatId int, userId int) {
+	adapter.db.Unscoped().Where("chat_id = ? AND user_id = ?", chatId, userId).Delete(&ChatBan{})
+}
+
+func (adapter ChatsAdapter) GetUserSettings(chatIds []int, userId int) map[int]chats.ChatUserSettings {
+	userSettings := make(map[int]chats.ChatUserSettings)
+	if len(chatIds) == 0 {
+		return userSettings
+	}
+
+	var dbSettings []ChatUserSettings
+	adapter.db.Where("chat_id IN ? AND user_id = ?", chatIds, userId).Find(&dbSettings)
+	for _, settings := range dbSettings {
+		userSettings[int(settings.ChatId)] = DbChatUserSettingsToModel(settings)
+	}
+
+	return userSettings
+}
+
+func (adapter ChatsAdapter) SaveUserSettings(settings chats.ChatUserSettings) error {
+	dbSettings := ModelToDbChatUserSettings(settings)
+	result := adapter.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chat_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"is_archived", "is_pinned", "pinned_at", "is_muted", "muted_until", "updated_at"}),
+	}).Create(&dbSettings)
+	return result.Error
+}
+
+func (adapter ChatsAdapter) dbChatsToModels(dbChats []Chat, viewerId int) []chats.Chat {
+	var chatIds []uint
+	for _, dbChat := range dbChats {
+		if dbChat.ID != 0 {
+			chatIds = append(chatIds, dbChat.ID)
+		}
+	}
+
+	pinnedMessages := adapter.getPinnedMessages(chatIds, viewerId)
+	adminsRights, permissions := getChatsRights(adapter.db, chatIds)
+	var chatModels []chats.Chat
+	for _, dbChat := range dbChats {
+		chatModel := DbChatToModel(dbChat)
+		setupChatRights(&chatModel, dbChat.ID, adminsRights, permissions)
+		if pinnedMessage, ok := pinnedMessages[dbChat.ID]; ok {
+			chatModel.SetPinnedMessage(&pinnedMessage)
+		}
+
+		chatModels = append(chatModels, chatModel)
+	}
+
+	return chatModels
+}
+
 func (adapter ChatsAdapter) GetById(id int) (*chats.Chat, error) {
 	var chat Chat
 	result := adapter.db.Preload("Avatar").Where("id = ?", id).First(&chat)
@@ -147,7 +754,19 @@ func (adapter ChatsAdapter) GetById(id int) (*chats.Chat, error) {
 		return nil, result.Error
 	}
 
-	chatModel := DbChatToModel(chat)
+	chatModel := adapter.dbChatsToModels([]Chat{chat}, noViewerId)[0]
+	return &chatModel, nil
+}
+
+func (adapter ChatsAdapter) GetBySlug(slug string) (*chats.Chat, error) {
+	var chat Chat
+	result := adapter.db.Preload("Avatar").Where("slug = ?", slug).First(&chat)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	chatModel := adapter.dbChatsToModels([]Chat{chat}, noViewerId)[0]
 	return &chatModel, nil
 }
 
@@ -156,10 +775,10 @@ func (adapter ChatsAdapter) GetByIdForUser(id int, userId int) (*chats.Chat, err
 	result := adapter.db.Preload("Avatar").Where("id = ? AND ? = ANY(members)", id, userId).First(&chat)
 
 	if result.Error != nil {
-		return nil, result.Error
+		return nil, notFoundAs(result.Error, chats.ErrChatNotFound)
 	}
 
-	chatModel := DbChatToModel(chat)
+	chatModel := adapter.dbChatsToModels([]Chat{chat}, userId)[0]
 	return &chatModel, nil
 }
 
@@ -170,22 +789,58 @@ func (adapter ChatsAdapter) GetByIdsForUser(ids []int, userId int) []chats.Chat
 		return []chats.Chat{}
 	}
 
-	var chatModels []chats.Chat
-	for _, chat := range foundedChats {
-		chatModels = append(chatModels, DbChatToModel(chat))
+	return adapter.dbChatsToModels(foundedChats, userId)
+}
+
+func (adapter ChatsAdapter) GetAllByMember(userId int) []chats.Chat {
+	var foundedChats []Chat
+	result := adapter.db.Preload("Avatar").Where("? = ANY(members)", userId).Order("id").Find(&foundedChats)
+	if result.Error != nil {
+		return []chats.Chat{}
 	}
 
-	return chatModels
+	return adapter.dbChatsToModels(foundedChats, noViewerId)
+}
+
+func (adapter ChatsAdapter) GetSavedMessagesChat(userId int) (*chats.Chat, error) {
+	var dbChat Chat
+	result := adapter.db.Preload("Avatar").Where("owner_id = ? AND type = ?", userId, string(chats.SavedMessagesChatType)).First(&dbChat)
+	if result.Error != nil {
+		return nil, notFoundAs(result.Error, chats.ErrChatNotFound)
+	}
+
+	chat := adapter.dbChatsToModels([]Chat{dbChat}, userId)[0]
+	return &chat, nil
 }
 
-func (adapter ChatsAdapter) getUserAllCount(userId int, page int, perPage int) int {
+func (adapter ChatsAdapter) userChatsQuery(userId int, chatScopes ...func(db *gorm.DB) *gorm.DB) *gorm.DB {
+	return adapter.db.Model(&Chat{}).Scopes(scopes.WithUserSettings(userId)).Scopes(chatScopes...).Where("? = ANY(chats.members)", userId)
+}
+
+func (adapter ChatsAdapter) getUserAllCount(userId int, chatScopes ...func(db *gorm.DB) *gorm.DB) int {
 	var count int64
-	adapter.db.Model(&Chat{}).Where("? = ANY(members)", userId).Count(&count)
+	adapter.userChatsQuery(userId, chatScopes...).Count(&count)
 	return int(count)
 }
 
-func (adapter ChatsAdapter) GetUserAll(userId int, page int, perPage int) utils.PaginatedResponse[chats.Chat] {
-	totalCount := adapter.getUserAllCount(userId, page, perPage)
+func (adapter ChatsAdapter) GetUserAll(userId int, includeArchived bool, folder *chats.ChatFolder, page int, perPage int) utils.PaginatedResponse[chats.Chat] {
+	var chatScopes []func(db *gorm.DB) *gorm.DB
+	if !includeArchived {
+		chatScopes = append(chatScopes, scopes.ArchivedForUser(false))
+	}
+	if folder != nil {
+		chatScopes = append(chatScopes, scopes.InFolder(*folder))
+	}
+
+	return adapter.getUserChats(userId, page, perPage, chatScopes...)
+}
+
+func (adapter ChatsAdapter) GetUserArchived(userId int, page int, perPage int) utils.PaginatedResponse[chats.Chat] {
+	return adapter.getUserChats(userId, page, perPage, scopes.ArchivedForUser(true))
+}
+
+func (adapter ChatsAdapter) getUserChats(userId int, page int, perPage int, chatScopes ...func(db *gorm.DB) *gorm.DB) utils.PaginatedResponse[chats.Chat] {
+	totalCount := adapter.getUserAllCount(userId, chatScopes...)
 	if totalCount == 0 {
 		return utils.NewPaginatedResponse(
 			1, 1, 1, 0, []chats.Chat{},
@@ -193,10 +848,10 @@ func (adapter ChatsAdapter) GetUserAll(userId int, page int, perPage int) utils.
 	}
 
 	var foundedChats []*Chat
-	result := adapter.db.Scopes(Paginate(page, perPage)).Preload("Avatar").Where(
-		"? = ANY(members)", userId,
+	result := adapter.userChatsQuery(userId, chatScopes...).Scopes(scopes.Paginate(page, perPage)).Select("chats.*").Preload("Avatar").Order(
+		"COALESCE(chat_user_settings.is_pinned, false) DESC, chat_user_settings.pinned_at DESC NULLS LAST",
 	).Order(
-		"(SELECT created_at FROM messages WHERE chat_id = chats.id ORDER BY created_at DESC LIMIT 1) DESC NULLS LAST",
+		"(SELECT created_at FROM messages WHERE chat_id = chats.id AND send_at IS NULL ORDER BY created_at DESC LIMIT 1) DESC NULLS LAST",
 	).Find(&foundedChats)
 
 	if result.Error != nil {
@@ -210,9 +865,9 @@ func (adapter ChatsAdapter) GetUserAll(userId int, page int, perPage int) utils.
 		pagesCount = 1
 	}
 
-	var chats []chats.Chat
+	var dbChats []Chat
 	for _, chat := range foundedChats {
-		chats = append(chats, DbChatToModel(*chat))
+		dbChats = append(dbChats, *chat)
 	}
 
 	return utils.NewPaginatedResponse(
@@ -220,7 +875,7 @@ func (adapter ChatsAdapter) GetUserAll(userId int, page int, perPage int) utils.
 		perPage,
 		int(pagesCount),
 		totalCount,
-		chats,
+		adapter.dbChatsToModels(dbChats, userId),
 	)
 }
 
@@ -233,7 +888,11 @@ func (adapter ChatsAdapter) Save(chat chats.Chat) (*chats.Chat, error) {
 		return nil, result.Error
 	}
 
-	chatModel := DbChatToModel(dbChat)
+	if err := adapter.saveChatRights(chat, dbChat.ID); err != nil {
+		return nil, err
+	}
+
+	chatModel := adapter.dbChatsToModels([]Chat{dbChat}, noViewerId)[0]
 	return &chatModel, nil
 }
 
@@ -276,8 +935,8 @@ func (adapter ChatsAdapter) SearchChats(userId int, query string, page int, perP
 	stmt.Count(&totalCount)
 
 	var foundedChats []*Chat
-	result := stmt.Scopes(Paginate(page, perPage)).Preload("Avatar").Order(
-		"(SELECT created_at FROM messages WHERE chat_id = chats.id ORDER BY created_at DESC LIMIT 1) DESC NULLS LAST",
+	result := stmt.Scopes(scopes.Paginate(page, perPage)).Preload("Avatar").Order(
+		"(SELECT created_at FROM messages WHERE chat_id = chats.id AND send_at IS NULL ORDER BY created_at DESC LIMIT 1) DESC NULLS LAST",
 	).Find(&foundedChats)
 
 	if result.Error != nil {
@@ -291,9 +950,9 @@ func (adapter ChatsAdapter) SearchChats(userId int, query string, page int, perP
 		pagesCount = 1
 	}
 
-	var chats []chats.Chat
+	var dbChats []Chat
 	for _, chat := range foundedChats {
-		chats = append(chats, DbChatToModel(*chat))
+		dbChats = append(dbChats, *chat)
 	}
 
 	return utils.NewPaginatedResponse(
@@ -301,7 +960,7 @@ func (adapter ChatsAdapter) SearchChats(userId int, query string, page int, perP
 		perPage,
 		int(pagesCount),
 		int(totalCount),
-		chats,
+		adapter.dbChatsToModels(dbChats, userId),
 	)
 }
 
@@ -323,6 +982,20 @@ func (adapter MessagesLoggingAdapter) GetChatCursorAllForUser(chatId int, userId
 	return messages
 }
 
+func (adapter MessagesLoggingAdapter) GetThreadAllForUser(rootMessageId int, userId int, offset int, limit int) utils.OffsetResponse[messages.Message] {
+	log.Printf("fetching thread all messages for user: rootMessageId=%d, userId=%d, offset=%d, limit=%d", rootMessageId, userId, offset, limit)
+	messages := adapter.adapter.GetThreadAllForUser(rootMessageId, userId, offset, limit)
+	log.Printf("fetched messages: %+v", messages)
+	return messages
+}
+
+func (adapter MessagesLoggingAdapter) SearchForUser(filter messages.SearchMessagesFilter, userId int, offset int, limit int) utils.OffsetResponse[messages.MessageSearchResult] {
+	log.Printf("searching messages for user: filter=%+v, userId=%d, offset=%d, limit=%d", filter, userId, offset, limit)
+	results := adapter.adapter.SearchForUser(filter, userId, offset, limit)
+	log.Printf("found messages count: %d", results.GetTotal())
+	return results
+}
+
 func (adapter MessagesLoggingAdapter) GetChatsLast(chatIds []int, userId int) []messages.Message {
 	log.Printf("fetching last messages for chats: chatIds=%v, userId=%d", chatIds, userId)
 	messages := adapter.adapter.GetChatsLast(chatIds, userId)
@@ -374,14 +1047,291 @@ func (adapter MessagesLoggingAdapter) Delete(message messages.Message) {
 	log.Printf("message deleted")
 }
 
+func (adapter MessagesLoggingAdapter) DeleteForUser(message messages.Message, userId int) error {
+	log.Printf("deleting message for user: message=%+v, userId=%d", message, userId)
+	err := adapter.adapter.DeleteForUser(message, userId)
+	if err != nil {
+		log.Printf("error deleting message for user: %v", err)
+		return err
+	}
+
+	log.Printf("message deleted for user")
+	return nil
+}
+
+func (adapter MessagesLoggingAdapter) Pin(message messages.Message, pinnedBy int) error {
+	log.Printf("pinning message: message=%+v, pinnedBy=%d", message, pinnedBy)
+	err := adapter.adapter.Pin(message, pinnedBy)
+	if err != nil {
+		log.Printf("error pinning message: %v", err)
+		return err
+	}
+
+	log.Printf("message pinned")
+	return nil
+}
+
+func (adapter MessagesLoggingAdapter) Unpin(message messages.Message) error {
+	log.Printf("unpinning message: %+v", message)
+	err := adapter.adapter.Unpin(message)
+	if err != nil {
+		log.Printf("error unpinning message: %v", err)
+		return err
+	}
+
+	log.Printf("message unpinned")
+	return nil
+}
+
+func (adapter MessagesLoggingAdapter) GetChatPinned(chatId int, userId int) []messages.Message {
+	log.Printf("fetching chat pinned messages: chatId=%d, userId=%d", chatId, userId)
+	messages := adapter.adapter.GetChatPinned(chatId, userId)
+	log.Printf("fetched pinned messages: %+v", messages)
+	return messages
+}
+
+func (adapter MessagesLoggingAdapter) GetChatScheduledForUser(chatId int, userId int) []messages.Message {
+	log.Printf("fetching chat scheduled messages for user: chatId=%d, userId=%d", chatId, userId)
+	messages := adapter.adapter.GetChatScheduledForUser(chatId, userId)
+	log.Printf("fetched scheduled messages: %+v", messages)
+	return messages
+}
+
+func (adapter MessagesLoggingAdapter) GetScheduledByIdForUser(messageId int, userId int) (*messages.Message, error) {
+	log.Printf("fetching scheduled message by id for user: messageId=%d, userId=%d", messageId, userId)
+	message, err := adapter.adapter.GetScheduledByIdForUser(messageId, userId)
+	if err != nil {
+		log.Printf("error fetching scheduled message: %v", err)
+		return message, err
+	}
+
+	log.Printf("fetched scheduled message: %+v", message)
+	return message, err
+}
+
+func (adapter MessagesLoggingAdapter) GetAllScheduled() []messages.Message {
+	log.Printf("fetching all scheduled messages")
+	messages := adapter.adapter.GetAllScheduled()
+	log.Printf("fetched scheduled messages count: %d", len(messages))
+	return messages
+}
+
+func (adapter MessagesLoggingAdapter) SaveRevision(revision messages.MessageRevision) error {
+	log.Printf("saving message revision: %+v", revision)
+	err := adapter.adapter.SaveRevision(revision)
+	if err != nil {
+		log.Printf("error saving message revision: %v", err)
+		return err
+	}
+
+	log.Printf("message revision saved")
+	return nil
+}
+
+func (adapter MessagesLoggingAdapter) GetRevisions(message messages.Message) []messages.MessageRevision {
+	log.Printf("fetching message revisions: messageId=%d", message.GetId())
+	revisions := adapter.adapter.GetRevisions(message)
+	log.Printf("fetched message revisions: %+v", revisions)
+	return revisions
+}
+
+func (adapter MessagesLoggingAdapter) PublishScheduled(message messages.Message) (*messages.Message, error) {
+	log.Printf("publishing scheduled message: %+v", message)
+	publishedMessage, err := adapter.adapter.PublishScheduled(message)
+	if err != nil {
+		log.Printf("error publishing scheduled message: %v", err)
+		return publishedMessage, err
+	}
+
+	log.Printf("published scheduled message: %+v", publishedMessage)
+	return publishedMessage, err
+}
+
+func (adapter MessagesLoggingAdapter) VotePoll(message messages.Message, userId int, optionIds []int) error {
+	log.Printf("voting in poll: messageId=%d, userId=%d, optionIds=%v", message.GetId(), userId, optionIds)
+	err := adapter.adapter.VotePoll(message, userId, optionIds)
+	if err != nil {
+		log.Printf("error voting in poll: %v", err)
+		return err
+	}
+
+	log.Printf("poll vote saved")
+	return nil
+}
+
+func (adapter MessagesLoggingAdapter) RetractPollVote(message messages.Message, userId int) error {
+	log.Printf("retracting poll vote: messageId=%d, userId=%d", message.GetId(), userId)
+	err := adapter.adapter.RetractPollVote(message, userId)
+	if err != nil {
+		log.Printf("error retracting poll vote: %v", err)
+		return err
+	}
+
+	log.Printf("poll vote retracted")
+	return nil
+}
+
+func (adapter MessagesLoggingAdapter) GetReadPointer(chatId int, userId int) (*messages.ChatReadPointer, error) {
+	log.Printf("fetching chat read pointer: chatId=%d, userId=%d", chatId, userId)
+	pointer, err := adapter.adapter.GetReadPointer(chatId, userId)
+	if err != nil {
+		log.Printf("error fetching chat read pointer: %v", err)
+		return pointer, err
+	}
+
+	log.Printf("fetched chat read pointer: %+v", pointer)
+	return pointer, err
+}
+
+func (adapter MessagesLoggingAdapter) SaveReadPointer(pointer messages.ChatReadPointer) error {
+	log.Printf("saving chat read pointer: %+v", pointer)
+	err := adapter.adapter.SaveReadPointer(pointer)
+	if err != nil {
+		log.Printf("error saving chat read pointer: %v", err)
+		return err
+	}
+
+	log.Printf("chat read pointer saved")
+	return nil
+}
+
+func (adapter MessagesLoggingAdapter) ClosePoll(message messages.Message, closedAt time.Time) error {
+	log.Printf("closing poll: messageId=%d, closedAt=%v", message.GetId(), closedAt)
+	err := adapter.adapter.ClosePoll(message, closedAt)
+	if err != nil {
+		log.Printf("error closing poll: %v", err)
+		return err
+	}
+
+	log.Printf("poll closed")
+	return nil
+}
+
 type MessagesAdapter struct {
 	db gorm.DB
 }
 
+type messageThreadRow struct {
+	ThreadRootId      uint
+	RepliesCount      int
+	LastReplyId       uint
+	LastReplySenderId uint
+	LastReplyAt       time.Time
+}
+
+func (adapter MessagesAdapter) getThreads(rootMessageIds []uint, viewerId int) map[uint]messages.MessageThread {
+	threads := make(map[uint]messages.MessageThread)
+	if len(rootMessageIds) == 0 {
+		return threads
+	}
+
+	var rows []messageThreadRow
+	adapter.db.Raw(
+		`SELECT DISTINCT ON (thread_root_id)
+			thread_root_id,
+			COUNT(*) OVER (PARTITION BY thread_root_id) AS replies_count,
+			id AS last_reply_id,
+			sender_id AS last_reply_sender_id,
+			created_at AS last_reply_at
+		FROM messages
+		WHERE thread_root_id IN ? AND send_at IS NULL AND deleted_at IS NULL
+			AND NOT (? = ANY(COALESCE(deleted_for, '{}')))
+		ORDER BY thread_root_id, created_at DESC`,
+		rootMessageIds,
+		viewerId,
+	).Scan(&rows)
+
+	for _, row := range rows {
+		lastReply := messages.NewMessageThreadReply(int(row.LastReplyId), int(row.LastReplySenderId), row.LastReplyAt)
+		threads[row.ThreadRootId] = messages.NewMessageThread(row.RepliesCount, &lastReply)
+	}
+
+	return threads
+}
+
+func (adapter MessagesAdapter) getPolls(messageIds []uint) map[uint]messages.MessagePoll {
+	polls := make(map[uint]messages.MessagePoll)
+	if len(messageIds) == 0 {
+		return polls
+	}
+
+	var dbPolls []Poll
+	adapter.db.Preload("Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("poll_options.position ASC")
+	}).Preload("Options.Votes").Where("message_id IN ?", messageIds).Find(&dbPolls)
+
+	for _, dbPoll := range dbPolls {
+		polls[dbPoll.MessageId] = DbPollToModel(dbPoll)
+	}
+
+	return polls
+}
+
+// getReadPointers loads the pointers that reach at least the oldest message
+// of the page, the others can't mark any of its messages as read.
+func (adapter MessagesAdapter) getReadPointers(chatIds []uint, minCreatedAt time.Time) map[uint][]messages.ChatReadPointer {
+	pointers := make(map[uint][]messages.ChatReadPointer)
+	if len(chatIds) == 0 {
+		return pointers
+	}
+
+	var dbPointers []ChatReadPointer
+	adapter.db.Scopes(scopes.ReadPointersReaching(chatIds, minCreatedAt)).Find(&dbPointers)
+	for _, pointer := range dbPointers {
+		pointers[pointer.ChatId] = append(pointers[pointer.ChatId], DbChatReadPointerToModel(pointer))
+	}
+
+	return pointers
+}
+
+func (adapter MessagesAdapter) dbMessagesToModels(dbMessages []Message, viewerId int) []messages.Message {
+	var rootMessageIds []uint
+	var pollMessageIds []uint
+	var chatIds []uint
+	var minCreatedAt time.Time
+	for _, dbMessage := range dbMessages {
+		if dbMessage.ID != 0 {
+			rootMessageIds = append(rootMessageIds, dbMessage.ID)
+		}
+		if minCreatedAt.IsZero() || dbMessage.CreatedAt.Before(minCreatedAt) {
+			minCreatedAt = dbMessage.CreatedAt
+		}
+		if dbMessage.ChatId != 0 && !slices.Contains(chatIds, dbMessage.ChatId) {
+			chatIds = append(chatIds, dbMessage.ChatId)
+		}
+		if dbMessage.Type == string(messages.PollMessageType) {
+			pollMessageIds = append(pollMessageIds, dbMessage.ID)
+		}
+	}
+
+	threads := adapter.getThreads(rootMessageIds, viewerId)
+	polls := adapter.getPolls(pollMessageIds)
+	readPointers := adapter.getReadPointers(chatIds, minCreatedAt)
+	adminsRights, permissions := getChatsRights(adapter.db, chatIds)
+	var messagesModels []messages.Message
+	for _, dbMessage := range dbMessages {
+		messageModel := DbMessageToModel(dbMessage)
+		chat := messageModel.GetChat()
+		setupChatRights(&chat, dbMessage.ChatId, adminsRights, permissions)
+		messageModel.SetChat(chat)
+		messageModel.ReadByPointers(readPointers[dbMessage.ChatId])
+		if thread, ok := threads[dbMessage.ID]; ok {
+			messageModel.SetThread(thread)
+		}
+		if poll, ok := polls[dbMessage.ID]; ok {
+			messageModel.SetPoll(&poll)
+		}
+
+		messagesModels = append(messagesModels, messageModel)
+	}
+
+	return messagesModels
+}
+
 func (adapter MessagesAdapter) getChatAllForUserTotal(chatId int, userId int) int {
 	var count int64
 
-	adapter.db.Model(&Message{}).Joins("JOIN chats ON messages.chat_id = chats.id").Where(
+	adapter.db.Model(&Message{}).Scopes(scopes.PublishedMessages, scopes.NotDeletedForUser(userId)).Joins("JOIN chats ON messages.chat_id = chats.id").Where(
 		"messages.chat_id = ? AND ? = ANY(chats.members)", chatId, userId,
 	).Count(&count)
 
@@ -393,31 +1343,26 @@ func (adapter MessagesAdapter) GetChatAllForUser(chatId int, userId int, offset
 
 	total := adapter.getChatAllForUserTotal(chatId, userId)
 
-	adapter.db.Preload("Chat").Preload("Reactions").Preload("Voice").Preload("Circle").Preload("Attachments").Joins("JOIN chats ON messages.chat_id = chats.id").Where(
+	adapter.db.Scopes(scopes.PublishedMessages, scopes.NotDeletedForUser(userId)).Preload("Chat").Preload("Reactions").Preload("Voice").Preload("Circle").Preload("Attachments").Joins("JOIN chats ON messages.chat_id = chats.id").Where(
 		"messages.chat_id = ? AND ? = ANY(chats.members)", chatId, userId,
 	).Order(
 		"messages.created_at DESC NULLS LAST",
 	).Offset(offset).Limit(limit).Find(&dbMessages)
 
-	var messagesModels []messages.Message
-	for _, dbMessage := range dbMessages {
-		messagesModels = append(messagesModels, DbMessageToModel(dbMessage))
-	}
-
 	return utils.NewOffsetResponse(
 		offset,
 		limit,
 		total,
-		messagesModels,
+		adapter.dbMessagesToModels(dbMessages, userId),
 	)
 }
 
 func (adapter MessagesAdapter) getMessageOffsetById(chatId int, userId int, messageId int) int {
 	var offset int64
 
-	adapter.db.Model(&Message{}).Joins("JOIN chats ON messages.chat_id = chats.id").Where(
-		"messages.chat_id = ? AND ? = ANY(chats.members) AND messages.id >= ?", chatId, userId, messageId,
-	).Order("messages.created_at DESC NULLS LAST").Count(&offset)
+	adapter.db.Model(&Message{}).Scopes(scopes.PublishedMessages, scopes.NotDeletedForUser(userId)).Joins("JOIN chats ON messages.chat_id = chats.id").Where(
+		"messages.chat_id = ? AND ? = ANY(chats.members) AND messages.created_at >= (SELECT created_at FROM messages WHERE id = ?)", chatId, userId, messageId,
+	).Count(&offset)
 
 	return int(offset)
 }
@@ -433,21 +1378,140 @@ func (adapter MessagesAdapter) GetChatCursorAllForUser(chatId int, userId int, m
 	return adapter.GetChatAllForUser(chatId, userId, startOffset, aroundOffset*2)
 }
 
+func (adapter MessagesAdapter) getThreadAllForUserTotal(rootMessageId int, userId int) int {
+	var count int64
+
+	adapter.db.Model(&Message{}).Scopes(scopes.PublishedMessages, scopes.NotDeletedForUser(userId)).Joins("JOIN chats ON messages.chat_id = chats.id").Where(
+		"messages.thread_root_id = ? AND ? = ANY(chats.members)", rootMessageId, userId,
+	).Count(&count)
+
+	return int(count)
+}
+
+func (adapter MessagesAdapter) GetThreadAllForUser(rootMessageId int, userId int, offset int, limit int) utils.OffsetResponse[messages.Message] {
+	var dbMessages []Message
+
+	total := adapter.getThreadAllForUserTotal(rootMessageId, userId)
+
+	adapter.db.Scopes(scopes.PublishedMessages, scopes.NotDeletedForUser(userId)).Preload("Chat").Preload("Reactions").Preload("Voice").Preload("Circle").Preload("Attachments").Joins("JOIN chats ON messages.chat_id = chats.id").Where(
+		"messages.thread_root_id = ? AND ? = ANY(chats.members)", rootMessageId, userId,
+	).Order(
+		"messages.created_at DESC NULLS LAST",
+	).Offset(offset).Limit(limit).Find(&dbMessages)
+
+	return utils.NewOffsetResponse(
+		offset,
+		limit,
+		total,
+		adapter.dbMessagesToModels(dbMessages, userId),
+	)
+}
+
+type messageSearchRow struct {
+	Id      uint
+	Snippet string
+}
+
+// ts_headline marks matches with control characters that are stripped from
+// the content beforehand, so the snippet can be HTML escaped before the marks
+// are turned into <b> tags.
+const (
+	searchHighlightStart = "\x02"
+	searchHighlightStop  = "\x03"
+)
+
+var searchHighlightReplacer = strings.NewReplacer(searchHighlightStart, "<b>", searchHighlightStop, "</b>")
+
+func searchSnippetToHtml(snippet string) string {
+	return searchHighlightReplacer.Replace(html.EscapeString(snippet))
+}
+
+func (adapter MessagesAdapter) searchForUserQuery(filter messages.SearchMessagesFilter, userId int) *gorm.DB {
+	stmt := adapter.db.Model(&Message{}).Scopes(scopes.PublishedMessages, scopes.NotDeletedForUser(userId), scopes.MatchingSearchQuery(filter.GetQuery())).Joins("JOIN chats ON messages.chat_id = chats.id").Where(
+		"? = ANY(chats.members)", userId,
+	)
+
+	if chatId := filter.GetChatId(); chatId != nil {
+		stmt = stmt.Where("messages.chat_id = ?", *chatId)
+	}
+	if senderId := filter.GetSenderId(); senderId != nil {
+		stmt = stmt.Where("messages.sender_id = ?", *senderId)
+	}
+	stmt = stmt.Where("messages.type <> ?", string(messages.EventMessageType))
+	if messageType := filter.GetType(); messageType != nil {
+		stmt = stmt.Where("messages.type = ?", string(*messageType))
+	}
+	if from := filter.GetFrom(); from != nil {
+		stmt = stmt.Where("messages.created_at >= ?", *from)
+	}
+	if to := filter.GetTo(); to != nil {
+		stmt = stmt.Where("messages.created_at <= ?", *to)
+	}
+
+	return stmt
+}
+
+func (adapter MessagesAdapter) SearchForUser(filter messages.SearchMessagesFilter, userId int, offset int, limit int) utils.OffsetResponse[messages.MessageSearchResult] {
+	var total int64
+	adapter.searchForUserQuery(filter, userId).Count(&total)
+
+	var rows []messageSearchRow
+	adapter.searchForUserQuery(filter, userId).Select(
+		`messages.id AS id,
+		ts_headline('simple', translate(messages.content, ?, ''), websearch_to_tsquery('simple', ?), ?) AS snippet,
+		ts_rank(to_tsvector('simple', messages.content), websearch_to_tsquery('simple', ?)) AS rank`,
+		searchHighlightStart+searchHighlightStop,
+		filter.GetQuery(),
+		fmt.Sprintf(`StartSel="%s", StopSel="%s", MaxFragments=2, MaxWords=20, MinWords=5`, searchHighlightStart, searchHighlightStop),
+		filter.GetQuery(),
+	).Order("rank DESC").Order("messages.created_at DESC").Offset(offset).Limit(limit).Scan(&rows)
+
+	var messageIds []uint
+	for _, row := range rows {
+		messageIds = append(messageIds, row.Id)
+	}
+
+	var dbMessages []Message
+	if len(messageIds) > 0 {
+		adapter.db.Preload("Chat").Preload("Voice").Preload("Circle").Preload("Attachments").Preload("Reactions").Where(
+			"messages.id IN ?", messageIds,
+		).Find(&dbMessages)
+	}
+
+	foundMessages := make(map[int]messages.Message)
+	for _, message := range adapter.dbMessagesToModels(dbMessages, userId) {
+		foundMessages[message.GetId()] = message
+	}
+
+	var results []messages.MessageSearchResult
+	for _, row := range rows {
+		if message, ok := foundMessages[int(row.Id)]; ok {
+			results = append(results, messages.NewMessageSearchResult(message, searchSnippetToHtml(row.Snippet)))
+		}
+	}
+
+	return utils.NewOffsetResponse(
+		offset,
+		limit,
+		int(total),
+		results,
+	)
+}
+
 func (adapter MessagesAdapter) GetChatsLast(chatIds []int, userId int) []messages.Message {
-	var messages []messages.Message
+	var dbMessages []Message
 
 	for _, chatId := range chatIds {
 		var message Message
 
-		adapter.db.Preload("Chat").Preload("Voice").Preload("Circle").Preload("Attachments").Preload("Reactions").Joins("JOIN chats ON messages.chat_id = chats.id").Preload("Circle").Preload("Voice").Preload("Attachments").Where(
+		adapter.db.Scopes(scopes.PublishedMessages, scopes.NotDeletedForUser(userId)).Preload("Chat").Preload("Voice").Preload("Circle").Preload("Attachments").Preload("Reactions").Joins("JOIN chats ON messages.chat_id = chats.id").Preload("Circle").Preload("Voice").Preload("Attachments").Where(
 			"messages.chat_id = ? AND ? = ANY(chats.members)", chatId, userId,
 		).Order("messages.created_at DESC NULLS LAST").Limit(1).First(&message)
 
-		messageModel := DbMessageToModel(message)
-		messages = append(messages, messageModel)
+		dbMessages = append(dbMessages, message)
 	}
 
-	return messages
+	return adapter.dbMessagesToModels(dbMessages, userId)
 }
 
 func (adapter MessagesAdapter) GetById(messageId int) (*messages.Message, error) {
@@ -458,17 +1522,17 @@ func (adapter MessagesAdapter) GetById(messageId int) (*messages.Message, error)
 	).First(&dbMessage)
 
 	if result.Error != nil {
-		return nil, result.Error
+		return nil, notFoundAs(result.Error, messages.ErrMessageNotFound)
 	}
 
-	messageModel := DbMessageToModel(dbMessage)
+	messageModel := adapter.dbMessagesToModels([]Message{dbMessage}, noViewerId)[0]
 	return &messageModel, nil
 }
 
 func (adapter MessagesAdapter) GetByIdForUser(messageId int, userId int) (*messages.Message, error) {
 	var dbMessage Message
 
-	result := adapter.db.Preload("Chat").Preload("Voice").Preload("Circle").Preload("Attachments").Preload("Reactions").Joins("JOIN chats ON messages.chat_id = chats.id").Preload("Circle").Preload("Voice").Preload("Attachments").Where(
+	result := adapter.db.Scopes(scopes.PublishedMessages, scopes.NotDeletedForUser(userId)).Preload("Chat").Preload("Voice").Preload("Circle").Preload("Attachments").Preload("Reactions").Joins("JOIN chats ON messages.chat_id = chats.id").Preload("Circle").Preload("Voice").Preload("Attachments").Where(
 		"messages.id = ? AND ? = ANY(chats.members)", messageId, userId,
 	).First(&dbMessage)
 
@@ -476,23 +1540,18 @@ func (adapter MessagesAdapter) GetByIdForUser(messageId int, userId int) (*messa
 		return nil, result.Error
 	}
 
-	messageModel := DbMessageToModel(dbMessage)
+	messageModel := adapter.dbMessagesToModels([]Message{dbMessage}, userId)[0]
 	return &messageModel, nil
 }
 
 func (adapter MessagesAdapter) GetByIdsForUser(messageIds []int, userId int) []messages.Message {
 	var dbMessages []Message
 
-	adapter.db.Preload("Chat").Preload("Voice").Preload("Circle").Preload("Attachments").Preload("Reactions").Joins("JOIN chats ON messages.chat_id = chats.id").Preload("Circle").Preload("Voice").Preload("Attachments").Where(
+	adapter.db.Scopes(scopes.PublishedMessages, scopes.NotDeletedForUser(userId)).Preload("Chat").Preload("Voice").Preload("Circle").Preload("Attachments").Preload("Reactions").Joins("JOIN chats ON messages.chat_id = chats.id").Preload("Circle").Preload("Voice").Preload("Attachments").Where(
 		"messages.id IN ? AND ? = ANY(chats.members)", messageIds, userId,
 	).Find(&dbMessages)
 
-	var modelMessages []messages.Message
-	for _, dbMessage := range dbMessages {
-		modelMessages = append(modelMessages, DbMessageToModel(dbMessage))
-	}
-
-	return modelMessages
+	return adapter.dbMessagesToModels(dbMessages, userId)
 }
 
 func (adapter MessagesAdapter) getOrCreateReaction(reaction messages.MessageReaction) Reaction {
@@ -538,11 +1597,17 @@ func (adapter MessagesAdapter) Save(message messages.Message) (*messages.Message
 	}
 
 	dbMessage := ModelToDbMessage(message, voicePointer, circlePointer, attachments, reactions)
-	result := adapter.db.Save(&dbMessage)
+	result := adapter.db.Omit("ReadedBy").Save(&dbMessage)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 
+	if poll := message.GetPoll(); poll != nil {
+		if err := adapter.createPoll(dbMessage.ID, *poll); err != nil {
+			return nil, err
+		}
+	}
+
 	savedMessage, err := adapter.GetById(int(dbMessage.ID))
 	if err != nil {
 		return nil, err
@@ -551,10 +1616,192 @@ func (adapter MessagesAdapter) Save(message messages.Message) (*messages.Message
 	return savedMessage, nil
 }
 
+func (adapter MessagesAdapter) createPoll(messageId uint, poll messages.MessagePoll) error {
+	var count int64
+	adapter.db.Model(&Poll{}).Where("message_id = ?", messageId).Count(&count)
+	if count > 0 {
+		return nil
+	}
+
+	dbPoll := ModelToDbPoll(poll, messageId)
+	result := adapter.db.Create(&dbPoll)
+	return result.Error
+}
+
 func (adapter MessagesAdapter) Delete(message messages.Message) {
 	adapter.db.Delete(&Message{ID: uint(message.GetId())})
 }
 
+func (adapter MessagesAdapter) DeleteForUser(message messages.Message, userId int) error {
+	result := adapter.db.Model(&Message{}).Where(
+		"id = ? AND NOT (? = ANY(COALESCE(deleted_for, '{}')))", message.GetId(), userId,
+	).UpdateColumn("deleted_for", gorm.Expr("array_append(COALESCE(deleted_for, '{}'), ?)", userId))
+	return result.Error
+}
+
+func (adapter MessagesAdapter) Pin(message messages.Message, pinnedBy int) error {
+	chat := message.GetChat()
+	pinnedMessage := PinnedMessage{
+		ChatId:    uint(chat.GetId()),
+		MessageId: uint(message.GetId()),
+		PinnedBy:  uint(pinnedBy),
+	}
+
+	result := adapter.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&pinnedMessage)
+	return result.Error
+}
+
+func (adapter MessagesAdapter) Unpin(message messages.Message) error {
+	result := adapter.db.Unscoped().Where("message_id = ?", message.GetId()).Delete(&PinnedMessage{})
+	return result.Error
+}
+
+func (adapter MessagesAdapter) GetChatPinned(chatId int, userId int) []messages.Message {
+	var dbMessages []Message
+
+	adapter.db.Scopes(scopes.NotDeletedForUser(userId)).Preload("Chat").Preload("Reactions").Preload("Voice").Preload("Circle").Preload("Attachments").Joins(
+		"JOIN pinned_messages ON pinned_messages.message_id = messages.id",
+	).Where(
+		"pinned_messages.chat_id = ? AND pinned_messages.deleted_at IS NULL", chatId,
+	).Order("pinned_messages.created_at DESC").Find(&dbMessages)
+
+	return adapter.dbMessagesToModels(dbMessages, userId)
+}
+
+func (adapter MessagesAdapter) GetChatScheduledForUser(chatId int, userId int) []messages.Message {
+	var dbMessages []Message
+
+	adapter.db.Scopes(scopes.NotDeletedForUser(userId)).Preload("Chat").Preload("Reactions").Preload("Voice").Preload("Circle").Preload("Attachments").Joins("JOIN chats ON messages.chat_id = chats.id").Where(
+		"messages.chat_id = ? AND messages.sender_id = ? AND messages.send_at IS NOT NULL AND ? = ANY(chats.members)", chatId, userId, userId,
+	).Order("messages.send_at ASC").Find(&dbMessages)
+
+	return adapter.dbMessagesToModels(dbMessages, userId)
+}
+
+func (adapter MessagesAdapter) GetScheduledByIdForUser(messageId int, userId int) (*messages.Message, error) {
+	var dbMessage Message
+
+	result := adapter.db.Preload("Chat").Preload("Voice").Preload("Circle").Preload("Attachments").Preload("Reactions").Joins("JOIN chats ON messages.chat_id = chats.id").Where(
+		"messages.id = ? AND messages.sender_id = ? AND messages.send_at IS NOT NULL AND ? = ANY(chats.members)", messageId, userId, userId,
+	).First(&dbMessage)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	messageModel := adapter.dbMessagesToModels([]Message{dbMessage}, userId)[0]
+	return &messageModel, nil
+}
+
+func (adapter MessagesAdapter) GetAllScheduled() []messages.Message {
+	var dbMessages []Message
+
+	adapter.db.Preload("Chat").Preload("Voice").Preload("Circle").Preload("Attachments").Preload("Reactions").Where(
+		"messages.send_at IS NOT NULL",
+	).Order("messages.send_at ASC").Find(&dbMessages)
+
+	return adapter.dbMessagesToModels(dbMessages, noViewerId)
+}
+
+func (adapter MessagesAdapter) PublishScheduled(message messages.Message) (*messages.Message, error) {
+	result := adapter.db.Model(&Message{}).Where(
+		"id = ? AND send_at IS NOT NULL", message.GetId(),
+	).Updates(map[string]interface{}{"send_at": nil, "created_at": time.Now()})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return adapter.GetById(message.GetId())
+}
+
+func (adapter MessagesAdapter) SaveRevision(revision messages.MessageRevision) error {
+	var attachments []SavedFile
+	for _, attachment := range revision.GetAttachments() {
+		attachments = append(attachments, GetOrCreateFile(&attachment, adapter.db))
+	}
+
+	dbRevision := ModelToDbMessageRevision(revision, attachments)
+	result := adapter.db.Create(&dbRevision)
+	return result.Error
+}
+
+func (adapter MessagesAdapter) GetRevisions(message messages.Message) []messages.MessageRevision {
+	var dbRevisions []MessageRevision
+	adapter.db.Preload("Attachments").Where("message_id = ?", message.GetId()).Order("created_at ASC").Find(&dbRevisions)
+
+	var revisions []messages.MessageRevision
+	for _, dbRevision := range dbRevisions {
+		revisions = append(revisions, DbMessageRevisionToModel(dbRevision))
+	}
+
+	return revisions
+}
+
+func (adapter MessagesAdapter) VotePoll(message messages.Message, userId int, optionIds []int) error {
+	return adapter.db.Transaction(func(tx *gorm.DB) error {
+		var poll Poll
+		if result := tx.Where("message_id = ?", message.GetId()).First(&poll); result.Error != nil {
+			return result.Error
+		}
+
+		if result := tx.Unscoped().Where("poll_id = ? AND user_id = ?", poll.ID, userId).Delete(&PollVote{}); result.Error != nil {
+			return result.Error
+		}
+
+		var votes []PollVote
+		for _, optionId := range optionIds {
+			votes = append(votes, PollVote{
+				PollId:       poll.ID,
+				PollOptionId: uint(optionId),
+				UserId:       uint(userId),
+			})
+		}
+
+		return tx.Create(&votes).Error
+	})
+}
+
+func (adapter MessagesAdapter) RetractPollVote(message messages.Message, userId int) error {
+	result := adapter.db.Unscoped().Where(
+		"poll_id IN (SELECT id FROM polls WHERE message_id = ?) AND user_id = ?", message.GetId(), userId,
+	).Delete(&PollVote{})
+	return result.Error
+}
+
+func (adapter MessagesAdapter) ClosePoll(message messages.Message, closedAt time.Time) error {
+	result := adapter.db.Model(&Poll{}).Where(
+		"message_id = ? AND closed_at IS NULL", message.GetId(),
+	).Update("closed_at", closedAt)
+	return result.Error
+}
+
+func (adapter MessagesAdapter) GetReadPointer(chatId int, userId int) (*messages.ChatReadPointer, error) {
+	var dbPointer ChatReadPointer
+	result := adapter.db.Where("chat_id = ? AND user_id = ?", chatId, userId).First(&dbPointer)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	pointer := DbChatReadPointerToModel(dbPointer)
+	return &pointer, nil
+}
+
+func (adapter MessagesAdapter) SaveReadPointer(pointer messages.ChatReadPointer) error {
+	dbPointer := ModelToDbChatReadPointer(pointer)
+	result := adapter.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chat_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_read_message_id", "last_read_at", "updated_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "chat_read_pointers.last_read_at < EXCLUDED.last_read_at"},
+		}},
+	}).Create(&dbPointer)
+	return result.Error
+}
+
 func NewChatsAdapter(db gorm.DB) chats.ChatsPort {
 	return ChatsLoggingAdapter{adapter: ChatsAdapter{db: db}}
 }
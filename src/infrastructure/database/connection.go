@@ -1,8 +1,10 @@
 package database
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -17,4 +19,91 @@ func GetConnection() *gorm.DB {
 	return db
 }
 
+// Migrate brings the schema up to date. It runs before the consumers and the
+// outbox relay start, so they never write to a schema without its indexes.
+func Migrate(db *gorm.DB) {
+	db.AutoMigrate(&Chat{}, &Message{}, &SavedFile{}, Reaction{}, &PinnedMessage{}, &MessageRevision{}, &Poll{}, &PollOption{}, &PollVote{}, &ChatReadPointer{}, &ChatAdminRights{}, &ChatPermissions{}, &ChatInviteLink{}, &JoinRequest{}, &ChatUserSettings{}, &ChatFolder{}, &ChatBan{}, &OutboxEvent{})
+	MigrateSearchIndexes(db)
+	MigrateSavedMessagesIndex(db)
+	MigrateChatUserSettings(db)
+}
+
+func MigrateSearchIndexes(db *gorm.DB) {
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_messages_content_search ON messages USING GIN (to_tsvector('simple', content))")
+}
+
+// MigrateSavedMessagesIndex guarantees a single saved messages chat per user
+// even when concurrent deliveries of the same user_created event race.
+func MigrateSavedMessagesIndex(db *gorm.DB) {
+	result := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_chats_saved_messages_owner ON chats (owner_id) WHERE type = 'saved_messages' AND deleted_at IS NULL")
+	if result.Error != nil {
+		log.Printf("error creating saved messages chat index: %v", result.Error)
+	}
+}
+
+// MigrateChatUserSettings moves the legacy global chats.is_archived flag into
+// per-user settings for every member and drops the column afterwards.
+func MigrateChatUserSettings(db *gorm.DB) {
+	if !db.Migrator().HasColumn(&Chat{}, "is_archived") {
+		return
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(
+			`INSERT INTO chat_user_settings (chat_id, user_id, is_archived, created_at, updated_at)
+			SELECT chats.id, member, true, now(), now()
+			FROM chats, unnest(chats.members) AS member
+			WHERE chats.is_archived AND chats.deleted_at IS NULL
+			ON CONFLICT (chat_id, user_id) DO UPDATE SET is_archived = true`,
+		)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Migrator().DropColumn(&Chat{}, "is_archived")
+	})
+	if err != nil {
+		panic(errors.Join(fmt.Errorf("error migrating chat user settings"), err))
+	}
+}
+
+type afterCommitKey struct{}
+
+type afterCommitHooks struct {
+	hooks []func()
+}
+
+// Transaction runs fn inside a single database transaction. Adapters built from
+// the passed connection commit or roll back together. Hooks registered with
+// AfterCommit run only once the transaction has been committed.
+func Transaction(fn func(tx gorm.DB) error) error {
+	hooks := &afterCommitHooks{}
+	ctx := context.WithValue(context.Background(), afterCommitKey{}, hooks)
+	err := DatabaseConnection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(*tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range hooks.hooks {
+		hook()
+	}
+
+	return nil
+}
+
+// AfterCommit defers hook until the Transaction db belongs to is committed and
+// drops it on rollback. Outside of Transaction the hook runs immediately.
+func AfterCommit(db gorm.DB, hook func()) {
+	if db.Statement != nil && db.Statement.Context != nil {
+		if hooks, ok := db.Statement.Context.Value(afterCommitKey{}).(*afterCommitHooks); ok {
+			hooks.hooks = append(hooks.hooks, hook)
+			return
+		}
+	}
+
+	hook()
+}
+
 var DatabaseConnection *gorm.DB = GetConnection()
@@ -65,16 +65,22 @@ func DbChatToModel(chat Chat) chats.Chat {
 		admins = append(admins, int(admin))
 	}
 
-	return chats.NewChat(
+	chatModel := chats.NewChat(
 		int(chat.ID),
 		avatar,
 		chat.Title,
 		chats.ChatTypes(chat.Type),
 		members,
-		chat.IsArchived,
+		false,
 		int(chat.OwnerId),
 		admins,
 	)
+	chatModel.SetSlug(chat.Slug)
+	chatModel.SetDescription(chat.Description)
+	chatModel.SetRules(chat.Rules)
+	chatModel.SetSlowModeInterval(time.Duration(chat.SlowModeInterval) * time.Second)
+	chatModel.SetIsUserDeleted(chat.IsUserDeleted)
+	return chatModel
 }
 
 func ModelToDbChat(chat chats.Chat, avatar SavedFile) Chat {
@@ -95,15 +101,235 @@ func ModelToDbChat(chat chats.Chat, avatar SavedFile) Chat {
 	}
 
 	return Chat{
-		ID:         uint(chat.GetId()),
-		AvatarId:   avatarId,
-		Avatar:     avatar,
-		Title:      chat.GetTitle(),
-		Type:       string(chat.GetType()),
-		Members:    members,
-		IsArchived: chat.GetIsArchived(),
-		OwnerId:    uint(chat.GetOwnerId()),
-		Admins:     admins,
+		ID:          uint(chat.GetId()),
+		AvatarId:    avatarId,
+		Avatar:      avatar,
+		Title:       chat.GetTitle(),
+		Type:        string(chat.GetType()),
+		Slug:        chat.GetSlug(),
+		Description: chat.GetDescription(),
+		Rules:       chat.GetRules(),
+		Members:     members,
+		OwnerId:     uint(chat.GetOwnerId()),
+		Admins:      admins,
+
+		SlowModeInterval: int(chat.GetSlowModeInterval().Seconds()),
+		IsUserDeleted:    chat.GetIsUserDeleted(),
+	}
+}
+
+func DbChatAdminRightsToModel(rights ChatAdminRights) chats.AdminRights {
+	return chats.NewAdminRights(
+		rights.CanAddMembers,
+		rights.CanRemoveMembers,
+		rights.CanEditInfo,
+		rights.CanPinMessages,
+		rights.CanDeleteMessages,
+		rights.CanManageAdmins,
+	)
+}
+
+func ModelToDbChatAdminRights(rights chats.AdminRights, chatId uint, userId uint) ChatAdminRights {
+	return ChatAdminRights{
+		ChatId:            chatId,
+		UserId:            userId,
+		CanAddMembers:     rights.GetCanAddMembers(),
+		CanRemoveMembers:  rights.GetCanRemoveMembers(),
+		CanEditInfo:       rights.GetCanEditInfo(),
+		CanPinMessages:    rights.GetCanPinMessages(),
+		CanDeleteMessages: rights.GetCanDeleteMessages(),
+		CanManageAdmins:   rights.GetCanManageAdmins(),
+	}
+}
+
+func DbChatPermissionsToModel(permissions ChatPermissions) chats.MemberPermissions {
+	return chats.NewMemberPermissions(
+		permissions.CanSendMessages,
+		permissions.CanSendMedia,
+		permissions.CanAddMembers,
+		permissions.CanPinMessages,
+	)
+}
+
+func ModelToDbChatPermissions(permissions chats.MemberPermissions, chatId uint) ChatPermissions {
+	return ChatPermissions{
+		ChatId:          chatId,
+		CanSendMessages: permissions.GetCanSendMessages(),
+		CanSendMedia:    permissions.GetCanSendMedia(),
+		CanAddMembers:   permissions.GetCanAddMembers(),
+		CanPinMessages:  permissions.GetCanPinMessages(),
+	}
+}
+
+func DbChatInviteLinkToModel(link ChatInviteLink) chats.ChatInviteLink {
+	return chats.NewChatInviteLink(
+		int(link.ID),
+		int(link.ChatId),
+		link.Code,
+		int(link.CreatedBy),
+		link.ExpiresAt,
+		link.UsageLimit,
+		link.UsageCount,
+		link.RequiresApproval,
+		link.RevokedAt,
+		link.CreatedAt,
+	)
+}
+
+func ModelToDbChatInviteLink(link chats.ChatInviteLink) ChatInviteLink {
+	return ChatInviteLink{
+		ID:               uint(link.GetId()),
+		ChatId:           uint(link.GetChatId()),
+		Code:             link.GetCode(),
+		CreatedBy:        uint(link.GetCreatedBy()),
+		ExpiresAt:        link.GetExpiresAt(),
+		UsageLimit:       link.GetUsageLimit(),
+		UsageCount:       link.GetUsageCount(),
+		RequiresApproval: link.GetRequiresApproval(),
+		RevokedAt:        link.GetRevokedAt(),
+		CreatedAt:        link.GetCreatedAt(),
+	}
+}
+
+func DbJoinRequestToModel(request JoinRequest) chats.ChatJoinRequest {
+	var inviteLinkId *int
+	if request.InviteLinkId != nil {
+		id := int(*request.InviteLinkId)
+		inviteLinkId = &id
+	}
+
+	var resolvedBy *int
+	if request.ResolvedBy != nil {
+		userId := int(*request.ResolvedBy)
+		resolvedBy = &userId
+	}
+
+	return chats.NewChatJoinRequest(
+		int(request.ID),
+		int(request.ChatId),
+		int(request.UserId),
+		inviteLinkId,
+		chats.JoinRequestStatuses(request.Status),
+		resolvedBy,
+		request.CreatedAt,
+		request.ResolvedAt,
+	)
+}
+
+func ModelToDbJoinRequest(request chats.ChatJoinRequest) JoinRequest {
+	var inviteLinkId *uint
+	if id := request.GetInviteLinkId(); id != nil {
+		linkId := uint(*id)
+		inviteLinkId = &linkId
+	}
+
+	var resolvedBy *uint
+	if userId := request.GetResolvedBy(); userId != nil {
+		resolvedByUserId := uint(*userId)
+		resolvedBy = &resolvedByUserId
+	}
+
+	return JoinRequest{
+		ID:           uint(request.GetId()),
+		ChatId:       uint(request.GetChatId()),
+		UserId:       uint(request.GetUserId()),
+		InviteLinkId: inviteLinkId,
+		Status:       string(request.GetStatus()),
+		ResolvedBy:   resolvedBy,
+		CreatedAt:    request.GetCreatedAt(),
+		ResolvedAt:   request.GetResolvedAt(),
+	}
+}
+
+func DbChatBanToModel(ban ChatBan) chats.ChatBan {
+	return chats.NewChatBan(
+		int(ban.ID),
+		int(ban.ChatId),
+		int(ban.UserId),
+		int(ban.BannedBy),
+		ban.ExpiresAt,
+		ban.CreatedAt,
+	)
+}
+
+func ModelToDbChatBan(ban chats.ChatBan) ChatBan {
+	return ChatBan{
+		ID:        uint(ban.GetId()),
+		ChatId:    uint(ban.GetChatId()),
+		UserId:    uint(ban.GetUserId()),
+		BannedBy:  uint(ban.GetBannedBy()),
+		ExpiresAt: ban.GetExpiresAt(),
+		CreatedAt: ban.GetCreatedAt(),
+	}
+}
+
+func DbChatFolderToModel(folder ChatFolder) chats.ChatFolder {
+	var chatIds []int
+	for _, chatId := range folder.ChatIds {
+		chatIds = append(chatIds, int(chatId))
+	}
+
+	var chatTypes []chats.ChatTypes
+	for _, chatType := range folder.ChatTypes {
+		chatTypes = append(chatTypes, chats.ChatTypes(chatType))
+	}
+
+	return chats.NewChatFolder(
+		int(folder.ID),
+		int(folder.UserId),
+		folder.Title,
+		folder.Position,
+		chatIds,
+		chatTypes,
+		folder.UnreadOnly,
+		folder.MutedOnly,
+	)
+}
+
+func ModelToDbChatFolder(folder chats.ChatFolder) ChatFolder {
+	chatIds := pq.Int64Array{}
+	for _, chatId := range folder.GetChatIds() {
+		chatIds = append(chatIds, int64(chatId))
+	}
+
+	chatTypes := pq.StringArray{}
+	for _, chatType := range folder.GetChatTypes() {
+		chatTypes = append(chatTypes, string(chatType))
+	}
+
+	return ChatFolder{
+		ID:         uint(folder.GetId()),
+		UserId:     uint(folder.GetUserId()),
+		Title:      folder.GetTitle(),
+		Position:   folder.GetPosition(),
+		ChatIds:    chatIds,
+		ChatTypes:  chatTypes,
+		UnreadOnly: folder.GetUnreadOnly(),
+		MutedOnly:  folder.GetMutedOnly(),
+	}
+}
+
+func DbChatUserSettingsToModel(settings ChatUserSettings) chats.ChatUserSettings {
+	return chats.NewChatUserSettings(
+		int(settings.ChatId),
+		int(settings.UserId),
+		settings.IsArchived,
+		settings.IsPinned,
+		settings.PinnedAt,
+		settings.IsMuted,
+		settings.MutedUntil,
+	)
+}
+
+func ModelToDbChatUserSettings(settings chats.ChatUserSettings) ChatUserSettings {
+	return ChatUserSettings{
+		ChatId:     uint(settings.GetChatId()),
+		UserId:     uint(settings.GetUserId()),
+		IsArchived: settings.GetIsArchived(),
+		IsPinned:   settings.GetIsPinned(),
+		PinnedAt:   settings.GetPinnedAt(),
+		IsMuted:    settings.GetIsMuted(),
+		MutedUntil: settings.GetMutedUntil(),
 	}
 }
 
@@ -142,6 +368,22 @@ func DbMessageToModel(message Message) messages.Message {
 		replyToId = &replyToIdInt
 	}
 
+	var threadRootId *int
+	if message.ThreadRootID != 0 {
+		threadRootIdInt := int(message.ThreadRootID)
+		threadRootId = &threadRootIdInt
+	}
+
+	var forwardedFrom *messages.MessageForwardedFrom
+	if message.ForwardedFromMessageID != 0 {
+		original := messages.NewMessageForwardedFrom(
+			int(message.ForwardedFromMessageID),
+			int(message.ForwardedFromSenderID),
+			int(message.ForwardedFromChatID),
+		)
+		forwardedFrom = &original
+	}
+
 	var mentioned []int
 	for _, ment := range message.Mentioned {
 		mentioned = append(mentioned, int(ment))
@@ -163,7 +405,7 @@ func DbMessageToModel(message Message) messages.Message {
 		reactions = append(reactions, reactionModel)
 	}
 
-	return messages.NewMessage(
+	messageModel := messages.NewMessage(
 		int(message.ID),
 		int(message.SenderId),
 		DbChatToModel(message.Chat),
@@ -179,6 +421,11 @@ func DbMessageToModel(message Message) messages.Message {
 		deletedFor,
 		&message.CreatedAt,
 	)
+	messageModel.SetThreadRootId(threadRootId)
+	messageModel.SetForwardedFrom(forwardedFrom)
+	messageModel.SetSendAt(message.SendAt)
+	messageModel.SetEditedAt(message.EditedAt)
+	return messageModel
 }
 
 func ModelToDbMessage(message messages.Message, voice *SavedFile, circle *SavedFile, attachments []SavedFile, reactions []Reaction) Message {
@@ -194,6 +441,16 @@ func ModelToDbMessage(message messages.Message, voice *SavedFile, circle *SavedF
 		replyToId = *messageReplyToId
 	}
 
+	var threadRootId int
+	if messageThreadRootId := message.GetThreadRootId(); messageThreadRootId != nil {
+		threadRootId = *messageThreadRootId
+	}
+
+	var forwardedFrom messages.MessageForwardedFrom
+	if messageForwardedFrom := message.GetForwardedFrom(); messageForwardedFrom != nil {
+		forwardedFrom = *messageForwardedFrom
+	}
+
 	var mentioned pq.Int32Array
 	for _, ment := range message.GetMentioned() {
 		mentioned = append(mentioned, int32(ment))
@@ -204,24 +461,134 @@ func ModelToDbMessage(message messages.Message, voice *SavedFile, circle *SavedF
 		readedBy = append(readedBy, int32(reader))
 	}
 
+	var deletedFor pq.Int32Array
+	for _, user := range message.GetDeletedForIds() {
+		deletedFor = append(deletedFor, int32(user))
+	}
+
 	var createdAt time.Time
 	if dt := message.GetCreatedAt(); dt != nil {
 		createdAt = *dt
 	}
 
 	return Message{
-		ID:          uint(message.GetId()),
-		SenderId:    uint(message.GetSenderId()),
-		ChatId:      uint(chat.GetId()),
-		Type:        string(message.GetType()),
+		ID:                     uint(message.GetId()),
+		SenderId:               uint(message.GetSenderId()),
+		ChatId:                 uint(chat.GetId()),
+		Type:                   string(message.GetType()),
+		Content:                content,
+		Voice:                  voice,
+		Circle:                 circle,
+		Attachments:            attachments,
+		ReplyToID:              uint(replyToId),
+		ThreadRootID:           uint(threadRootId),
+		ForwardedFromMessageID: uint(forwardedFrom.GetMessageId()),
+		ForwardedFromSenderID:  uint(forwardedFrom.GetSenderId()),
+		ForwardedFromChatID:    uint(forwardedFrom.GetChatId()),
+		Mentioned:              mentioned,
+		ReadedBy:               readedBy,
+		Reactions:              reactions,
+		DeletedFor:             deletedFor,
+		SendAt:                 message.GetSendAt(),
+		EditedAt:               message.GetEditedAt(),
+		CreatedAt:              createdAt,
+	}
+}
+
+func DbMessageRevisionToModel(revision MessageRevision) messages.MessageRevision {
+	var attachments []files.SavedFile
+	for _, attachment := range revision.Attachments {
+		attachments = append(attachments, DbSavedFileToModel(attachment))
+	}
+
+	var mentioned []int
+	for _, ment := range revision.Mentioned {
+		mentioned = append(mentioned, int(ment))
+	}
+
+	return messages.NewMessageRevision(
+		int(revision.MessageId),
+		&revision.Content,
+		attachments,
+		mentioned,
+		revision.CreatedAt,
+	)
+}
+
+func ModelToDbMessageRevision(revision messages.MessageRevision, attachments []SavedFile) MessageRevision {
+	var content string
+	if revisionContent := revision.GetContent(); revisionContent != nil {
+		content = *revisionContent
+	}
+
+	var mentioned pq.Int32Array
+	for _, ment := range revision.GetMentioned() {
+		mentioned = append(mentioned, int32(ment))
+	}
+
+	return MessageRevision{
+		MessageId:   uint(revision.GetMessageId()),
 		Content:     content,
-		Voice:       voice,
-		Circle:      circle,
 		Attachments: attachments,
-		ReplyToID:   uint(replyToId),
 		Mentioned:   mentioned,
-		ReadedBy:    readedBy,
-		Reactions:   reactions,
-		CreatedAt:   createdAt,
+		CreatedAt:   revision.GetCreatedAt(),
+	}
+}
+
+func DbPollToModel(poll Poll) messages.MessagePoll {
+	var options []messages.MessagePollOption
+	for _, option := range poll.Options {
+		var voters []int
+		for _, vote := range option.Votes {
+			voters = append(voters, int(vote.UserId))
+		}
+
+		options = append(options, messages.NewMessagePollOption(int(option.ID), option.Content, voters))
+	}
+
+	return messages.NewMessagePoll(
+		options,
+		poll.MultipleChoice,
+		poll.Anonymous,
+		poll.ClosesAt,
+		poll.ClosedAt,
+	)
+}
+
+func ModelToDbPoll(poll messages.MessagePoll, messageId uint) Poll {
+	var options []PollOption
+	for position, option := range poll.GetOptions() {
+		options = append(options, PollOption{
+			ID:       uint(option.GetId()),
+			Position: position,
+			Content:  option.GetContent(),
+		})
+	}
+
+	return Poll{
+		MessageId:      messageId,
+		MultipleChoice: poll.GetMultipleChoice(),
+		Anonymous:      poll.GetAnonymous(),
+		ClosesAt:       poll.GetClosesAt(),
+		ClosedAt:       poll.GetClosedAt(),
+		Options:        options,
+	}
+}
+
+func DbChatReadPointerToModel(pointer ChatReadPointer) messages.ChatReadPointer {
+	return messages.NewChatReadPointer(
+		int(pointer.ChatId),
+		int(pointer.UserId),
+		int(pointer.LastReadMessageId),
+		pointer.LastReadAt,
+	)
+}
+
+func ModelToDbChatReadPointer(pointer messages.ChatReadPointer) ChatReadPointer {
+	return ChatReadPointer{
+		ChatId:            uint(pointer.GetChatId()),
+		UserId:            uint(pointer.GetUserId()),
+		LastReadMessageId: uint(pointer.GetMessageId()),
+		LastReadAt:        pointer.GetReadAt(),
 	}
 }
@@ -18,38 +18,206 @@ type SavedFile struct {
 
 type Chat struct {
 	*gorm.Model
-	ID         uint          `gorm:"primaryKey" json:"id"`
-	AvatarId   *uint         `json:"avatar_id"`
-	Avatar     SavedFile     `gorm:"foreignKey:AvatarId" json:"avatar"`
-	Title      string        `json:"title"`
-	Type       string        `json:"type"`
-	Members    pq.Int64Array `gorm:"type:integer[]" json:"members"`
-	IsArchived bool          `gorm:"default:false" json:"is_archived"`
-	OwnerId    uint          `json:"owner_id"`
-	Admins     pq.Int64Array `gorm:"type:integer[]" json:"admins"`
+	ID          uint          `gorm:"primaryKey" json:"id"`
+	AvatarId    *uint         `json:"avatar_id"`
+	Avatar      SavedFile     `gorm:"foreignKey:AvatarId" json:"avatar"`
+	Title       string        `json:"title"`
+	Type        string        `json:"type"`
+	Slug        *string       `gorm:"uniqueIndex" json:"slug"`
+	Description *string       `json:"description"`
+	Rules       *string       `gorm:"type:text" json:"rules"`
+	Members     pq.Int64Array `gorm:"type:integer[]" json:"members"`
+	OwnerId     uint          `json:"owner_id"`
+	Admins      pq.Int64Array `gorm:"type:integer[]" json:"admins"`
+
+	SlowModeInterval int  `gorm:"default:0" json:"slow_mode_interval"`
+	IsUserDeleted    bool `gorm:"default:false" json:"is_user_deleted"`
+}
+
+type ChatFolder struct {
+	*gorm.Model
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	UserId     uint           `gorm:"index" json:"user_id"`
+	Title      string         `json:"title"`
+	Position   int            `json:"position"`
+	ChatIds    pq.Int64Array  `gorm:"type:integer[]" json:"chat_ids"`
+	ChatTypes  pq.StringArray `gorm:"type:varchar[]" json:"chat_types"`
+	UnreadOnly bool           `gorm:"default:false" json:"unread_only"`
+	MutedOnly  bool           `gorm:"default:false" json:"muted_only"`
+}
+
+type ChatUserSettings struct {
+	*gorm.Model
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	ChatId     uint       `gorm:"uniqueIndex:idx_chat_user_settings_chat_user" json:"chat_id"`
+	UserId     uint       `gorm:"uniqueIndex:idx_chat_user_settings_chat_user" json:"user_id"`
+	IsArchived bool       `gorm:"default:false" json:"is_archived"`
+	IsPinned   bool       `gorm:"default:false" json:"is_pinned"`
+	PinnedAt   *time.Time `json:"pinned_at"`
+	IsMuted    bool       `gorm:"default:false" json:"is_muted"`
+	MutedUntil *time.Time `json:"muted_until"`
+}
+
+type ChatAdminRights struct {
+	*gorm.Model
+	ID                uint `gorm:"primaryKey" json:"id"`
+	ChatId            uint `gorm:"uniqueIndex:idx_chat_admin_rights_chat_user" json:"chat_id"`
+	UserId            uint `gorm:"uniqueIndex:idx_chat_admin_rights_chat_user" json:"user_id"`
+	CanAddMembers     bool `json:"can_add_members"`
+	CanRemoveMembers  bool `json:"can_remove_members"`
+	CanEditInfo       bool `json:"can_edit_info"`
+	CanPinMessages    bool `json:"can_pin_messages"`
+	CanDeleteMessages bool `json:"can_delete_messages"`
+	CanManageAdmins   bool `json:"can_manage_admins"`
+}
+
+type ChatPermissions struct {
+	*gorm.Model
+	ID              uint `gorm:"primaryKey" json:"id"`
+	ChatId          uint `gorm:"uniqueIndex" json:"chat_id"`
+	CanSendMessages bool `json:"can_send_messages"`
+	CanSendMedia    bool `json:"can_send_media"`
+	CanAddMembers   bool `json:"can_add_members"`
+	CanPinMessages  bool `json:"can_pin_messages"`
+}
+
+type ChatInviteLink struct {
+	*gorm.Model
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	ChatId           uint       `gorm:"index" json:"chat_id"`
+	Code             string     `gorm:"uniqueIndex" json:"code"`
+	CreatedBy        uint       `json:"created_by"`
+	ExpiresAt        *time.Time `json:"expires_at"`
+	UsageLimit       *int       `json:"usage_limit"`
+	UsageCount       int        `gorm:"default:0" json:"usage_count"`
+	RequiresApproval bool       `gorm:"default:false" json:"requires_approval"`
+	RevokedAt        *time.Time `json:"revoked_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+type JoinRequest struct {
+	*gorm.Model
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	ChatId       uint       `gorm:"index" json:"chat_id"`
+	UserId       uint       `gorm:"index" json:"user_id"`
+	InviteLinkId *uint      `json:"invite_link_id"`
+	Status       string     `gorm:"default:pending" json:"status"`
+	ResolvedBy   *uint      `json:"resolved_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ResolvedAt   *time.Time `json:"resolved_at"`
+}
+
+type ChatBan struct {
+	*gorm.Model
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	ChatId    uint       `gorm:"uniqueIndex:idx_chat_bans_chat_user" json:"chat_id"`
+	UserId    uint       `gorm:"uniqueIndex:idx_chat_bans_chat_user" json:"user_id"`
+	BannedBy  uint       `json:"banned_by"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// OutboxEvent is a serialized system event waiting to be relayed to the
+// events exchange. It is written in the same transaction as the change that
+// produced it, so an event is never lost or published for a rolled back change.
+type OutboxEvent struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	EventId       string     `gorm:"uniqueIndex" json:"event_id"`
+	OrderingKey   string     `gorm:"index" json:"ordering_key"`
+	EventType     string     `json:"event_type"`
+	Payload       string     `gorm:"type:text" json:"payload"`
+	Attempts      int        `gorm:"default:0" json:"attempts"`
+	LastError     *string    `gorm:"type:text" json:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	PublishedAt   *time.Time `gorm:"index" json:"published_at"`
+	DeadAt        *time.Time `gorm:"index" json:"dead_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type PinnedMessage struct {
+	*gorm.Model
+	ID        uint    `gorm:"primaryKey" json:"id"`
+	ChatId    uint    `gorm:"uniqueIndex:idx_pinned_messages_chat_message" json:"chat_id"`
+	MessageId uint    `gorm:"uniqueIndex:idx_pinned_messages_chat_message" json:"message_id"`
+	Message   Message `gorm:"foreignKey:MessageId"`
+	PinnedBy  uint    `json:"pinned_by"`
+	CreatedAt time.Time
 }
 
 type Message struct {
+	*gorm.Model
+	ID                     uint          `gorm:"primaryKey" json:"id"`
+	SenderId               uint          `json:"sender_id"`
+	ChatId                 uint          `json:"chat_id"`
+	Chat                   Chat          `gorm:"foreignKey:ChatId"`
+	Type                   string        `json:"type"`
+	Content                string        `json:"content"`
+	VoiceId                *int          `json:"voice_id"`
+	Voice                  *SavedFile    `gorm:"foreignKey:VoiceId" json:"voice"`
+	CircleId               *int          `json:"circle_id"`
+	Circle                 *SavedFile    `gorm:"foreignKey:CircleId" json:"circle"`
+	Attachments            []SavedFile   `gorm:"many2many:message_attachments" json:"attachments"`
+	ReplyToID              uint          `json:"reply_to_id"`
+	ThreadRootID           uint          `gorm:"index" json:"thread_root_id"`
+	ForwardedFromMessageID uint          `json:"forwarded_from_message_id"`
+	ForwardedFromSenderID  uint          `json:"forwarded_from_sender_id"`
+	ForwardedFromChatID    uint          `json:"forwarded_from_chat_id"`
+	Mentioned              pq.Int32Array `gorm:"type:integer[]" json:"mentioned"`
+	ReadedBy               pq.Int32Array `gorm:"type:integer[]" json:"readed_by"`
+	Reactions              []Reaction    `gorm:"foreignKey:MessageId" json:"reactions"`
+	DeletedFor             pq.Int32Array `gorm:"type:integer[]" json:"deleted_for"`
+	SendAt                 *time.Time    `gorm:"index" json:"send_at"`
+	EditedAt               *time.Time    `json:"edited_at"`
+	CreatedAt              time.Time
+}
+
+type ChatReadPointer struct {
+	*gorm.Model
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	ChatId            uint      `gorm:"uniqueIndex:idx_chat_read_pointers_chat_user" json:"chat_id"`
+	UserId            uint      `gorm:"uniqueIndex:idx_chat_read_pointers_chat_user" json:"user_id"`
+	LastReadMessageId uint      `json:"last_read_message_id"`
+	LastReadAt        time.Time `json:"last_read_at"`
+}
+
+type MessageRevision struct {
 	*gorm.Model
 	ID          uint          `gorm:"primaryKey" json:"id"`
-	SenderId    uint          `json:"sender_id"`
-	ChatId      uint          `json:"chat_id"`
-	Chat        Chat          `gorm:"foreignKey:ChatId"`
-	Type        string        `json:"type"`
+	MessageId   uint          `gorm:"index" json:"message_id"`
 	Content     string        `json:"content"`
-	VoiceId     *int          `json:"voice_id"`
-	Voice       *SavedFile    `gorm:"foreignKey:VoiceId" json:"voice"`
-	CircleId    *int          `json:"circle_id"`
-	Circle      *SavedFile    `gorm:"foreignKey:CircleId" json:"circle"`
-	Attachments []SavedFile   `gorm:"many2many:message_attachments" json:"attachments"`
-	ReplyToID   uint          `json:"reply_to_id"`
+	Attachments []SavedFile   `gorm:"many2many:message_revision_attachments" json:"attachments"`
 	Mentioned   pq.Int32Array `gorm:"type:integer[]" json:"mentioned"`
-	ReadedBy    pq.Int32Array `gorm:"type:integer[]" json:"readed_by"`
-	Reactions   []Reaction    `gorm:"foreignKey:MessageId" json:"reactions"`
-	DeletedFor  pq.Int32Array `gorm:"type:integer[]" json:"deleted_for"`
 	CreatedAt   time.Time
 }
 
+type Poll struct {
+	*gorm.Model
+	ID             uint         `gorm:"primaryKey" json:"id"`
+	MessageId      uint         `gorm:"uniqueIndex" json:"message_id"`
+	MultipleChoice bool         `json:"multiple_choice"`
+	Anonymous      bool         `json:"anonymous"`
+	ClosesAt       *time.Time   `json:"closes_at"`
+	ClosedAt       *time.Time   `json:"closed_at"`
+	Options        []PollOption `gorm:"foreignKey:PollId" json:"options"`
+}
+
+type PollOption struct {
+	*gorm.Model
+	ID       uint       `gorm:"primaryKey" json:"id"`
+	PollId   uint       `gorm:"index" json:"poll_id"`
+	Position int        `json:"position"`
+	Content  string     `json:"content"`
+	Votes    []PollVote `gorm:"foreignKey:PollOptionId" json:"votes"`
+}
+
+type PollVote struct {
+	*gorm.Model
+	ID           uint `gorm:"primaryKey" json:"id"`
+	PollId       uint `gorm:"index" json:"poll_id"`
+	PollOptionId uint `gorm:"uniqueIndex:idx_poll_votes_option_user" json:"poll_option_id"`
+	UserId       uint `gorm:"uniqueIndex:idx_poll_votes_option_user" json:"user_id"`
+}
+
 type Reaction struct {
 	*gorm.Model
 	ID        uint   `gorm:"primaryKey" json:"id"`
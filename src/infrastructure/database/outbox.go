@@ -0,0 +1,84 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OutboxAdapter struct {
+	db gorm.DB
+}
+
+func (adapter OutboxAdapter) Save(event OutboxEvent) error {
+	result := adapter.db.Create(&event)
+	return result.Error
+}
+
+// TryLock takes a transaction scoped advisory lock so only one relay instance
+// publishes outbox events at a time. It must be called inside a transaction.
+func (adapter OutboxAdapter) TryLock(key int64) (bool, error) {
+	var locked bool
+	result := adapter.db.Raw("SELECT pg_try_advisory_xact_lock(?)", key).Scan(&locked)
+	return locked, result.Error
+}
+
+// GetPending returns events that are due for publishing. An event is skipped
+// while an earlier event with the same ordering key is still waiting for its
+// retry, so events of one key are never published out of order.
+func (adapter OutboxAdapter) GetPending(limit int, now time.Time) ([]OutboxEvent, error) {
+	var events []OutboxEvent
+	result := adapter.db.Where(
+		"published_at IS NULL AND dead_at IS NULL AND next_attempt_at <= ?", now,
+	).Where(
+		`NOT EXISTS (
+			SELECT 1 FROM outbox_events earlier
+			WHERE earlier.ordering_key = outbox_events.ordering_key
+				AND earlier.id < outbox_events.id
+				AND earlier.published_at IS NULL
+				AND earlier.dead_at IS NULL
+				AND earlier.next_attempt_at > ?
+		)`, now,
+	).Order("id").Limit(limit).Find(&events)
+	return events, result.Error
+}
+
+func (adapter OutboxAdapter) MarkPublished(event OutboxEvent, publishedAt time.Time) error {
+	result := adapter.db.Model(&OutboxEvent{}).Where("id = ?", event.ID).Updates(map[string]interface{}{
+		"published_at": publishedAt,
+		"attempts":     event.Attempts + 1,
+		"last_error":   nil,
+	})
+	return result.Error
+}
+
+func (adapter OutboxAdapter) MarkFailed(event OutboxEvent, publishErr error, nextAttemptAt time.Time) error {
+	lastError := publishErr.Error()
+	result := adapter.db.Model(&OutboxEvent{}).Where("id = ?", event.ID).Updates(map[string]interface{}{
+		"attempts":        event.Attempts + 1,
+		"last_error":      lastError,
+		"next_attempt_at": nextAttemptAt,
+	})
+	return result.Error
+}
+
+// MarkDead stops retrying an event that has used up its attempts. Dead events
+// are kept for inspection and no longer block their ordering key.
+func (adapter OutboxAdapter) MarkDead(event OutboxEvent, publishErr error, deadAt time.Time) error {
+	lastError := publishErr.Error()
+	result := adapter.db.Model(&OutboxEvent{}).Where("id = ?", event.ID).Updates(map[string]interface{}{
+		"attempts":   event.Attempts + 1,
+		"last_error": lastError,
+		"dead_at":    deadAt,
+	})
+	return result.Error
+}
+
+func (adapter OutboxAdapter) DeletePublishedBefore(before time.Time) error {
+	result := adapter.db.Where("published_at < ?", before).Delete(&OutboxEvent{})
+	return result.Error
+}
+
+func NewOutboxAdapter(db gorm.DB) OutboxAdapter {
+	return OutboxAdapter{db: db}
+}
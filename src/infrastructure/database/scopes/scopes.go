@@ -0,0 +1,153 @@
+// Package scopes holds the query scopes shared by the database adapters.
+package scopes
+
+import (
+	"log"
+	"time"
+
+	"github.com/chack-check/chats-service/domain/chats"
+	"gorm.io/gorm"
+)
+
+func Paginate(page int, perPage int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		log.Printf("Paginating query. page = %d perPage = %d", page, perPage)
+		if page <= 0 {
+			page = 1
+		}
+
+		if perPage <= 20 {
+			perPage = 20
+		} else {
+			perPage = 100
+		}
+
+		log.Printf("Fixed page = %d perPage = %d", page, perPage)
+		offset := (page - 1) * perPage
+		log.Printf("Offset: %v", offset)
+		return db.Offset(offset).Limit(perPage)
+	}
+}
+
+func PublishedMessages(db *gorm.DB) *gorm.DB {
+	return db.Where("messages.send_at IS NULL")
+}
+
+func NotDeletedForUser(userId int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("NOT (? = ANY(COALESCE(messages.deleted_for, '{}')))", userId)
+	}
+}
+
+func UnreadForUser(userId int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(
+			`messages.sender_id <> ? AND NOT (? = ANY(COALESCE(messages.readed_by, '{}'))) AND messages.created_at > COALESCE((
+				SELECT chat_read_pointers.last_read_at FROM chat_read_pointers
+				WHERE chat_read_pointers.chat_id = messages.chat_id AND chat_read_pointers.user_id = ? AND chat_read_pointers.deleted_at IS NULL
+			), '-infinity')`,
+			userId, userId, userId,
+		)
+	}
+}
+
+const unreadCountersSelect = `COUNT(*) AS unread_count,
+	COUNT(*) FILTER (WHERE ? = ANY(COALESCE(messages.mentioned, '{}'))) AS unread_mentions_count`
+
+// UnreadCountersByChat counts the user's unread messages and mentions of
+// every given chat in a single grouped query.
+func UnreadCountersByChat(chatIds []int, userId int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Scopes(PublishedMessages, NotDeletedForUser(userId), UnreadForUser(userId)).Select(
+			"messages.chat_id AS chat_id, "+unreadCountersSelect,
+			userId,
+		).Where("messages.chat_id IN ?", chatIds).Group("messages.chat_id")
+	}
+}
+
+// TotalUnread counts the user's unread messages and mentions across all the
+// chats the user is a member of.
+func TotalUnread(userId int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Scopes(PublishedMessages, NotDeletedForUser(userId), UnreadForUser(userId)).Select(
+			unreadCountersSelect,
+			userId,
+		).Joins("JOIN chats ON messages.chat_id = chats.id").Where(
+			"? = ANY(chats.members) AND chats.deleted_at IS NULL", userId,
+		)
+	}
+}
+
+// ReadPointersReaching selects the read pointers of the chats that were read
+// at or after minCreatedAt. Channels are skipped, listing every subscriber who
+// read a post doesn't scale.
+func ReadPointersReaching(chatIds []uint, minCreatedAt time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Joins("JOIN chats ON chats.id = chat_read_pointers.chat_id").Where(
+			"chat_read_pointers.chat_id IN ? AND chat_read_pointers.last_read_at >= ? AND chats.type <> ?",
+			chatIds,
+			minCreatedAt,
+			string(chats.ChannelChatType),
+		)
+	}
+}
+
+func MatchingSearchQuery(query string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("to_tsvector('simple', messages.content) @@ websearch_to_tsquery('simple', ?)", query)
+	}
+}
+
+func WithUserSettings(userId int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Joins(
+			"LEFT JOIN chat_user_settings ON chat_user_settings.chat_id = chats.id AND chat_user_settings.user_id = ? AND chat_user_settings.deleted_at IS NULL",
+			userId,
+		)
+	}
+}
+
+func ArchivedForUser(archived bool) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("COALESCE(chat_user_settings.is_archived, false) = ?", archived)
+	}
+}
+
+func MutedForUser(db *gorm.DB) *gorm.DB {
+	return db.Where("chat_user_settings.is_muted AND (chat_user_settings.muted_until IS NULL OR chat_user_settings.muted_until > now())")
+}
+
+func InFolder(folder chats.ChatFolder) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		rules := db.Session(&gorm.Session{NewDB: true})
+		if chatTypes := folder.GetChatTypes(); len(chatTypes) > 0 {
+			var types []string
+			for _, chatType := range chatTypes {
+				types = append(types, string(chatType))
+			}
+
+			rules = rules.Where("chats.type IN ?", types)
+		}
+		if folder.GetUnreadOnly() {
+			unreadMessages := db.Session(&gorm.Session{NewDB: true}).Table("messages").Select("1").Where(
+				"messages.chat_id = chats.id AND messages.deleted_at IS NULL",
+			).Scopes(PublishedMessages, NotDeletedForUser(folder.GetUserId()), UnreadForUser(folder.GetUserId()))
+			rules = rules.Where("EXISTS (?)", unreadMessages)
+		}
+		if folder.GetMutedOnly() {
+			rules = rules.Scopes(MutedForUser)
+		}
+
+		chatIds := folder.GetChatIds()
+		switch {
+		case len(chatIds) > 0 && folder.HasRules():
+			return db.Where(db.Session(&gorm.Session{NewDB: true}).Where("chats.id IN ?", chatIds).Or(rules))
+		case len(chatIds) > 0:
+			return db.Where("chats.id IN ?", chatIds)
+		case folder.HasRules():
+			return db.Where(rules)
+		default:
+			return db.Where("1 = 0")
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package scopes
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chack-check/chats-service/domain/chats"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunConnection builds statements without connecting to the database.
+func dryRunConnection(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatalf("opening dry run connection: %v", err)
+	}
+
+	return db
+}
+
+func TestInFolder(t *testing.T) {
+	const selectUserChats = `SELECT * FROM "chats" WHERE chats.members @> '{5}' AND `
+	tests := []struct {
+		name             string
+		folder           chats.ChatFolder
+		expected         string
+		expectedContains []string
+	}{
+		{
+			name:     "chats only",
+			folder:   chats.NewChatFolder(1, 5, "folder", 0, []int{1, 2}, nil, false, false),
+			expected: selectUserChats + "chats.id IN (1,2)",
+		},
+		{
+			name:     "chat types only",
+			folder:   chats.NewChatFolder(1, 5, "folder", 0, nil, []chats.ChatTypes{chats.GroupChatType, chats.ChannelChatType}, false, false),
+			expected: selectUserChats + "chats.type IN ('group','channel')",
+		},
+		{
+			name:     "muted only",
+			folder:   chats.NewChatFolder(1, 5, "folder", 0, nil, nil, false, true),
+			expected: selectUserChats + "(chat_user_settings.is_muted AND (chat_user_settings.muted_until IS NULL OR chat_user_settings.muted_until > now()))",
+		},
+		{
+			name:     "chats or rules",
+			folder:   chats.NewChatFolder(1, 5, "folder", 0, []int{1}, []chats.ChatTypes{chats.UserChatType}, false, true),
+			expected: selectUserChats + "(chats.id IN (1) OR (chats.type IN ('user') AND (chat_user_settings.is_muted AND (chat_user_settings.muted_until IS NULL OR chat_user_settings.muted_until > now()))))",
+		},
+		{
+			name:     "no chats and no rules",
+			folder:   chats.NewChatFolder(1, 5, "folder", 0, nil, nil, false, false),
+			expected: selectUserChats + "1 = 0",
+		},
+		{
+			name:   "unread only",
+			folder: chats.NewChatFolder(1, 5, "folder", 0, nil, nil, true, false),
+			expectedContains: []string{
+				selectUserChats + `EXISTS (SELECT 1 FROM "messages" WHERE (messages.chat_id = chats.id AND messages.deleted_at IS NULL)`,
+				"messages.send_at IS NULL",
+				"NOT (5 = ANY(COALESCE(messages.deleted_for, '{}')))",
+				"messages.sender_id <> 5",
+				"chat_read_pointers.user_id = 5",
+			},
+		},
+	}
+
+	db := dryRunConnection(t)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				return tx.Table("chats").Where("chats.members @> '{5}'").Scopes(InFolder(test.folder)).Find(&[]map[string]any{})
+			})
+
+			if test.expected != "" && sql != test.expected {
+				t.Errorf("sql = %s\nexpected %s", sql, test.expected)
+			}
+			for _, part := range test.expectedContains {
+				if !strings.Contains(sql, part) {
+					t.Errorf("sql = %s\nexpected it to contain %s", sql, part)
+				}
+			}
+		})
+	}
+}
+
+func TestUnreadCounters(t *testing.T) {
+	const unreadMessages = "messages.send_at IS NULL AND NOT (5 = ANY(COALESCE(messages.deleted_for, '{}'))) AND (messages.sender_id <> 5 AND "
+	tests := []struct {
+		name             string
+		scope            func(db *gorm.DB) *gorm.DB
+		expectedContains []string
+	}{
+		{
+			name:  "by chat",
+			scope: UnreadCountersByChat([]int{1, 2}, 5),
+			expectedContains: []string{
+				`SELECT messages.chat_id AS chat_id, COUNT(*) AS unread_count,`,
+				`COUNT(*) FILTER (WHERE 5 = ANY(COALESCE(messages.mentioned, '{}'))) AS unread_mentions_count FROM "messages"`,
+				unreadMessages,
+				"chat_read_pointers.user_id = 5",
+				"messages.chat_id IN (1,2)",
+				`GROUP BY "messages"."chat_id"`,
+			},
+		},
+		{
+			name:  "total",
+			scope: TotalUnread(5),
+			expectedContains: []string{
+				`SELECT COUNT(*) AS unread_count,`,
+				`COUNT(*) FILTER (WHERE 5 = ANY(COALESCE(messages.mentioned, '{}'))) AS unread_mentions_count FROM "messages" JOIN chats ON messages.chat_id = chats.id`,
+				unreadMessages,
+				"chat_read_pointers.user_id = 5",
+				"5 = ANY(chats.members) AND chats.deleted_at IS NULL",
+			},
+		},
+	}
+
+	db := dryRunConnection(t)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				return tx.Table("messages").Scopes(test.scope).Find(&[]map[string]any{})
+			})
+
+			for _, part := range test.expectedContains {
+				if !strings.Contains(sql, part) {
+					t.Errorf("sql = %s\nexpected it to contain %s", sql, part)
+				}
+			}
+			if strings.Count(sql, "SELECT") != 2 {
+				t.Errorf("sql = %s\nexpected a single query with the read pointer subquery", sql)
+			}
+		})
+	}
+}
+
+func TestReadPointersReaching(t *testing.T) {
+	// A published scheduled message keeps its lower id, so only the read time
+	// tells whether a pointer reaches the page.
+	minCreatedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	db := dryRunConnection(t)
+	sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Table("chat_read_pointers").Scopes(ReadPointersReaching([]uint{1, 2}, minCreatedAt)).Find(&[]map[string]any{})
+	})
+
+	expected := `SELECT * FROM "chat_read_pointers" JOIN chats ON chats.id = chat_read_pointers.chat_id WHERE chat_read_pointers.chat_id IN (1,2) AND chat_read_pointers.last_read_at >= '2024-05-01 12:00:00' AND chats.type <> 'channel'`
+	if sql != expected {
+		t.Errorf("sql = %s\nexpected %s", sql, expected)
+	}
+}
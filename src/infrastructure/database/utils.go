@@ -1,27 +1,21 @@
 package database
 
 import (
-	"log"
+	"errors"
 
 	"gorm.io/gorm"
 )
 
-func Paginate(page int, perPage int) func(db *gorm.DB) *gorm.DB {
-	return func(db *gorm.DB) *gorm.DB {
-		log.Printf("Paginating query. page = %d perPage = %d", page, perPage)
-		if page <= 0 {
-			page = 1
-		}
+// noViewerId loads chats and messages without hiding what a user deleted for
+// themselves, for reads that are not made on behalf of a single user.
+const noViewerId = 0
 
-		if perPage <= 20 {
-			perPage = 20
-		} else {
-			perPage = 100
-		}
-
-		log.Printf("Fixed page = %d perPage = %d", page, perPage)
-		offset := (page - 1) * perPage
-		log.Printf("Offset: %v", offset)
-		return db.Offset(offset).Limit(perPage)
+// notFoundAs joins notFoundErr to a missing row error, so handlers can tell a
+// missing record from a failed query.
+func notFoundAs(err error, notFoundErr error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.Join(notFoundErr, err)
 	}
+
+	return err
 }
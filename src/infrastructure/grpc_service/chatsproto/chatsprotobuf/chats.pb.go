@@ -96,14 +96,16 @@ type ChatResponse struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id         int32      `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Avatar     *SavedFile `protobuf:"bytes,2,opt,name=avatar,proto3" json:"avatar,omitempty"`
-	Title      string     `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
-	Type       string     `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
-	MembersIds []int32    `protobuf:"varint,5,rep,packed,name=members_ids,json=membersIds,proto3" json:"members_ids,omitempty"`
-	IsArchived bool       `protobuf:"varint,6,opt,name=is_archived,json=isArchived,proto3" json:"is_archived,omitempty"`
-	OwnerId    int32      `protobuf:"varint,7,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
-	AdminsIds  []int32    `protobuf:"varint,8,rep,packed,name=admins_ids,json=adminsIds,proto3" json:"admins_ids,omitempty"`
+	Id                  int32      `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Avatar              *SavedFile `protobuf:"bytes,2,opt,name=avatar,proto3" json:"avatar,omitempty"`
+	Title               string     `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Type                string     `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	MembersIds          []int32    `protobuf:"varint,5,rep,packed,name=members_ids,json=membersIds,proto3" json:"members_ids,omitempty"`
+	IsArchived          bool       `protobuf:"varint,6,opt,name=is_archived,json=isArchived,proto3" json:"is_archived,omitempty"`
+	OwnerId             int32      `protobuf:"varint,7,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	AdminsIds           []int32    `protobuf:"varint,8,rep,packed,name=admins_ids,json=adminsIds,proto3" json:"admins_ids,omitempty"`
+	UnreadCount         int32      `protobuf:"varint,9,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"`
+	UnreadMentionsCount int32      `protobuf:"varint,10,opt,name=unread_mentions_count,json=unreadMentionsCount,proto3" json:"unread_mentions_count,omitempty"`
 }
 
 func (x *ChatResponse) Reset() {
@@ -194,6 +196,20 @@ func (x *ChatResponse) GetAdminsIds() []int32 {
 	return nil
 }
 
+func (x *ChatResponse) GetUnreadCount() int32 {
+	if x != nil {
+		return x.UnreadCount
+	}
+	return 0
+}
+
+func (x *ChatResponse) GetUnreadMentionsCount() int32 {
+	if x != nil {
+		return x.UnreadMentionsCount
+	}
+	return 0
+}
+
 type MessageReaction struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -249,30 +265,261 @@ func (x *MessageReaction) GetContent() string {
 	return ""
 }
 
+type ForwardedFrom struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MessageId int32 `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	SenderId  int32 `protobuf:"varint,2,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	ChatId    int32 `protobuf:"varint,3,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+}
+
+func (x *ForwardedFrom) Reset() {
+	*x = ForwardedFrom{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chats_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ForwardedFrom) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardedFrom) ProtoMessage() {}
+
+func (x *ForwardedFrom) ProtoReflect() protoreflect.Message {
+	mi := &file_chats_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardedFrom.ProtoReflect.Descriptor instead.
+func (*ForwardedFrom) Descriptor() ([]byte, []int) {
+	return file_chats_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ForwardedFrom) GetMessageId() int32 {
+	if x != nil {
+		return x.MessageId
+	}
+	return 0
+}
+
+func (x *ForwardedFrom) GetSenderId() int32 {
+	if x != nil {
+		return x.SenderId
+	}
+	return 0
+}
+
+func (x *ForwardedFrom) GetChatId() int32 {
+	if x != nil {
+		return x.ChatId
+	}
+	return 0
+}
+
+type PollOption struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         int32   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Content    string  `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	VotesCount int32   `protobuf:"varint,3,opt,name=votes_count,json=votesCount,proto3" json:"votes_count,omitempty"`
+	Voters     []int32 `protobuf:"varint,4,rep,packed,name=voters,proto3" json:"voters,omitempty"`
+}
+
+func (x *PollOption) Reset() {
+	*x = PollOption{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chats_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PollOption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PollOption) ProtoMessage() {}
+
+func (x *PollOption) ProtoReflect() protoreflect.Message {
+	mi := &file_chats_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PollOption.ProtoReflect.Descriptor instead.
+func (*PollOption) Descriptor() ([]byte, []int) {
+	return file_chats_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PollOption) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PollOption) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *PollOption) GetVotesCount() int32 {
+	if x != nil {
+		return x.VotesCount
+	}
+	return 0
+}
+
+func (x *PollOption) GetVoters() []int32 {
+	if x != nil {
+		return x.Voters
+	}
+	return nil
+}
+
+type Poll struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Options        []*PollOption `protobuf:"bytes,1,rep,name=options,proto3" json:"options,omitempty"`
+	MultipleChoice bool          `protobuf:"varint,2,opt,name=multiple_choice,json=multipleChoice,proto3" json:"multiple_choice,omitempty"`
+	Anonymous      bool          `protobuf:"varint,3,opt,name=anonymous,proto3" json:"anonymous,omitempty"`
+	ClosesAt       *string       `protobuf:"bytes,4,opt,name=closes_at,json=closesAt,proto3,oneof" json:"closes_at,omitempty"`
+	ClosedAt       *string       `protobuf:"bytes,5,opt,name=closed_at,json=closedAt,proto3,oneof" json:"closed_at,omitempty"`
+	IsClosed       bool          `protobuf:"varint,6,opt,name=is_closed,json=isClosed,proto3" json:"is_closed,omitempty"`
+	TotalVoters    int32         `protobuf:"varint,7,opt,name=total_voters,json=totalVoters,proto3" json:"total_voters,omitempty"`
+}
+
+func (x *Poll) Reset() {
+	*x = Poll{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chats_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Poll) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Poll) ProtoMessage() {}
+
+func (x *Poll) ProtoReflect() protoreflect.Message {
+	mi := &file_chats_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Poll.ProtoReflect.Descriptor instead.
+func (*Poll) Descriptor() ([]byte, []int) {
+	return file_chats_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Poll) GetOptions() []*PollOption {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *Poll) GetMultipleChoice() bool {
+	if x != nil {
+		return x.MultipleChoice
+	}
+	return false
+}
+
+func (x *Poll) GetAnonymous() bool {
+	if x != nil {
+		return x.Anonymous
+	}
+	return false
+}
+
+func (x *Poll) GetClosesAt() string {
+	if x != nil && x.ClosesAt != nil {
+		return *x.ClosesAt
+	}
+	return ""
+}
+
+func (x *Poll) GetClosedAt() string {
+	if x != nil && x.ClosedAt != nil {
+		return *x.ClosedAt
+	}
+	return ""
+}
+
+func (x *Poll) GetIsClosed() bool {
+	if x != nil {
+		return x.IsClosed
+	}
+	return false
+}
+
+func (x *Poll) GetTotalVoters() int32 {
+	if x != nil {
+		return x.TotalVoters
+	}
+	return 0
+}
+
 type MessageResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id          int32              `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	SenderId    int32              `protobuf:"varint,2,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
-	ChatId      int32              `protobuf:"varint,3,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
-	Type        string             `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
-	Content     *string            `protobuf:"bytes,5,opt,name=content,proto3,oneof" json:"content,omitempty"`
-	Voice       *SavedFile         `protobuf:"bytes,6,opt,name=voice,proto3,oneof" json:"voice,omitempty"`
-	Circle      *SavedFile         `protobuf:"bytes,7,opt,name=circle,proto3,oneof" json:"circle,omitempty"`
-	Attachments []*SavedFile       `protobuf:"bytes,8,rep,name=attachments,proto3" json:"attachments,omitempty"`
-	ReplyToId   *int32             `protobuf:"varint,9,opt,name=reply_to_id,json=replyToId,proto3,oneof" json:"reply_to_id,omitempty"`
-	Mentioned   []int32            `protobuf:"varint,10,rep,packed,name=mentioned,proto3" json:"mentioned,omitempty"`
-	ReadedBy    []int32            `protobuf:"varint,11,rep,packed,name=readed_by,json=readedBy,proto3" json:"readed_by,omitempty"`
-	Reactions   []*MessageReaction `protobuf:"bytes,12,rep,name=reactions,proto3" json:"reactions,omitempty"`
-	CreatedAt   *string            `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3,oneof" json:"created_at,omitempty"`
+	Id            int32              `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	SenderId      int32              `protobuf:"varint,2,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	ChatId        int32              `protobuf:"varint,3,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Type          string             `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	Content       *string            `protobuf:"bytes,5,opt,name=content,proto3,oneof" json:"content,omitempty"`
+	Voice         *SavedFile         `protobuf:"bytes,6,opt,name=voice,proto3,oneof" json:"voice,omitempty"`
+	Circle        *SavedFile         `protobuf:"bytes,7,opt,name=circle,proto3,oneof" json:"circle,omitempty"`
+	Attachments   []*SavedFile       `protobuf:"bytes,8,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	ReplyToId     *int32             `protobuf:"varint,9,opt,name=reply_to_id,json=replyToId,proto3,oneof" json:"reply_to_id,omitempty"`
+	Mentioned     []int32            `protobuf:"varint,10,rep,packed,name=mentioned,proto3" json:"mentioned,omitempty"`
+	ReadedBy      []int32            `protobuf:"varint,11,rep,packed,name=readed_by,json=readedBy,proto3" json:"readed_by,omitempty"`
+	Reactions     []*MessageReaction `protobuf:"bytes,12,rep,name=reactions,proto3" json:"reactions,omitempty"`
+	CreatedAt     *string            `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3,oneof" json:"created_at,omitempty"`
+	ForwardedFrom *ForwardedFrom     `protobuf:"bytes,14,opt,name=forwarded_from,json=forwardedFrom,proto3,oneof" json:"forwarded_from,omitempty"`
+	Poll          *Poll              `protobuf:"bytes,15,opt,name=poll,proto3,oneof" json:"poll,omitempty"`
 }
 
 func (x *MessageResponse) Reset() {
 	*x = MessageResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chats_proto_msgTypes[3]
+		mi := &file_chats_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -285,7 +532,7 @@ func (x *MessageResponse) String() string {
 func (*MessageResponse) ProtoMessage() {}
 
 func (x *MessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_chats_proto_msgTypes[3]
+	mi := &file_chats_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -298,7 +545,7 @@ func (x *MessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MessageResponse.ProtoReflect.Descriptor instead.
 func (*MessageResponse) Descriptor() ([]byte, []int) {
-	return file_chats_proto_rawDescGZIP(), []int{3}
+	return file_chats_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *MessageResponse) GetId() int32 {
@@ -392,6 +639,20 @@ func (x *MessageResponse) GetCreatedAt() string {
 	return ""
 }
 
+func (x *MessageResponse) GetForwardedFrom() *ForwardedFrom {
+	if x != nil {
+		return x.ForwardedFrom
+	}
+	return nil
+}
+
+func (x *MessageResponse) GetPoll() *Poll {
+	if x != nil {
+		return x.Poll
+	}
+	return nil
+}
+
 type GetChatByIdRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -404,7 +665,7 @@ type GetChatByIdRequest struct {
 func (x *GetChatByIdRequest) Reset() {
 	*x = GetChatByIdRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chats_proto_msgTypes[4]
+		mi := &file_chats_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -417,7 +678,7 @@ func (x *GetChatByIdRequest) String() string {
 func (*GetChatByIdRequest) ProtoMessage() {}
 
 func (x *GetChatByIdRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_chats_proto_msgTypes[4]
+	mi := &file_chats_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -430,7 +691,7 @@ func (x *GetChatByIdRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetChatByIdRequest.ProtoReflect.Descriptor instead.
 func (*GetChatByIdRequest) Descriptor() ([]byte, []int) {
-	return file_chats_proto_rawDescGZIP(), []int{4}
+	return file_chats_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetChatByIdRequest) GetId() int32 {
@@ -459,7 +720,7 @@ type GetChatsByIdsRequest struct {
 func (x *GetChatsByIdsRequest) Reset() {
 	*x = GetChatsByIdsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chats_proto_msgTypes[5]
+		mi := &file_chats_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -472,7 +733,7 @@ func (x *GetChatsByIdsRequest) String() string {
 func (*GetChatsByIdsRequest) ProtoMessage() {}
 
 func (x *GetChatsByIdsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_chats_proto_msgTypes[5]
+	mi := &file_chats_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -485,7 +746,7 @@ func (x *GetChatsByIdsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetChatsByIdsRequest.ProtoReflect.Descriptor instead.
 func (*GetChatsByIdsRequest) Descriptor() ([]byte, []int) {
-	return file_chats_proto_rawDescGZIP(), []int{5}
+	return file_chats_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *GetChatsByIdsRequest) GetIds() []int32 {
@@ -514,7 +775,7 @@ type GetMessagesByIdsRequest struct {
 func (x *GetMessagesByIdsRequest) Reset() {
 	*x = GetMessagesByIdsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chats_proto_msgTypes[6]
+		mi := &file_chats_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -527,7 +788,7 @@ func (x *GetMessagesByIdsRequest) String() string {
 func (*GetMessagesByIdsRequest) ProtoMessage() {}
 
 func (x *GetMessagesByIdsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_chats_proto_msgTypes[6]
+	mi := &file_chats_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -540,7 +801,7 @@ func (x *GetMessagesByIdsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMessagesByIdsRequest.ProtoReflect.Descriptor instead.
 func (*GetMessagesByIdsRequest) Descriptor() ([]byte, []int) {
-	return file_chats_proto_rawDescGZIP(), []int{6}
+	return file_chats_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetMessagesByIdsRequest) GetIds() []int32 {
@@ -569,7 +830,7 @@ type GetMessageByIdRequest struct {
 func (x *GetMessageByIdRequest) Reset() {
 	*x = GetMessageByIdRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chats_proto_msgTypes[7]
+		mi := &file_chats_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -582,7 +843,7 @@ func (x *GetMessageByIdRequest) String() string {
 func (*GetMessageByIdRequest) ProtoMessage() {}
 
 func (x *GetMessageByIdRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_chats_proto_msgTypes[7]
+	mi := &file_chats_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -595,7 +856,7 @@ func (x *GetMessageByIdRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMessageByIdRequest.ProtoReflect.Descriptor instead.
 func (*GetMessageByIdRequest) Descriptor() ([]byte, []int) {
-	return file_chats_proto_rawDescGZIP(), []int{7}
+	return file_chats_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *GetMessageByIdRequest) GetId() int32 {
@@ -626,7 +887,7 @@ type GetMessagesByChatIdRequest struct {
 func (x *GetMessagesByChatIdRequest) Reset() {
 	*x = GetMessagesByChatIdRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chats_proto_msgTypes[8]
+		mi := &file_chats_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -639,7 +900,7 @@ func (x *GetMessagesByChatIdRequest) String() string {
 func (*GetMessagesByChatIdRequest) ProtoMessage() {}
 
 func (x *GetMessagesByChatIdRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_chats_proto_msgTypes[8]
+	mi := &file_chats_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -652,7 +913,7 @@ func (x *GetMessagesByChatIdRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMessagesByChatIdRequest.ProtoReflect.Descriptor instead.
 func (*GetMessagesByChatIdRequest) Descriptor() ([]byte, []int) {
-	return file_chats_proto_rawDescGZIP(), []int{8}
+	return file_chats_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *GetMessagesByChatIdRequest) GetChatId() int32 {
@@ -694,7 +955,7 @@ type ChatsArrayResponse struct {
 func (x *ChatsArrayResponse) Reset() {
 	*x = ChatsArrayResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chats_proto_msgTypes[9]
+		mi := &file_chats_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -707,7 +968,7 @@ func (x *ChatsArrayResponse) String() string {
 func (*ChatsArrayResponse) ProtoMessage() {}
 
 func (x *ChatsArrayResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_chats_proto_msgTypes[9]
+	mi := &file_chats_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -720,7 +981,7 @@ func (x *ChatsArrayResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChatsArrayResponse.ProtoReflect.Descriptor instead.
 func (*ChatsArrayResponse) Descriptor() ([]byte, []int) {
-	return file_chats_proto_rawDescGZIP(), []int{9}
+	return file_chats_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *ChatsArrayResponse) GetChats() []*ChatResponse {
@@ -741,7 +1002,7 @@ type MessagesArrayResponse struct {
 func (x *MessagesArrayResponse) Reset() {
 	*x = MessagesArrayResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chats_proto_msgTypes[10]
+		mi := &file_chats_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -754,7 +1015,7 @@ func (x *MessagesArrayResponse) String() string {
 func (*MessagesArrayResponse) ProtoMessage() {}
 
 func (x *MessagesArrayResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_chats_proto_msgTypes[10]
+	mi := &file_chats_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -767,7 +1028,7 @@ func (x *MessagesArrayResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MessagesArrayResponse.ProtoReflect.Descriptor instead.
 func (*MessagesArrayResponse) Descriptor() ([]byte, []int) {
-	return file_chats_proto_rawDescGZIP(), []int{10}
+	return file_chats_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *MessagesArrayResponse) GetMessages() []*MessageResponse {
@@ -791,7 +1052,7 @@ type PaginatedMessages struct {
 func (x *PaginatedMessages) Reset() {
 	*x = PaginatedMessages{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chats_proto_msgTypes[11]
+		mi := &file_chats_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -804,7 +1065,7 @@ func (x *PaginatedMessages) String() string {
 func (*PaginatedMessages) ProtoMessage() {}
 
 func (x *PaginatedMessages) ProtoReflect() protoreflect.Message {
-	mi := &file_chats_proto_msgTypes[11]
+	mi := &file_chats_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -817,7 +1078,7 @@ func (x *PaginatedMessages) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PaginatedMessages.ProtoReflect.Descriptor instead.
 func (*PaginatedMessages) Descriptor() ([]byte, []int) {
-	return file_chats_proto_rawDescGZIP(), []int{11}
+	return file_chats_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *PaginatedMessages) GetOffset() int32 {
@@ -867,7 +1128,7 @@ var file_chats_proto_rawDesc = []byte{
 	0x65, 0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x63, 0x6f, 0x6e,
 	0x76, 0x65, 0x72, 0x74, 0x65, 0x64, 0x5f, 0x75, 0x72, 0x6c, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x63,
 	0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x65, 0x64, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d,
-	0x65, 0x22, 0xf6, 0x01, 0x0a, 0x0c, 0x43, 0x68, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x65, 0x22, 0xcd, 0x02, 0x0a, 0x0c, 0x43, 0x68, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
 	0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02,
 	0x69, 0x64, 0x12, 0x30, 0x0a, 0x06, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x18, 0x02, 0x20, 0x01,
 	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
@@ -882,123 +1143,169 @@ var file_chats_proto_rawDesc = []byte{
 	0x12, 0x19, 0x0a, 0x08, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01,
 	0x28, 0x05, 0x52, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x61,
 	0x64, 0x6d, 0x69, 0x6e, 0x73, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x05, 0x52,
-	0x09, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x73, 0x49, 0x64, 0x73, 0x22, 0x44, 0x0a, 0x0f, 0x4d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x17, 0x0a,
-	0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
-	0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
-	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74,
-	0x22, 0xb4, 0x04, 0x0a, 0x0f, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x69,
-	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49,
-	0x64, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x06, 0x63, 0x68, 0x61, 0x74, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79,
-	0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1d,
-	0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48,
-	0x00, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x33, 0x0a,
-	0x05, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63,
-	0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x61, 0x76,
-	0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x48, 0x01, 0x52, 0x05, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x88,
-	0x01, 0x01, 0x12, 0x35, 0x0a, 0x06, 0x63, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x18, 0x07, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x53, 0x61, 0x76, 0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x48, 0x02, 0x52, 0x06,
-	0x63, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x3a, 0x0a, 0x0b, 0x61, 0x74, 0x74,
-	0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18,
-	0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53,
-	0x61, 0x76, 0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x0b, 0x61, 0x74, 0x74, 0x61, 0x63, 0x68,
-	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x23, 0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x5f, 0x74,
-	0x6f, 0x5f, 0x69, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x48, 0x03, 0x52, 0x09, 0x72, 0x65,
-	0x70, 0x6c, 0x79, 0x54, 0x6f, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x65,
-	0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x64, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x05, 0x52, 0x09, 0x6d,
-	0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x65, 0x61, 0x64,
-	0x65, 0x64, 0x5f, 0x62, 0x79, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x05, 0x52, 0x08, 0x72, 0x65, 0x61,
-	0x64, 0x65, 0x64, 0x42, 0x79, 0x12, 0x3c, 0x0a, 0x09, 0x72, 0x65, 0x61, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x52, 0x65, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x72, 0x65, 0x61, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x73, 0x12, 0x22, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61,
-	0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x64, 0x41, 0x74, 0x88, 0x01, 0x01, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x63, 0x6f, 0x6e, 0x74,
-	0x65, 0x6e, 0x74, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x42, 0x09, 0x0a,
-	0x07, 0x5f, 0x63, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x72, 0x65, 0x70,
-	0x6c, 0x79, 0x5f, 0x74, 0x6f, 0x5f, 0x69, 0x64, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x63, 0x72, 0x65,
-	0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x22, 0x3a, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x43, 0x68,
-	0x61, 0x74, 0x42, 0x79, 0x49, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a,
-	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a,
-	0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f,
-	0x6b, 0x65, 0x6e, 0x22, 0x3e, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x73, 0x42,
-	0x79, 0x49, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x69,
-	0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x05, 0x52, 0x03, 0x69, 0x64, 0x73, 0x12, 0x14, 0x0a,
-	0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f,
-	0x6b, 0x65, 0x6e, 0x22, 0x41, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x73, 0x42, 0x79, 0x49, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10,
-	0x0a, 0x03, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x05, 0x52, 0x03, 0x69, 0x64, 0x73,
-	0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x3d, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x42, 0x79, 0x49, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12,
-	0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x98, 0x01, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x73, 0x42, 0x79, 0x43, 0x68, 0x61, 0x74, 0x49, 0x64, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x74, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x63, 0x68, 0x61, 0x74, 0x49, 0x64, 0x12, 0x14, 0x0a,
-	0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f,
-	0x6b, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x88, 0x01, 0x01,
-	0x12, 0x19, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48,
-	0x01, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x88, 0x01, 0x01, 0x42, 0x09, 0x0a, 0x07, 0x5f,
-	0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74,
-	0x22, 0x47, 0x0a, 0x12, 0x43, 0x68, 0x61, 0x74, 0x73, 0x41, 0x72, 0x72, 0x61, 0x79, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x05, 0x63, 0x68, 0x61, 0x74, 0x73, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x52, 0x05, 0x63, 0x68, 0x61, 0x74, 0x73, 0x22, 0x53, 0x0a, 0x15, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x73, 0x41, 0x72, 0x72, 0x61, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x3a, 0x0a, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x22, 0x8b,
-	0x01, 0x0a, 0x11, 0x50, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05,
-	0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d,
-	0x69, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x32, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61,
-	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72,
+	0x09, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x73, 0x49, 0x64, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x75, 0x6e,
+	0x72, 0x65, 0x61, 0x64, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0b, 0x75, 0x6e, 0x72, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x32, 0x0a,
+	0x15, 0x75, 0x6e, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x6d, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x13, 0x75, 0x6e,
+	0x72, 0x65, 0x61, 0x64, 0x4d, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x22, 0x44, 0x0a, 0x0f, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x18, 0x0a,
+	0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x64, 0x0a, 0x0d, 0x46, 0x6f, 0x72, 0x77, 0x61,
+	0x72, 0x64, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x65, 0x6e, 0x64, 0x65,
+	0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x63, 0x68, 0x61, 0x74, 0x49, 0x64, 0x22, 0x6f, 0x0a,
+	0x0a, 0x50, 0x6f, 0x6c, 0x6c, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x76, 0x6f, 0x74, 0x65, 0x73, 0x5f, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x76, 0x6f, 0x74, 0x65,
+	0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x6f, 0x74, 0x65, 0x72, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x05, 0x52, 0x06, 0x76, 0x6f, 0x74, 0x65, 0x72, 0x73, 0x22, 0xa2,
+	0x02, 0x0a, 0x04, 0x50, 0x6f, 0x6c, 0x6c, 0x12, 0x33, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x50, 0x6f, 0x6c, 0x6c, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x27, 0x0a, 0x0f,
+	0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x65, 0x5f, 0x63, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x65, 0x43,
+	0x68, 0x6f, 0x69, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x6e, 0x6f, 0x6e, 0x79, 0x6d, 0x6f,
+	0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x61, 0x6e, 0x6f, 0x6e, 0x79, 0x6d,
+	0x6f, 0x75, 0x73, 0x12, 0x20, 0x0a, 0x09, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x73, 0x5f, 0x61, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x08, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x73,
+	0x41, 0x74, 0x88, 0x01, 0x01, 0x12, 0x20, 0x0a, 0x09, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x64, 0x5f,
+	0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x08, 0x63, 0x6c, 0x6f, 0x73,
+	0x65, 0x64, 0x41, 0x74, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x73, 0x5f, 0x63, 0x6c,
+	0x6f, 0x73, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x43, 0x6c,
+	0x6f, 0x73, 0x65, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x76, 0x6f,
+	0x74, 0x65, 0x72, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x56, 0x6f, 0x74, 0x65, 0x72, 0x73, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x63, 0x6c, 0x6f, 0x73,
+	0x65, 0x73, 0x5f, 0x61, 0x74, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x64,
+	0x5f, 0x61, 0x74, 0x22, 0xc8, 0x05, 0x0a, 0x0f, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x65, 0x6e, 0x64, 0x65,
+	0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x63, 0x68, 0x61, 0x74, 0x49, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x12, 0x1d, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x88, 0x01, 0x01,
+	0x12, 0x33, 0x0a, 0x05, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x53, 0x61, 0x76, 0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x48, 0x01, 0x52, 0x05, 0x76, 0x6f, 0x69,
+	0x63, 0x65, 0x88, 0x01, 0x01, 0x12, 0x35, 0x0a, 0x06, 0x63, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x61, 0x76, 0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x48,
+	0x02, 0x52, 0x06, 0x63, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x3a, 0x0a, 0x0b,
+	0x61, 0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x53, 0x61, 0x76, 0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x0b, 0x61, 0x74, 0x74,
+	0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x23, 0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6c,
+	0x79, 0x5f, 0x74, 0x6f, 0x5f, 0x69, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x48, 0x03, 0x52,
+	0x09, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x54, 0x6f, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x1c, 0x0a,
+	0x09, 0x6d, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x64, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x05,
+	0x52, 0x09, 0x6d, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x72,
+	0x65, 0x61, 0x64, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x05, 0x52, 0x08,
+	0x72, 0x65, 0x61, 0x64, 0x65, 0x64, 0x42, 0x79, 0x12, 0x3c, 0x0a, 0x09, 0x72, 0x65, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x63, 0x68,
+	0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x52, 0x65, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x72, 0x65, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x22, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x09, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x88, 0x01, 0x01, 0x12, 0x48, 0x0a, 0x0e, 0x66, 0x6f,
+	0x72, 0x77, 0x61, 0x72, 0x64, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x0e, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d,
+	0x48, 0x05, 0x52, 0x0d, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x65, 0x64, 0x46, 0x72, 0x6f,
+	0x6d, 0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x04, 0x70, 0x6f, 0x6c, 0x6c, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x50, 0x6f, 0x6c, 0x6c, 0x48, 0x06, 0x52, 0x04, 0x70, 0x6f, 0x6c, 0x6c, 0x88,
+	0x01, 0x01, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x42, 0x08,
+	0x0a, 0x06, 0x5f, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x63, 0x69, 0x72,
+	0x63, 0x6c, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x5f, 0x74, 0x6f,
+	0x5f, 0x69, 0x64, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f,
+	0x61, 0x74, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x65, 0x64,
+	0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x70, 0x6f, 0x6c, 0x6c, 0x22, 0x3a,
+	0x0a, 0x12, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x42, 0x79, 0x49, 0x64, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x3e, 0x0a, 0x14, 0x47, 0x65,
+	0x74, 0x43, 0x68, 0x61, 0x74, 0x73, 0x42, 0x79, 0x49, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x05, 0x52,
+	0x03, 0x69, 0x64, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x41, 0x0a, 0x17, 0x47, 0x65,
+	0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x42, 0x79, 0x49, 0x64, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x05, 0x52, 0x03, 0x69, 0x64, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x3d, 0x0a,
+	0x15, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x42, 0x79, 0x49, 0x64, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x98, 0x01, 0x0a,
+	0x1a, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x42, 0x79, 0x43, 0x68,
+	0x61, 0x74, 0x49, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x63,
+	0x68, 0x61, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x63, 0x68,
+	0x61, 0x74, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x06, 0x6f, 0x66,
+	0x66, 0x73, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x06, 0x6f, 0x66,
+	0x66, 0x73, 0x65, 0x74, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x88,
+	0x01, 0x01, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x42, 0x08, 0x0a,
+	0x06, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x47, 0x0a, 0x12, 0x43, 0x68, 0x61, 0x74, 0x73,
+	0x41, 0x72, 0x72, 0x61, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a,
+	0x05, 0x63, 0x68, 0x61, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x63,
+	0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x43, 0x68, 0x61,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x05, 0x63, 0x68, 0x61, 0x74, 0x73,
+	0x22, 0x53, 0x0a, 0x15, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x41, 0x72, 0x72, 0x61,
+	0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x08, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x63, 0x68,
+	0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x73, 0x22, 0x8b, 0x01, 0x0a, 0x11, 0x50, 0x61, 0x67, 0x69, 0x6e, 0x61,
+	0x74, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x6f,
+	0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12,
+	0x32, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e,
+	0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x32, 0xd7, 0x03, 0x0a, 0x05, 0x43, 0x68, 0x61, 0x74, 0x73, 0x12, 0x4f, 0x0a,
+	0x0b, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x42, 0x79, 0x49, 0x64, 0x12, 0x21, 0x2e, 0x63,
+	0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x47, 0x65, 0x74,
+	0x43, 0x68, 0x61, 0x74, 0x42, 0x79, 0x49, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1b, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x43, 0x68, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x58,
+	0x0a, 0x0e, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x42, 0x79, 0x49, 0x64,
+	0x12, 0x24, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x42, 0x79, 0x49, 0x64, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x32, 0xd7, 0x03, 0x0a,
-	0x05, 0x43, 0x68, 0x61, 0x74, 0x73, 0x12, 0x4f, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61,
-	0x74, 0x42, 0x79, 0x49, 0x64, 0x12, 0x21, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x42, 0x79, 0x49,
-	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x4d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x42, 0x79, 0x49, 0x64, 0x12, 0x24, 0x2e, 0x63, 0x68, 0x61, 0x74,
-	0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x42, 0x79, 0x49, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x1e, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x12, 0x59, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x73, 0x42, 0x79, 0x49,
-	0x64, 0x73, 0x12, 0x23, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x73, 0x42, 0x79, 0x49, 0x64, 0x73,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x73, 0x41, 0x72, 0x72,
-	0x61, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x62, 0x0a, 0x10,
-	0x47, 0x65, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x42, 0x79, 0x49, 0x64, 0x73,
-	0x12, 0x26, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x42, 0x79, 0x49, 0x64,
-	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x73, 0x41, 0x72, 0x72, 0x61, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
-	0x12, 0x64, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x42,
-	0x79, 0x43, 0x68, 0x61, 0x74, 0x49, 0x64, 0x12, 0x29, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x59, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x43,
+	0x68, 0x61, 0x74, 0x73, 0x42, 0x79, 0x49, 0x64, 0x73, 0x12, 0x23, 0x2e, 0x63, 0x68, 0x61, 0x74,
+	0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61,
+	0x74, 0x73, 0x42, 0x79, 0x49, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21,
+	0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x43,
+	0x68, 0x61, 0x74, 0x73, 0x41, 0x72, 0x72, 0x61, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x62, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x73, 0x42, 0x79, 0x49, 0x64, 0x73, 0x12, 0x26, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x73, 0x42, 0x79, 0x43, 0x68, 0x61, 0x74, 0x49, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x20, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x50, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x73, 0x22, 0x00, 0x42, 0x11, 0x5a, 0x0f, 0x2e, 0x2f, 0x63, 0x68, 0x61, 0x74,
-	0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x67, 0x65, 0x73, 0x42, 0x79, 0x49, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x24, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x41, 0x72, 0x72, 0x61, 0x79, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x64, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x42, 0x79, 0x43, 0x68, 0x61, 0x74, 0x49, 0x64, 0x12, 0x29,
+	0x2e, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x47,
+	0x65, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x42, 0x79, 0x43, 0x68, 0x61, 0x74,
+	0x49, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x63, 0x68, 0x61, 0x74,
+	0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x50, 0x61, 0x67, 0x69, 0x6e, 0x61,
+	0x74, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x22, 0x00, 0x42, 0x11, 0x5a,
+	0x0f, 0x2e, 0x2f, 0x63, 0x68, 0x61, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1013,45 +1320,51 @@ func file_chats_proto_rawDescGZIP() []byte {
 	return file_chats_proto_rawDescData
 }
 
-var file_chats_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_chats_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_chats_proto_goTypes = []interface{}{
 	(*SavedFile)(nil),                  // 0: chatsprotobuf.SavedFile
 	(*ChatResponse)(nil),               // 1: chatsprotobuf.ChatResponse
 	(*MessageReaction)(nil),            // 2: chatsprotobuf.MessageReaction
-	(*MessageResponse)(nil),            // 3: chatsprotobuf.MessageResponse
-	(*GetChatByIdRequest)(nil),         // 4: chatsprotobuf.GetChatByIdRequest
-	(*GetChatsByIdsRequest)(nil),       // 5: chatsprotobuf.GetChatsByIdsRequest
-	(*GetMessagesByIdsRequest)(nil),    // 6: chatsprotobuf.GetMessagesByIdsRequest
-	(*GetMessageByIdRequest)(nil),      // 7: chatsprotobuf.GetMessageByIdRequest
-	(*GetMessagesByChatIdRequest)(nil), // 8: chatsprotobuf.GetMessagesByChatIdRequest
-	(*ChatsArrayResponse)(nil),         // 9: chatsprotobuf.ChatsArrayResponse
-	(*MessagesArrayResponse)(nil),      // 10: chatsprotobuf.MessagesArrayResponse
-	(*PaginatedMessages)(nil),          // 11: chatsprotobuf.PaginatedMessages
+	(*ForwardedFrom)(nil),              // 3: chatsprotobuf.ForwardedFrom
+	(*PollOption)(nil),                 // 4: chatsprotobuf.PollOption
+	(*Poll)(nil),                       // 5: chatsprotobuf.Poll
+	(*MessageResponse)(nil),            // 6: chatsprotobuf.MessageResponse
+	(*GetChatByIdRequest)(nil),         // 7: chatsprotobuf.GetChatByIdRequest
+	(*GetChatsByIdsRequest)(nil),       // 8: chatsprotobuf.GetChatsByIdsRequest
+	(*GetMessagesByIdsRequest)(nil),    // 9: chatsprotobuf.GetMessagesByIdsRequest
+	(*GetMessageByIdRequest)(nil),      // 10: chatsprotobuf.GetMessageByIdRequest
+	(*GetMessagesByChatIdRequest)(nil), // 11: chatsprotobuf.GetMessagesByChatIdRequest
+	(*ChatsArrayResponse)(nil),         // 12: chatsprotobuf.ChatsArrayResponse
+	(*MessagesArrayResponse)(nil),      // 13: chatsprotobuf.MessagesArrayResponse
+	(*PaginatedMessages)(nil),          // 14: chatsprotobuf.PaginatedMessages
 }
 var file_chats_proto_depIdxs = []int32{
 	0,  // 0: chatsprotobuf.ChatResponse.avatar:type_name -> chatsprotobuf.SavedFile
-	0,  // 1: chatsprotobuf.MessageResponse.voice:type_name -> chatsprotobuf.SavedFile
-	0,  // 2: chatsprotobuf.MessageResponse.circle:type_name -> chatsprotobuf.SavedFile
-	0,  // 3: chatsprotobuf.MessageResponse.attachments:type_name -> chatsprotobuf.SavedFile
-	2,  // 4: chatsprotobuf.MessageResponse.reactions:type_name -> chatsprotobuf.MessageReaction
-	1,  // 5: chatsprotobuf.ChatsArrayResponse.chats:type_name -> chatsprotobuf.ChatResponse
-	3,  // 6: chatsprotobuf.MessagesArrayResponse.messages:type_name -> chatsprotobuf.MessageResponse
-	3,  // 7: chatsprotobuf.PaginatedMessages.data:type_name -> chatsprotobuf.MessageResponse
-	4,  // 8: chatsprotobuf.Chats.GetChatById:input_type -> chatsprotobuf.GetChatByIdRequest
-	7,  // 9: chatsprotobuf.Chats.GetMessageById:input_type -> chatsprotobuf.GetMessageByIdRequest
-	5,  // 10: chatsprotobuf.Chats.GetChatsByIds:input_type -> chatsprotobuf.GetChatsByIdsRequest
-	6,  // 11: chatsprotobuf.Chats.GetMessagesByIds:input_type -> chatsprotobuf.GetMessagesByIdsRequest
-	8,  // 12: chatsprotobuf.Chats.GetMessagesByChatId:input_type -> chatsprotobuf.GetMessagesByChatIdRequest
-	1,  // 13: chatsprotobuf.Chats.GetChatById:output_type -> chatsprotobuf.ChatResponse
-	3,  // 14: chatsprotobuf.Chats.GetMessageById:output_type -> chatsprotobuf.MessageResponse
-	9,  // 15: chatsprotobuf.Chats.GetChatsByIds:output_type -> chatsprotobuf.ChatsArrayResponse
-	10, // 16: chatsprotobuf.Chats.GetMessagesByIds:output_type -> chatsprotobuf.MessagesArrayResponse
-	11, // 17: chatsprotobuf.Chats.GetMessagesByChatId:output_type -> chatsprotobuf.PaginatedMessages
-	13, // [13:18] is the sub-list for method output_type
-	8,  // [8:13] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	4,  // 1: chatsprotobuf.Poll.options:type_name -> chatsprotobuf.PollOption
+	0,  // 2: chatsprotobuf.MessageResponse.voice:type_name -> chatsprotobuf.SavedFile
+	0,  // 3: chatsprotobuf.MessageResponse.circle:type_name -> chatsprotobuf.SavedFile
+	0,  // 4: chatsprotobuf.MessageResponse.attachments:type_name -> chatsprotobuf.SavedFile
+	2,  // 5: chatsprotobuf.MessageResponse.reactions:type_name -> chatsprotobuf.MessageReaction
+	3,  // 6: chatsprotobuf.MessageResponse.forwarded_from:type_name -> chatsprotobuf.ForwardedFrom
+	5,  // 7: chatsprotobuf.MessageResponse.poll:type_name -> chatsprotobuf.Poll
+	1,  // 8: chatsprotobuf.ChatsArrayResponse.chats:type_name -> chatsprotobuf.ChatResponse
+	6,  // 9: chatsprotobuf.MessagesArrayResponse.messages:type_name -> chatsprotobuf.MessageResponse
+	6,  // 10: chatsprotobuf.PaginatedMessages.data:type_name -> chatsprotobuf.MessageResponse
+	7,  // 11: chatsprotobuf.Chats.GetChatById:input_type -> chatsprotobuf.GetChatByIdRequest
+	10, // 12: chatsprotobuf.Chats.GetMessageById:input_type -> chatsprotobuf.GetMessageByIdRequest
+	8,  // 13: chatsprotobuf.Chats.GetChatsByIds:input_type -> chatsprotobuf.GetChatsByIdsRequest
+	9,  // 14: chatsprotobuf.Chats.GetMessagesByIds:input_type -> chatsprotobuf.GetMessagesByIdsRequest
+	11, // 15: chatsprotobuf.Chats.GetMessagesByChatId:input_type -> chatsprotobuf.GetMessagesByChatIdRequest
+	1,  // 16: chatsprotobuf.Chats.GetChatById:output_type -> chatsprotobuf.ChatResponse
+	6,  // 17: chatsprotobuf.Chats.GetMessageById:output_type -> chatsprotobuf.MessageResponse
+	12, // 18: chatsprotobuf.Chats.GetChatsByIds:output_type -> chatsprotobuf.ChatsArrayResponse
+	13, // 19: chatsprotobuf.Chats.GetMessagesByIds:output_type -> chatsprotobuf.MessagesArrayResponse
+	14, // 20: chatsprotobuf.Chats.GetMessagesByChatId:output_type -> chatsprotobuf.PaginatedMessages
+	16, // [16:21] is the sub-list for method output_type
+	11, // [11:16] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_chats_proto_init() }
@@ -1097,7 +1410,7 @@ func file_chats_proto_init() {
 			}
 		}
 		file_chats_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MessageResponse); i {
+			switch v := v.(*ForwardedFrom); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1109,7 +1422,7 @@ func file_chats_proto_init() {
 			}
 		}
 		file_chats_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetChatByIdRequest); i {
+			switch v := v.(*PollOption); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1121,7 +1434,7 @@ func file_chats_proto_init() {
 			}
 		}
 		file_chats_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetChatsByIdsRequest); i {
+			switch v := v.(*Poll); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1133,7 +1446,7 @@ func file_chats_proto_init() {
 			}
 		}
 		file_chats_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetMessagesByIdsRequest); i {
+			switch v := v.(*MessageResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1145,7 +1458,7 @@ func file_chats_proto_init() {
 			}
 		}
 		file_chats_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetMessageByIdRequest); i {
+			switch v := v.(*GetChatByIdRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1157,7 +1470,7 @@ func file_chats_proto_init() {
 			}
 		}
 		file_chats_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetMessagesByChatIdRequest); i {
+			switch v := v.(*GetChatsByIdsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1169,7 +1482,7 @@ func file_chats_proto_init() {
 			}
 		}
 		file_chats_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ChatsArrayResponse); i {
+			switch v := v.(*GetMessagesByIdsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1181,7 +1494,7 @@ func file_chats_proto_init() {
 			}
 		}
 		file_chats_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MessagesArrayResponse); i {
+			switch v := v.(*GetMessageByIdRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1193,6 +1506,42 @@ func file_chats_proto_init() {
 			}
 		}
 		file_chats_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetMessagesByChatIdRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chats_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatsArrayResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chats_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessagesArrayResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chats_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*PaginatedMessages); i {
 			case 0:
 				return &v.state
@@ -1206,15 +1555,16 @@ func file_chats_proto_init() {
 		}
 	}
 	file_chats_proto_msgTypes[0].OneofWrappers = []interface{}{}
-	file_chats_proto_msgTypes[3].OneofWrappers = []interface{}{}
-	file_chats_proto_msgTypes[8].OneofWrappers = []interface{}{}
+	file_chats_proto_msgTypes[5].OneofWrappers = []interface{}{}
+	file_chats_proto_msgTypes[6].OneofWrappers = []interface{}{}
+	file_chats_proto_msgTypes[11].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_chats_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -35,6 +35,7 @@ func ChatModelToProto(chat chats.Chat) *chatsprotobuf.ChatResponse {
 		admins = append(admins, int32(admin))
 	}
 
+	unreadCounters := chat.GetUnreadCounters()
 	return &chatsprotobuf.ChatResponse{
 		Id:         int32(chat.GetId()),
 		Avatar:     avatar,
@@ -44,6 +45,9 @@ func ChatModelToProto(chat chats.Chat) *chatsprotobuf.ChatResponse {
 		IsArchived: chat.GetIsArchived(),
 		OwnerId:    int32(chat.GetOwnerId()),
 		AdminsIds:  admins,
+
+		UnreadCount:         int32(unreadCounters.GetUnreadCount()),
+		UnreadMentionsCount: int32(unreadCounters.GetUnreadMentionsCount()),
 	}
 }
 
@@ -54,6 +58,55 @@ func ReactionToProto(reaction messages.MessageReaction) *chatsprotobuf.MessageRe
 	}
 }
 
+func ForwardedFromToProto(forwardedFrom messages.MessageForwardedFrom) *chatsprotobuf.ForwardedFrom {
+	return &chatsprotobuf.ForwardedFrom{
+		MessageId: int32(forwardedFrom.GetMessageId()),
+		SenderId:  int32(forwardedFrom.GetSenderId()),
+		ChatId:    int32(forwardedFrom.GetChatId()),
+	}
+}
+
+func PollToProto(poll messages.MessagePoll) *chatsprotobuf.Poll {
+	var options []*chatsprotobuf.PollOption
+	for _, option := range poll.GetOptions() {
+		var voters []int32
+		if !poll.GetAnonymous() {
+			for _, voter := range option.GetVoters() {
+				voters = append(voters, int32(voter))
+			}
+		}
+
+		options = append(options, &chatsprotobuf.PollOption{
+			Id:         int32(option.GetId()),
+			Content:    option.GetContent(),
+			VotesCount: int32(option.GetVotesCount()),
+			Voters:     voters,
+		})
+	}
+
+	var closesAt *string
+	if dt := poll.GetClosesAt(); dt != nil {
+		isodt := dt.Format(time.RFC3339)
+		closesAt = &isodt
+	}
+
+	var closedAt *string
+	if dt := poll.GetClosedAt(); dt != nil {
+		isodt := dt.Format(time.RFC3339)
+		closedAt = &isodt
+	}
+
+	return &chatsprotobuf.Poll{
+		Options:        options,
+		MultipleChoice: poll.GetMultipleChoice(),
+		Anonymous:      poll.GetAnonymous(),
+		ClosesAt:       closesAt,
+		ClosedAt:       closedAt,
+		IsClosed:       poll.IsClosed(),
+		TotalVoters:    int32(poll.GetTotalVoters()),
+	}
+}
+
 func MessageToProto(message messages.Message) *chatsprotobuf.MessageResponse {
 	var voice *chatsprotobuf.SavedFile
 	if file := message.GetVoice(); file != nil {
@@ -97,21 +150,33 @@ func MessageToProto(message messages.Message) *chatsprotobuf.MessageResponse {
 		createdAt = &isodt
 	}
 
+	var forwardedFrom *chatsprotobuf.ForwardedFrom
+	if messageForwardedFrom := message.GetForwardedFrom(); messageForwardedFrom != nil {
+		forwardedFrom = ForwardedFromToProto(*messageForwardedFrom)
+	}
+
+	var poll *chatsprotobuf.Poll
+	if messagePoll := message.GetPoll(); messagePoll != nil {
+		poll = PollToProto(*messagePoll)
+	}
+
 	chat := message.GetChat()
 	return &chatsprotobuf.MessageResponse{
-		Id:          int32(message.GetId()),
-		SenderId:    int32(message.GetSenderId()),
-		ChatId:      int32(chat.GetId()),
-		Type:        string(message.GetType()),
-		Content:     message.GetContent(),
-		Voice:       voice,
-		Circle:      circle,
-		Attachments: attachments,
-		ReplyToId:   replyToId,
-		Mentioned:   mentioned,
-		ReadedBy:    readedBy,
-		Reactions:   reactions,
-		CreatedAt:   createdAt,
+		Id:            int32(message.GetId()),
+		SenderId:      int32(message.GetSenderId()),
+		ChatId:        int32(chat.GetId()),
+		Type:          string(message.GetType()),
+		Content:       message.GetContent(),
+		Voice:         voice,
+		Circle:        circle,
+		Attachments:   attachments,
+		ReplyToId:     replyToId,
+		Mentioned:     mentioned,
+		ReadedBy:      readedBy,
+		Reactions:     reactions,
+		CreatedAt:     createdAt,
+		ForwardedFrom: forwardedFrom,
+		Poll:          poll,
 	}
 }
 
@@ -0,0 +1,25 @@
+package grpcservice
+
+import (
+	"context"
+	"strings"
+
+	"github.com/chack-check/chats-service/infrastructure/requestcontext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func CorrelationInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	correlationId := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(strings.ToLower(requestcontext.CorrelationIdHeader)); len(values) != 0 {
+			correlationId = values[0]
+		}
+	}
+
+	if correlationId == "" {
+		correlationId = requestcontext.NewCorrelationId()
+	}
+
+	return handler(requestcontext.WithCorrelationId(ctx, correlationId), req)
+}
@@ -16,7 +16,7 @@ func RunGrpcServer() {
 		panic(err)
 	}
 
-	var opts []grpc.ServerOption
+	opts := []grpc.ServerOption{grpc.UnaryInterceptor(CorrelationInterceptor)}
 	grpcServer := grpc.NewServer(opts...)
 	chatsServer := chatsproto.ChatsServer{}
 	chatsprotobuf.RegisterChatsServer(grpcServer, chatsServer)
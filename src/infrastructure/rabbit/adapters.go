@@ -1,45 +1,119 @@
 package rabbit
 
 import (
+	"context"
 	"log"
 
 	"github.com/chack-check/chats-service/domain/chats"
 	"github.com/chack-check/chats-service/domain/messages"
+	"github.com/chack-check/chats-service/infrastructure/broker"
+	"github.com/chack-check/chats-service/infrastructure/database"
+	"gorm.io/gorm"
 )
 
 type ChatEventsLoggingAdapter struct {
 	adapter chats.ChatEventsPort
 }
 
-func (adapter ChatEventsLoggingAdapter) SendChatCreated(chat chats.Chat) {
+func (adapter ChatEventsLoggingAdapter) SendChatCreated(chat chats.Chat) error {
 	log.Printf("sending chat created event: %+v", chat)
-	adapter.adapter.SendChatCreated(chat)
+	err := adapter.adapter.SendChatCreated(chat)
+	if err != nil {
+		log.Printf("error sending chat created event: %v", err)
+	}
+
+	return err
 }
 
-func (adapter ChatEventsLoggingAdapter) SendChatDeleted(chat chats.Chat) {
+func (adapter ChatEventsLoggingAdapter) SendChatDeleted(chat chats.Chat) error {
 	log.Printf("sending chat deleted event: %+v", chat)
-	adapter.adapter.SendChatDeleted(chat)
+	err := adapter.adapter.SendChatDeleted(chat)
+	if err != nil {
+		log.Printf("error sending chat deleted event: %v", err)
+	}
+
+	return err
 }
 
-func (adapter ChatEventsLoggingAdapter) SendChatUserAction(chat chats.Chat) {
+func (adapter ChatEventsLoggingAdapter) SendChatUserAction(chat chats.Chat) error {
 	log.Printf("sending chat user action event: %+v", chat)
-	adapter.adapter.SendChatUserAction(chat)
+	err := adapter.adapter.SendChatUserAction(chat)
+	if err != nil {
+		log.Printf("error sending chat user action event: %v", err)
+	}
+
+	return err
 }
 
-func (adapter ChatEventsLoggingAdapter) SendChatChanged(chat chats.Chat) {
+func (adapter ChatEventsLoggingAdapter) SendChatChanged(chat chats.Chat) error {
 	log.Printf("sending chat changed event: %+v", chat)
-	adapter.adapter.SendChatChanged(chat)
+	err := adapter.adapter.SendChatChanged(chat)
+	if err != nil {
+		log.Printf("error sending chat changed event: %v", err)
+	}
+
+	return err
+}
+
+func (adapter ChatEventsLoggingAdapter) SendChatChangedForUser(chat chats.Chat, userId int) error {
+	log.Printf("sending chat changed event for user %d: %+v", userId, chat)
+	err := adapter.adapter.SendChatChangedForUser(chat, userId)
+	if err != nil {
+		log.Printf("error sending chat changed event for user %d: %v", userId, err)
+	}
+
+	return err
+}
+
+func (adapter ChatEventsLoggingAdapter) SendChatArchiveChanged(chat chats.Chat, userId int) error {
+	log.Printf("sending chat archive changed event for user %d: %+v", userId, chat)
+	err := adapter.adapter.SendChatArchiveChanged(chat, userId)
+	if err != nil {
+		log.Printf("error sending chat archive changed event for user %d: %v", userId, err)
+	}
+
+	return err
+}
+
+func (adapter ChatEventsLoggingAdapter) SendChatMembersRemoved(chat chats.Chat, userIds []int) error {
+	log.Printf("sending chat members removed event for users %v: %+v", userIds, chat)
+	err := adapter.adapter.SendChatMembersRemoved(chat, userIds)
+	if err != nil {
+		log.Printf("error sending chat members removed event for users %v: %v", userIds, err)
+	}
+
+	return err
+}
+
+func (adapter ChatEventsLoggingAdapter) SendJoinRequestResolved(request chats.ChatJoinRequest) error {
+	log.Printf("sending join request resolved event: %+v", request)
+	err := adapter.adapter.SendJoinRequestResolved(request)
+	if err != nil {
+		log.Printf("error sending join request resolved event: %v", err)
+	}
+
+	return err
+}
+
+func (adapter ChatEventsLoggingAdapter) SendFoldersChanged(userId int, folders []chats.ChatFolder) error {
+	log.Printf("sending chat folders changed event: userId=%d, folders=%+v", userId, folders)
+	err := adapter.adapter.SendFoldersChanged(userId, folders)
+	if err != nil {
+		log.Printf("error sending chat folders changed event: %v", err)
+	}
+
+	return err
 }
 
 type ChatEventsAdapter struct {
-	connection RabbitConnection
+	outbox EventsOutbox
 }
 
 func (adapter ChatEventsAdapter) getSystemEventForChat(chat chats.Chat, eventType string) (*SystemEvent, error) {
 	chatEvent := ChatToChatEvent(chat)
-	systemEvent, err := NewSystemEvent(
+	systemEvent, err := NewChatSystemEvent(
 		eventType,
-		chat.GetMembers(),
+		chat,
 		chatEvent,
 	)
 	if err != nil {
@@ -49,75 +123,251 @@ func (adapter ChatEventsAdapter) getSystemEventForChat(chat chats.Chat, eventTyp
 	return systemEvent, nil
 }
 
-func (adapter ChatEventsAdapter) sendChatEvent(chat chats.Chat, eventType string) {
+func (adapter ChatEventsAdapter) sendChatEvent(chat chats.Chat, eventType string) error {
 	systemEvent, err := adapter.getSystemEventForChat(chat, eventType)
 	if err != nil {
-		return
+		return err
+	}
+
+	return adapter.outbox.SendChatEvent(chat.GetId(), systemEvent)
+}
+
+func (adapter ChatEventsAdapter) SendChatCreated(chat chats.Chat) error {
+	return adapter.sendChatEvent(chat, "chat_created")
+}
+
+func (adapter ChatEventsAdapter) SendChatDeleted(chat chats.Chat) error {
+	return adapter.sendChatEvent(chat, "chat_deleted")
+}
+
+func (adapter ChatEventsAdapter) SendChatUserAction(chat chats.Chat) error {
+	return adapter.sendChatEvent(chat, "chat_user_action")
+}
+
+func (adapter ChatEventsAdapter) SendChatChanged(chat chats.Chat) error {
+	return adapter.sendChatEvent(chat, "chat_changed")
+}
+
+func (adapter ChatEventsAdapter) SendChatChangedForUser(chat chats.Chat, userId int) error {
+	systemEvent, err := NewSystemEvent(
+		"chat_changed",
+		[]int{userId},
+		ChatToUserChatEvent(chat),
+	)
+	if err != nil {
+		return err
 	}
 
-	adapter.connection.SendEvent(systemEvent)
+	return adapter.outbox.SendChatEvent(chat.GetId(), systemEvent)
 }
 
-func (adapter ChatEventsAdapter) SendChatCreated(chat chats.Chat) {
-	adapter.sendChatEvent(chat, "chat_created")
+// SendChatArchiveChanged notifies only the user whose archive state changed,
+// the archive flag is a per-user setting and isn't part of ChatEvent.
+func (adapter ChatEventsAdapter) SendChatArchiveChanged(chat chats.Chat, userId int) error {
+	systemEvent, err := NewSystemEvent(
+		"chat_archive_changed",
+		[]int{userId},
+		ChatArchiveEvent{ChatId: chat.GetId(), IsArchived: chat.GetIsArchived()},
+	)
+	if err != nil {
+		return err
+	}
+
+	return adapter.outbox.SendUserEvent(userId, systemEvent)
 }
 
-func (adapter ChatEventsAdapter) SendChatDeleted(chat chats.Chat) {
-	adapter.sendChatEvent(chat, "chat_deleted")
+func (adapter ChatEventsAdapter) SendChatMembersRemoved(chat chats.Chat, userIds []int) error {
+	systemEvent, err := NewSystemEvent(
+		"chat_members_removed",
+		userIds,
+		ChatToChatEvent(chat),
+	)
+	if err != nil {
+		return err
+	}
+
+	return adapter.outbox.SendChatEvent(chat.GetId(), systemEvent)
 }
 
-func (adapter ChatEventsAdapter) SendChatUserAction(chat chats.Chat) {
-	adapter.sendChatEvent(chat, "chat_user_action")
+func (adapter ChatEventsAdapter) SendJoinRequestResolved(request chats.ChatJoinRequest) error {
+	eventType := "join_request_declined"
+	if request.GetStatus() == chats.ApprovedJoinRequestStatus {
+		eventType = "join_request_approved"
+	}
+
+	systemEvent, err := NewSystemEvent(
+		eventType,
+		[]int{request.GetUserId()},
+		JoinRequestToJoinRequestEvent(request),
+	)
+	if err != nil {
+		return err
+	}
+
+	return adapter.outbox.SendChatEvent(request.GetChatId(), systemEvent)
 }
 
-func (adapter ChatEventsAdapter) SendChatChanged(chat chats.Chat) {
-	adapter.sendChatEvent(chat, "chat_changed")
+func (adapter ChatEventsAdapter) SendFoldersChanged(userId int, folders []chats.ChatFolder) error {
+	foldersEvent := ChatFoldersEvent{UserId: userId, Folders: []ChatFolderEvent{}}
+	for _, folder := range folders {
+		foldersEvent.Folders = append(foldersEvent.Folders, ChatFolderToChatFolderEvent(folder))
+	}
+
+	systemEvent, err := NewSystemEvent(
+		"chat_folders_changed",
+		[]int{userId},
+		foldersEvent,
+	)
+	if err != nil {
+		return err
+	}
+
+	return adapter.outbox.SendUserEvent(userId, systemEvent)
 }
 
 type MessageEventsLoggingAdapter struct {
 	adapter messages.MessageEventsPort
 }
 
-func (adapter MessageEventsLoggingAdapter) SendMessageReacted(message messages.Message) {
+func (adapter MessageEventsLoggingAdapter) SendMessageReacted(message messages.Message) error {
 	log.Printf("sending message reacted event: %+v", message)
-	adapter.adapter.SendMessageReacted(message)
+	err := adapter.adapter.SendMessageReacted(message)
+	if err != nil {
+		log.Printf("error sending message reacted event: %v", err)
+	}
+
+	return err
 }
 
-func (adapter MessageEventsLoggingAdapter) SendReactionDeleted(message messages.Message) {
+func (adapter MessageEventsLoggingAdapter) SendReactionDeleted(message messages.Message) error {
 	log.Printf("sending message reaction deleted event: %+v", message)
-	adapter.adapter.SendReactionDeleted(message)
+	err := adapter.adapter.SendReactionDeleted(message)
+	if err != nil {
+		log.Printf("error sending message reaction deleted event: %v", err)
+	}
+
+	return err
 }
 
-func (adapter MessageEventsLoggingAdapter) SendMessageReaded(message messages.Message) {
+func (adapter MessageEventsLoggingAdapter) SendMessageReaded(message messages.Message) error {
 	log.Printf("sending message readed event: %+v", message)
-	adapter.adapter.SendMessageReaded(message)
+	err := adapter.adapter.SendMessageReaded(message)
+	if err != nil {
+		log.Printf("error sending message readed event: %v", err)
+	}
+
+	return err
 }
 
-func (adapter MessageEventsLoggingAdapter) SendMessageDeleted(message messages.Message) {
+func (adapter MessageEventsLoggingAdapter) SendMessageDeleted(message messages.Message) error {
 	log.Printf("sending message deleted event: %+v", message)
-	adapter.adapter.SendMessageDeleted(message)
+	err := adapter.adapter.SendMessageDeleted(message)
+	if err != nil {
+		log.Printf("error sending message deleted event: %v", err)
+	}
+
+	return err
 }
 
-func (adapter MessageEventsLoggingAdapter) SendMessageUpdated(message messages.Message) {
+func (adapter MessageEventsLoggingAdapter) SendMessageDeletedForUser(message messages.Message, userId int) error {
+	log.Printf("sending message deleted for user event: message=%+v, userId=%d", message, userId)
+	err := adapter.adapter.SendMessageDeletedForUser(message, userId)
+	if err != nil {
+		log.Printf("error sending message deleted for user event: %v", err)
+	}
+
+	return err
+}
+
+func (adapter MessageEventsLoggingAdapter) SendMessageUpdated(message messages.Message) error {
 	log.Printf("sending message updated event: %+v", message)
-	adapter.adapter.SendMessageUpdated(message)
+	err := adapter.adapter.SendMessageUpdated(message)
+	if err != nil {
+		log.Printf("error sending message updated event: %v", err)
+	}
+
+	return err
 }
 
-func (adapter MessageEventsLoggingAdapter) SendMessageCreated(message messages.Message) {
+func (adapter MessageEventsLoggingAdapter) SendMessageCreated(message messages.Message) error {
 	log.Printf("sending message created event: %+v", message)
-	adapter.adapter.SendMessageCreated(message)
+	err := adapter.adapter.SendMessageCreated(message)
+	if err != nil {
+		log.Printf("error sending message created event: %v", err)
+	}
+
+	return err
+}
+
+func (adapter MessageEventsLoggingAdapter) SendThreadUpdated(message messages.Message) error {
+	log.Printf("sending thread updated event: %+v", message)
+	err := adapter.adapter.SendThreadUpdated(message)
+	if err != nil {
+		log.Printf("error sending thread updated event: %v", err)
+	}
+
+	return err
+}
+
+func (adapter MessageEventsLoggingAdapter) SendMessagePinned(message messages.Message) error {
+	log.Printf("sending message pinned event: %+v", message)
+	err := adapter.adapter.SendMessagePinned(message)
+	if err != nil {
+		log.Printf("error sending message pinned event: %v", err)
+	}
+
+	return err
+}
+
+func (adapter MessageEventsLoggingAdapter) SendMessageUnpinned(message messages.Message) error {
+	log.Printf("sending message unpinned event: %+v", message)
+	err := adapter.adapter.SendMessageUnpinned(message)
+	if err != nil {
+		log.Printf("error sending message unpinned event: %v", err)
+	}
+
+	return err
+}
+
+func (adapter MessageEventsLoggingAdapter) SendPollUpdated(message messages.Message) error {
+	log.Printf("sending poll updated event: %+v", message)
+	err := adapter.adapter.SendPollUpdated(message)
+	if err != nil {
+		log.Printf("error sending poll updated event: %v", err)
+	}
+
+	return err
+}
+
+func (adapter MessageEventsLoggingAdapter) SendChatRead(chat chats.Chat, pointer messages.ChatReadPointer) error {
+	log.Printf("sending chat read event: chatId=%d, pointer=%+v", chat.GetId(), pointer)
+	err := adapter.adapter.SendChatRead(chat, pointer)
+	if err != nil {
+		log.Printf("error sending chat read event: %v", err)
+	}
+
+	return err
+}
+
+func (adapter MessageEventsLoggingAdapter) SendScheduledMessageDropped(message messages.Message) error {
+	log.Printf("sending scheduled message dropped event: %+v", message)
+	err := adapter.adapter.SendScheduledMessageDropped(message)
+	if err != nil {
+		log.Printf("error sending scheduled message dropped event: %v", err)
+	}
+
+	return err
 }
 
 type MessageEventsAdapter struct {
-	connection RabbitConnection
+	outbox EventsOutbox
 }
 
-func (adapter MessageEventsAdapter) getSystemEventForMessage(message messages.Message, eventType string) (*SystemEvent, error) {
+func (adapter MessageEventsAdapter) getSystemEventForMessage(message messages.Message, eventType string, includedUsers []int) (*SystemEvent, error) {
 	messageEvent := MessageToMessageEvent(message)
-	chat := message.GetChat()
 	systemEvent, err := NewSystemEvent(
 		eventType,
-		chat.GetMembers(),
+		includedUsers,
 		messageEvent,
 	)
 	if err != nil {
@@ -127,43 +377,105 @@ func (adapter MessageEventsAdapter) getSystemEventForMessage(message messages.Me
 	return systemEvent, nil
 }
 
-func (adapter MessageEventsAdapter) sendMessageEvent(message messages.Message, eventType string) {
-	systemEvent, err := adapter.getSystemEventForMessage(message, eventType)
+func (adapter MessageEventsAdapter) sendMessageEventToUsers(message messages.Message, eventType string, includedUsers []int) error {
+	systemEvent, err := adapter.getSystemEventForMessage(message, eventType, includedUsers)
 	if err != nil {
-		return
+		return err
 	}
 
-	adapter.connection.SendEvent(systemEvent)
+	chat := message.GetChat()
+	return adapter.outbox.SendChatEvent(chat.GetId(), systemEvent)
 }
 
-func (adapter MessageEventsAdapter) SendMessageReacted(message messages.Message) {
-	adapter.sendMessageEvent(message, "message_reacted")
+func (adapter MessageEventsAdapter) sendMessageEvent(message messages.Message, eventType string) error {
+	chat := message.GetChat()
+	systemEvent, err := NewChatSystemEvent(
+		eventType,
+		chat,
+		MessageToMessageEvent(message),
+	)
+	if err != nil {
+		return err
+	}
+
+	return adapter.outbox.SendChatEvent(chat.GetId(), systemEvent)
 }
 
-func (adapter MessageEventsAdapter) SendReactionDeleted(message messages.Message) {
-	adapter.sendMessageEvent(message, "message_reaction_deleted")
+func (adapter MessageEventsAdapter) SendMessageReacted(message messages.Message) error {
+	return adapter.sendMessageEvent(message, "message_reacted")
 }
 
-func (adapter MessageEventsAdapter) SendMessageReaded(message messages.Message) {
-	adapter.sendMessageEvent(message, "message_readed")
+func (adapter MessageEventsAdapter) SendReactionDeleted(message messages.Message) error {
+	return adapter.sendMessageEvent(message, "message_reaction_deleted")
 }
 
-func (adapter MessageEventsAdapter) SendMessageDeleted(message messages.Message) {
-	adapter.sendMessageEvent(message, "message_deleted")
+func (adapter MessageEventsAdapter) SendMessageReaded(message messages.Message) error {
+	return adapter.sendMessageEvent(message, "message_readed")
 }
 
-func (adapter MessageEventsAdapter) SendMessageUpdated(message messages.Message) {
-	adapter.sendMessageEvent(message, "message_updated")
+func (adapter MessageEventsAdapter) SendMessageDeleted(message messages.Message) error {
+	return adapter.sendMessageEvent(message, "message_deleted")
 }
 
-func (adapter MessageEventsAdapter) SendMessageCreated(message messages.Message) {
-	adapter.sendMessageEvent(message, "message_created")
+func (adapter MessageEventsAdapter) SendMessageDeletedForUser(message messages.Message, userId int) error {
+	return adapter.sendMessageEventToUsers(message, "message_deleted_for_user", []int{userId})
 }
 
-func NewChatEventsAdapter(connection RabbitConnection) chats.ChatEventsPort {
-	return ChatEventsLoggingAdapter{adapter: ChatEventsAdapter{connection: connection}}
+func (adapter MessageEventsAdapter) SendMessageUpdated(message messages.Message) error {
+	return adapter.sendMessageEvent(message, "message_updated")
 }
 
-func NewMessageEventsAdapter(connection RabbitConnection) messages.MessageEventsPort {
-	return MessageEventsLoggingAdapter{adapter: MessageEventsAdapter{connection: connection}}
+func (adapter MessageEventsAdapter) SendMessageCreated(message messages.Message) error {
+	return adapter.sendMessageEvent(message, "message_created")
+}
+
+func (adapter MessageEventsAdapter) SendThreadUpdated(message messages.Message) error {
+	return adapter.sendMessageEvent(message, "thread_updated")
+}
+
+func (adapter MessageEventsAdapter) SendMessagePinned(message messages.Message) error {
+	return adapter.sendMessageEvent(message, "message_pinned")
+}
+
+func (adapter MessageEventsAdapter) SendMessageUnpinned(message messages.Message) error {
+	return adapter.sendMessageEvent(message, "message_unpinned")
+}
+
+func (adapter MessageEventsAdapter) SendPollUpdated(message messages.Message) error {
+	return adapter.sendMessageEvent(message, "poll_updated")
+}
+
+func (adapter MessageEventsAdapter) SendScheduledMessageDropped(message messages.Message) error {
+	return adapter.sendMessageEventToUsers(message, "scheduled_message_dropped", []int{message.GetSenderId()})
+}
+
+func (adapter MessageEventsAdapter) SendChatRead(chat chats.Chat, pointer messages.ChatReadPointer) error {
+	systemEvent, err := NewChatSystemEvent(
+		"chat_read",
+		chat,
+		ChatReadPointerToChatReadEvent(pointer),
+	)
+	if err != nil {
+		return err
+	}
+
+	return adapter.outbox.SendChatEvent(chat.GetId(), systemEvent)
+}
+
+func afterCommit(db gorm.DB) func(publish func()) {
+	return func(publish func()) {
+		database.AfterCommit(db, publish)
+	}
+}
+
+func NewChatEventsAdapter(ctx context.Context, db gorm.DB) chats.ChatEventsPort {
+	return ChatEventsLoggingAdapter{
+		adapter: broker.NewChatEventsAdapter(broker.EventsBroker, afterCommit(db), ChatEventsAdapter{outbox: NewEventsOutbox(ctx, db)}),
+	}
+}
+
+func NewMessageEventsAdapter(ctx context.Context, db gorm.DB) messages.MessageEventsPort {
+	return MessageEventsLoggingAdapter{
+		adapter: broker.NewMessageEventsAdapter(broker.EventsBroker, afterCommit(db), MessageEventsAdapter{outbox: NewEventsOutbox(ctx, db)}),
+	}
 }
@@ -2,13 +2,16 @@ package rabbit
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+var ErrEventNotConfirmed = fmt.Errorf("event was not confirmed by rabbitmq")
+
 func failOnError(err error, msg string) {
 	if err != nil {
 		log.Panicf("%s: %s", msg, err)
@@ -31,15 +34,25 @@ type EventActionUser struct {
 }
 
 type ChatEvent struct {
-	Id         int                          `json:"id"`
-	Avatar     *EventSavedFile              `json:"avatar"`
-	Title      string                       `json:"title"`
-	Type       string                       `json:"type"`
-	Members    []int                        `json:"members"`
-	IsArchived bool                         `json:"isArchived"`
-	OwnerId    int                          `json:"ownerId"`
-	Admins     []int                        `json:"admins"`
-	Actions    map[string][]EventActionUser `json:"actions"`
+	Id            int                          `json:"id"`
+	Avatar        *EventSavedFile              `json:"avatar"`
+	Title         string                       `json:"title"`
+	Type          string                       `json:"type"`
+	Slug          *string                      `json:"slug"`
+	Description   *string                      `json:"description"`
+	Rules         *string                      `json:"rules"`
+	Members       []int                        `json:"members"`
+	IsUserDeleted bool                         `json:"isUserDeleted"`
+	OwnerId       int                          `json:"ownerId"`
+	Admins        []int                        `json:"admins"`
+	Actions       map[string][]EventActionUser `json:"actions"`
+
+	SubscribersCount int `json:"subscribersCount"`
+
+	// The unread counters are set only on events delivered to a single
+	// member, a shared event can't carry every member's counters.
+	UnreadCount         *int `json:"unreadCount,omitempty"`
+	UnreadMentionsCount *int `json:"unreadMentionsCount,omitempty"`
 }
 
 type EventMessageReaction struct {
@@ -47,20 +60,92 @@ type EventMessageReaction struct {
 	Content string `json:"content"`
 }
 
+type EventThreadReply struct {
+	MessageId int       `json:"messageId"`
+	SenderId  int       `json:"senderId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type EventForwardedFrom struct {
+	MessageId int `json:"messageId"`
+	SenderId  int `json:"senderId"`
+	ChatId    int `json:"chatId"`
+}
+
+type EventPollOption struct {
+	Id         int    `json:"id"`
+	Content    string `json:"content"`
+	VotesCount int    `json:"votesCount"`
+	Voters     []int  `json:"voters"`
+}
+
+type EventPoll struct {
+	Options        []EventPollOption `json:"options"`
+	MultipleChoice bool              `json:"multipleChoice"`
+	Anonymous      bool              `json:"anonymous"`
+	ClosesAt       *time.Time        `json:"closesAt"`
+	ClosedAt       *time.Time        `json:"closedAt"`
+	IsClosed       bool              `json:"isClosed"`
+	TotalVoters    int               `json:"totalVoters"`
+}
+
+type JoinRequestEvent struct {
+	Id         int        `json:"id"`
+	ChatId     int        `json:"chatId"`
+	UserId     int        `json:"userId"`
+	Status     string     `json:"status"`
+	ResolvedBy *int       `json:"resolvedBy"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ResolvedAt *time.Time `json:"resolvedAt"`
+}
+
+type ChatFolderEvent struct {
+	Id         int      `json:"id"`
+	Title      string   `json:"title"`
+	Position   int      `json:"position"`
+	ChatIds    []int    `json:"chatIds"`
+	ChatTypes  []string `json:"chatTypes"`
+	UnreadOnly bool     `json:"unreadOnly"`
+	MutedOnly  bool     `json:"mutedOnly"`
+}
+
+type ChatArchiveEvent struct {
+	ChatId     int  `json:"chatId"`
+	IsArchived bool `json:"isArchived"`
+}
+
+type ChatFoldersEvent struct {
+	UserId  int               `json:"userId"`
+	Folders []ChatFolderEvent `json:"folders"`
+}
+
+type ChatReadEvent struct {
+	ChatId    int       `json:"chatId"`
+	UserId    int       `json:"userId"`
+	MessageId int       `json:"messageId"`
+	ReadAt    time.Time `json:"readAt"`
+}
+
 type MessageEvent struct {
-	Id          int                    `json:"id"`
-	SenderId    int                    `json:"senderId"`
-	ChatId      int                    `json:"chatId"`
-	Type        string                 `json:"type"`
-	Content     *string                `json:"content"`
-	Voice       *EventSavedFile        `json:"voice"`
-	Circle      *EventSavedFile        `json:"circle"`
-	Attachments []EventSavedFile       `json:"attachments"`
-	ReplyToId   *int                   `json:"replyToId"`
-	Mentioned   []int                  `json:"mentioned"`
-	ReadedBy    []int                  `json:"readedBy"`
-	Reactions   []EventMessageReaction `json:"reactions"`
-	CreatedAt   *time.Time             `json:"createdAt"`
+	Id                 int                    `json:"id"`
+	SenderId           int                    `json:"senderId"`
+	ChatId             int                    `json:"chatId"`
+	Type               string                 `json:"type"`
+	Content            *string                `json:"content"`
+	Voice              *EventSavedFile        `json:"voice"`
+	Circle             *EventSavedFile        `json:"circle"`
+	Attachments        []EventSavedFile       `json:"attachments"`
+	ReplyToId          *int                   `json:"replyToId"`
+	ThreadRootId       *int                   `json:"threadRootId"`
+	ThreadRepliesCount int                    `json:"threadRepliesCount"`
+	ThreadLastReply    *EventThreadReply      `json:"threadLastReply"`
+	ForwardedFrom      *EventForwardedFrom    `json:"forwardedFrom"`
+	Mentioned          []int                  `json:"mentioned"`
+	ReadedBy           []int                  `json:"readedBy"`
+	Reactions          []EventMessageReaction `json:"reactions"`
+	CreatedAt          *time.Time             `json:"createdAt"`
+	EditedAt           *time.Time             `json:"editedAt"`
+	Poll               *EventPoll             `json:"poll"`
 }
 
 type RabbitConnection struct {
@@ -70,14 +155,32 @@ type RabbitConnection struct {
 	Channel      *amqp.Channel
 }
 
-func (conn *RabbitConnection) Connect() {
+// dial opens a new connection and a channel in confirm mode, so every publish
+// is acknowledged by the broker before the event is considered sent.
+func (conn *RabbitConnection) dial() error {
 	connection, err := amqp.Dial(conn.Host)
-	failOnError(err, "Failed to connect to RabbitMQ")
-	conn.Connection = connection
+	if err != nil {
+		return errors.Join(fmt.Errorf("failed to connect to rabbitmq"), err)
+	}
 
 	channel, err := connection.Channel()
-	failOnError(err, "Failed to open a channel")
+	if err != nil {
+		connection.Close()
+		return errors.Join(fmt.Errorf("failed to open a channel"), err)
+	}
+
+	if err := channel.Confirm(false); err != nil {
+		connection.Close()
+		return errors.Join(fmt.Errorf("failed to enable publisher confirms"), err)
+	}
+
+	conn.Connection = connection
 	conn.Channel = channel
+	return nil
+}
+
+func (conn *RabbitConnection) Connect() {
+	failOnError(conn.dial(), "Failed to connect to RabbitMQ")
 }
 
 func (conn *RabbitConnection) DeclareExchange() {
@@ -93,33 +196,47 @@ func (conn *RabbitConnection) DeclareExchange() {
 	failOnError(err, "Failed to declare an exchange")
 }
 
-func (conn *RabbitConnection) SendEvent(event interface{}) error {
-	body, err := json.Marshal(event)
-	if err != nil {
-		return err
-	}
-
-	log.Printf("Sending message to queue: %+v", event)
-
-	if conn.Connection.IsClosed() {
+// PublishEvent publishes an already serialized event and waits for the broker
+// confirmation. The event id is sent as the message id so consumers can drop
+// redelivered duplicates.
+func (conn *RabbitConnection) PublishEvent(eventId string, body []byte) error {
+	if conn.Connection.IsClosed() || conn.Channel.IsClosed() {
 		log.Printf("Rabbitmq connection is closed. Reconnecting")
-		conn.Connect()
+		if err := conn.dial(); err != nil {
+			return err
+		}
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	log.Printf("Sending content to rabbitmq: %s, exchange name: %s, closed: %v", body, conn.ExchangeName, conn.Connection.IsClosed())
-	return conn.Channel.PublishWithContext(
+	log.Printf("Sending content to rabbitmq: %s, event id: %s, exchange name: %s", body, eventId, conn.ExchangeName)
+	confirmation, err := conn.Channel.PublishWithDeferredConfirmWithContext(
 		ctx,
 		conn.ExchangeName,
 		"",
 		false,
 		false,
 		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			MessageId:    eventId,
+			Body:         body,
 		},
 	)
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !acked {
+		return ErrEventNotConfirmed
+	}
+
+	return nil
 }
 
 func (conn *RabbitConnection) Close() {
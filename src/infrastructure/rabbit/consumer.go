@@ -2,42 +2,46 @@ package rabbit
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
-
-	"github.com/getsentry/sentry-go"
 )
 
 func StartConsumer(ctag string) error {
 	queue := NewQueue(Settings.APP_RABBIT_HOST, Settings.APP_RABBIT_CONSUMER_QUEUE_NAME, Settings.APP_RABBIT_USERS_EXCHANGE_NAME)
 	recognitionQueue := NewQueue(Settings.APP_RABBIT_HOST, Settings.APP_RABBIT_RECOGNITION_QUEUE_NAME, Settings.APP_RABBIT_RECOGNITION_EXCHANGE_NAME)
 
-	queue.Consume(func(msg []byte) {
+	queue.Consume(func(msg []byte) error {
 		log.Printf("fetched event: %s", string(msg))
 		var event SystemEvent
 		err := json.Unmarshal(msg, &event)
 		if err != nil {
-			log.Printf("Error unmarshalling message: %v", err)
-			sentry.CaptureException(err)
-			return
+			return errors.Join(ErrEventNotProcessable, err)
 		}
 
-		if event.EventType == "user_created" {
+		switch event.EventType {
+		case "user_created":
 			log.Printf("Fetched user created event: %+v", event)
-			HandleUserCreated(event)
+			return HandleUserCreated(event)
+		case "user_updated":
+			log.Printf("Fetched user updated event: %+v", event)
+			return HandleUserUpdated(event)
+		case "user_deleted":
+			log.Printf("Fetched user deleted event: %+v", event)
+			return HandleUserDeleted(event)
 		}
+
+		return nil
 	})
 
-	recognitionQueue.Consume(func(msg []byte) {
+	recognitionQueue.Consume(func(msg []byte) error {
 		log.Printf("fetched recognition event: %s", string(msg))
 		var event RecognitionEvent
 		err := json.Unmarshal(msg, &event)
 		if err != nil {
-			log.Printf("Error unmarshalling message: %v", err)
-			sentry.CaptureException(err)
-			return
+			return errors.Join(ErrEventNotProcessable, err)
 		}
 
-		HandleMessageRecognized(event.MessageId, event.Content)
+		return HandleMessageRecognized(event.MessageId, event.Content)
 	})
 
 	return nil
@@ -1,18 +1,52 @@
 package rabbit
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/chack-check/chats-service/domain/chats"
 	"github.com/chack-check/chats-service/domain/messages"
 	"github.com/chack-check/chats-service/infrastructure/database"
+	"github.com/chack-check/chats-service/infrastructure/grpc_service/usersproto"
+	"github.com/chack-check/chats-service/infrastructure/redisdb"
+	"github.com/chack-check/chats-service/infrastructure/requestcontext"
+	"gorm.io/gorm"
 )
 
+// SystemEventSchemaVersion is bumped whenever the envelope or an event payload
+// changes incompatibly. Version 1 events had no envelope fields at all.
+const SystemEventSchemaVersion = 2
+
+// SystemEvent is the envelope of every published event. The envelope fields
+// were added next to the original included_users, event_type and data keys,
+// which keep their meaning so version 1 consumers continue to work unchanged.
+//
+// Channel events are addressed by BroadcastChatId instead of listing every
+// subscriber in IncludedUsers; consumers deliver them to the chat subscribers.
 type SystemEvent struct {
-	IncludedUsers []int  `json:"included_users"`
-	EventType     string `json:"event_type"`
-	Data          string `json:"data"`
+	EventId       string    `json:"event_id"`
+	SchemaVersion int       `json:"schema_version"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	ActorId       *int      `json:"actor_id"`
+	ChatId        *int      `json:"chat_id"`
+	CorrelationId string    `json:"correlation_id"`
+
+	IncludedUsers   []int  `json:"included_users"`
+	BroadcastChatId *int   `json:"broadcast_chat_id,omitempty"`
+	EventType       string `json:"event_type"`
+	Data            string `json:"data"`
+}
+
+func NewEventId() string {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	failOnError(err, "Failed to generate event id")
+	return hex.EncodeToString(id)
 }
 
 func NewSystemEvent(eventType string, includedUsers []int, data interface{}) (*SystemEvent, error) {
@@ -21,7 +55,32 @@ func NewSystemEvent(eventType string, includedUsers []int, data interface{}) (*S
 		return nil, err
 	}
 
-	return &SystemEvent{IncludedUsers: includedUsers, EventType: eventType, Data: string(json_data)}, nil
+	return &SystemEvent{
+		EventId:       NewEventId(),
+		SchemaVersion: SystemEventSchemaVersion,
+		OccurredAt:    time.Now().UTC(),
+		IncludedUsers: includedUsers,
+		EventType:     eventType,
+		Data:          string(json_data),
+	}, nil
+}
+
+func NewBroadcastSystemEvent(eventType string, chatId int, data interface{}) (*SystemEvent, error) {
+	systemEvent, err := NewSystemEvent(eventType, []int{}, data)
+	if err != nil {
+		return nil, err
+	}
+
+	systemEvent.BroadcastChatId = &chatId
+	return systemEvent, nil
+}
+
+func NewChatSystemEvent(eventType string, chat chats.Chat, data interface{}) (*SystemEvent, error) {
+	if chat.GetType() == chats.ChannelChatType {
+		return NewBroadcastSystemEvent(eventType, chat.GetId(), data)
+	}
+
+	return NewSystemEvent(eventType, chat.GetMembers(), data)
 }
 
 type RecognitionEvent struct {
@@ -29,24 +88,92 @@ type RecognitionEvent struct {
 	Content   string `json:"content"`
 }
 
-func HandleUserCreated(event SystemEvent) {
+// parseEventUser decodes the user of a users service event. Payloads without
+// a user id are rejected instead of being handled for a zero user.
+func parseEventUser(event SystemEvent) (EventUser, error) {
 	var eventUser EventUser
-	err := json.Unmarshal([]byte(event.Data), &eventUser)
+	if err := json.Unmarshal([]byte(event.Data), &eventUser); err != nil {
+		return eventUser, errors.Join(ErrEventNotProcessable, err)
+	}
+
+	if eventUser.Id <= 0 {
+		return eventUser, errors.Join(ErrEventNotProcessable, fmt.Errorf("event %s has no user id", event.EventType))
+	}
+
+	return eventUser, nil
+}
+
+// eventContext carries the correlation id of a consumed event over to the
+// events published while handling it.
+func eventContext(event SystemEvent) context.Context {
+	ctx := context.Background()
+	if event.CorrelationId != "" {
+		ctx = requestcontext.WithCorrelationId(ctx, event.CorrelationId)
+	}
+
+	return ctx
+}
+
+func HandleUserCreated(event SystemEvent) error {
+	eventUser, err := parseEventUser(event)
 	if err != nil {
-		log.Printf("error unmarshaling event user data: %v", err)
+		return err
 	}
 
-	data := chats.NewCreateChatData(chats.SavedMessagesChatType, nil, nil, []int{}, &eventUser.Id)
+	data := chats.NewCreateChatData(chats.SavedMessagesChatType, nil, nil, []int{}, &eventUser.Id, nil)
 	handler := chats.NewCreateSavedMessagesChatHandler(
 		database.NewChatsAdapter(*database.DatabaseConnection),
 	)
-	handler.Execute(data, eventUser.Id)
+	_, err = handler.Execute(data, eventUser.Id)
+	return err
 }
 
-func HandleMessageRecognized(messageId int, content string) {
-	handler := messages.NewRecognizeMessageHandler(
-		database.NewMessagesAdapter(*database.DatabaseConnection),
-		NewMessageEventsAdapter(*EventsRabbitConnection),
-	)
-	handler.Execute(messageId, content)
+func HandleUserUpdated(event SystemEvent) error {
+	eventUser, err := parseEventUser(event)
+	if err != nil {
+		return err
+	}
+
+	ctx := eventContext(event)
+	return database.Transaction(func(tx gorm.DB) error {
+		handler := chats.NewUpdateUserDataHandler(
+			database.NewChatsAdapter(tx),
+			NewChatEventsAdapter(ctx, tx),
+			usersproto.NewUsersAdapter(usersproto.UsersClientConnect()),
+			redisdb.NewUserActionsAdapter(redisdb.RedisConnection),
+		)
+		return handler.Execute(eventUser.Id)
+	})
+}
+
+func HandleUserDeleted(event SystemEvent) error {
+	eventUser, err := parseEventUser(event)
+	if err != nil {
+		return err
+	}
+
+	ctx := eventContext(event)
+	return database.Transaction(func(tx gorm.DB) error {
+		handler := chats.NewDeleteUserDataHandler(
+			database.NewChatsAdapter(tx),
+			NewChatEventsAdapter(ctx, tx),
+			redisdb.NewUserActionsAdapter(redisdb.RedisConnection),
+		)
+		return handler.Execute(eventUser.Id)
+	})
+}
+
+func HandleMessageRecognized(messageId int, content string) error {
+	err := database.Transaction(func(tx gorm.DB) error {
+		handler := messages.NewRecognizeMessageHandler(
+			database.NewMessagesAdapter(tx),
+			NewMessageEventsAdapter(context.Background(), tx),
+		)
+		return handler.Execute(messageId, content)
+	})
+	if errors.Is(err, messages.ErrMessageNotFound) {
+		return errors.Join(ErrEventNotProcessable, err)
+	}
+
+	return err
 }
@@ -43,19 +43,40 @@ func ChatToChatEvent(chat chats.Chat) ChatEvent {
 		actions[string(action)] = eventUsers
 	}
 
+	members := chat.GetMembers()
+	if chat.GetType() == chats.ChannelChatType {
+		members = []int{}
+	}
+
 	return ChatEvent{
-		Id:         chat.GetId(),
-		Avatar:     avatar,
-		Title:      chat.GetTitle(),
-		Type:       string(chat.GetType()),
-		Members:    chat.GetMembers(),
-		IsArchived: chat.GetIsArchived(),
-		OwnerId:    chat.GetOwnerId(),
-		Admins:     chat.GetAdmins(),
-		Actions:    actions,
+		Id:               chat.GetId(),
+		Avatar:           avatar,
+		Title:            chat.GetTitle(),
+		Type:             string(chat.GetType()),
+		Slug:             chat.GetSlug(),
+		Description:      chat.GetDescription(),
+		Rules:            chat.GetRules(),
+		Members:          members,
+		SubscribersCount: chat.GetSubscribersCount(),
+		IsUserDeleted:    chat.GetIsUserDeleted(),
+		OwnerId:          chat.GetOwnerId(),
+		Admins:           chat.GetAdmins(),
+		Actions:          actions,
 	}
 }
 
+// ChatToUserChatEvent builds the event of a chat loaded for a single member,
+// including that member's unread counters.
+func ChatToUserChatEvent(chat chats.Chat) ChatEvent {
+	chatEvent := ChatToChatEvent(chat)
+	unreadCounters := chat.GetUnreadCounters()
+	unreadCount := unreadCounters.GetUnreadCount()
+	unreadMentionsCount := unreadCounters.GetUnreadMentionsCount()
+	chatEvent.UnreadCount = &unreadCount
+	chatEvent.UnreadMentionsCount = &unreadMentionsCount
+	return chatEvent
+}
+
 func MessageReactionToEventReaction(reaction messages.MessageReaction) EventMessageReaction {
 	return EventMessageReaction{
 		UserId:  reaction.GetUserId(),
@@ -63,6 +84,49 @@ func MessageReactionToEventReaction(reaction messages.MessageReaction) EventMess
 	}
 }
 
+func ThreadReplyToEventThreadReply(reply messages.MessageThreadReply) EventThreadReply {
+	return EventThreadReply{
+		MessageId: reply.GetMessageId(),
+		SenderId:  reply.GetSenderId(),
+		CreatedAt: reply.GetCreatedAt(),
+	}
+}
+
+func ForwardedFromToEventForwardedFrom(forwardedFrom messages.MessageForwardedFrom) EventForwardedFrom {
+	return EventForwardedFrom{
+		MessageId: forwardedFrom.GetMessageId(),
+		SenderId:  forwardedFrom.GetSenderId(),
+		ChatId:    forwardedFrom.GetChatId(),
+	}
+}
+
+func PollToEventPoll(poll messages.MessagePoll) EventPoll {
+	var options []EventPollOption
+	for _, option := range poll.GetOptions() {
+		var voters []int
+		if !poll.GetAnonymous() {
+			voters = option.GetVoters()
+		}
+
+		options = append(options, EventPollOption{
+			Id:         option.GetId(),
+			Content:    option.GetContent(),
+			VotesCount: option.GetVotesCount(),
+			Voters:     voters,
+		})
+	}
+
+	return EventPoll{
+		Options:        options,
+		MultipleChoice: poll.GetMultipleChoice(),
+		Anonymous:      poll.GetAnonymous(),
+		ClosesAt:       poll.GetClosesAt(),
+		ClosedAt:       poll.GetClosedAt(),
+		IsClosed:       poll.IsClosed(),
+		TotalVoters:    poll.GetTotalVoters(),
+	}
+}
+
 func MessageToMessageEvent(message messages.Message) MessageEvent {
 	chat := message.GetChat()
 	var eventVoice *EventSavedFile
@@ -89,19 +153,85 @@ func MessageToMessageEvent(message messages.Message) MessageEvent {
 		reactions = append(reactions, eventReaction)
 	}
 
+	thread := message.GetThread()
+	var threadLastReply *EventThreadReply
+	if lastReply := thread.GetLastReply(); lastReply != nil {
+		eventReply := ThreadReplyToEventThreadReply(*lastReply)
+		threadLastReply = &eventReply
+	}
+
+	var forwardedFrom *EventForwardedFrom
+	if messageForwardedFrom := message.GetForwardedFrom(); messageForwardedFrom != nil {
+		eventForwardedFrom := ForwardedFromToEventForwardedFrom(*messageForwardedFrom)
+		forwardedFrom = &eventForwardedFrom
+	}
+
+	var poll *EventPoll
+	if messagePoll := message.GetPoll(); messagePoll != nil {
+		eventPoll := PollToEventPoll(*messagePoll)
+		poll = &eventPoll
+	}
+
 	return MessageEvent{
-		Id:          message.GetId(),
-		SenderId:    message.GetSenderId(),
-		ChatId:      chat.GetId(),
-		Type:        string(message.GetType()),
-		Content:     message.GetContent(),
-		Voice:       eventVoice,
-		Circle:      eventCircle,
-		Attachments: attachments,
-		ReplyToId:   message.GetReplyToId(),
-		Mentioned:   message.GetMentioned(),
-		ReadedBy:    message.GetReadedBy(),
-		Reactions:   reactions,
-		CreatedAt:   message.GetCreatedAt(),
+		Id:                 message.GetId(),
+		SenderId:           message.GetSenderId(),
+		ChatId:             chat.GetId(),
+		Type:               string(message.GetType()),
+		Content:            message.GetContent(),
+		Voice:              eventVoice,
+		Circle:             eventCircle,
+		Attachments:        attachments,
+		ReplyToId:          message.GetReplyToId(),
+		ThreadRootId:       message.GetThreadRootId(),
+		ThreadRepliesCount: thread.GetRepliesCount(),
+		ThreadLastReply:    threadLastReply,
+		ForwardedFrom:      forwardedFrom,
+		Mentioned:          message.GetMentioned(),
+		ReadedBy:           message.GetReadedBy(),
+		Reactions:          reactions,
+		CreatedAt:          message.GetCreatedAt(),
+		EditedAt:           message.GetEditedAt(),
+		Poll:               poll,
+	}
+}
+
+func ChatReadPointerToChatReadEvent(pointer messages.ChatReadPointer) ChatReadEvent {
+	return ChatReadEvent{
+		ChatId:    pointer.GetChatId(),
+		UserId:    pointer.GetUserId(),
+		MessageId: pointer.GetMessageId(),
+		ReadAt:    pointer.GetReadAt(),
+	}
+}
+
+func JoinRequestToJoinRequestEvent(request chats.ChatJoinRequest) JoinRequestEvent {
+	return JoinRequestEvent{
+		Id:         request.GetId(),
+		ChatId:     request.GetChatId(),
+		UserId:     request.GetUserId(),
+		Status:     string(request.GetStatus()),
+		ResolvedBy: request.GetResolvedBy(),
+		CreatedAt:  request.GetCreatedAt(),
+		ResolvedAt: request.GetResolvedAt(),
+	}
+}
+
+func ChatFolderToChatFolderEvent(folder chats.ChatFolder) ChatFolderEvent {
+	chatIds := []int{}
+	chatIds = append(chatIds, folder.GetChatIds()...)
+
+	chatTypes := []string{}
+	for _, chatType := range folder.GetChatTypes() {
+		chatTypes = append(chatTypes, string(chatType))
+	}
+
+	return ChatFolderEvent{
+		Id:         folder.GetId(),
+		Title:      folder.GetTitle(),
+		Position:   folder.GetPosition(),
+		ChatIds:    chatIds,
+		ChatTypes:  chatTypes,
+		UnreadOnly: folder.GetUnreadOnly(),
+		MutedOnly:  folder.GetMutedOnly(),
 	}
 }
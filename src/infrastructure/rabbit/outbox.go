@@ -0,0 +1,160 @@
+package rabbit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/chack-check/chats-service/infrastructure/database"
+	"github.com/chack-check/chats-service/infrastructure/rabbit/retries"
+	"github.com/chack-check/chats-service/infrastructure/requestcontext"
+	"gorm.io/gorm"
+)
+
+const (
+	outboxRelayInterval  = 500 * time.Millisecond
+	outboxRelayBatchSize = 100
+	outboxMinRetryDelay  = time.Second
+	outboxMaxRetryDelay  = time.Minute
+	outboxMaxAttempts    = 20
+	outboxRetention      = 24 * time.Hour
+	outboxRelayLockKey   = 7305041
+)
+
+func chatOrderingKey(chatId int) string {
+	return fmt.Sprintf("chat:%d", chatId)
+}
+
+func userOrderingKey(userId int) string {
+	return fmt.Sprintf("user:%d", userId)
+}
+
+// EventsOutbox stores system events in the outbox table instead of publishing
+// them directly. Events sharing an ordering key are relayed in insertion order.
+// The actor and correlation id of the originating request are stamped on every
+// event it stores.
+type EventsOutbox struct {
+	adapter       database.OutboxAdapter
+	actorId       *int
+	correlationId string
+}
+
+func (outbox EventsOutbox) SendChatEvent(chatId int, event *SystemEvent) error {
+	event.ChatId = &chatId
+	return outbox.sendEvent(chatOrderingKey(chatId), event)
+}
+
+func (outbox EventsOutbox) SendUserEvent(userId int, event *SystemEvent) error {
+	return outbox.sendEvent(userOrderingKey(userId), event)
+}
+
+func (outbox EventsOutbox) sendEvent(orderingKey string, event *SystemEvent) error {
+	event.ActorId = outbox.actorId
+	event.CorrelationId = outbox.correlationId
+	if event.CorrelationId == "" {
+		event.CorrelationId = event.EventId
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return errors.Join(fmt.Errorf("error marshaling system event %s", event.EventType), err)
+	}
+
+	outboxEvent := database.OutboxEvent{
+		EventId:       event.EventId,
+		OrderingKey:   orderingKey,
+		EventType:     event.EventType,
+		Payload:       string(payload),
+		NextAttemptAt: time.Now(),
+	}
+	if err := outbox.adapter.Save(outboxEvent); err != nil {
+		return errors.Join(fmt.Errorf("error saving system event %s to outbox", event.EventType), err)
+	}
+
+	return nil
+}
+
+func NewEventsOutbox(ctx context.Context, db gorm.DB) EventsOutbox {
+	return EventsOutbox{
+		adapter:       database.NewOutboxAdapter(db),
+		actorId:       requestcontext.GetActorId(ctx),
+		correlationId: requestcontext.GetCorrelationId(ctx),
+	}
+}
+
+// OutboxRelay publishes pending outbox events to the events exchange. An
+// event that fails to publish blocks the later events with the same ordering
+// key until its retry succeeds, so consumers see each chat's events in order.
+// After outboxMaxAttempts failures the event is marked dead and skipped.
+type OutboxRelay struct {
+	db         *gorm.DB
+	connection *RabbitConnection
+}
+
+func (relay OutboxRelay) publishPending() error {
+	return relay.db.Transaction(func(tx *gorm.DB) error {
+		outbox := database.NewOutboxAdapter(*tx)
+		locked, err := outbox.TryLock(outboxRelayLockKey)
+		if err != nil || !locked {
+			return err
+		}
+
+		now := time.Now()
+		events, err := outbox.GetPending(outboxRelayBatchSize, now)
+		if err != nil {
+			return err
+		}
+
+		blockedKeys := make(map[string]bool)
+		for _, event := range events {
+			if blockedKeys[event.OrderingKey] {
+				continue
+			}
+
+			if err := relay.connection.PublishEvent(event.EventId, []byte(event.Payload)); err != nil {
+				log.Printf("error publishing outbox event %s (attempt %d): %v", event.EventId, event.Attempts+1, err)
+				if retries.AttemptsExhausted(event.Attempts+1, outboxMaxAttempts) {
+					log.Printf("outbox event %s exceeded %d attempts, marking it as dead", event.EventId, outboxMaxAttempts)
+					if err := outbox.MarkDead(event, err, now); err != nil {
+						return err
+					}
+					continue
+				}
+
+				blockedKeys[event.OrderingKey] = true
+				if err := outbox.MarkFailed(event, err, now.Add(retries.BackoffDelay(event.Attempts+1, outboxMinRetryDelay, outboxMaxRetryDelay))); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := outbox.MarkPublished(event, now); err != nil {
+				return err
+			}
+		}
+
+		return outbox.DeletePublishedBefore(now.Add(-outboxRetention))
+	})
+}
+
+func (relay OutboxRelay) Run() {
+	ticker := time.NewTicker(outboxRelayInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := relay.publishPending(); err != nil {
+			log.Printf("error relaying outbox events: %v", err)
+		}
+	}
+}
+
+func NewOutboxRelay(db *gorm.DB, connection *RabbitConnection) OutboxRelay {
+	return OutboxRelay{db: db, connection: connection}
+}
+
+func RunOutboxRelay() {
+	NewOutboxRelay(database.DatabaseConnection, EventsRabbitConnection).Run()
+}
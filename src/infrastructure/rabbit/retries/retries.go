@@ -0,0 +1,55 @@
+// Package retries holds the retry policies of the events outbox and the
+// rabbit consumers. It doesn't import the connections, so the policies can
+// be tested on their own.
+package retries
+
+import (
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+// RetryCountHeader holds the number of retries a consumer delivery went
+// through.
+const RetryCountHeader = "x-retry-count"
+
+// BackoffDelay returns the delay before retrying after the given number of
+// failed attempts. It starts at minDelay and doubles up to maxDelay.
+func BackoffDelay(attempts int, minDelay time.Duration, maxDelay time.Duration) time.Duration {
+	delay := minDelay
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > maxDelay {
+		return maxDelay
+	}
+
+	return delay
+}
+
+func AttemptsExhausted(attempts int, maxAttempts int) bool {
+	return attempts >= maxAttempts
+}
+
+// NextRetry returns the retry a failed delivery goes to. It returns false
+// when the delivery has to be dead lettered, because retrying can't fix it or
+// it already went through all the retries.
+func NextRetry(retryCount int, retriesCount int, retryable bool) (int, bool) {
+	if !retryable || retryCount >= retriesCount {
+		return 0, false
+	}
+
+	return retryCount + 1, true
+}
+
+func RetryCount(headers amqp.Table) int {
+	switch retryCount := headers[RetryCountHeader].(type) {
+	case int32:
+		return int(retryCount)
+	case int64:
+		return int(retryCount)
+	default:
+		return 0
+	}
+}
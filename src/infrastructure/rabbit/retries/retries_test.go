@@ -0,0 +1,102 @@
+package retries
+
+import (
+	"testing"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int
+		expected time.Duration
+	}{
+		{name: "no attempts yet", attempts: 0, expected: time.Second},
+		{name: "first attempt", attempts: 1, expected: time.Second},
+		{name: "second attempt", attempts: 2, expected: 2 * time.Second},
+		{name: "third attempt", attempts: 3, expected: 4 * time.Second},
+		{name: "sixth attempt", attempts: 6, expected: 32 * time.Second},
+		{name: "capped at max delay", attempts: 7, expected: time.Minute},
+		{name: "many attempts", attempts: 1000, expected: time.Minute},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if delay := BackoffDelay(test.attempts, time.Second, time.Minute); delay != test.expected {
+				t.Errorf("BackoffDelay() = %s, expected %s", delay, test.expected)
+			}
+		})
+	}
+}
+
+func TestAttemptsExhausted(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int
+		expected bool
+	}{
+		{name: "first attempt", attempts: 1, expected: false},
+		{name: "last allowed attempt", attempts: 19, expected: false},
+		{name: "max attempts", attempts: 20, expected: true},
+		{name: "over max attempts", attempts: 21, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if exhausted := AttemptsExhausted(test.attempts, 20); exhausted != test.expected {
+				t.Errorf("AttemptsExhausted() = %v, expected %v", exhausted, test.expected)
+			}
+		})
+	}
+}
+
+func TestNextRetry(t *testing.T) {
+	tests := []struct {
+		name              string
+		retryCount        int
+		retryable         bool
+		expectedNextRetry int
+		expectedOk        bool
+	}{
+		{name: "first failure", retryCount: 0, retryable: true, expectedNextRetry: 1, expectedOk: true},
+		{name: "second failure", retryCount: 1, retryable: true, expectedNextRetry: 2, expectedOk: true},
+		{name: "last retry", retryCount: 2, retryable: true, expectedNextRetry: 3, expectedOk: true},
+		{name: "retries exhausted", retryCount: 3, retryable: true, expectedOk: false},
+		{name: "retry count over the retries", retryCount: 5, retryable: true, expectedOk: false},
+		{name: "not retryable on first failure", retryCount: 0, retryable: false, expectedOk: false},
+		{name: "not retryable after a retry", retryCount: 1, retryable: false, expectedOk: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nextRetry, ok := NextRetry(test.retryCount, 3, test.retryable)
+			if ok != test.expectedOk || nextRetry != test.expectedNextRetry {
+				t.Errorf("NextRetry() = (%d, %v), expected (%d, %v)", nextRetry, ok, test.expectedNextRetry, test.expectedOk)
+			}
+		})
+	}
+}
+
+func TestRetryCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  amqp.Table
+		expected int
+	}{
+		{name: "no headers", headers: nil, expected: 0},
+		{name: "no retry header", headers: amqp.Table{"x-other": int32(2)}, expected: 0},
+		{name: "int32 header", headers: amqp.Table{RetryCountHeader: int32(2)}, expected: 2},
+		{name: "int64 header", headers: amqp.Table{RetryCountHeader: int64(3)}, expected: 3},
+		{name: "malformed header", headers: amqp.Table{RetryCountHeader: "2"}, expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if retryCount := RetryCount(test.headers); retryCount != test.expected {
+				t.Errorf("RetryCount() = %d, expected %d", retryCount, test.expected)
+			}
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package rabbit
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"sync"
 	"time"
 
+	"github.com/chack-check/chats-service/infrastructure/rabbit/retries"
 	"github.com/getsentry/sentry-go"
 	"github.com/streadway/amqp"
 )
@@ -17,9 +21,33 @@ type queue struct {
 	channel      *amqp.Channel
 	closed       bool
 	consumers    []messageConsumer
+
+	// Failed deliveries are republished on a separate channel in confirm
+	// mode. publishMutex serializes republishing so every confirmation can be
+	// matched to its publish by delivery tag.
+	publishMutex   sync.Mutex
+	publishChannel *amqp.Channel
+	confirmations  chan amqp.Confirmation
+	publishedCount uint64
 }
 
-type messageConsumer func([]byte)
+// Failed deliveries are republished to the delay queue of the next attempt
+// and come back to the consumer queue once its TTL expires. Deliveries that
+// exhaust every attempt, or can never be processed, go to the dead letter
+// exchange for manual inspection.
+var consumerRetryDelays = []time.Duration{time.Second, 10 * time.Second, time.Minute}
+
+const (
+	consumerPrefetchCount   = 10
+	republishConfirmTimeout = 10 * time.Second
+	deadLetterErrorHeader   = "x-dead-letter-error"
+)
+
+// ErrEventNotProcessable marks consumer errors that retrying can't fix, such
+// as malformed payloads. Such deliveries are dead lettered right away.
+var ErrEventNotProcessable = fmt.Errorf("event can't be processed")
+
+type messageConsumer func([]byte) error
 
 func NewQueue(url string, qName string, exchangeName string) *queue {
 	q := new(queue)
@@ -60,6 +88,9 @@ func (q *queue) Close() {
 	log.Printf("Closing connection")
 	q.closed = true
 	q.channel.Close()
+	if q.publishChannel != nil {
+		q.publishChannel.Close()
+	}
 	q.connection.Close()
 }
 
@@ -87,9 +118,12 @@ func (q *queue) connect() {
 			log.Println("Connection established!")
 
 			q.openChannel()
+			q.openPublishChannel()
 			q.declareQueue()
 			q.declareExchange()
 			q.bindQueue()
+			q.declareRetryQueues()
+			q.declareDeadLetter()
 
 			return
 		}
@@ -129,17 +163,93 @@ func (q *queue) bindQueue() {
 	logError("Exchange queue binding error", err)
 }
 
+func (q *queue) retryQueueName(attempt int) string {
+	return fmt.Sprintf("%s.retry.%d", q.name, attempt)
+}
+
+func (q *queue) deadLetterName() string {
+	return fmt.Sprintf("%s.dead-letter", q.name)
+}
+
+// declareRetryQueues declares a delay queue per retry attempt. Expired
+// messages are dead lettered through the default exchange straight back to
+// the consumer queue, so other queues bound to the exchange never see them.
+func (q *queue) declareRetryQueues() {
+	for i, delay := range consumerRetryDelays {
+		_, err := q.channel.QueueDeclare(
+			q.retryQueueName(i+1),
+			true,
+			false,
+			false,
+			false,
+			amqp.Table{
+				"x-message-ttl":             int64(delay / time.Millisecond),
+				"x-dead-letter-exchange":    "",
+				"x-dead-letter-routing-key": q.name,
+			},
+		)
+		logError("Retry queue declaration failed", err)
+	}
+}
+
+func (q *queue) declareDeadLetter() {
+	err := q.channel.ExchangeDeclare(
+		q.deadLetterName(),
+		"fanout",
+		true,
+		false,
+		false,
+		false,
+		nil,
+	)
+	logError("Dead letter exchange declaration failed", err)
+
+	_, err = q.channel.QueueDeclare(
+		q.deadLetterName(),
+		true,
+		false,
+		false,
+		false,
+		nil,
+	)
+	logError("Dead letter queue declaration failed", err)
+
+	err = q.channel.QueueBind(q.deadLetterName(), "", q.deadLetterName(), false, nil)
+	logError("Dead letter queue binding error", err)
+}
+
 func (q *queue) openChannel() {
 	channel, err := q.connection.Channel()
 	logError("Opening channel failed", err)
 	q.channel = channel
+
+	err = q.channel.Qos(consumerPrefetchCount, 0, false)
+	logError("Setting channel prefetch failed", err)
+}
+
+func (q *queue) openPublishChannel() {
+	q.publishMutex.Lock()
+	defer q.publishMutex.Unlock()
+
+	channel, err := q.connection.Channel()
+	logError("Opening publish channel failed", err)
+	if err != nil {
+		return
+	}
+
+	err = channel.Confirm(false)
+	logError("Enabling publisher confirms failed", err)
+
+	q.publishChannel = channel
+	q.confirmations = channel.NotifyPublish(make(chan amqp.Confirmation, consumerPrefetchCount))
+	q.publishedCount = 0
 }
 
 func (q *queue) registerQueueConsumer() (<-chan amqp.Delivery, error) {
 	msgs, err := q.channel.Consume(
 		q.name,
 		"",
-		true,
+		false,
 		false,
 		false,
 		false,
@@ -160,9 +270,117 @@ func (q *queue) executeMessageConsumer(err error, consumer messageConsumer, deli
 
 	go func() {
 		for delivery := range deliveries {
-			consumer(delivery.Body[:])
+			q.handleDelivery(consumer, delivery)
+		}
+	}()
+}
+
+func (q *queue) runConsumer(consumer messageConsumer, body []byte) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("consumer panicked: %v", recovered)
 		}
 	}()
+
+	return consumer(body)
+}
+
+func (q *queue) handleDelivery(consumer messageConsumer, delivery amqp.Delivery) {
+	err := q.runConsumer(consumer, delivery.Body)
+	if err == nil {
+		logError("Acknowledging message failed", delivery.Ack(false))
+		return
+	}
+
+	retryCount := retries.RetryCount(delivery.Headers)
+	logError(fmt.Sprintf("Consuming message failed (retry %d)", retryCount), err)
+
+	var publishErr error
+	if nextRetry, ok := retries.NextRetry(retryCount, len(consumerRetryDelays), !errors.Is(err, ErrEventNotProcessable)); ok {
+		publishErr = q.republish(delivery, "", q.retryQueueName(nextRetry), amqp.Table{
+			retries.RetryCountHeader: int32(nextRetry),
+		})
+	} else {
+		publishErr = q.republish(delivery, q.deadLetterName(), "", amqp.Table{
+			retries.RetryCountHeader: int32(retryCount),
+			deadLetterErrorHeader:    err.Error(),
+		})
+	}
+
+	if publishErr != nil {
+		logError("Republishing failed message failed", publishErr)
+		logError("Requeueing message failed", delivery.Nack(false, true))
+		return
+	}
+
+	logError("Acknowledging message failed", delivery.Ack(false))
+}
+
+// republish copies the delivery with its original headers, overridden by
+// headers, and waits for the broker to confirm it. The caller acks the
+// delivery only after a successful republish, so a message is never lost
+// between the two queues.
+func (q *queue) republish(delivery amqp.Delivery, exchange string, routingKey string, headers amqp.Table) error {
+	publishHeaders := amqp.Table{}
+	for key, value := range delivery.Headers {
+		publishHeaders[key] = value
+	}
+	for key, value := range headers {
+		publishHeaders[key] = value
+	}
+
+	q.publishMutex.Lock()
+	defer q.publishMutex.Unlock()
+
+	if q.publishChannel == nil {
+		return fmt.Errorf("publish channel is not open")
+	}
+
+	err := q.publishChannel.Publish(
+		exchange,
+		routingKey,
+		false,
+		false,
+		amqp.Publishing{
+			Headers:       publishHeaders,
+			ContentType:   delivery.ContentType,
+			DeliveryMode:  amqp.Persistent,
+			CorrelationId: delivery.CorrelationId,
+			MessageId:     delivery.MessageId,
+			Timestamp:     delivery.Timestamp,
+			Type:          delivery.Type,
+			Body:          delivery.Body,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	q.publishedCount++
+	return q.waitConfirmation(q.publishedCount)
+}
+
+func (q *queue) waitConfirmation(deliveryTag uint64) error {
+	timeout := time.After(republishConfirmTimeout)
+	for {
+		select {
+		case confirmation, ok := <-q.confirmations:
+			if !ok {
+				return fmt.Errorf("publish channel closed before confirmation")
+			}
+			// Confirmations of earlier publishes that timed out are skipped.
+			if confirmation.DeliveryTag < deliveryTag {
+				continue
+			}
+			if !confirmation.Ack {
+				return ErrEventNotConfirmed
+			}
+
+			return nil
+		case <-timeout:
+			return fmt.Errorf("republished message was not confirmed in %s", republishConfirmTimeout)
+		}
+	}
 }
 
 func (q *queue) recoverConsumers() {
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/chack-check/chats-service/domain/chats"
+	"github.com/chack-check/chats-service/domain/messages"
 	"github.com/chack-check/chats-service/domain/users"
 	"github.com/redis/go-redis/v9"
 )
@@ -44,6 +46,16 @@ func (adapter UserActionsLoggingAdapter) GetAllChatActionsUsers(chat chats.Chat)
 	return actions
 }
 
+func (adapter UserActionsLoggingAdapter) RemoveUserFromChatActions(chat chats.Chat, userId int) {
+	log.Printf("removing user from all chat actions: chat=%+v, userId=%d", chat, userId)
+	adapter.adapter.RemoveUserFromChatActions(chat, userId)
+}
+
+func (adapter UserActionsLoggingAdapter) RefreshChatActionUser(chat chats.Chat, user users.User) {
+	log.Printf("refreshing chat action user: chat=%+v, user=%+v", chat, user)
+	adapter.adapter.RefreshChatActionUser(chat, user)
+}
+
 type UserActionsAdapter struct {
 	db *redis.Client
 }
@@ -142,6 +154,116 @@ func (adapter UserActionsAdapter) GetAllChatActionsUsers(chat chats.Chat) map[ch
 	return actions
 }
 
+// updateAllChatActionsUsers rewrites the users list of every action type in the
+// chat with the result of update.
+func (adapter UserActionsAdapter) updateAllChatActionsUsers(chat chats.Chat, update func(users []RedisActionUser) []RedisActionUser) {
+	key := adapter.getChatActionsKey(chat.GetId())
+	chatAllActions, err := adapter.db.HGetAll(context.Background(), key).Result()
+	if err != nil {
+		return
+	}
+
+	for actionType, value := range chatAllActions {
+		var actionUsers []RedisActionUser
+		if err := json.Unmarshal([]byte(value), &actionUsers); err != nil {
+			continue
+		}
+
+		usersJson, err := json.Marshal(update(actionUsers))
+		if err != nil {
+			continue
+		}
+
+		adapter.db.HSet(context.Background(), key, actionType, string(usersJson))
+	}
+}
+
+func (adapter UserActionsAdapter) RemoveUserFromChatActions(chat chats.Chat, userId int) {
+	adapter.updateAllChatActionsUsers(chat, func(actionUsers []RedisActionUser) []RedisActionUser {
+		var resultUsers []RedisActionUser
+		for _, actionUser := range actionUsers {
+			if actionUser.Id != userId {
+				resultUsers = append(resultUsers, actionUser)
+			}
+		}
+
+		return resultUsers
+	})
+}
+
+func (adapter UserActionsAdapter) RefreshChatActionUser(chat chats.Chat, user users.User) {
+	adapter.updateAllChatActionsUsers(chat, func(actionUsers []RedisActionUser) []RedisActionUser {
+		for i, actionUser := range actionUsers {
+			if actionUser.Id != user.GetId() {
+				continue
+			}
+
+			actionUsers[i] = RedisActionUser{
+				Id:         user.GetId(),
+				LastName:   user.GetLastName(),
+				FirstName:  user.GetFirstName(),
+				MiddleName: user.GetMiddleName(),
+				Username:   user.GetUsername(),
+			}
+		}
+
+		return actionUsers
+	})
+}
+
 func NewUserActionsAdapter(db *redis.Client) chats.UserActionsPort {
 	return UserActionsLoggingAdapter{adapter: UserActionsAdapter{db: db}}
 }
+
+type SlowModeLoggingAdapter struct {
+	adapter messages.SlowModePort
+}
+
+func (adapter SlowModeLoggingAdapter) Acquire(chatId int, userId int, interval time.Duration) time.Duration {
+	log.Printf("acquiring slow mode slot: chatId=%d, userId=%d, interval=%v", chatId, userId, interval)
+	remainingWait := adapter.adapter.Acquire(chatId, userId, interval)
+	log.Printf("slow mode remaining wait: %v", remainingWait)
+	return remainingWait
+}
+
+func (adapter SlowModeLoggingAdapter) Release(chatId int, userId int) {
+	log.Printf("releasing slow mode slot: chatId=%d, userId=%d", chatId, userId)
+	adapter.adapter.Release(chatId, userId)
+}
+
+type SlowModeAdapter struct {
+	db *redis.Client
+}
+
+func (adapter SlowModeAdapter) getSlowModeKey(chatId int, userId int) string {
+	return fmt.Sprintf("chat:%d:slow_mode:%d", chatId, userId)
+}
+
+func (adapter SlowModeAdapter) Acquire(chatId int, userId int, interval time.Duration) time.Duration {
+	key := adapter.getSlowModeKey(chatId, userId)
+	acquired, err := adapter.db.SetNX(context.Background(), key, 1, interval).Result()
+	if err != nil {
+		log.Printf("error acquiring slow mode slot: %v", err)
+		return 0
+	}
+	if acquired {
+		return 0
+	}
+
+	remainingWait, err := adapter.db.PTTL(context.Background(), key).Result()
+	if err != nil || remainingWait < 0 {
+		return 0
+	}
+
+	return remainingWait
+}
+
+func (adapter SlowModeAdapter) Release(chatId int, userId int) {
+	if err := adapter.db.Del(context.Background(), adapter.getSlowModeKey(chatId, userId)).Err(); err != nil {
+		log.Printf("error releasing slow mode slot: %v", err)
+	}
+}
+
+func NewSlowModeAdapter(db *redis.Client) messages.SlowModePort {
+	return SlowModeLoggingAdapter{adapter: SlowModeAdapter{db: db}}
+}
@@ -0,0 +1,49 @@
+package requestcontext
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// CorrelationIdHeader carries the correlation id in HTTP headers and gRPC
+// metadata (lower cased there).
+const CorrelationIdHeader = "X-Correlation-Id"
+
+type contextKey string
+
+const (
+	correlationIdKey contextKey = "correlation_id"
+	actorIdKey       contextKey = "actor_id"
+)
+
+func NewCorrelationId() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(id)
+}
+
+func WithCorrelationId(ctx context.Context, correlationId string) context.Context {
+	return context.WithValue(ctx, correlationIdKey, correlationId)
+}
+
+func GetCorrelationId(ctx context.Context) string {
+	correlationId, _ := ctx.Value(correlationIdKey).(string)
+	return correlationId
+}
+
+func WithActorId(ctx context.Context, actorId int) context.Context {
+	return context.WithValue(ctx, actorIdKey, actorId)
+}
+
+func GetActorId(ctx context.Context) *int {
+	actorId, ok := ctx.Value(actorIdKey).(int)
+	if !ok {
+		return nil
+	}
+
+	return &actorId
+}
@@ -0,0 +1,43 @@
+package requestcontext
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+)
+
+func TestCorrelationId(t *testing.T) {
+	if correlationId := GetCorrelationId(context.Background()); correlationId != "" {
+		t.Errorf("correlation id of an empty context = %q, expected none", correlationId)
+	}
+
+	ctx := WithCorrelationId(context.Background(), "correlation")
+	if correlationId := GetCorrelationId(ctx); correlationId != "correlation" {
+		t.Errorf("correlation id = %q, expected %q", correlationId, "correlation")
+	}
+}
+
+func TestNewCorrelationId(t *testing.T) {
+	correlationId := NewCorrelationId()
+	if decoded, err := hex.DecodeString(correlationId); err != nil || len(decoded) != 16 {
+		t.Errorf("correlation id = %q, expected 16 hex encoded bytes", correlationId)
+	}
+	if NewCorrelationId() == correlationId {
+		t.Errorf("two correlation ids are equal")
+	}
+}
+
+func TestActorId(t *testing.T) {
+	if actorId := GetActorId(context.Background()); actorId != nil {
+		t.Errorf("actor id of an empty context = %d, expected none", *actorId)
+	}
+
+	ctx := WithActorId(WithCorrelationId(context.Background(), "correlation"), 5)
+	actorId := GetActorId(ctx)
+	if actorId == nil || *actorId != 5 {
+		t.Errorf("actor id = %v, expected 5", actorId)
+	}
+	if correlationId := GetCorrelationId(ctx); correlationId != "correlation" {
+		t.Errorf("correlation id = %q, expected it to be kept next to the actor id", correlationId)
+	}
+}
@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"log"
+
+	"github.com/chack-check/chats-service/domain/messages"
+)
+
+type MessagesSchedulerLoggingAdapter struct {
+	adapter messages.MessagesSchedulerPort
+}
+
+func (adapter MessagesSchedulerLoggingAdapter) Schedule(message messages.Message) {
+	log.Printf("scheduling message: id=%d, sendAt=%v", message.GetId(), message.GetSendAt())
+	adapter.adapter.Schedule(message)
+}
+
+func (adapter MessagesSchedulerLoggingAdapter) Cancel(message messages.Message) {
+	log.Printf("cancelling scheduled message: id=%d", message.GetId())
+	adapter.adapter.Cancel(message)
+}
+
+type MessagesSchedulerAdapter struct {
+	scheduler *MessagesScheduler
+}
+
+func (adapter MessagesSchedulerAdapter) Schedule(message messages.Message) {
+	sendAt := message.GetSendAt()
+	if sendAt == nil {
+		return
+	}
+
+	adapter.scheduler.Schedule(message.GetId(), *sendAt)
+}
+
+func (adapter MessagesSchedulerAdapter) Cancel(message messages.Message) {
+	adapter.scheduler.Cancel(message.GetId())
+}
+
+func NewMessagesSchedulerAdapter(scheduler *MessagesScheduler) messages.MessagesSchedulerPort {
+	return MessagesSchedulerLoggingAdapter{adapter: MessagesSchedulerAdapter{scheduler: scheduler}}
+}
@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// publishRetryDelay is how long a scheduled message waits for another publish
+// attempt after a failed one.
+const publishRetryDelay = time.Minute
+
+type MessagesScheduler struct {
+	mutex   sync.Mutex
+	timers  map[int]*time.Timer
+	publish func(scheduler *MessagesScheduler, messageId int) error
+}
+
+func (scheduler *MessagesScheduler) Schedule(messageId int, sendAt time.Time) {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	if timer, ok := scheduler.timers[messageId]; ok {
+		timer.Stop()
+	}
+
+	scheduler.timers[messageId] = time.AfterFunc(time.Until(sendAt), func() {
+		scheduler.mutex.Lock()
+		delete(scheduler.timers, messageId)
+		scheduler.mutex.Unlock()
+
+		log.Printf("publishing scheduled message: %d", messageId)
+		if err := scheduler.publish(scheduler, messageId); err != nil {
+			log.Printf("error publishing scheduled message %d, retrying in %v: %v", messageId, publishRetryDelay, err)
+			scheduler.Schedule(messageId, time.Now().Add(publishRetryDelay))
+		}
+	})
+}
+
+func (scheduler *MessagesScheduler) Cancel(messageId int) {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	if timer, ok := scheduler.timers[messageId]; ok {
+		timer.Stop()
+		delete(scheduler.timers, messageId)
+	}
+}
+
+func NewMessagesScheduler(publish func(scheduler *MessagesScheduler, messageId int) error) *MessagesScheduler {
+	return &MessagesScheduler{
+		timers:  make(map[int]*time.Timer),
+		publish: publish,
+	}
+}
+
+var Scheduler = NewMessagesScheduler(HandleScheduledMessage)
@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/chack-check/chats-service/domain/messages"
+	"github.com/chack-check/chats-service/infrastructure/database"
+	"github.com/chack-check/chats-service/infrastructure/rabbit"
+	"github.com/chack-check/chats-service/infrastructure/redisdb"
+	"gorm.io/gorm"
+)
+
+// HandleScheduledMessage publishes a due scheduled message and returns an
+// error only when the publish should be retried.
+func HandleScheduledMessage(scheduler *MessagesScheduler, messageId int) error {
+	err := database.Transaction(func(tx gorm.DB) error {
+		handler := messages.NewPublishScheduledMessageHandler(
+			database.NewChatsAdapter(tx),
+			database.NewMessagesAdapter(tx),
+			rabbit.NewMessageEventsAdapter(context.Background(), tx),
+			NewMessagesSchedulerAdapter(scheduler),
+			redisdb.NewSlowModeAdapter(redisdb.RedisConnection),
+		)
+		return handler.Execute(messageId)
+	})
+	if errors.Is(err, messages.ErrMessageNotFound) {
+		log.Printf("scheduled message %d no longer exists", messageId)
+		return nil
+	}
+
+	return err
+}
+
+func RestoreScheduledMessages() {
+	handler := messages.NewRestoreScheduledMessagesHandler(
+		database.NewMessagesAdapter(*database.DatabaseConnection),
+		NewMessagesSchedulerAdapter(Scheduler),
+	)
+	handler.Execute()
+}
@@ -2,12 +2,15 @@ package main
 
 import (
 	"github.com/chack-check/chats-service/infrastructure/api"
+	"github.com/chack-check/chats-service/infrastructure/database"
 	grpcservice "github.com/chack-check/chats-service/infrastructure/grpc_service"
 	"github.com/chack-check/chats-service/infrastructure/rabbit"
 )
 
 func main() {
+	database.Migrate(database.DatabaseConnection)
 	go grpcservice.RunGrpcServer()
+	go rabbit.RunOutboxRelay()
 	rabbit.StartConsumer("chats-service")
 	api.RunApi()
 }